@@ -18,8 +18,9 @@ import (
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/modules"
 	"github.com/Seklfreak/Robyul2/ratelimits"
+	"github.com/Seklfreak/Robyul2/robyulstate"
+	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
-	raven "github.com/getsentry/raven-go"
 	"github.com/sirupsen/logrus"
 )
 
@@ -54,16 +55,20 @@ func OnFirstReady(session *discordgo.Session, event *discordgo.Ready) {
 	log.WithField("module", "bot").Info("Connected to discord!")
 	log.WithField("module", "bot").Info("Invite link: " + fmt.Sprintf(
 		"https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot&permissions=%s",
-		helpers.GetConfig().Path("discord.id").Data().(string),
-		helpers.GetConfig().Path("discord.perms").Data().(string),
+		helpers.GetConfig().Discord.ID,
+		helpers.GetConfig().Discord.Perms,
 	))
 
 	for _, guild := range session.State.Guilds {
 		helpers.AddAutoleaverGuildID(guild.ID)
 	}
 
-	// request guild members from the gateway
+	// request guild members from the gateway, they are only needed if all members are cached
 	go func() {
+		if cache.GetRobyulstate().Members != robyulstate.MemberCachingAll {
+			return
+		}
+
 		time.Sleep(5 * time.Minute)
 
 		for _, guild := range session.State.Guilds {
@@ -74,8 +79,8 @@ func OnFirstReady(session *discordgo.Session, event *discordgo.Ready) {
 			//if guild.Large {
 			err := session.RequestGuildMembers(guild.ID, "", 0, false)
 			if err != nil && strings.Contains(err.Error(), "no websocket connection exists") {
-				cache.GetLogger().WithField("module", "bot").Warn("OnFirstReady: no websocket connection exists, stopping Robyul")
-				BotRuntimeChannel <- os.Interrupt
+				cache.GetLogger().WithField("module", "bot").Warnf("OnFirstReady: no websocket connection exists, restarting shard %d", session.ShardID)
+				go restartShard(session.ShardID)
 				return
 			}
 			helpers.RelaxLog(err)
@@ -89,13 +94,10 @@ func OnFirstReady(session *discordgo.Session, event *discordgo.Ready) {
 		}
 	}()
 
-	// Run ratelimiter
-	ratelimits.Container.Init()
-
 	go func() {
 		time.Sleep(3 * time.Second)
 
-		configName := helpers.GetConfig().Path("bot.name").Data().(string)
+		configName := helpers.GetConfig().Bot.Name
 
 		// Change name if desired
 		if configName != "" && configName != session.State.User.Username {
@@ -116,6 +118,15 @@ func OnFirstReady(session *discordgo.Session, event *discordgo.Ready) {
 	// }()
 }
 
+// restartShard restarts a shard whose gateway connection broke, instead of stopping Robyul
+func restartShard(shard int) {
+	err := cache.GetSession().RestartShard(shard)
+	if err == shardmanager.ErrShardRestarting {
+		return
+	}
+	helpers.RelaxLog(err)
+}
+
 func BotDestroy() {
 	modules.Uninit(cache.GetSession())
 	helpers.RemoveReactionsFromPagedEmbeds()
@@ -124,8 +135,12 @@ func BotDestroy() {
 func OnReconnect(session *discordgo.Session, event *discordgo.Ready) {
 	cache.GetLogger().WithField("module", "bot").Info("Reconnected to discord!")
 
-	// request guild members from the gateway
+	// request guild members from the gateway, they are only needed if all members are cached
 	go func() {
+		if cache.GetRobyulstate().Members != robyulstate.MemberCachingAll {
+			return
+		}
+
 		time.Sleep(5 * time.Second)
 
 		// for _, guild := range session.State.Guilds {
@@ -143,8 +158,8 @@ func OnReconnect(session *discordgo.Session, event *discordgo.Ready) {
 			//if guild.Large {
 			err := session.RequestGuildMembers(guild.ID, "", 0, false)
 			if err != nil && strings.Contains(err.Error(), "no websocket connection exists") {
-				cache.GetLogger().WithField("module", "bot").Warn("OnReconnect: no websocket connection exists, stopping Robyul")
-				BotRuntimeChannel <- os.Interrupt
+				cache.GetLogger().WithField("module", "bot").Warnf("OnReconnect: no websocket connection exists, restarting shard %d", session.ShardID)
+				go restartShard(session.ShardID)
 				return
 			}
 			helpers.RelaxLog(err)
@@ -171,50 +186,6 @@ func BotOnMemberListChunk(session *discordgo.Session, members *discordgo.GuildMe
 	//	members.GuildID, len(members.Members)))
 }
 
-func BotGuildOnPresenceUpdate(session *discordgo.Session, presence *discordgo.PresenceUpdate) {
-	if presence.GuildID == "" {
-		return
-	}
-
-	member, err := session.State.Member(presence.GuildID, presence.User.ID)
-	if err != nil {
-		if strings.Contains(err.Error(), "state cache not found") {
-			return
-		}
-		raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-		return
-	}
-
-	change := false
-	if presence.User.Avatar != "" {
-		member.User.Avatar = presence.User.Avatar
-		change = true
-	}
-	if presence.User.Discriminator != "" {
-		member.User.Discriminator = presence.User.Discriminator
-		change = true
-	}
-	if presence.User.Email != "" {
-		member.User.Email = presence.User.Email
-		change = true
-	}
-	if presence.User.Token != "" {
-		member.User.Token = presence.User.Token
-		change = true
-	}
-	if presence.User.Username != "" {
-		member.User.Username = presence.User.Username
-		change = true
-	}
-
-	if change == true {
-		err = session.State.MemberAdd(member)
-		if err != nil {
-			raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-		}
-	}
-}
-
 func BotOnGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMemberAdd) {
 	modules.CallExtendedPluginOnGuildMemberAdd(
 		member.Member,
@@ -277,9 +248,9 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 
 	// Check if the message contains @mentions for us
 	if strings.HasPrefix(message.Content, "<@") && len(message.Mentions) > 0 && message.Mentions[0].ID == session.State.User.ID {
-		// Consume a key for this action
-		e := ratelimits.Container.Drain(1, message.Author.ID)
-		if e != nil {
+		// Consume a command for this action
+		retryAfter, e := ratelimits.TakeCommand(message.Author.ID)
+		if e != nil || retryAfter > 0 {
 			return
 		}
 
@@ -310,14 +281,14 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 			if prefix == "" {
 				helpers.SendMessage(
 					channel.ID,
-					helpers.GetText("bot.prefix.not-set"),
+					helpers.GetTextForMessage(message.Message, "bot.prefix.not-set"),
 				)
 				return
 			}
 
 			helpers.SendMessage(
 				channel.ID,
-				helpers.GetTextF("bot.prefix.is", prefix),
+				helpers.GetTextForMessage(message.Message, "bot.prefix.is", prefix),
 			)
 			return
 
@@ -353,7 +324,7 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 					helpers.SendError(message.Message, err)
 				} else {
 					helpers.SendMessage(channel.ID,
-						helpers.GetTextF("plugins.mod.prefix-set-success",
+						helpers.GetTextForMessage(message.Message, "plugins.mod.prefix-set-success",
 							helpers.GetPrefixForServer(channel.GuildID)))
 				}
 			})
@@ -418,7 +389,7 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 				}
 				// send builtin emoji
 				for character := range msg {
-					filename := emojiFile(helpers.GetConfig().Path("assets_folder").Data().(string)+"/twemoji72", msg[character:])
+					filename := emojiFile(helpers.GetConfig().AssetsFolder+"/twemoji72", msg[character:])
 					if filename != "" {
 						emojiFile, err := os.Open(filename)
 						if err == nil {
@@ -490,10 +461,7 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 	}
 
 	// Check if the user is allowed to request commands
-	if !ratelimits.Container.HasKeys(message.Author.ID) && !helpers.IsBotAdmin(message.Author.ID) {
-		helpers.SendMessage(message.ChannelID, helpers.GetTextF("bot.ratelimit.hit", message.Author.ID))
-
-		ratelimits.Container.Set(message.Author.ID, -1)
+	if !modules.CommandAllowed(message.Message) {
 		return
 	}
 
@@ -506,6 +474,10 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 	// Check if the user calls for help
 	if cmd == "h" || cmd == "help" {
 		metrics.CommandsExecuted.Add(1)
+		// [p]help <command> [<subcommand>...] for declared commands
+		if len(parts) >= 2 && modules.SendCommandHelp(resolveHelpPath(channel.GuildID, parts[1:]), message.Message) {
+			return
+		}
 		sendHelp(message)
 		return
 	}
@@ -525,6 +497,17 @@ func BotOnMessageCreate(session *discordgo.Session, message *discordgo.MessageCr
 	modules.CallBotPlugin(cmd, content, message.Message)
 }
 
+// resolveHelpPath replaces an alias of the guild at the start of the help path with the command it calls
+func resolveHelpPath(guildID string, path []string) []string {
+	commandAlias, ok := helpers.GetCommandAlias(guildID, path[0])
+	if !ok {
+		return path
+	}
+
+	resolvedPath := append([]string{commandAlias.Command}, strings.Fields(commandAlias.Arguments)...)
+	return append(resolvedPath, path[1:]...)
+}
+
 func emojiFile(base, s string) string {
 	found := ""
 	filename := ""
@@ -632,6 +615,6 @@ func sendHelp(message *discordgo.MessageCreate) {
 
 	helpers.SendMessage(
 		message.ChannelID,
-		helpers.GetTextF("bot.help", message.Author.ID, channel.GuildID),
+		helpers.GetTextForMessage(message.Message, "bot.help", message.Author.ID, channel.GuildID),
 	)
 }
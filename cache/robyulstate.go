@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Seklfreak/Robyul2/robyulstate"
+)
+
+var (
+	robyulState      *robyulstate.Robyulstate
+	robyulStateMutex sync.RWMutex
+)
+
+func SetRobyulstate(s *robyulstate.Robyulstate) {
+	robyulStateMutex.Lock()
+	robyulState = s
+	robyulStateMutex.Unlock()
+}
+
+func GetRobyulstate() *robyulstate.Robyulstate {
+	robyulStateMutex.RLock()
+	defer robyulStateMutex.RUnlock()
+
+	if robyulState == nil {
+		panic(errors.New("Tried to get robyul state before cache#SetRobyulstate() was called"))
+	}
+
+	return robyulState
+}
@@ -14,6 +14,7 @@ require (
 	github.com/Seklfreak/polr-go v0.0.0-20180425152206-e6c594fafce8
 	github.com/Unleash/unleash-client-go v0.0.0-20181121205122-ae068e0ad68c
 	github.com/VojtechVitek/go-trello v0.0.0-20161023024849-28ebf2756ecc
+	github.com/alicebob/miniredis/v2 v2.11.4
 	github.com/andybons/gogif v0.0.0-20140526152223-16d573594812
 	github.com/azr/backoff v0.0.0-20160115115103-53511d3c7330 // indirect
 	github.com/beefsack/go-rate v0.0.0-20180408011153-efa7637bb9b6 // indirect
@@ -42,10 +43,10 @@ require (
 	github.com/go-redis/cache v6.3.5+incompatible
 	github.com/go-redis/redis v6.15.7+incompatible
 	github.com/gopherjs/gopherjs v0.0.0-20181103185306-d547d1d9531e // indirect
-	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/gorilla/websocket v1.4.2
 	github.com/huandu/facebook v2.3.1+incompatible
 	github.com/inconshreveable/go-keen v0.0.0-20170228023802-f7cb12356363
-	github.com/json-iterator/go v1.1.5
+	github.com/json-iterator/go v1.1.10
 	github.com/jtolds/gls v4.2.1+incompatible // indirect
 	github.com/jzelinskie/geddit v0.0.0-20181001045958-34240685d019
 	github.com/karrick/tparse/v2 v2.6.1
@@ -65,6 +66,7 @@ require (
 	github.com/olebedev/when v0.0.0-20171024044931-53693fbb59a8
 	github.com/olivere/elastic v6.2.14+incompatible
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.7.0
 	github.com/renstrom/fuzzysearch v1.0.1
 	github.com/saintfish/chardet v0.0.0-20120816061221-3af4cd4741ca // indirect
 	github.com/satori/go.uuid v1.2.0
@@ -79,6 +81,7 @@ require (
 	github.com/zonedb/zonedb v0.0.0-20181223081958-1e4b8eea6f56 // indirect
 	go4.org v0.0.0-20181109185143-00e24f1b2599 // indirect
 	golang.org/x/crypto v0.0.0-20201016220609-9e8e0b390897 // indirect
+	golang.org/x/image v0.0.0-20190802002840-cff245a6509b
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/sys v0.0.0-20201029080932-201ba4db2418 // indirect
 	golang.org/x/text v0.3.2
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/Seklfreak/Robyul2/cache"
+)
+
+const cacheInvalidationChannel = "robyul2-discord:cache-invalidation"
+
+type cacheInvalidation struct {
+	Cache   string
+	Process string
+}
+
+var (
+	cacheInvalidationHandlers     = make(map[string][]func() error)
+	cacheInvalidationHandlersLock sync.RWMutex
+)
+
+// OnCacheInvalidation registers a function which reloads a process local cache
+// It is called in every process when any process invalidates the cache with the same name
+func OnCacheInvalidation(name string, reload func() error) {
+	cacheInvalidationHandlersLock.Lock()
+	defer cacheInvalidationHandlersLock.Unlock()
+
+	cacheInvalidationHandlers[name] = append(cacheInvalidationHandlers[name], reload)
+}
+
+// InvalidateCache reloads the cache in this process and tells all other processes to reload it
+// Returns the error of the local reload, or the error publishing the invalidation
+func InvalidateCache(name string) (err error) {
+	err = reloadCache(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&cacheInvalidation{
+		Cache:   name,
+		Process: ProcessName(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return cache.GetRedisClient().Publish(cacheInvalidationChannel, string(data)).Err()
+}
+
+// ListenForCacheInvalidations reloads the caches invalidated by other processes, blocks forever
+func ListenForCacheInvalidations() {
+	defer Recover()
+
+	pubSub := cache.GetRedisClient().Subscribe(cacheInvalidationChannel)
+	defer pubSub.Close()
+
+	for message := range pubSub.Channel() {
+		var invalidation cacheInvalidation
+		err := json.Unmarshal([]byte(message.Payload), &invalidation)
+		if err != nil {
+			RelaxLog(err)
+			continue
+		}
+
+		if invalidation.Process == ProcessName() {
+			continue
+		}
+
+		cache.GetLogger().WithField("module", "helpers").Infof("reloading cache %s, invalidated by process %s",
+			invalidation.Cache, invalidation.Process)
+		RelaxLog(reloadCache(invalidation.Cache))
+	}
+}
+
+func reloadCache(name string) (err error) {
+	cacheInvalidationHandlersLock.RLock()
+	handlers := cacheInvalidationHandlers[name]
+	cacheInvalidationHandlersLock.RUnlock()
+
+	for _, reload := range handlers {
+		err = reload()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ func PictureIsSafe(reader io.Reader) (safe bool) {
 	if visionClient == nil {
 		os.Setenv(
 			"GOOGLE_APPLICATION_CREDENTIALS",
-			GetConfig().Path("google.client_credentials_json_location").Data().(string),
+			GetConfig().Google.ClientCredentialsJSONLocation,
 		)
 
 		visionClient, err = vision.NewImageAnnotatorClient(ctx)
@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/models"
+)
+
+// GetCommandAlias returns the alias of the guild with the given name
+func GetCommandAlias(guildID, alias string) (commandAlias models.CommandAlias, ok bool) {
+	if guildID == "" {
+		return commandAlias, false
+	}
+
+	alias = strings.ToLower(alias)
+	for _, commandAlias = range GuildSettingsGetCached(guildID).CommandAliases {
+		if commandAlias.Alias == alias {
+			return commandAlias, true
+		}
+	}
+	return commandAlias, false
+}
+
+// IsCommandDisabled returns true if guild admins disabled the command name in the guild
+func IsCommandDisabled(guildID, command string) bool {
+	if guildID == "" {
+		return false
+	}
+
+	command = strings.ToLower(command)
+	for _, disabledCommand := range GuildSettingsGetCached(guildID).DisabledCommands {
+		if disabledCommand == command {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveCommand resolves the aliases of the guild
+// Returns the command and content to execute, and false if the command or the command called by the alias has been disabled in the guild
+func ResolveCommand(guildID, command, content string) (resolvedCommand, resolvedContent string, ok bool) {
+	if IsCommandDisabled(guildID, command) {
+		return command, content, false
+	}
+
+	commandAlias, isAlias := GetCommandAlias(guildID, command)
+	if !isAlias {
+		return command, content, true
+	}
+
+	if IsCommandDisabled(guildID, commandAlias.Command) {
+		return command, content, false
+	}
+
+	resolvedContent = strings.TrimSpace(commandAlias.Arguments + " " + content)
+	return commandAlias.Command, resolvedContent, true
+}
@@ -0,0 +1,178 @@
+package helpers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConfig = `{
+	"bot": {"name": "Robyul"},
+	"discord": {"id": "283848369250500608", "token": "token"},
+	"redis": {"address": "localhost:6379"},
+	"mongodb": {"url": "mongodb://localhost"}
+}`
+
+func testLookupEnv(environment map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := environment[key]
+		return value, ok
+	}
+}
+
+func TestParseConfigDefaults(t *testing.T) {
+	parsedConfig, _, err := ParseConfig([]byte(testConfig), testLookupEnv(nil))
+	if err != nil {
+		t.Fatal("failed to parse config:", err)
+	}
+
+	if parsedConfig.Bot.Name != "Robyul" || parsedConfig.Discord.Token != "token" {
+		t.Fatal("unexpected values", parsedConfig.Bot.Name, parsedConfig.Discord.Token)
+	}
+	if parsedConfig.MongoDB.DB != "Robyul" || parsedConfig.MetricsIP != "127.0.0.1" || parsedConfig.Discord.State.Members != "all" {
+		t.Fatal("defaults have not been applied", parsedConfig.MongoDB.DB, parsedConfig.MetricsIP, parsedConfig.Discord.State.Members)
+	}
+	// examples are only used for config.dist.json
+	if parsedConfig.Translator.LibreTranslate.URL != "" {
+		t.Fatal("example has been applied", parsedConfig.Translator.LibreTranslate.URL)
+	}
+}
+
+func TestParseConfigEnvironment(t *testing.T) {
+	parsedConfig, _, err := ParseConfig([]byte(testConfig), testLookupEnv(map[string]string{
+		"ROBYUL_DISCORD_TOKEN":                 "other token",
+		"ROBYUL_DEBUG":                         "true",
+		"ROBYUL_DISCORD_SHARDING_COUNT":        "16",
+		"ROBYUL_AUTOLEAVER_WHITELIST":          "1, 2,,3",
+		"ROBYUL_SUSHII_IMAGE_SERVER_BASE":      "http://sushii:3000",
+		"ROBYUL_TRANSLATOR_LIBRETRANSLATE_URL": "http://libretranslate:5000",
+		"ROBYUL_LOGGING_DISCORD_WEBHOOK":       "https://discord.com/api/webhooks/1/token",
+		"ROBYUL_NOT_A_CONFIG_KEY_BUT_EXPORTED": "ignored",
+	}))
+	if err != nil {
+		t.Fatal("failed to parse config:", err)
+	}
+
+	if parsedConfig.Discord.Token != "other token" || !parsedConfig.Debug || parsedConfig.Discord.Sharding.Count != 16 {
+		t.Fatal("environment has not been applied", parsedConfig.Discord.Token, parsedConfig.Debug, parsedConfig.Discord.Sharding.Count)
+	}
+	if !reflect.DeepEqual(parsedConfig.Autoleaver.Whitelist, []string{"1", "2", "3"}) {
+		t.Fatal("unexpected whitelist", parsedConfig.Autoleaver.Whitelist)
+	}
+	if parsedConfig.SushiiImageServer.Base != "http://sushii:3000" ||
+		parsedConfig.Translator.LibreTranslate.URL != "http://libretranslate:5000" ||
+		parsedConfig.Logging.DiscordWebhook != "https://discord.com/api/webhooks/1/token" {
+		t.Fatal("environment has not been applied to nested keys")
+	}
+}
+
+func TestParseConfigProblems(t *testing.T) {
+	_, _, err := ParseConfig([]byte(`{
+		"debug": "yes",
+		"discord": {"id": "283848369250500608", "sharding": {"count": 1.5}},
+		"redis": [],
+		"autoleaver": {"whitelist": ["1", 2]}
+	}`), testLookupEnv(map[string]string{
+		"ROBYUL_DISCORD_SHARDING_MAX_SHARDS": "many",
+	}))
+	if err == nil {
+		t.Fatal("invalid config has been accepted")
+	}
+
+	// every problem is reported at once
+	for _, problem := range []string{
+		"debug: expected a boolean, got a string",
+		"discord.sharding.count: expected an integer, got a number",
+		"redis: expected an object, got a list",
+		"autoleaver.whitelist: expected a list of strings, got an item of type a number",
+		"ROBYUL_DISCORD_SHARDING_MAX_SHARDS: expected an integer",
+		"bot.name: missing",
+		"discord.token: missing",
+		"mongodb.url: missing",
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Fatalf("problem %q has not been reported:\n%s", problem, err.Error())
+		}
+	}
+	if strings.Contains(err.Error(), "discord.id") {
+		t.Fatal("valid key has been reported:\n", err.Error())
+	}
+}
+
+func TestParseConfigUnknownKeys(t *testing.T) {
+	_, warnings, err := ParseConfig([]byte(`{
+		"bot": {"name": "Robyul", "nmae": "Robyul"},
+		"discord": {"id": "283848369250500608", "token": "token"},
+		"redis": {"address": "localhost:6379"},
+		"mongodb": {"url": "mongodb://localhost"},
+		"removed_section": {}
+	}`), testLookupEnv(nil))
+	if err != nil {
+		t.Fatal("unknown keys have been reported as problems:", err)
+	}
+
+	if len(warnings) != 2 ||
+		!strings.Contains(warnings[0], "removed_section") || !strings.Contains(warnings[1], "bot.nmae") {
+		t.Fatal("unknown keys have not been reported:", warnings)
+	}
+}
+
+func TestParseConfigRequiredFromEnvironment(t *testing.T) {
+	parsedConfig, _, err := ParseConfig([]byte(`{"bot": {"name": "Robyul"}, "discord": {"id": "1"}}`), testLookupEnv(map[string]string{
+		"ROBYUL_DISCORD_TOKEN": "token",
+		"ROBYUL_REDIS_ADDRESS": "localhost:6379",
+		"ROBYUL_MONGODB_URL":   "mongodb://localhost",
+	}))
+	if err != nil {
+		t.Fatal("required keys from the environment have not been accepted:", err)
+	}
+	if parsedConfig.Redis.Address != "localhost:6379" {
+		t.Fatal("unexpected redis address", parsedConfig.Redis.Address)
+	}
+}
+
+func TestCopyReloadableConfig(t *testing.T) {
+	currentConfig, _, err := ParseConfig([]byte(testConfig), testLookupEnv(nil))
+	if err != nil {
+		t.Fatal("failed to parse config:", err)
+	}
+	reloadedConfig, _, err := ParseConfig([]byte(testConfig), testLookupEnv(map[string]string{
+		"ROBYUL_DISCORD_TOKEN":           "other token",
+		"ROBYUL_LASTFM_API_KEY":          "lastfm key",
+		"ROBYUL_LOGGING_DISCORD_WEBHOOK": "https://discord.com/api/webhooks/1/token",
+		"ROBYUL_AUTOLEAVER_WHITELIST":    "1",
+	}))
+	if err != nil {
+		t.Fatal("failed to parse config:", err)
+	}
+
+	newConfig := *currentConfig
+	restartRequired := copyReloadableConfig(reflect.ValueOf(&newConfig).Elem(), reflect.ValueOf(reloadedConfig).Elem(), "", false)
+
+	if !reflect.DeepEqual(restartRequired, []string{"discord.token"}) {
+		t.Fatal("unexpected keys requiring a restart", restartRequired)
+	}
+	if newConfig.Discord.Token != "token" {
+		t.Fatal("key requiring a restart has been reloaded")
+	}
+	if newConfig.LastFm.APIKey != "lastfm key" || newConfig.Logging.DiscordWebhook != "https://discord.com/api/webhooks/1/token" ||
+		!reflect.DeepEqual(newConfig.Autoleaver.Whitelist, []string{"1"}) {
+		t.Fatal("reloadable keys have not been reloaded")
+	}
+}
+
+func TestDistConfigUpToDate(t *testing.T) {
+	generated, err := GenerateDistConfig()
+	if err != nil {
+		t.Fatal("failed to generate config:", err)
+	}
+	current, err := ioutil.ReadFile("../config.dist.json")
+	if err != nil {
+		t.Fatal("failed to read config.dist.json:", err)
+	}
+	if !bytes.Equal(generated, current) {
+		t.Fatal("config.dist.json is out of date, run go generate ./helpers/")
+	}
+}
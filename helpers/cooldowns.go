@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/ratelimits"
+	"github.com/bwmarrin/discordgo"
+)
+
+// GetCooldownDuration returns the duration of the cooldown in the guild, guild admins can override the default duration
+func GetCooldownDuration(cooldown ratelimits.Cooldown, guildID string) time.Duration {
+	if guildID == "" {
+		return cooldown.Duration
+	}
+
+	names := append([]string{cooldown.Command}, cooldown.Aliases...)
+	for _, override := range GuildSettingsGetCached(guildID).CommandCooldowns {
+		for _, name := range names {
+			if override.Command == name {
+				return override.Duration
+			}
+		}
+	}
+	return cooldown.Duration
+}
+
+// TakeCooldown starts the cooldown for the author of the message
+// Returns false and tells the author how long they have to wait if the cooldown is still running
+func TakeCooldown(cooldown ratelimits.Cooldown, command string, msg *discordgo.Message) bool {
+	if IsBotAdmin(msg.Author.ID) {
+		return true
+	}
+
+	duration := GetCooldownDuration(cooldown, msg.GuildID)
+	if duration <= 0 {
+		return true
+	}
+
+	retryAfter, err := ratelimits.TakeCooldown(cooldown, duration, msg.GuildID, msg.ChannelID, msg.Author.ID)
+	if err != nil {
+		// do not block commands if the limiter is unavailable
+		cache.GetLogger().WithField("module", "helpers/cooldowns").Warnf("failed to take cooldown %s: %s", cooldown.Command, err.Error())
+		return true
+	}
+	if retryAfter <= 0 {
+		return true
+	}
+
+	// round up to full seconds
+	retryAfter = ((retryAfter + time.Second - 1) / time.Second) * time.Second
+
+	_, err = SendMessage(msg.ChannelID, GetTextForMessage(msg, "bot.ratelimit.cooldown",
+		msg.Author.ID, GetPrefixForServer(msg.GuildID)+command, HumanizeDuration(retryAfter)))
+	RelaxLog(err)
+	return false
+}
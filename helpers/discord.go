@@ -298,7 +298,7 @@ func IsModByID(guildID string, userID string) bool {
 // RequireAdmin only calls $cb if the author is an admin or has MANAGE_SERVER permission
 func RequireAdmin(msg *discordgo.Message, cb Callback) {
 	if !IsAdmin(msg) {
-		SendMessage(msg.ChannelID, GetText("admin.no_permission"))
+		SendMessage(msg.ChannelID, GetTextForMessage(msg, "admin.no_permission"))
 		return
 	}
 
@@ -308,7 +308,7 @@ func RequireAdmin(msg *discordgo.Message, cb Callback) {
 // RequireAdmin only calls $cb if the author is an admin or has MANAGE_SERVER permission
 func RequireAdminOrStaff(msg *discordgo.Message, cb Callback) {
 	if !IsAdmin(msg) && !IsRobyulMod(msg.Author.ID) {
-		SendMessage(msg.ChannelID, GetText("admin.no_permission"))
+		SendMessage(msg.ChannelID, GetTextForMessage(msg, "admin.no_permission"))
 		return
 	}
 
@@ -318,7 +318,7 @@ func RequireAdminOrStaff(msg *discordgo.Message, cb Callback) {
 // RequireAdmin only calls $cb if the author is an admin or has MANAGE_SERVER permission
 func RequireMod(msg *discordgo.Message, cb Callback) {
 	if !IsMod(msg) {
-		SendMessage(msg.ChannelID, GetText("mod.no_permission"))
+		SendMessage(msg.ChannelID, GetTextForMessage(msg, "mod.no_permission"))
 		return
 	}
 
@@ -328,7 +328,7 @@ func RequireMod(msg *discordgo.Message, cb Callback) {
 // RequireBotAdmin only calls $cb if the author is a bot admin
 func RequireBotAdmin(msg *discordgo.Message, cb Callback) {
 	if !IsBotAdmin(msg.Author.ID) {
-		SendMessage(msg.ChannelID, GetText("botadmin.no_permission"))
+		SendMessage(msg.ChannelID, GetTextForMessage(msg, "botadmin.no_permission"))
 		return
 	}
 
@@ -338,7 +338,7 @@ func RequireBotAdmin(msg *discordgo.Message, cb Callback) {
 // RequireSupportMod only calls $cb if the author is a support mod
 func RequireRobyulMod(msg *discordgo.Message, cb Callback) {
 	if !IsRobyulMod(msg.Author.ID) {
-		SendMessage(msg.ChannelID, GetText("robyulmod.no_permission"))
+		SendMessage(msg.ChannelID, GetTextForMessage(msg, "robyulmod.no_permission"))
 		return
 	}
 
@@ -351,21 +351,21 @@ func ConfirmEmbed(guildID, channelID string, author *discordgo.User, confirmMess
 		&discordgo.MessageSend{
 			Content: "<@" + author.ID + ">",
 			Embed: &discordgo.MessageEmbed{
-				Title:       GetText("bot.embeds.please-confirm-title"),
+				Title:       GetTextForGuild(guildID, "bot.embeds.please-confirm-title"),
 				Description: confirmMessageText,
 			},
 		})
 	if err != nil {
-		SendMessage(channelID, GetTextF("bot.errors.general", err.Error()))
+		SendMessage(channelID, GetTextForGuild(guildID, "bot.errors.general", err.Error()))
 		return false
 	}
 	if len(confirmMessages) <= 0 {
-		SendMessage(channelID, GetText("bot.errors.generic-nomessage"))
+		SendMessage(channelID, GetTextForGuild(guildID, "bot.errors.generic-nomessage"))
 		return false
 	}
 	confirmMessage := confirmMessages[0]
 	if len(confirmMessage.Embeds) <= 0 {
-		SendMessage(channelID, GetText("bot.errors.no-embed"))
+		SendMessage(channelID, GetTextForGuild(guildID, "bot.errors.no-embed"))
 		return false
 	}
 
@@ -779,7 +779,7 @@ func LogMachineryError(errorMessage string) (err error) {
 }
 
 func GetGuildMember(guildID string, userID string) (*discordgo.Member, error) {
-	targetMember, err := cache.GetSession().SessionForGuildS(guildID).State.Member(guildID, userID)
+	targetMember, err := GetGuildMemberWithoutApi(guildID, userID)
 	if targetMember == nil || targetMember.GuildID == "" || targetMember.JoinedAt == "" {
 		cache.GetLogger().WithField("module", "discord").WithField("method", "GetGuildMember").Debug(
 			fmt.Sprintf("discord api request: GuildMember: %s, %s", guildID, userID))
@@ -791,8 +791,29 @@ func GetGuildMember(guildID string, userID string) (*discordgo.Member, error) {
 	return targetMember, err
 }
 
+// GetGuildMemberWithoutApi returns the member from the state, members of other processes are read from the shared state
 func GetGuildMemberWithoutApi(guildID string, userID string) (*discordgo.Member, error) {
-	return cache.GetSession().SessionForGuildS(guildID).State.Member(guildID, userID)
+	return cache.GetRobyulstate().Member(guildID, userID)
+}
+
+// GetAllGuildMembers requests all members of the guild from Discord, in pages of 1000 members
+// Use it where every member is needed, the state only has the members which are cached by the member caching
+func GetAllGuildMembers(guildID string) (members []*discordgo.Member, err error) {
+	var after string
+	for {
+		page, err := cache.GetSession().SessionForGuildS(guildID).GuildMembers(guildID, after, 1000)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range page {
+			member.GuildID = guildID
+		}
+		members = append(members, page...)
+		if len(page) < 1000 {
+			return members, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
 }
 
 func GetIsInGuild(guildID string, userID string) bool {
@@ -805,7 +826,7 @@ func GetIsInGuild(guildID string, userID string) bool {
 }
 
 func GetGuild(guildID string) (*discordgo.Guild, error) {
-	targetGuild, err := cache.GetSession().SessionForGuildS(guildID).State.Guild(guildID)
+	targetGuild, err := GetGuildWithoutApi(guildID)
 	if targetGuild == nil || targetGuild.ID == "" {
 		//cache.GetLogger().WithField("module", "discord").WithField("method", "GetGuild").Debug(
 		//		fmt.Sprintf("discord api request: Guild: %s", guildID))
@@ -814,9 +835,9 @@ func GetGuild(guildID string) (*discordgo.Guild, error) {
 	return targetGuild, err
 }
 
+// GetGuildWithoutApi returns the guild from the state, guilds of other processes are read from the shared state
 func GetGuildWithoutApi(guildID string) (*discordgo.Guild, error) {
-	targetGuild, err := cache.GetSession().SessionForGuildS(guildID).State.Guild(guildID)
-	return targetGuild, err
+	return cache.GetRobyulstate().Guild(guildID)
 }
 
 func GetChannel(channelID string) (*discordgo.Channel, error) {
@@ -824,7 +845,15 @@ func GetChannel(channelID string) (*discordgo.Channel, error) {
 	// targetChannel, err = cache.GetSession().Channel(channelID)
 }
 
+// GetChannelWithoutApi returns channels of guilds run by this process, and private channels
+// Feeds rely on channels of other processes not being returned, to post only once
 func GetChannelWithoutApi(channelID string) (*discordgo.Channel, error) {
+	targetChannel, err := cache.GetRobyulstate().LocalChannel(channelID)
+	if err == nil {
+		return targetChannel, nil
+	}
+
+	// private channels are not tracked by the robyul state
 	for _, shard := range cache.GetSession().Sessions {
 		targetChannel, err := shard.State.Channel(channelID)
 		if err == nil && targetChannel != nil && targetChannel.ID != "" {
@@ -1443,7 +1472,7 @@ func GetGuildDefaultChannel(guildID string) (channelID string, err error) {
 	if guild.SystemChannelID != "" {
 		channel, err := GetChannel(guild.SystemChannelID)
 		if err == nil && channel.Type == discordgo.ChannelTypeGuildText {
-			channelPermissions, err := cache.GetSession().SessionForGuildS(guildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(guildID).State.User.ID, channel.ID)
+			channelPermissions, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 			if err == nil {
 				if channelPermissions&discordgo.PermissionSendMessages == discordgo.PermissionSendMessages {
 					return channel.ID, nil
@@ -1457,7 +1486,7 @@ func GetGuildDefaultChannel(guildID string) (channelID string, err error) {
 		if guild.WidgetChannelID != "" {
 			channel, err := GetChannel(guild.WidgetChannelID)
 			if err == nil && channel.Type == discordgo.ChannelTypeGuildText {
-				channelPermissions, err := cache.GetSession().State.UserChannelPermissions(cache.GetSession().State.User.ID, channel.ID)
+				channelPermissions, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 				if err == nil {
 					if channelPermissions&discordgo.PermissionSendMessages == discordgo.PermissionSendMessages {
 						return channel.ID, nil
@@ -1470,7 +1499,7 @@ func GetGuildDefaultChannel(guildID string) (channelID string, err error) {
 	// check channel with the same ID as the guild, the default channel when a guild is being created
 	channel, err := GetChannel(guildID)
 	if err == nil && channel.Type == discordgo.ChannelTypeGuildText {
-		channelPermissions, err := cache.GetSession().SessionForGuildS(guildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(guildID).State.User.ID, channel.ID)
+		channelPermissions, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 		if err == nil {
 			if channelPermissions&discordgo.PermissionSendMessages == discordgo.PermissionSendMessages {
 				return channel.ID, nil
@@ -1490,7 +1519,7 @@ func GetGuildDefaultChannel(guildID string) (channelID string, err error) {
 		if guildChannel.Type != discordgo.ChannelTypeGuildText {
 			continue
 		}
-		channelPermissions, err := cache.GetSession().SessionForGuildS(guildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(guildID).State.User.ID, guildChannel.ID)
+		channelPermissions, err := cache.GetRobyulstate().BotChannelPermissions(guildChannel.ID)
 		if err == nil {
 			if channelPermissions&discordgo.PermissionSendMessages == discordgo.PermissionSendMessages {
 				return guildChannel.ID, nil
@@ -1635,13 +1664,26 @@ func EmojIURL(emojiID string, animated bool) string {
 	return discordgo.EndpointCDN + "emojis/" + emojiID + ".png"
 }
 
+// AllGuilds returns the guilds of all processes
 func AllGuilds() []*discordgo.Guild {
-	var guilds []*discordgo.Guild
-	for _, shard := range cache.GetSession().Sessions {
-		for _, guild := range shard.State.Guilds {
-			guilds = append(guilds, guild)
-		}
-	}
+	guilds, err := cache.GetRobyulstate().Guilds()
+	RelaxLog(err)
+
+	return guilds
+}
+
+// AllGuildsWithoutMembers returns the guilds of all processes without their members, use it if the members are not needed
+func AllGuildsWithoutMembers() []*discordgo.Guild {
+	guilds, err := cache.GetRobyulstate().GuildsWithoutMembers()
+	RelaxLog(err)
 
 	return guilds
 }
+
+// GuildCount returns the amount of guilds of all processes
+func GuildCount() int {
+	count, err := cache.GetRobyulstate().GuildCount()
+	RelaxLog(err)
+
+	return count
+}
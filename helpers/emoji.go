@@ -67,7 +67,7 @@ func GetDiscordEmojiFromText(guildID string, text string) (emoji *discordgo.Emoj
 }
 
 func GetDiscordEmojiFromName(guildID string, name string) (emoji *discordgo.Emoji, err error) {
-	guild, err := cache.GetSession().SessionForGuildS(guildID).State.Guild(guildID)
+	guild, err := GetGuildWithoutApi(guildID)
 	if err != nil {
 		return nil, err
 	}
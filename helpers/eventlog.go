@@ -333,7 +333,9 @@ func getEventlogEmbed(eventlogID string, createdAt time.Time, guildID, targetID,
 		actionType == models.EventlogTypeRobyulTroublemakerReport ||
 		actionType == models.EventlogTypeRobyulPersistencyRoleRemove ||
 		actionType == models.EventlogTypeRobyulEventlogConfigUpdate ||
-		actionType == models.EventlogTypeRobyulTwitterFeedRemove {
+		actionType == models.EventlogTypeRobyulTwitterFeedRemove ||
+		actionType == models.EventlogTypeRobyulChartsWatchRemove ||
+		actionType == models.EventlogTypeRobyulBiasgamePoolDelete {
 		embed.Color = GetDiscordColorFromHex("#b22222") // firebrick red
 	}
 	if waitingForAuditLogBackfill {
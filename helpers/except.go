@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/bwmarrin/discordgo"
 	"github.com/davecgh/go-spew/spew"
 	raven "github.com/getsentry/raven-go"
@@ -30,10 +31,36 @@ func RecoverDiscord(msg *discordgo.Message) {
 
 		fmt.Printf("RecoverDiscord: %s\n", spew.Sdump(err))
 
+		prom.PanicsRecovered.WithLabelValues(panickingPlugin()).Inc()
+
 		SendError(msg, err)
 	}
 }
 
+// panickingPlugin returns the name of the plugin the panic happened in, has to be called in the deferred function
+// Plugins in their own package are named after the package, the others after their type
+func panickingPlugin() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+
+		if index := strings.Index(frame.Function, "/modules/plugins"); index >= 0 {
+			name := frame.Function[index+len("/modules/plugins"):]
+			if strings.HasPrefix(name, "/") {
+				return strings.SplitN(strings.TrimPrefix(name, "/"), ".", 2)[0]
+			}
+			name = strings.TrimPrefix(name, ".")
+			name = strings.TrimPrefix(name, "(*")
+			return strings.SplitN(strings.SplitN(name, ")", 2)[0], ".", 2)[0]
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
 // Recover recover()s and prints the error to console
 func Recover() {
 	err := recover()
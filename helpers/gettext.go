@@ -2,13 +2,41 @@ package helpers
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Jeffail/gabs"
+	"github.com/bwmarrin/discordgo"
 )
 
-var translations *gabs.Container
+const (
+	// DefaultLocale is the locale of _assets/i18n.json, all other locales fall back to it
+	DefaultLocale = "en"
+	// UserConfigLanguageKey is the user config key of the language override of an user
+	UserConfigLanguageKey = "language"
+
+	userLocaleCacheDuration = 10 * time.Minute
+	// expired entries are removed once the cache holds this many users
+	userLocaleCacheEvictionSize = 10000
+)
+
+type userLocaleCacheEntry struct {
+	Locale   string
+	CachedAt time.Time
+}
+
+var (
+	translations       *gabs.Container
+	localeTranslations = make(map[string]*gabs.Container)
+	userLocaleCache    = make(map[string]userLocaleCacheEntry)
+	userLocaleLock     sync.RWMutex
+)
 
 func LoadTranslations() {
 	jsonFile, err := Asset("_assets/i18n.json")
@@ -18,14 +46,205 @@ func LoadTranslations() {
 	Relax(err)
 
 	translations = json
+
+	// load other locales from <assets folder>/i18n/<locale>.json
+	if GetConfig() != nil {
+		localeTranslations, err = LoadLocaleTranslations(
+			filepath.Join(GetConfig().AssetsFolder, "i18n"))
+		Relax(err)
+	}
+}
+
+// LoadLocaleTranslations reads all <locale>.json files in the folder
+func LoadLocaleTranslations(folder string) (result map[string]*gabs.Container, err error) {
+	result = make(map[string]*gabs.Container)
+
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		locale := strings.ToLower(strings.TrimSuffix(file.Name(), ".json"))
+		if locale == DefaultLocale {
+			continue
+		}
+
+		container, err := gabs.ParseJSONFile(filepath.Join(folder, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", file.Name(), err.Error())
+		}
+		result[locale] = container
+	}
+
+	return result, nil
+}
+
+// GetLocales returns all available locales, starting with the default locale
+func GetLocales() (locales []string) {
+	for locale := range localeTranslations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return append([]string{DefaultLocale}, locales...)
+}
+
+// IsLocale returns true if translations are available for the locale
+func IsLocale(locale string) bool {
+	locale = strings.ToLower(locale)
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := localeTranslations[locale]
+	return ok
 }
 
 func GetText(id string) string {
-	if !translations.ExistsP(id) {
+	return getTextFromContainer(translations, id)
+}
+
+func GetTextF(id string, replacements ...interface{}) string {
+	return fmt.Sprintf(GetText(id), replacements...)
+}
+
+// GetTextForLocale returns the text in the locale, falls back to the default locale if the text is not translated
+// The text is always formatted like GetTextF, even without replacements
+func GetTextForLocale(locale string, id string, replacements ...interface{}) string {
+	text := GetText(id)
+	if container, ok := localeTranslations[locale]; ok && container.ExistsP(id) {
+		text = getTextFromContainer(container, id)
+	}
+
+	return fmt.Sprintf(text, replacements...)
+}
+
+// GetTextForGuild returns the text in the language configured for the guild
+func GetTextForGuild(guildID string, id string, replacements ...interface{}) string {
+	return GetTextForLocale(GetLocaleForGuild(guildID), id, replacements...)
+}
+
+// GetTextForMessage returns the text in the language of the author of the message,
+// the language override of the author is used if set, otherwise the language of the guild
+func GetTextForMessage(msg *discordgo.Message, id string, replacements ...interface{}) string {
+	var guildID, userID string
+	if msg != nil {
+		guildID = msg.GuildID
+		if msg.Author != nil {
+			userID = msg.Author.ID
+		}
+	}
+	return GetTextForLocale(GetLocaleForUser(guildID, userID), id, replacements...)
+}
+
+// GetLocaleForGuild returns the locale configured for the guild, or the default locale
+func GetLocaleForGuild(guildID string) string {
+	if guildID == "" {
+		return DefaultLocale
+	}
+
+	locale := GuildSettingsGetCached(guildID).Language
+	if locale == "" || !IsLocale(locale) {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// GetLocaleForUser returns the language override of the user, or the locale of the guild
+func GetLocaleForUser(guildID string, userID string) string {
+	if userID != "" && len(localeTranslations) > 0 {
+		userLocaleLock.RLock()
+		entry, ok := userLocaleCache[userID]
+		userLocaleLock.RUnlock()
+
+		if !ok || time.Since(entry.CachedAt) > userLocaleCacheDuration {
+			entry = userLocaleCacheEntry{
+				Locale:   GetUserConfigString(userID, UserConfigLanguageKey, ""),
+				CachedAt: time.Now(),
+			}
+			setUserLocaleCache(userID, entry)
+		}
+
+		if entry.Locale != "" && IsLocale(entry.Locale) {
+			return entry.Locale
+		}
+	}
+
+	return GetLocaleForGuild(guildID)
+}
+
+// SetLocaleForUser stores the language override of the user, an empty locale removes the override
+func SetLocaleForUser(userID string, locale string) (err error) {
+	err = SetUserConfigString(userID, UserConfigLanguageKey, locale)
+	if err != nil {
+		return err
+	}
+
+	setUserLocaleCache(userID, userLocaleCacheEntry{Locale: locale, CachedAt: time.Now()})
+	return nil
+}
+
+// setUserLocaleCache caches the locale of the user, expired entries are removed if the cache grows too large
+func setUserLocaleCache(userID string, entry userLocaleCacheEntry) {
+	userLocaleLock.Lock()
+	defer userLocaleLock.Unlock()
+
+	if len(userLocaleCache) >= userLocaleCacheEvictionSize {
+		for cachedUserID, cachedEntry := range userLocaleCache {
+			if time.Since(cachedEntry.CachedAt) > userLocaleCacheDuration {
+				delete(userLocaleCache, cachedUserID)
+			}
+		}
+	}
+
+	userLocaleCache[userID] = entry
+}
+
+// GetMissingTranslationKeys returns the keys of the base translations which are missing in the locale translations
+func GetMissingTranslationKeys(base *gabs.Container, locale *gabs.Container) (missing []string) {
+	for _, key := range getTranslationKeys(base, "") {
+		if !locale.ExistsP(key) {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// getTranslationKeys returns the paths of all texts in the container
+func getTranslationKeys(container *gabs.Container, prefix string) (keys []string) {
+	children, err := container.ChildrenMap()
+	if err != nil {
+		return nil
+	}
+
+	for key, child := range children {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if _, isObject := child.Data().(map[string]interface{}); isObject {
+			keys = append(keys, getTranslationKeys(child, path)...)
+			continue
+		}
+		keys = append(keys, path)
+	}
+	return keys
+}
+
+func getTextFromContainer(container *gabs.Container, id string) string {
+	if !container.ExistsP(id) {
 		return id
 	}
 
-	item := translations.Path(id)
+	item := container.Path(id)
 
 	// If this is an object return __
 	if strings.Contains(item.String(), "{") {
@@ -40,7 +259,3 @@ func GetText(id string) string {
 
 	return item.Data().(string)
 }
-
-func GetTextF(id string, replacements ...interface{}) string {
-	return fmt.Sprintf(GetText(id), replacements...)
-}
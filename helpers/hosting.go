@@ -21,7 +21,7 @@ func UploadImage(imageData []byte) (hostedUrl string, err error) {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Authorization", "Client-ID "+GetConfig().Path("imgur.client_id").Data().(string))
+	req.Header.Set("Authorization", "Client-ID "+GetConfig().Imgur.ClientID)
 	res, err := DefaultClient.Do(req)
 	if err != nil {
 		return "", err
@@ -13,6 +13,15 @@ var (
 	lastFmClientMutex sync.Mutex
 )
 
+func init() {
+	// the client is created again with the new API key
+	OnConfigReload(func(config *BotConfig) {
+		lastFmClientMutex.Lock()
+		defer lastFmClientMutex.Unlock()
+		lastFmClient = nil
+	})
+}
+
 // Gets the LastFM client from cache. If there is no LastFM client in cache yet it will create a new instance
 func GetLastFmClient() (client *lastfm.Api) {
 	lastFmClientMutex.Lock()
@@ -23,8 +32,8 @@ func GetLastFmClient() (client *lastfm.Api) {
 	}
 
 	lastFmClient = lastfm.New(
-		GetConfig().Path("lastfm.api_key").Data().(string),
-		GetConfig().Path("lastfm.api_secret").Data().(string),
+		GetConfig().LastFm.APIKey,
+		GetConfig().LastFm.APISecret,
 	)
 
 	return lastFmClient
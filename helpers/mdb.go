@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/pkg/errors"
 )
@@ -127,22 +128,7 @@ func MDbInsert(collection models.MongoDbCollection, data interface{}) (rid bson.
 	err = GetMDb().C(collection.String()).Insert(recordData.Interface())
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "insert",
-				Method:     "MDbInsert()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Data:       truncateKeenValue(fmt.Sprintf("%+v", data)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "insert", took)
 
 	if err != nil {
 		return bson.ObjectId(""), err
@@ -194,23 +180,7 @@ func MDbUpdate(collection models.MongoDbCollection, id bson.ObjectId, data inter
 	err = GetMDb().C(collection.String()).UpdateId(id, data)
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "update",
-				Method:     "MDbUpdate()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Id:         MdbIdToHuman(id),
-				Data:       truncateKeenValue(fmt.Sprintf("%+v", data)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "update", took)
 
 	if err != nil {
 		return err
@@ -232,23 +202,7 @@ func MDbUpdateQuery(collection models.MongoDbCollection, selector interface{}, d
 	err = GetMDb().C(collection.String()).Update(selector, data)
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "update",
-				Method:     "MDbUpdateSelector()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Query:      truncateKeenValue(fmt.Sprintf("%+v", selector)),
-				Data:       truncateKeenValue(fmt.Sprintf("%+v", data)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "update", took)
 
 	if err != nil {
 		return err
@@ -270,23 +224,7 @@ func MDbUpsertID(collection models.MongoDbCollection, id bson.ObjectId, data int
 	_, err = GetMDb().C(collection.String()).UpsertId(id, data)
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "upsert",
-				Method:     "MDbUpsertID()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Id:         MdbIdToHuman(id),
-				Data:       truncateKeenValue(fmt.Sprintf("%+v", data)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "upsert", took)
 
 	if err != nil {
 		return err
@@ -310,23 +248,7 @@ func MDbUpsert(collection models.MongoDbCollection, selector interface{}, data i
 	_, err = GetMDb().C(collection.String()).Upsert(selector, data)
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "upsert",
-				Method:     "MDbUpsert()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Query:      fmt.Sprintf("%+v", selector),
-				Data:       truncateKeenValue(fmt.Sprintf("%+v", data)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "upsert", took)
 
 	return err
 }
@@ -346,22 +268,7 @@ func MDbDelete(collection models.MongoDbCollection, id bson.ObjectId) (err error
 	err = GetMDb().C(collection.String()).RemoveId(id)
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "remove",
-				Method:     "MDbDelete()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Id:         MdbIdToHuman(id),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "remove", took)
 
 	if err != nil {
 		return err
@@ -383,22 +290,7 @@ func MdbDeleteQuery(collection models.MongoDbCollection, selector interface{}) (
 	err = GetMDb().C(collection.String()).Remove(selector)
 	took := time.Since(start)
 
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "remove",
-				Method:     "MdbDeleteQuery()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Query:      truncateKeenValue(fmt.Sprintf("%+v", selector)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "remove", took)
 
 	if err != nil {
 		return err
@@ -419,27 +311,7 @@ func MDbIter(query *mgo.Query) (iter *mgo.Iter) {
 	start := time.Now()
 	iter = query.Iter()
 	took := time.Since(start)
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			queryValue := reflect.ValueOf(*query)
-			queryOp := queryValue.FieldByName("query").FieldByName("op")
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "query",
-				Method:     "MdbIter()",
-				Collection: stripRobyulDatabaseFromCollection(queryOp.FieldByName("collection").String()),
-				Query:      truncateKeenValue(fmt.Sprintf("%+v", reflect.ValueOf(queryOp.FieldByName("query")).Interface())),
-				Skip:       queryOp.FieldByName("skip").Int(),
-				Limit:      queryOp.FieldByName("limit").Int(),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(mdbQueryCollection(query), "query", took)
 	return
 }
 
@@ -451,27 +323,7 @@ func MdbOne(query *mgo.Query, object interface{}) (err error) {
 	start := time.Now()
 	err = query.One(object)
 	took := time.Since(start)
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			queryValue := reflect.ValueOf(*query)
-			queryOp := queryValue.FieldByName("query").FieldByName("op")
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "query",
-				Method:     "MdbOne()",
-				Collection: stripRobyulDatabaseFromCollection(queryOp.FieldByName("collection").String()),
-				Query:      truncateKeenValue(fmt.Sprintf("%+v", reflect.ValueOf(queryOp.FieldByName("query")).Interface())),
-				Skip:       queryOp.FieldByName("skip").Int(),
-				Limit:      queryOp.FieldByName("limit").Int(),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(mdbQueryCollection(query), "query", took)
 	return
 }
 
@@ -483,22 +335,7 @@ func MdbPipeOne(collection models.MongoDbCollection, pipeline interface{}, objec
 	start := time.Now()
 	err = MdbCollection(collection).Pipe(pipeline).One(object)
 	took := time.Since(start)
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "pipeline",
-				Method:     "MdbPipeOne()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Query:      truncateKeenValue(fmt.Sprintf("%+v", pipeline)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "pipeline", took)
 	return nil
 }
 
@@ -510,22 +347,7 @@ func MdbCount(collection models.MongoDbCollection, query interface{}) (count int
 	start := time.Now()
 	count, err = MdbCollection(collection).Find(query).Count()
 	took := time.Since(start)
-	if cache.HasKeen() {
-		go func() {
-			defer Recover()
-
-			err := cache.GetKeen().AddEvent("Robyul_MongoDB", &KeenMongoDbEvent{
-				Seconds:    took.Seconds(),
-				Type:       "count",
-				Method:     "MdbCount()",
-				Collection: stripRobyulDatabaseFromCollection(collection.String()),
-				Query:      truncateKeenValue(fmt.Sprintf("%+v", query)),
-			})
-			if err != nil {
-				cache.GetLogger().WithField("module", "mdb").Error("Error logging MongoDB request to keen: ", err.Error())
-			}
-		}()
-	}
+	observeMdbQuery(collection.String(), "count", took)
 	return count, nil
 }
 
@@ -564,21 +386,13 @@ func stripRobyulDatabaseFromCollection(input string) (output string) {
 	return strings.TrimPrefix(input, mDbDatabase+".")
 }
 
-func truncateKeenValue(input string) string {
-	if len(input) < 8000 {
-		return input
-	}
-	return input[0:7999]
-}
-
-type KeenMongoDbEvent struct {
-	Seconds    float64
-	Collection string
-	Type       string
-	Method     string
-	Query      string `json:",omitempty"`
-	Skip       int64  `json:",omitempty"`
-	Limit      int64  `json:",omitempty"`
-	Id         string `json:",omitempty"`
-	Data       string `json:",omitempty"`
+// observeMdbQuery records the duration of a query in the Prometheus metrics
+func observeMdbQuery(collection, operation string, took time.Duration) {
+	prom.MongoQueryDuration.WithLabelValues(stripRobyulDatabaseFromCollection(collection), operation).Observe(took.Seconds())
+}
+
+// mdbQueryCollection returns the full name of the collection a query is run on
+func mdbQueryCollection(query *mgo.Query) string {
+	queryValue := reflect.ValueOf(*query)
+	return queryValue.FieldByName("query").FieldByName("op").FieldByName("collection").String()
 }
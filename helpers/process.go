@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	processName     string
+	processNameOnce sync.Once
+)
+
+// ProcessName returns the name of this Robyul process, it is unique for all processes sharing the same Redis
+// Set discord.sharding.process to use a fixed name, the default is <hostname>-<pid>
+func ProcessName() string {
+	processNameOnce.Do(func() {
+		processName = GetConfig().Discord.Sharding.Process
+		if processName != "" {
+			return
+		}
+
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "robyul"
+		}
+		processName = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	})
+	return processName
+}
@@ -7,15 +7,7 @@ import (
 	"net/http"
 )
 
-var (
-	sushiiImageServerBase string
-)
-
 func TakeHTMLScreenshot(html string, width, height int) (data []byte, err error) {
-	if sushiiImageServerBase == "" {
-		sushiiImageServerBase = GetConfig().Path("sushii-image-server.base").Data().(string)
-	}
-
 	marshalledRequest, err := json.Marshal(&SushiRequest{
 		Html:   html,
 		Width:  width,
@@ -25,7 +17,7 @@ func TakeHTMLScreenshot(html string, width, height int) (data []byte, err error)
 		return nil, err
 	}
 
-	request, err := http.NewRequest("POST", sushiiImageServerBase+"/html", bytes.NewBuffer(marshalledRequest))
+	request, err := http.NewRequest("POST", GetConfig().SushiiImageServer.Base+"/html", bytes.NewBuffer(marshalledRequest))
 	if err != nil {
 		return nil, err
 	}
@@ -406,7 +406,7 @@ func GeneratePublicFileLink(filename, filehash string) (link string) {
 	if dots > 1 {
 		filename = strings.Replace(filename, ".", "-", dots-1)
 	}
-	return fmt.Sprintf(GetConfig().Path("imageproxy.base_url").Data().(string),
+	return fmt.Sprintf(GetConfig().ImageProxy.BaseURL,
 		filehash, filename)
 }
 
@@ -520,17 +520,17 @@ func deleteBucketCache(objectName string) (err error) {
 }
 
 func getObjectPath(objectName string) (path string) {
-	return GetConfig().Path("cache_folder").Data().(string) + "/minio-" + GetConfig().Path("s3.bucket").Data().(string) + "/" + sanitize.BaseName(objectName)
+	return GetConfig().CacheFolder + "/minio-" + GetConfig().S3.Bucket + "/" + sanitize.BaseName(objectName)
 }
 
 // Initialize the minio client object, and creates the bucket if it doesn't exist yet
 func setupMinioClient() (err error) {
 	minioLock.Lock()
-	minioBucket = GetConfig().Path("s3.bucket").Data().(string)
+	minioBucket = GetConfig().S3.Bucket
 	minioClient, err = minio.New(
-		GetConfig().Path("s3.endpoint").Data().(string),
-		GetConfig().Path("s3.access_key").Data().(string),
-		GetConfig().Path("s3.secret_secret_key").Data().(string),
+		GetConfig().S3.Endpoint,
+		GetConfig().S3.AccessKey,
+		GetConfig().S3.SecretKey,
 		true,
 	)
 	minioLock.Unlock()
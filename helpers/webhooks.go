@@ -1,7 +1,12 @@
 package helpers
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
 
 	"time"
 
@@ -33,6 +38,99 @@ func WebhookExecuteWithResult(id, token string, data *discordgo.WebhookParams) (
 	return message, err
 }
 
+// Executes a webhook with file attachments and waites for the response
+// id	: the ID of the webhook to use
+// token		: the token of the webhook to use
+// data			: webhook params to send
+// files		: files to upload with the message
+func WebhookExecuteWithFilesAndResult(id, token string, data *discordgo.WebhookParams, files []*discordgo.File) (message *discordgo.Message, err error) {
+	if len(files) <= 0 {
+		return WebhookExecuteWithResult(id, token, data)
+	}
+
+	uri := discordgo.EndpointWebhookToken(id, token) + "?wait=true"
+
+	if data != nil && data.Content != "" {
+		data.Content = CleanDiscordContent(data.Content)
+	}
+
+	body := &bytes.Buffer{}
+	bodyWriter := multipart.NewWriter(body)
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return message, err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := bodyWriter.CreatePart(header)
+	if err != nil {
+		return message, err
+	}
+	if _, err = part.Write(payload); err != nil {
+		return message, err
+	}
+
+	for i, file := range files {
+		header = make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename="%s"`, i, strings.Replace(file.Name, `"`, `\"`, -1)))
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+
+		part, err = bodyWriter.CreatePart(header)
+		if err != nil {
+			return message, err
+		}
+		if _, err = io.Copy(part, file.Reader); err != nil {
+			return message, err
+		}
+	}
+
+	err = bodyWriter.Close()
+	if err != nil {
+		return message, err
+	}
+
+	// the request goes through the rate limiter of the session, in the same bucket as WebhookExecute
+	session := cache.GetSession().Session(0)
+	result, err := session.RequestWithLockedBucket("POST", uri, bodyWriter.FormDataContentType(), body.Bytes(),
+		session.Ratelimiter.LockBucket(discordgo.EndpointWebhookToken("", "")), 0)
+	if err != nil {
+		return message, err
+	}
+
+	err = json.Unmarshal(result, &message)
+	return message, err
+}
+
+// Edits a message previously sent by a webhook
+// id	: the ID of the webhook which sent the message
+// token		: the token of the webhook which sent the message
+// messageID	: the ID of the message to edit
+// content		: the new content of the message
+func WebhookEditMessageWithResult(id, token, messageID, content string) (message *discordgo.Message, err error) {
+	uri := discordgo.EndpointWebhookToken(id, token) + "/messages/" + messageID
+
+	data := struct {
+		Content string `json:"content"`
+	}{
+		Content: CleanDiscordContent(content),
+	}
+
+	result, err := cache.GetSession().Session(0).RequestWithBucketID("PATCH", uri, data, discordgo.EndpointWebhookToken("", "")+"/messages/")
+	if err != nil {
+		return message, err
+	}
+
+	err = json.Unmarshal(result, &message)
+	return message, err
+}
+
 // Gets a webhook for a channel (checks for permission, and uses cache)
 // guildID		: the guild from which to get the webhook
 // channelID	: the channel for which to get the webhook
@@ -56,7 +154,7 @@ func GetWebhook(guildID, channelID string) (webhook *discordgo.Webhook, err erro
 	}
 
 	// get robyul's permissions for the target channel
-	channelPermissions, err := cache.GetSession().SessionForGuildS(guildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(guildID).State.User.ID, channelID)
+	channelPermissions, err := cache.GetRobyulstate().BotChannelPermissions(channelID)
 	if err != nil {
 		return nil, err
 	}
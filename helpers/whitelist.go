@@ -27,5 +27,13 @@ func GuildIsOnWhitelist(guildID string) bool {
 		return true
 	}
 
+	if GetConfig() != nil {
+		for _, whitelistedGuildID := range GetConfig().Autoleaver.Whitelist {
+			if whitelistedGuildID == guildID {
+				return true
+			}
+		}
+	}
+
 	return whitelistCache[guildID]
 }
@@ -0,0 +1,30 @@
+package interactions
+
+import (
+	"encoding/json"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/bwmarrin/discordgo"
+)
+
+// DiscordClient follows up on interactions using the bot session
+type DiscordClient struct{}
+
+// GetOriginalResponse returns the message created by the response to an interaction
+func (c *DiscordClient) GetOriginalResponse(applicationID, token string) (message *discordgo.Message, err error) {
+	result, err := cache.GetSession().Session(0).RequestWithBucketID("GET",
+		discordgo.EndpointWebhookToken(applicationID, token)+"/messages/@original", nil,
+		discordgo.EndpointWebhookToken("", "")+"/messages/")
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(result, &message)
+	return message, err
+}
+
+// EditOriginalResponse changes the content of the message created by the response to an interaction
+func (c *DiscordClient) EditOriginalResponse(applicationID, token, content string) (message *discordgo.Message, err error) {
+	return helpers.WebhookEditMessageWithResult(applicationID, token, "@original", content)
+}
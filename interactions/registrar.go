@@ -0,0 +1,120 @@
+package interactions
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Seklfreak/Robyul2/modules/commands"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	maxOptionNameLength   = 32
+	maxDescriptionLength  = 100
+	applicationCommandsID = "applications/commands"
+)
+
+var (
+	invalidOptionNameCharactersRegex = regexp.MustCompile(`[^a-z0-9_-]+`)
+)
+
+// OptionName returns the name of a command, subcommand, or argument as accepted by Discord
+func OptionName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = invalidOptionNameCharactersRegex.ReplaceAllString(name, "-")
+	if len(name) > maxOptionNameLength {
+		name = name[:maxOptionNameLength]
+	}
+	return name
+}
+
+// ApplicationCommandFromCommand converts a declared command into an application command definition
+func ApplicationCommandFromCommand(command *commands.Command) *ApplicationCommand {
+	return &ApplicationCommand{
+		Name:        OptionName(command.Name),
+		Description: optionDescription(command.Description, command.Name),
+		Options:     optionsFromCommand(command, 0),
+	}
+}
+
+// optionsFromCommand returns the options for the arguments or subcommands of a command
+// Discord allows subcommand groups containing subcommands, deeper subcommands are not registered
+func optionsFromCommand(command *commands.Command, depth int) (options []*ApplicationCommandOption) {
+	if len(command.Subcommands) > 0 && depth < 2 {
+		for _, subcommand := range command.Subcommands {
+			optionType := ApplicationCommandOptionTypeSubCommand
+			if len(subcommand.Subcommands) > 0 && depth == 0 {
+				optionType = ApplicationCommandOptionTypeSubCommandGroup
+			}
+
+			option := &ApplicationCommandOption{
+				Type:        optionType,
+				Name:        OptionName(subcommand.Name),
+				Description: optionDescription(subcommand.Description, subcommand.Name),
+			}
+			if optionType == ApplicationCommandOptionTypeSubCommandGroup {
+				option.Options = optionsFromCommand(subcommand, depth+1)
+			} else {
+				option.Options = optionsFromArguments(subcommand.Arguments)
+			}
+			options = append(options, option)
+		}
+		return options
+	}
+
+	return optionsFromArguments(command.Arguments)
+}
+
+func optionsFromArguments(arguments []commands.Argument) (options []*ApplicationCommandOption) {
+	for _, argument := range arguments {
+		options = append(options, &ApplicationCommandOption{
+			Type:        optionTypeFromArgumentType(argument.Type),
+			Name:        OptionName(argument.Name),
+			Description: optionDescription(argument.Description, argument.Name),
+			Required:    !argument.Optional,
+		})
+	}
+	return options
+}
+
+func optionTypeFromArgumentType(argumentType commands.ArgumentType) ApplicationCommandOptionType {
+	switch argumentType {
+	case commands.ArgumentTypeInt:
+		return ApplicationCommandOptionTypeInteger
+	case commands.ArgumentTypeUser, commands.ArgumentTypeMember:
+		return ApplicationCommandOptionTypeUser
+	case commands.ArgumentTypeChannel:
+		return ApplicationCommandOptionTypeChannel
+	case commands.ArgumentTypeRole:
+		return ApplicationCommandOptionTypeRole
+	}
+	return ApplicationCommandOptionTypeString
+}
+
+func optionDescription(description string, fallback string) string {
+	if description == "" {
+		description = fallback
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		description = string([]rune(description)[:maxDescriptionLength-3]) + "..."
+	}
+	return description
+}
+
+// Sync overwrites the application commands with the given definitions
+// The commands are registered globally if guildID is empty, guild commands are updated instantly and useful for testing
+func Sync(session *discordgo.Session, applicationID string, guildID string, definitions []*commands.Command) (err error) {
+	applicationCommands := make([]*ApplicationCommand, 0)
+	for _, definition := range definitions {
+		applicationCommands = append(applicationCommands, ApplicationCommandFromCommand(definition))
+	}
+
+	uri := discordgo.EndpointAPI + "applications/" + applicationID + "/commands"
+	if guildID != "" {
+		uri = discordgo.EndpointAPI + "applications/" + applicationID + "/guilds/" + guildID + "/commands"
+	}
+
+	_, err = session.RequestWithBucketID("PUT", uri, applicationCommands, discordgo.EndpointAPI+applicationCommandsID)
+	return err
+}
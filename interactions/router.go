@@ -0,0 +1,255 @@
+package interactions
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/modules/commands"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// how long received interactions are remembered, has to be longer than the timestamps of replayed requests are accepted
+	claimExpiration = 2*maxTimestampSkew + time.Minute
+)
+
+var (
+	router     *Router
+	routerLock sync.RWMutex
+)
+
+// DispatchFunc runs a command the same way as if it has been sent as a message
+type DispatchFunc func(command string, content string, msg *discordgo.Message)
+
+// AllowFunc returns false if the author of the message is not allowed to run commands, for example because of the blacklist or ratelimits
+type AllowFunc func(msg *discordgo.Message) bool
+
+// ClaimFunc returns false if the interaction has already been received, the claim has to be kept for the given duration
+type ClaimFunc func(interactionID string, expiration time.Duration) bool
+
+// Client talks to the Discord API to follow up on interactions
+type Client interface {
+	GetOriginalResponse(applicationID, token string) (message *discordgo.Message, err error)
+	EditOriginalResponse(applicationID, token, content string) (message *discordgo.Message, err error)
+}
+
+// Router verifies interactions and routes them to the plugin handlers
+type Router struct {
+	publicKey   ed25519.PublicKey
+	definitions map[string]*commands.Command
+	dispatch    DispatchFunc
+	allow       AllowFunc
+	claim       ClaimFunc
+	client      Client
+	prefix      func(guildID string) string
+}
+
+// NewRouter creates a router for the given command definitions
+// allow is checked before a command is dispatched, it applies the same checks as for commands sent as messages
+// claim is checked before an interaction is handled, requests are signed with a timestamp but could be replayed within maxTimestampSkew
+// prefix returns the prefix of a guild, it is used for the content of the messages passed to the plugins
+func NewRouter(publicKey ed25519.PublicKey, definitions []*commands.Command, dispatch DispatchFunc, allow AllowFunc, claim ClaimFunc, client Client, prefix func(guildID string) string) *Router {
+	newRouter := &Router{
+		publicKey:   publicKey,
+		definitions: make(map[string]*commands.Command),
+		dispatch:    dispatch,
+		allow:       allow,
+		claim:       claim,
+		client:      client,
+		prefix:      prefix,
+	}
+	for _, definition := range definitions {
+		newRouter.definitions[OptionName(definition.Name)] = definition
+	}
+	return newRouter
+}
+
+// SetRouter sets the router used by the REST API
+func SetRouter(newRouter *Router) {
+	routerLock.Lock()
+	defer routerLock.Unlock()
+	router = newRouter
+}
+
+// GetRouter returns the router used by the REST API, nil if interactions are not configured
+func GetRouter() *Router {
+	routerLock.RLock()
+	defer routerLock.RUnlock()
+	return router
+}
+
+// ServeHTTP handles an interaction request sent by Discord
+func (r *Router) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	body, err := VerifyRequest(r.publicKey, request)
+	if err != nil {
+		http.Error(w, "401: Not Authorized", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction Interaction
+	err = json.Unmarshal(body, &interaction)
+	if err != nil {
+		http.Error(w, "400: Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	switch interaction.Type {
+	case InteractionTypePing:
+		writeResponse(w, &InteractionResponse{Type: InteractionResponseTypePong})
+		return
+	case InteractionTypeApplicationCommand:
+		if interaction.Data == nil || interaction.Member == nil || interaction.Member.User == nil {
+			// commands in direct messages are not supported
+			http.Error(w, "400: Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		if !r.claim(interaction.ID, claimExpiration) {
+			http.Error(w, "409: Conflict", http.StatusConflict)
+			return
+		}
+
+		command, content, deferred := r.Route(interaction.Data)
+
+		response := &InteractionResponse{
+			Type: InteractionResponseTypeChannelMessageWithSource,
+			Data: &InteractionApplicationCommandCallbackData{
+				Content:         r.invocationText(interaction.GuildID, command, content),
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
+			},
+		}
+		if deferred {
+			response = &InteractionResponse{Type: InteractionResponseTypeDeferredChannelMessageWithSource}
+		}
+		writeResponse(w, response)
+
+		go r.run(interaction, command, content, deferred)
+		return
+	}
+
+	http.Error(w, "400: Bad Request", http.StatusBadRequest)
+}
+
+// Route resolves the command name and the message content for an invoked application command
+// deferred is true for commands which take longer to respond
+func (r *Router) Route(data *ApplicationCommandInteractionData) (command string, content string, deferred bool) {
+	definition := r.definitions[data.Name]
+	command = data.Name
+	if definition != nil {
+		command = definition.Name
+		deferred = definition.Deferred
+	}
+
+	parts := make([]string, 0)
+	options := data.Options
+	for len(options) == 1 &&
+		(options[0].Type == ApplicationCommandOptionTypeSubCommand || options[0].Type == ApplicationCommandOptionTypeSubCommandGroup) {
+		name := options[0].Name
+		if definition != nil {
+			var subcommandDefinition *commands.Command
+			for _, subcommand := range definition.Subcommands {
+				if OptionName(subcommand.Name) == name {
+					subcommandDefinition = subcommand
+					name = subcommand.Name
+				}
+			}
+			definition = subcommandDefinition
+		}
+		parts = append(parts, name)
+		options = options[0].Options
+	}
+
+	parts = append(parts, formatOptions(options, definition)...)
+	return command, strings.Join(parts, " "), deferred
+}
+
+// run passes the interaction to the plugin handlers, the original response is used as the message of the command
+func (r *Router) run(interaction Interaction, command string, content string, deferred bool) {
+	if interaction.Member.GuildID == "" {
+		interaction.Member.GuildID = interaction.GuildID
+	}
+
+	msg := &discordgo.Message{
+		ID:        interaction.ID,
+		ChannelID: interaction.ChannelID,
+		GuildID:   interaction.GuildID,
+		Author:    interaction.Member.User,
+		Member:    interaction.Member,
+		Content:   r.prefix(interaction.GuildID) + command + " " + content,
+		Timestamp: discordgo.Timestamp(time.Now().Format(time.RFC3339)),
+	}
+
+	original, err := r.client.GetOriginalResponse(interaction.ApplicationID, interaction.Token)
+	if err == nil && original != nil && original.ID != "" {
+		msg.ID = original.ID
+	}
+
+	if r.allow(msg) {
+		r.dispatch(command, content, msg)
+	}
+
+	if deferred {
+		r.client.EditOriginalResponse(interaction.ApplicationID, interaction.Token,
+			r.invocationText(interaction.GuildID, command, content))
+	}
+}
+
+func (r *Router) invocationText(guildID string, command string, content string) string {
+	return "`" + strings.TrimSpace(r.prefix(guildID)+command+" "+content) + "`"
+}
+
+// formatOptions turns the options into arguments for the message content, in the order of the declared arguments if known
+func formatOptions(options []*ApplicationCommandInteractionDataOption, definition *commands.Command) (arguments []string) {
+	if definition == nil || len(definition.Arguments) <= 0 {
+		for i, option := range options {
+			arguments = append(arguments, formatOptionValue(option, i == len(options)-1))
+		}
+		return arguments
+	}
+
+	for _, argument := range definition.Arguments {
+		for _, option := range options {
+			if option.Name != OptionName(argument.Name) {
+				continue
+			}
+			arguments = append(arguments, formatOptionValue(option, argument.Type == commands.ArgumentTypeRest))
+		}
+	}
+	return arguments
+}
+
+func formatOptionValue(option *ApplicationCommandInteractionDataOption, raw bool) string {
+	var value string
+	switch typedValue := option.Value.(type) {
+	case string:
+		value = typedValue
+	case float64:
+		value = strconv.FormatFloat(typedValue, 'f', -1, 64)
+	case bool:
+		value = strconv.FormatBool(typedValue)
+	}
+
+	switch option.Type {
+	case ApplicationCommandOptionTypeUser:
+		return "<@" + value + ">"
+	case ApplicationCommandOptionTypeChannel:
+		return "<#" + value + ">"
+	case ApplicationCommandOptionTypeRole:
+		return "<@&" + value + ">"
+	}
+
+	if !raw && strings.ContainsAny(value, " \t\n") {
+		return "\"" + value + "\""
+	}
+	return value
+}
+
+func writeResponse(w http.ResponseWriter, response *InteractionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
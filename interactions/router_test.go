@@ -0,0 +1,327 @@
+package interactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Seklfreak/Robyul2/modules/commands"
+	"github.com/bwmarrin/discordgo"
+)
+
+type dispatchedCommand struct {
+	command string
+	content string
+	msg     *discordgo.Message
+}
+
+type testClient struct {
+	edited chan string
+}
+
+func (c *testClient) GetOriginalResponse(applicationID, token string) (*discordgo.Message, error) {
+	return &discordgo.Message{ID: "786008729715212400"}, nil
+}
+
+func (c *testClient) EditOriginalResponse(applicationID, token, content string) (*discordgo.Message, error) {
+	c.edited <- content
+	return &discordgo.Message{ID: "786008729715212400", Content: content}, nil
+}
+
+var testDefinitions = []*commands.Command{
+	{
+		Name: "roll",
+		Arguments: []commands.Argument{
+			{Name: "max", Type: commands.ArgumentTypeInt, Optional: true},
+		},
+	},
+	{
+		Name: "levels",
+		Subcommands: []*commands.Command{
+			{
+				Name: "season",
+				Subcommands: []*commands.Command{
+					{
+						Name: "start",
+						Arguments: []commands.Argument{
+							{Name: "name", Type: commands.ArgumentTypeQuoted},
+							{Name: "days", Type: commands.ArgumentTypeInt},
+							{Name: "announce in", Type: commands.ArgumentTypeChannel, Optional: true},
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:      "profile",
+		Arguments: []commands.Argument{{Name: "arguments", Type: commands.ArgumentTypeRest, Optional: true}},
+		Deferred:  true,
+	},
+}
+
+func newTestClaim() ClaimFunc {
+	var claimedLock sync.Mutex
+	claimed := make(map[string]bool)
+	return func(interactionID string, expiration time.Duration) bool {
+		claimedLock.Lock()
+		defer claimedLock.Unlock()
+		if claimed[interactionID] {
+			return false
+		}
+		claimed[interactionID] = true
+		return true
+	}
+}
+
+func newTestRouter(t *testing.T) (*Router, ed25519.PrivateKey, chan dispatchedCommand, *testClient) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err.Error())
+	}
+
+	dispatched := make(chan dispatchedCommand, 1)
+	client := &testClient{edited: make(chan string, 1)}
+	testRouter := NewRouter(publicKey, testDefinitions, func(command string, content string, msg *discordgo.Message) {
+		dispatched <- dispatchedCommand{command: command, content: content, msg: msg}
+	}, func(msg *discordgo.Message) bool {
+		return true
+	}, newTestClaim(), client, func(guildID string) string {
+		return "_"
+	})
+
+	return testRouter, privateKey, dispatched, client
+}
+
+func newSignedRequest(t *testing.T, privateKey ed25519.PrivateKey, payloadFile string) *http.Request {
+	return newSignedRequestWithTimestamp(t, privateKey, payloadFile, time.Now())
+}
+
+func newSignedRequestWithTimestamp(t *testing.T, privateKey ed25519.PrivateKey, payloadFile string, signedAt time.Time) *http.Request {
+	body, err := ioutil.ReadFile("testdata/" + payloadFile)
+	if err != nil {
+		t.Fatalf("failed to read payload %s: %s", payloadFile, err.Error())
+	}
+
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	signature := ed25519.Sign(privateKey, append([]byte(timestamp), body...))
+
+	request := httptest.NewRequest("POST", "/interactions", bytes.NewReader(body))
+	request.Header.Set(signatureHeader, hex.EncodeToString(signature))
+	request.Header.Set(signatureTimestampHeader, timestamp)
+	return request
+}
+
+func serve(testRouter *Router, request *http.Request) (*httptest.ResponseRecorder, InteractionResponse) {
+	recorder := httptest.NewRecorder()
+	testRouter.ServeHTTP(recorder, request)
+
+	var response InteractionResponse
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func TestVerifyRequest(t *testing.T) {
+	testRouter, privateKey, _, _ := newTestRouter(t)
+
+	request := newSignedRequest(t, privateKey, "ping.json")
+	if _, err := VerifyRequest(testRouter.publicKey, request); err != nil {
+		t.Fatalf("interactions.VerifyRequest() rejected a valid signature: %s", err.Error())
+	}
+
+	request = newSignedRequest(t, privateKey, "ping.json")
+	request.Header.Set(signatureTimestampHeader, "1")
+	if _, err := VerifyRequest(testRouter.publicKey, request); err == nil {
+		t.Fatal("interactions.VerifyRequest() accepted a signature for a different timestamp")
+	}
+
+	request = newSignedRequestWithTimestamp(t, privateKey, "ping.json", time.Now().Add(-time.Minute))
+	if _, err := VerifyRequest(testRouter.publicKey, request); err == nil {
+		t.Fatal("interactions.VerifyRequest() accepted a signature with an old timestamp")
+	}
+
+	_, otherPrivateKey, _ := ed25519.GenerateKey(rand.Reader)
+	request = newSignedRequest(t, otherPrivateKey, "ping.json")
+	if _, err := VerifyRequest(testRouter.publicKey, request); err == nil {
+		t.Fatal("interactions.VerifyRequest() accepted a signature of a different key")
+	}
+
+	request = newSignedRequest(t, privateKey, "ping.json")
+	request.Header.Del(signatureHeader)
+	recorder, _ := serve(testRouter, request)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("interactions.Router returned %d for a request without signature, expected 401", recorder.Code)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	parsedKey, err := ParsePublicKey(hex.EncodeToString(publicKey))
+	if err != nil || !bytes.Equal(parsedKey, publicKey) {
+		t.Fatal("interactions.ParsePublicKey() failed to parse a valid key")
+	}
+
+	if _, err = ParsePublicKey("abcd"); err == nil {
+		t.Fatal("interactions.ParsePublicKey() accepted a key with an invalid size")
+	}
+}
+
+func TestPing(t *testing.T) {
+	testRouter, privateKey, _, _ := newTestRouter(t)
+
+	recorder, response := serve(testRouter, newSignedRequest(t, privateKey, "ping.json"))
+	if recorder.Code != http.StatusOK || response.Type != InteractionResponseTypePong {
+		t.Fatalf("interactions.Router did not respond to a ping with a pong, got %d %d", recorder.Code, response.Type)
+	}
+}
+
+func TestRouteCommand(t *testing.T) {
+	testRouter, privateKey, dispatched, _ := newTestRouter(t)
+
+	_, response := serve(testRouter, newSignedRequest(t, privateKey, "roll.json"))
+	if response.Type != InteractionResponseTypeChannelMessageWithSource || response.Data == nil ||
+		response.Data.Content != "`_roll 6`" {
+		t.Fatalf("interactions.Router sent an unexpected response: %+v", response)
+	}
+
+	select {
+	case result := <-dispatched:
+		if result.command != "roll" || result.content != "6" {
+			t.Fatalf("interactions.Router dispatched %q %q, expected \"roll\" \"6\"", result.command, result.content)
+		}
+		if result.msg.ID != "786008729715212400" || result.msg.Author.ID != "116620585638821891" ||
+			result.msg.GuildID != "208673735580844032" || result.msg.Content != "_roll 6" {
+			t.Fatalf("interactions.Router dispatched an invalid message: %+v", result.msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactions.Router did not dispatch the command")
+	}
+}
+
+func TestRouteNotAllowed(t *testing.T) {
+	testRouter, privateKey, dispatched, client := newTestRouter(t)
+	testRouter.allow = func(msg *discordgo.Message) bool {
+		return msg.Author.ID != "116620585638821891"
+	}
+
+	serve(testRouter, newSignedRequest(t, privateKey, "profile.json"))
+
+	select {
+	case <-client.edited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactions.Router did not edit the deferred response")
+	}
+	select {
+	case result := <-dispatched:
+		t.Fatalf("interactions.Router dispatched %q %q for a user who is not allowed to run commands", result.command, result.content)
+	default:
+	}
+}
+
+func TestRouteSubcommand(t *testing.T) {
+	testRouter, privateKey, dispatched, _ := newTestRouter(t)
+
+	serve(testRouter, newSignedRequest(t, privateKey, "levels_season_start.json"))
+
+	select {
+	case result := <-dispatched:
+		expected := `season start "summer 2021" 30 <#301622547296198658>`
+		if result.command != "levels" || result.content != expected {
+			t.Fatalf("interactions.Router dispatched %q %q, expected \"levels\" %q", result.command, result.content, expected)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactions.Router did not dispatch the command")
+	}
+}
+
+func TestRouteDeferred(t *testing.T) {
+	testRouter, privateKey, dispatched, client := newTestRouter(t)
+
+	_, response := serve(testRouter, newSignedRequest(t, privateKey, "profile.json"))
+	if response.Type != InteractionResponseTypeDeferredChannelMessageWithSource {
+		t.Fatalf("interactions.Router did not defer a slow command, got response type %d", response.Type)
+	}
+
+	select {
+	case result := <-dispatched:
+		if result.command != "profile" || result.content != "Sekl#7397 gif" {
+			t.Fatalf("interactions.Router dispatched %q %q, expected \"profile\" \"Sekl#7397 gif\"", result.command, result.content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactions.Router did not dispatch the command")
+	}
+
+	select {
+	case content := <-client.edited:
+		if content != "`_profile Sekl#7397 gif`" {
+			t.Fatalf("interactions.Router edited the deferred response to %q", content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactions.Router did not edit the deferred response")
+	}
+}
+
+func TestApplicationCommandFromCommand(t *testing.T) {
+	applicationCommand := ApplicationCommandFromCommand(testDefinitions[1])
+
+	if applicationCommand.Name != "levels" || len(applicationCommand.Options) != 1 {
+		t.Fatalf("interactions.ApplicationCommandFromCommand() created an invalid command: %+v", applicationCommand)
+	}
+
+	group := applicationCommand.Options[0]
+	if group.Type != ApplicationCommandOptionTypeSubCommandGroup || group.Name != "season" || len(group.Options) != 1 {
+		t.Fatalf("interactions.ApplicationCommandFromCommand() created an invalid subcommand group: %+v", group)
+	}
+
+	subcommand := group.Options[0]
+	if subcommand.Type != ApplicationCommandOptionTypeSubCommand || len(subcommand.Options) != 3 {
+		t.Fatalf("interactions.ApplicationCommandFromCommand() created an invalid subcommand: %+v", subcommand)
+	}
+	if subcommand.Options[2].Name != "announce-in" || subcommand.Options[2].Type != ApplicationCommandOptionTypeChannel ||
+		subcommand.Options[2].Required || !subcommand.Options[1].Required {
+		t.Fatalf("interactions.ApplicationCommandFromCommand() created invalid options: %+v", subcommand.Options[2])
+	}
+	if subcommand.Description != "start" {
+		t.Fatalf("interactions.ApplicationCommandFromCommand() did not fall back to the name as description")
+	}
+}
+
+func TestRouteReplayed(t *testing.T) {
+	testRouter, privateKey, dispatched, _ := newTestRouter(t)
+
+	serve(testRouter, newSignedRequest(t, privateKey, "roll.json"))
+	select {
+	case <-dispatched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactions.Router did not dispatch the command")
+	}
+
+	recorder, _ := serve(testRouter, newSignedRequest(t, privateKey, "roll.json"))
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("interactions.Router returned %d for a replayed interaction, expected 409", recorder.Code)
+	}
+	select {
+	case result := <-dispatched:
+		t.Fatalf("interactions.Router dispatched %q %q for a replayed interaction", result.command, result.content)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestOptionDescription(t *testing.T) {
+	description := optionDescription(strings.Repeat("가", 150), "fallback")
+	if !utf8.ValidString(description) || utf8.RuneCountInString(description) != maxDescriptionLength {
+		t.Fatalf("interactions.optionDescription() truncated the description to an invalid text: %q", description)
+	}
+}
@@ -0,0 +1,98 @@
+// Package interactions receives Discord application command interactions (slash commands) over HTTP,
+// verifies their signature, and routes them into the plugin handlers.
+// It also syncs the application command definitions with Discord, see Sync.
+package interactions
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// InteractionType is the type of an interaction received from Discord
+type InteractionType int
+
+const (
+	InteractionTypePing               InteractionType = 1
+	InteractionTypeApplicationCommand InteractionType = 2
+)
+
+// InteractionResponseType is the type of the response to an interaction
+type InteractionResponseType int
+
+const (
+	InteractionResponseTypePong                             InteractionResponseType = 1
+	InteractionResponseTypeChannelMessageWithSource         InteractionResponseType = 4
+	InteractionResponseTypeDeferredChannelMessageWithSource InteractionResponseType = 5
+)
+
+// ApplicationCommandOptionType is the type of an application command option
+type ApplicationCommandOptionType int
+
+const (
+	ApplicationCommandOptionTypeSubCommand      ApplicationCommandOptionType = 1
+	ApplicationCommandOptionTypeSubCommandGroup ApplicationCommandOptionType = 2
+	ApplicationCommandOptionTypeString          ApplicationCommandOptionType = 3
+	ApplicationCommandOptionTypeInteger         ApplicationCommandOptionType = 4
+	ApplicationCommandOptionTypeBoolean         ApplicationCommandOptionType = 5
+	ApplicationCommandOptionTypeUser            ApplicationCommandOptionType = 6
+	ApplicationCommandOptionTypeChannel         ApplicationCommandOptionType = 7
+	ApplicationCommandOptionTypeRole            ApplicationCommandOptionType = 8
+)
+
+// Interaction is an interaction received from Discord
+type Interaction struct {
+	ID            string                             `json:"id"`
+	ApplicationID string                             `json:"application_id"`
+	Type          InteractionType                    `json:"type"`
+	Data          *ApplicationCommandInteractionData `json:"data,omitempty"`
+	GuildID       string                             `json:"guild_id"`
+	ChannelID     string                             `json:"channel_id"`
+	Member        *discordgo.Member                  `json:"member,omitempty"`
+	User          *discordgo.User                    `json:"user,omitempty"`
+	Token         string                             `json:"token"`
+	Version       int                                `json:"version"`
+}
+
+// ApplicationCommandInteractionData is the invoked command of an application command interaction
+type ApplicationCommandInteractionData struct {
+	ID      string                                     `json:"id"`
+	Name    string                                     `json:"name"`
+	Options []*ApplicationCommandInteractionDataOption `json:"options,omitempty"`
+}
+
+// ApplicationCommandInteractionDataOption is an option the user filled in, or an invoked subcommand
+type ApplicationCommandInteractionDataOption struct {
+	Name    string                                     `json:"name"`
+	Type    ApplicationCommandOptionType               `json:"type"`
+	Value   interface{}                                `json:"value,omitempty"`
+	Options []*ApplicationCommandInteractionDataOption `json:"options,omitempty"`
+}
+
+// InteractionResponse is the response to an interaction
+type InteractionResponse struct {
+	Type InteractionResponseType                    `json:"type"`
+	Data *InteractionApplicationCommandCallbackData `json:"data,omitempty"`
+}
+
+// InteractionApplicationCommandCallbackData is the message sent as response to an interaction
+type InteractionApplicationCommandCallbackData struct {
+	Content         string                            `json:"content,omitempty"`
+	AllowedMentions *discordgo.MessageAllowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// ApplicationCommand is the definition of an application command
+type ApplicationCommand struct {
+	ID            string                      `json:"id,omitempty"`
+	ApplicationID string                      `json:"application_id,omitempty"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description"`
+	Options       []*ApplicationCommandOption `json:"options,omitempty"`
+}
+
+// ApplicationCommandOption is the definition of an application command option or subcommand
+type ApplicationCommandOption struct {
+	Type        ApplicationCommandOptionType `json:"type"`
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	Required    bool                         `json:"required,omitempty"`
+	Options     []*ApplicationCommandOption  `json:"options,omitempty"`
+}
@@ -0,0 +1,71 @@
+package interactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	signatureHeader          = "X-Signature-Ed25519"
+	signatureTimestampHeader = "X-Signature-Timestamp"
+	// the maximum size of an interaction payload we accept
+	maxPayloadSize = 1 << 20
+	// how far the timestamp of a request may be off, older requests could be replayed
+	maxTimestampSkew = 5 * time.Second
+)
+
+var (
+	errInvalidSignature = errors.New("invalid request signature")
+)
+
+// ParsePublicKey parses the hex encoded public key shown on the Discord developer portal
+func ParsePublicKey(publicKeyHex string) (publicKey ed25519.PublicKey, err error) {
+	publicKeyBytes, err := hex.DecodeString(publicKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	if len(publicKeyBytes) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key size")
+	}
+	return ed25519.PublicKey(publicKeyBytes), nil
+}
+
+// VerifyRequest reads the body of the request and checks its Ed25519 signature
+// Discord signs the timestamp header followed by the body, requests with an old timestamp are rejected
+func VerifyRequest(publicKey ed25519.PublicKey, request *http.Request) (body []byte, err error) {
+	signature, err := hex.DecodeString(request.Header.Get(signatureHeader))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, errInvalidSignature
+	}
+
+	timestamp := request.Header.Get(signatureTimestampHeader)
+	timestampSeconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errInvalidSignature
+	}
+	skew := time.Since(time.Unix(timestampSeconds, 0))
+	if skew > maxTimestampSkew || skew < -maxTimestampSkew {
+		return nil, errInvalidSignature
+	}
+
+	body, err = ioutil.ReadAll(io.LimitReader(request.Body, maxPayloadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	message.WriteString(timestamp)
+	message.Write(body)
+
+	if !ed25519.Verify(publicKey, message.Bytes(), signature) {
+		return nil, errInvalidSignature
+	}
+	return body, nil
+}
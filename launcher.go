@@ -11,6 +11,7 @@ import (
 	"os/signal"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/RichardKnop/machinery/v1"
@@ -18,12 +19,16 @@ import (
 	marchineryLog "github.com/RichardKnop/machinery/v1/log"
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/interactions"
 	"github.com/Seklfreak/Robyul2/logging"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/migrations"
 	"github.com/Seklfreak/Robyul2/modules"
 	"github.com/Seklfreak/Robyul2/modules/plugins"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 	"github.com/Seklfreak/Robyul2/rest"
+	"github.com/Seklfreak/Robyul2/robyulstate"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/Seklfreak/Robyul2/version"
 	"github.com/Seklfreak/polr-go"
@@ -41,6 +46,9 @@ import (
 
 var (
 	BotRuntimeChannel chan os.Signal
+
+	// discordWebhookHook sends errors to logging.discord_webhook, it is replaced when the config is reloaded
+	discordWebhookHook = logging.NewReloadableHook([]logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel})
 )
 
 // Entrypoint
@@ -59,12 +67,12 @@ func main() {
 	config := helpers.GetConfig()
 
 	// Check if the bot is being debugged
-	if config.Path("debug").Data().(bool) {
+	if config.Debug {
 		helpers.DEBUG_MODE = true
 	}
 
-	if config.Path("logging.jsonfile").Data().(string) != "" {
-		fileHook, err := logging.NewLogrusFileHook(config.Path("logging.jsonfile").Data().(string), os.O_CREATE|os.O_APPEND|os.O_RDWR, 0666)
+	if config.Logging.JSONFile != "" {
+		fileHook, err := logging.NewLogrusFileHook(config.Logging.JSONFile, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0666)
 		if err != nil {
 			log.WithField("module", "launcher").Error("logrus file hook failed, err:", err.Error())
 		} else {
@@ -72,26 +80,8 @@ func main() {
 		}
 	}
 
-	if config.Path("logging.discord_webhook").Data().(string) != "" {
-		log.Hooks.Add(discordrus.NewHook(
-			config.Path("logging.discord_webhook").Data().(string),
-			logrus.ErrorLevel,
-			&discordrus.Opts{
-				Username:           "Logging",
-				DisableTimestamp:   false,
-				TimestampFormat:    "Jan 2 15:04:05.00000",
-				EnableCustomColors: true,
-				CustomLevelColors: &discordrus.LevelColors{
-					//Debug: 10170623,
-					//Info:  3581519,
-					//Warn:  14327864,
-					Error: 13631488,
-					Panic: 13631488,
-					Fatal: 13631488,
-				},
-			},
-		))
-	}
+	discordWebhookHook.Set(newDiscordWebhookHook(config.Logging.DiscordWebhook))
+	log.Hooks.Add(discordWebhookHook)
 
 	log.WithField("module", "launcher").Info("Booting Robyul...")
 
@@ -112,7 +102,7 @@ func main() {
 
 	// Call home
 	log.WithField("module", "launcher").Info("Connecting to sentry...")
-	err = raven.SetDSN(config.Path("sentry").Data().(string))
+	err = raven.SetDSN(config.Sentry)
 	if err != nil {
 		panic(err)
 	}
@@ -124,20 +114,21 @@ func main() {
 
 	// Connect to MongoDB
 	helpers.ConnectMDB(
-		config.Path("mongodb.url").Data().(string),
-		config.Path("mongodb.db").Data().(string),
+		config.MongoDB.URL,
+		config.MongoDB.DB,
 	)
 	defer helpers.GetMDbSession().Close()
 
 	// Connect to elastic search
-	if config.Path("elasticsearch.url").Data().(string) != "" {
+	if config.Elasticsearch.URL != "" {
 		log.WithField("module", "launcher").Info("Connecting to ElasticSearch...")
 		client, err := elastic.NewClient(
 			elastic.SetURL(
-				strings.Split(config.Path("elasticsearch.url").Data().(string), ",")...,
+				strings.Split(config.Elasticsearch.URL, ",")...,
 			),
 			elastic.SetSniff(true),
 			elastic.SetErrorLog(log),
+			elastic.SetHttpClient(&http.Client{Transport: &prom.ElasticTransport{}}),
 			// elastic.SetInfoLog(log),
 		)
 		if err != nil {
@@ -145,20 +136,18 @@ func main() {
 		}
 		cache.SetElastic(client)
 
-		version, err := client.ElasticsearchVersion(config.Path("elasticsearch.url").Data().(string))
+		version, err := client.ElasticsearchVersion(config.Elasticsearch.URL)
 		if err != nil {
 			panic(err)
 		}
 		log.WithField("module", "launcher").Info("Connected to ElasticSearch v" + version)
 	}
 
-	if config.ExistsP("polr.url") &&
-		config.ExistsP("polr.api-key") &&
-		config.Path("polr.url").Data().(string) != "" &&
-		config.Path("polr.api-key").Data().(string) != "" {
+	if config.Polr.URL != "" &&
+		config.Polr.APIKey != "" {
 		polrClient, err := polr.New(
-			config.Path("polr.url").Data().(string),
-			config.Path("polr.api-key").Data().(string),
+			config.Polr.URL,
+			config.Polr.APIKey,
 			nil,
 		)
 		if err != nil {
@@ -181,18 +170,25 @@ func main() {
 	// Connecting to redis
 	log.WithField("module", "launcher").Info("Connecting to redis...")
 	redisClient := redis.NewClient(&redis.Options{
-		Addr:         config.Path("redis.address").Data().(string),
+		Addr:         config.Redis.Address,
 		Password:     "", // no password set
 		DB:           0,  // use default DB
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 	})
+	prom.InstrumentRedis(redisClient)
 	cache.SetRedisClient(redisClient)
 
+	// Share rate limits and cooldowns between all processes
+	ratelimits.SetLimiter(ratelimits.NewRedisLimiter(redisClient))
+
+	// Reload caches invalidated by other processes
+	go helpers.ListenForCacheInvalidations()
+
 	// Set up Google Drive Client
-	if helpers.GetConfig().Path("google.client_credentials_json_location").Data().(string) != "" {
+	if helpers.GetConfig().Google.ClientCredentialsJSONLocation != "" {
 		driveCtx := context.Background()
-		driveAuthJson, err := ioutil.ReadFile(helpers.GetConfig().Path("google.client_credentials_json_location").Data().(string))
+		driveAuthJson, err := ioutil.ReadFile(helpers.GetConfig().Google.ClientCredentialsJSONLocation)
 		if err != nil {
 			panic(err)
 		}
@@ -212,18 +208,15 @@ func main() {
 	}
 
 	// connect to unleash
-	if helpers.GetConfig().ExistsP("unleash.app-name") &&
-		helpers.GetConfig().ExistsP("unleash.instance-id") &&
-		helpers.GetConfig().ExistsP("unleash.url") &&
-		helpers.GetConfig().Path("unleash.app-name").Data().(string) != "" &&
-		helpers.GetConfig().Path("unleash.instance-id").Data().(string) != "" &&
-		helpers.GetConfig().Path("unleash.url").Data().(string) != "" {
+	if helpers.GetConfig().Unleash.AppName != "" &&
+		helpers.GetConfig().Unleash.InstanceID != "" &&
+		helpers.GetConfig().Unleash.URL != "" {
 		log.WithField("module", "launcher").Info("Connecting to unleash…")
 		err := unleash.Initialize(
 			unleash.WithListener(&helpers.UnleashListener{}),
-			unleash.WithAppName(helpers.GetConfig().Path("unleash.app-name").Data().(string)),
-			unleash.WithUrl(helpers.GetConfig().Path("unleash.url").Data().(string)),
-			unleash.WithInstanceId(helpers.GetConfig().Path("unleash.instance-id").Data().(string)),
+			unleash.WithAppName(helpers.GetConfig().Unleash.AppName),
+			unleash.WithUrl(helpers.GetConfig().Unleash.URL),
+			unleash.WithInstanceId(helpers.GetConfig().Unleash.InstanceID),
 			unleash.WithHttpClient(&http.Client{Timeout: 3 * time.Second}),
 		)
 		if err != nil {
@@ -261,21 +254,40 @@ func main() {
 	}
 	log.WithField("module", "launcher").Info("Connecting Robyul to discord...")
 
-	discord := shardmanager.New("Bot " + config.Path("discord.token").Data().(string))
+	discord := shardmanager.New("Bot " + config.Discord.Token)
 	if err != nil {
 		panic(err)
 	}
 
-	discord.LogChannel = config.Path("sharding-channel").Data().(string)
-	discord.StatusMessageChannel = config.Path("sharding-channel").Data().(string)
+	discord.LogChannel = config.ShardingChannel
+	discord.StatusMessageChannel = config.ShardingChannel
 
-	amount, err := discord.GetRecommendedCount()
-	if err != nil {
-		panic(err)
+	discord.Process = helpers.ProcessName()
+
+	amount := config.Discord.Sharding.Count
+	if amount <= 0 {
+		amount, err = discord.GetRecommendedCount()
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	discord.SetNumShards(amount)
 
+	// Run only some of the shards if Robyul runs as several processes
+	shards, err := shardmanager.ParseShards(config.Discord.Sharding.Shards)
+	if err != nil {
+		panic(err)
+	}
+	if config.Discord.Sharding.Leases {
+		coordinator := shardmanager.NewRedisCoordinator(redisClient, helpers.ProcessName())
+		coordinator.Shards = shards
+		coordinator.MaxShards = config.Discord.Sharding.MaxShards
+		discord.Coordinator = coordinator
+	} else {
+		discord.Coordinator = &shardmanager.StaticCoordinator{Shards: shards}
+	}
+
 	discord.AddHandler(BotOnReady)
 	discord.AddHandler(BotOnMessageCreate)
 	discord.AddHandler(BotOnMessageDelete)
@@ -288,7 +300,6 @@ func main() {
 	discord.AddHandler(metrics.OnReady)
 	discord.AddHandler(metrics.OnMessageCreate)
 	discord.AddHandler(BotOnMemberListChunk)
-	discord.AddHandler(BotGuildOnPresenceUpdate)
 	discord.AddHandler(BotOnGuildCreate)
 	discord.AddHandler(BotOnGuildDelete)
 
@@ -301,35 +312,54 @@ func main() {
 		// Guild Member Add in modules/plugins/mod.go
 	}
 
-	// robyulState := robyulstate.NewState()
-	// robyulState.Logger = func(msgL, caller int, format string, a ...interface{}) {
-	// 	pc, file, line, _ := runtime.Caller(caller)
-	//
-	// 	files := strings.Split(file, "/")
-	// 	file = files[len(files)-1]
-	//
-	// 	name := runtime.FuncForPC(pc).Name()
-	// 	fns := strings.Split(name, ".")
-	// 	name = fns[len(fns)-1]
-	//
-	// 	msg := format
-	// 	if strings.Contains(msg, "%") {
-	// 		msg = fmt.Sprintf(format, a...)
-	// 	}
-	//
-	// 	switch msgL {
-	// 	case discordgo.LogError:
-	// 		log.WithField("module", "robyulState").Errorf("%s:%d:%s() %s", file, line, name, msg)
-	// 	case discordgo.LogWarning:
-	// 		log.WithField("module", "robyulState").Warnf("%s:%d:%s() %s", file, line, name, msg)
-	// 	case discordgo.LogInformational:
-	// 		log.WithField("module", "robyulState").Infof("%s:%d:%s() %s", file, line, name, msg)
-	// 	case discordgo.LogDebug:
-	// 		log.WithField("module", "robyulState").Debugf("%s:%d:%s() %s", file, line, name, msg)
-	// 	}
-	// }
-
-	// discord.AddHandler(robyulState.OnInterface)
+	robyulState := robyulstate.NewState()
+	robyulState.Members, err = robyulstate.ParseMemberCaching(config.Discord.State.Members)
+	if err != nil {
+		raven.CaptureErrorAndWait(err, nil)
+		panic(err)
+	}
+	if config.Discord.State.Shared {
+		robyulState.Store = robyulstate.NewRedisStore(redisClient)
+		go robyulState.StoreRefreshLoop(time.Minute)
+	}
+	robyulState.Logger = func(msgL, caller int, format string, a ...interface{}) {
+		pc, file, line, _ := runtime.Caller(caller)
+
+		files := strings.Split(file, "/")
+		file = files[len(files)-1]
+
+		name := runtime.FuncForPC(pc).Name()
+		fns := strings.Split(name, ".")
+		name = fns[len(fns)-1]
+
+		msg := format
+		if strings.Contains(msg, "%") {
+			msg = fmt.Sprintf(format, a...)
+		}
+
+		switch msgL {
+		case discordgo.LogError:
+			log.WithField("module", "robyulState").Errorf("%s:%d:%s() %s", file, line, name, msg)
+		case discordgo.LogWarning:
+			log.WithField("module", "robyulState").Warnf("%s:%d:%s() %s", file, line, name, msg)
+		case discordgo.LogInformational:
+			log.WithField("module", "robyulState").Infof("%s:%d:%s() %s", file, line, name, msg)
+		case discordgo.LogDebug:
+			log.WithField("module", "robyulState").Debugf("%s:%d:%s() %s", file, line, name, msg)
+		}
+	}
+
+	// log changes with the state before and after the change
+	robyulState.OnGuildUpdate(helpers.OnEventlogGuildUpdate)
+	robyulState.OnChannelUpdate(helpers.OnEventlogChannelUpdate)
+	robyulState.OnMemberUpdate(helpers.OnEventlogMemberUpdate)
+	robyulState.OnRoleUpdate(helpers.OnEventlogRoleUpdate)
+	robyulState.OnEmojiCreate(helpers.OnEventlogEmojiCreate)
+	robyulState.OnEmojiUpdate(helpers.OnEventlogEmojiUpdate)
+	robyulState.OnEmojiDelete(helpers.OnEventlogEmojiDelete)
+
+	discord.AddHandler(robyulState.OnInterface)
+	cache.SetRobyulstate(robyulState)
 
 	cache.SetSession(discord)
 
@@ -382,9 +412,9 @@ func main() {
 	// Launch machinery
 	marchineryLog.Set(log.WithField("module", "machinery"))
 	machineryServerConfig := &marchineryConfig.Config{
-		Broker:          "redis://" + config.Path("redis.address").Data().(string) + "/2",
+		Broker:          "redis://" + config.Redis.Address + "/2",
 		DefaultQueue:    "robyul_tasks",
-		ResultBackend:   "redis://" + config.Path("redis.address").Data().(string) + "/2",
+		ResultBackend:   "redis://" + config.Redis.Address + "/2",
 		ResultsExpireIn: 3600,
 	}
 	machineryServer, err := machinery.NewServer(machineryServerConfig)
@@ -417,7 +447,7 @@ func main() {
 	}()
 	log.WithField("module", "launcher").Info("started machinery worker robyul_worker_1 with concurrency 10")
 	machineryRedisClient := redis.NewClient(&redis.Options{
-		Addr:         config.Path("redis.address").Data().(string),
+		Addr:         config.Redis.Address,
 		Password:     "", // no password set
 		DB:           2,  // use default DB
 		ReadTimeout:  15 * time.Second,
@@ -430,9 +460,42 @@ func main() {
 
 	modules.Init(discord)
 
+	// Receive slash commands on the REST API
+	if config.Discord.Interactions.PublicKey != "" {
+		publicKey, err := interactions.ParsePublicKey(config.Discord.Interactions.PublicKey)
+		if err != nil {
+			log.WithField("module", "launcher").Error("invalid interactions public key: ", err.Error())
+		} else {
+			interactions.SetRouter(interactions.NewRouter(publicKey, modules.SlashCommands(), modules.CallBotPlugin,
+				modules.CommandAllowed, modules.ClaimInteraction, &interactions.DiscordClient{}, helpers.GetPrefixForServer))
+			log.WithField("module", "launcher").Info("receiving slash commands on /interactions")
+
+			if config.Discord.Interactions.Sync {
+				go func() {
+					defer helpers.Recover()
+
+					guildIDs := []string{""}
+					if len(config.Discord.Interactions.Guilds) > 0 {
+						guildIDs = config.Discord.Interactions.Guilds
+					}
+
+					for _, guildID := range guildIDs {
+						err := interactions.Sync(cache.GetSession().Session(0), config.Discord.ID,
+							guildID, modules.SlashCommands())
+						helpers.RelaxLog(err)
+					}
+					log.WithField("module", "launcher").Infof("synced slash commands to %d target(s)", len(guildIDs))
+				}()
+			}
+		}
+	}
+
 	// Run async worker for guild changes
 	go helpers.GuildSettingsUpdater()
 
+	// Reload the config on SIGHUP
+	go configReloader()
+
 	// Make a channel that waits for a os signal
 	BotRuntimeChannel = make(chan os.Signal, 1)
 	signal.Notify(BotRuntimeChannel, os.Interrupt, os.Kill)
@@ -466,6 +529,60 @@ func main() {
 	}
 }
 
+// newDiscordWebhookHook creates the hook sending errors to the webhook, returns nil if no webhook is set
+func newDiscordWebhookHook(webhookURL string) logrus.Hook {
+	if webhookURL == "" {
+		return nil
+	}
+
+	return discordrus.NewHook(
+		webhookURL,
+		logrus.ErrorLevel,
+		&discordrus.Opts{
+			Username:           "Logging",
+			DisableTimestamp:   false,
+			TimestampFormat:    "Jan 2 15:04:05.00000",
+			EnableCustomColors: true,
+			CustomLevelColors: &discordrus.LevelColors{
+				//Debug: 10170623,
+				//Info:  3581519,
+				//Warn:  14327864,
+				Error: 13631488,
+				Panic: 13631488,
+				Fatal: 13631488,
+			},
+		},
+	)
+}
+
+// configReloader reloads the config whenever the process receives SIGHUP
+func configReloader() {
+	log := cache.GetLogger().WithField("module", "launcher")
+
+	helpers.OnConfigReload(func(config *helpers.BotConfig) {
+		err := raven.SetDSN(config.Sentry)
+		if err != nil {
+			log.Error("failed to set sentry dsn: ", err.Error())
+		}
+
+		discordWebhookHook.Set(newDiscordWebhookHook(config.Logging.DiscordWebhook))
+	})
+
+	reloadChannel := make(chan os.Signal, 1)
+	signal.Notify(reloadChannel, syscall.SIGHUP)
+
+	for range reloadChannel {
+		restartRequired, err := helpers.ReloadConfig()
+		if err != nil {
+			log.Error("failed to reload config, keeping the current config: ", err.Error())
+			continue
+		}
+		if len(restartRequired) > 0 {
+			log.Warnf("changes to %s require a restart", strings.Join(restartRequired, ", "))
+		}
+	}
+}
+
 type KeenRestEvent struct {
 	Seconds   float64
 	Method    string
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReloadableHook passes entries to a hook which can be replaced at runtime, for example after the config has been reloaded
+type ReloadableHook struct {
+	sync.RWMutex
+
+	hook   logrus.Hook
+	levels []logrus.Level
+}
+
+// NewReloadableHook creates a hook firing for $levels, entries are dropped until a hook has been set
+func NewReloadableHook(levels []logrus.Level) *ReloadableHook {
+	return &ReloadableHook{levels: levels}
+}
+
+// Set replaces the hook, nil disables the hook
+func (hook *ReloadableHook) Set(newHook logrus.Hook) {
+	hook.Lock()
+	defer hook.Unlock()
+	hook.hook = newHook
+}
+
+// Fire event
+func (hook *ReloadableHook) Fire(entry *logrus.Entry) error {
+	hook.RLock()
+	currentHook := hook.hook
+	hook.RUnlock()
+
+	if currentHook == nil {
+		return nil
+	}
+	return currentHook.Fire(entry)
+}
+
+func (hook *ReloadableHook) Levels() []logrus.Level {
+	return hook.levels
+}
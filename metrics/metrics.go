@@ -9,8 +9,11 @@ import (
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -36,6 +39,9 @@ var (
 	// CommandsExecuted increases after each command execution
 	CommandsExecuted = expvar.NewInt("commands_executed")
 
+	// CommandsExecutedByCommand counts the executions of each command after guild aliases have been resolved
+	CommandsExecutedByCommand = expvar.NewMap("commands_executed_by_command")
+
 	// CoroutineCount counts all running coroutines
 	CoroutineCount = expvar.NewInt("coroutine_count")
 
@@ -90,6 +96,9 @@ var (
 	// GalleryPostsSent increased with every link reposted
 	GalleryPostsSent = expvar.NewInt("gallery_posts_sent")
 
+	// GalleryDuplicatesFound increased with every duplicate image found in a gallery
+	GalleryDuplicatesFound = expvar.NewInt("gallery_duplicates_found")
+
 	// GalleriesCount counts all galleries in the db
 	MirrorsCount = expvar.NewInt("mirrors_count")
 
@@ -164,10 +173,41 @@ var (
 )
 
 // Init starts a http server on 127.0.0.1:1337
+// Prometheus metrics are served on /metrics, the expvar metrics are still served on /debug/vars
 func Init() {
 	cache.GetLogger().WithField("module", "metrics").Info("Listening on TCP/1337")
 	Uptime.Set(time.Now().Unix())
-	go http.ListenAndServe(helpers.GetConfig().Path("metrics_ip").Data().(string)+":1337", nil)
+	prometheus.MustRegister(expvarCollector{})
+	http.Handle("/metrics", promhttp.Handler())
+	go http.ListenAndServe(helpers.GetConfig().MetricsIP+":1337", nil)
+}
+
+// expvarCollector exports all expvar numbers as untyped Prometheus metrics, prefixed with robyul_
+type expvarCollector struct{}
+
+// Describe sends no descriptions, which makes expvarCollector an unchecked collector
+// The expvar metrics are only known when collecting
+func (c expvarCollector) Describe(descs chan<- *prometheus.Desc) {
+}
+
+func (c expvarCollector) Collect(metrics chan<- prometheus.Metric) {
+	expvar.Do(func(variable expvar.KeyValue) {
+		var value float64
+		switch expvarValue := variable.Value.(type) {
+		case *expvar.Int:
+			value = float64(expvarValue.Value())
+		case *expvar.Float:
+			value = expvarValue.Value()
+		default:
+			return
+		}
+
+		metrics <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc("robyul_"+variable.Key, "expvar "+variable.Key, nil, nil),
+			prometheus.UntypedValue,
+			value,
+		)
+	})
 }
 
 var once sync.Once
@@ -177,6 +217,7 @@ func OnReady(session *discordgo.Session, event *discordgo.Ready) {
 	once.Do(func() {
 		go CollectDiscordMetrics(session)
 		go CollectRuntimeMetrics()
+		go CollectShardMetrics()
 	})
 }
 
@@ -199,7 +240,12 @@ func CollectDiscordMetrics(session *discordgo.Session) {
 
 		for _, guild := range session.State.Guilds {
 			channels += len(guild.Channels)
-			for _, u := range guild.Members {
+			// members are tracked by the robyul state, not by the discordgo state
+			stateGuild, err := helpers.GetGuildWithoutApi(guild.ID)
+			if err != nil {
+				continue
+			}
+			for _, u := range stateGuild.Members {
 				users[u.User.ID] = true
 			}
 		}
@@ -210,6 +256,26 @@ func CollectDiscordMetrics(session *discordgo.Session) {
 	}
 }
 
+// CollectShardMetrics records the gateway latency, state, and guild count of all shards
+func CollectShardMetrics() {
+	defer helpers.Recover()
+
+	for {
+		time.Sleep(15 * time.Second)
+
+		manager := cache.GetSession()
+		status := manager.GetFullStatus()
+		for _, shardStatus := range status.Shards {
+			var latency time.Duration
+			if session := manager.Session(shardStatus.Shard); session != nil {
+				latency = session.HeartbeatLatency()
+			}
+
+			prom.ObserveShard(shardStatus.Shard, shardStatus.OK, latency, shardStatus.NumGuilds)
+		}
+	}
+}
+
 // CollectRuntimeMetrics counts all running coroutines
 func CollectRuntimeMetrics() {
 	defer helpers.Recover()
@@ -0,0 +1,147 @@
+// Package prom contains the Prometheus metrics of Robyul
+// It does not depend on other Robyul packages, so helpers and plugins can record metrics without import cycles
+package prom
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "robyul"
+
+var (
+	// CommandsInvoked counts the invocations of each command, after guild aliases have been resolved
+	CommandsInvoked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_invoked_total",
+		Help:      "Number of command invocations.",
+	}, []string{"command"})
+
+	// CommandDuration is the time it took to execute each command
+	CommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "command_duration_seconds",
+		Help:      "Time it took to execute commands.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"command"})
+
+	// PanicsRecovered counts the panics of each plugin recovered while executing commands
+	PanicsRecovered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "panics_recovered_total",
+		Help:      "Number of panics recovered while executing commands.",
+	}, []string{"plugin"})
+
+	// ShardGatewayLatency is the latest heartbeat latency of each shard
+	ShardGatewayLatency = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shard_gateway_latency_seconds",
+		Help:      "Latest heartbeat latency of the shard.",
+	}, []string{"shard"})
+
+	// ShardReady is 1 if the shard is connected and received its ready event, 0 otherwise
+	ShardReady = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shard_ready",
+		Help:      "1 if the shard is connected and ready.",
+	}, []string{"shard"})
+
+	// ShardGuilds is the number of guilds of each shard
+	ShardGuilds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shard_guilds",
+		Help:      "Number of guilds on the shard.",
+	}, []string{"shard"})
+
+	// MongoQueryDuration is the time MongoDB queries took, by collection and operation
+	MongoQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongodb_query_duration_seconds",
+		Help:      "Time MongoDB queries took.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"collection", "operation"})
+
+	// ElasticRequestDuration is the time ElasticSearch requests took, by method and endpoint
+	ElasticRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "elasticsearch_request_duration_seconds",
+		Help:      "Time ElasticSearch requests took.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
+
+	// RedisCommandDuration is the time Redis commands took, by command
+	RedisCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Time Redis commands took.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command"})
+
+	// FeedLoopDuration is the time a full check of all feeds took, by feed
+	FeedLoopDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "feed_loop_duration_seconds",
+		Help:      "Time it took to check all feeds once.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"feed"})
+)
+
+// ObserveShard records the state of a shard
+func ObserveShard(shard int, ready bool, latency time.Duration, guilds int) {
+	label := strconv.Itoa(shard)
+
+	var readyValue float64
+	if ready {
+		readyValue = 1
+	}
+	ShardReady.WithLabelValues(label).Set(readyValue)
+	ShardGatewayLatency.WithLabelValues(label).Set(latency.Seconds())
+	ShardGuilds.WithLabelValues(label).Set(float64(guilds))
+}
+
+// InstrumentRedis records the duration of all commands sent by the client
+func InstrumentRedis(client *redis.Client) {
+	client.WrapProcess(func(oldProcess func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			start := time.Now()
+			err := oldProcess(cmd)
+			RedisCommandDuration.WithLabelValues(strings.ToLower(cmd.Name())).Observe(time.Since(start).Seconds())
+			return err
+		}
+	})
+}
+
+// ElasticTransport records the duration of all requests sent to ElasticSearch
+type ElasticTransport struct {
+	Transport http.RoundTripper
+}
+
+func (t *ElasticTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	start := time.Now()
+	response, err := transport.RoundTrip(request)
+	ElasticRequestDuration.WithLabelValues(request.Method, elasticEndpoint(request.URL.Path)).Observe(time.Since(start).Seconds())
+	return response, err
+}
+
+// elasticEndpoint returns the last API endpoint of the path, for example _search for /messages/_search
+// Paths without endpoints are document requests
+func elasticEndpoint(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if strings.HasPrefix(parts[i], "_") {
+			return parts[i]
+		}
+	}
+	return "document"
+}
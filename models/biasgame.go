@@ -1,12 +1,18 @@
 package models
 
 import (
+	"time"
+
 	"github.com/globalsign/mgo/bson"
 )
 
 const (
 	OldBiasGameTable MongoDbCollection = "biasgame"
 	BiasGameTable    MongoDbCollection = "biasgame_new"
+
+	BiasGameRatingsTable       MongoDbCollection = "biasgame_ratings"
+	BiasGameRatingHistoryTable MongoDbCollection = "biasgame_rating_history"
+	BiasGamePoolsTable         MongoDbCollection = "biasgame_pools"
 )
 
 type OldBiasGameEntry struct {
@@ -29,4 +35,39 @@ type BiasGameEntry struct {
 	RoundLosers  []bson.ObjectId
 	Gender       string // girl, boy, mixed
 	GameType     string // single, multi
+	Pool         string // name of the custom pool, empty for games with all idols
+}
+
+// BiasGameRatingEntry is the rating of an idol from the votes on one server
+// global ratings are stored on the IdolEntry
+type BiasGameRatingEntry struct {
+	ID      bson.ObjectId `bson:"_id,omitempty"`
+	IdolID  bson.ObjectId
+	GuildID string
+	Rating  float64
+	Votes   int
+}
+
+// BiasGameRatingHistoryEntry is the rating of an idol at the end of a day, GuildID is empty for global ratings
+type BiasGameRatingHistoryEntry struct {
+	ID      bson.ObjectId `bson:"_id,omitempty"`
+	IdolID  bson.ObjectId
+	GuildID string
+	Day     time.Time
+	Rating  float64
+}
+
+// BiasGamePoolEntry is a custom selection of idols for bias games, saved for a server or for a user
+// Without included groups or idols all idols are in the pool, excluded groups and idols are removed from it
+type BiasGamePoolEntry struct {
+	ID              bson.ObjectId `bson:"_id,omitempty"`
+	Name            string        // lowercase
+	GuildID         string        // set for server pools
+	UserID          string        // set for personal pools
+	CreatedByUserID string
+	CreatedAt       time.Time
+	IncludeGroups   []string
+	IncludeIdols    []bson.ObjectId
+	ExcludeGroups   []string
+	ExcludeIdols    []bson.ObjectId
 }
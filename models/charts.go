@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	ChartsSnapshotsTable MongoDbCollection = "charts_snapshots"
+	ChartsWatchesTable   MongoDbCollection = "charts_watches"
+)
+
+// ChartsSnapshotEntry is a ranking of a chart, it is stored once for every period of the chart
+type ChartsSnapshotEntry struct {
+	ID          bson.ObjectId `bson:"_id,omitempty"`
+	Chart       string        // for example melon-realtime
+	Period      string        // the period as shown by the chart, for example 2018.12.24 14:00
+	CollectedAt time.Time
+	Ranks       []ChartsRank
+}
+
+// ChartsRank is a song or album on a chart
+// Album charts have no Title
+type ChartsRank struct {
+	Key      string // lowercase artist and title or album, identifies the song or album across periods
+	Rank     int
+	PastRank int
+	Title    string
+	Artist   string
+	Album    string
+}
+
+// ChartsWatchEntry posts alerts for songs or artists to a channel
+type ChartsWatchEntry struct {
+	ID        bson.ObjectId `bson:"_id,omitempty"`
+	GuildID   string
+	ChannelID string
+	UserID    string
+	Query     string // lowercase artist, song, or album
+	CreatedAt time.Time
+}
@@ -41,6 +41,7 @@ type Config struct {
 	LevelsNotificationCode        string
 	LevelsNotificationDeleteAfter int
 	LevelsMaxBadges               int
+	LevelsRolesMode               LevelsRolesMode
 
 	MutedMembers []string // deprecated
 
@@ -68,11 +69,28 @@ type Config struct {
 	PerspectiveIsParticipating bool
 	PerspectiveChannelID       string
 
+	// LastFmCrownsMinimumPlays is the number of plays required to hold the crown of an artist, 0 for the default
+	LastFmCrownsMinimumPlays int
+
+	// TranslatorFlagReactions translates messages if members react with the flag of a country
+	TranslatorFlagReactions bool
+
 	CustomCommandsEveryoneCanAdd bool
 	CustomCommandsAddRoleID      string
 
 	AdminRoleIDs []string
 	ModRoleIDs   []string
+
+	// Language is the locale of the bot responses, empty for the default locale
+	Language string
+
+	// CommandCooldowns overwrite the default cooldowns of commands
+	CommandCooldowns []CommandCooldown
+
+	// CommandAliases are additional command names of the guild
+	CommandAliases []CommandAlias
+	// DisabledCommands are command names the bot does not react to in the guild
+	DisabledCommands []string
 }
 
 type InspectTriggersEnabled struct {
@@ -89,6 +107,19 @@ type DelayedAutoRole struct {
 	Delay  time.Duration
 }
 
+// CommandCooldown is the cooldown of a command set by guild admins, a Duration of 0 disables the cooldown
+type CommandCooldown struct {
+	Command  string
+	Duration time.Duration
+}
+
+// CommandAlias makes Alias call Command, Arguments are put in front of the arguments of the message
+type CommandAlias struct {
+	Alias     string
+	Command   string
+	Arguments string
+}
+
 // Default is a helper for generating default config values
 func (c Config) Default(guild string) Config {
 	return Config{
@@ -45,6 +45,16 @@ const (
 	EventlogTypeRobyulLevelsRoleDelete              = "Robyul_Levels_Role_Delete"              // EventlogTargetTypeRole
 	EventlogTypeRobyulLevelsRoleGrant               = "Robyul_Levels_Role_Grant"               // EventlogTargetTypeUser
 	EventlogTypeRobyulLevelsRoleDeny                = "Robyul_Levels_Role_Deny"                // EventlogTargetTypeUser
+	EventlogTypeRobyulLevelsRoleMode                = "Robyul_Levels_Role_Mode"                // EventlogTargetTypeGuild
+	EventlogTypeRobyulLevelsRoleReconcile           = "Robyul_Levels_Role_Reconcile"           // EventlogTargetTypeGuild
+	EventlogTypeRobyulLevelsExpGive                 = "Robyul_Levels_Exp_Give"                 // EventlogTargetTypeUser
+	EventlogTypeRobyulLevelsExpTake                 = "Robyul_Levels_Exp_Take"                 // EventlogTargetTypeUser
+	EventlogTypeRobyulLevelsExpSet                  = "Robyul_Levels_Exp_Set"                  // EventlogTargetTypeUser
+	EventlogTypeRobyulLevelsExpTransfer             = "Robyul_Levels_Exp_Transfer"             // EventlogTargetTypeUser
+	EventlogTypeRobyulLevelsExpImport               = "Robyul_Levels_Exp_Import"               // EventlogTargetTypeGuild
+	EventlogTypeRobyulLevelsSeasonStart             = "Robyul_Levels_Season_Start"             // EventlogTargetTypeRobyulLevelsSeason
+	EventlogTypeRobyulLevelsSeasonUpdate            = "Robyul_Levels_Season_Update"            // EventlogTargetTypeRobyulLevelsSeason
+	EventlogTypeRobyulLevelsSeasonEnd               = "Robyul_Levels_Season_End"               // EventlogTargetTypeRobyulLevelsSeason
 	EventlogTypeRobyulNotificationsChannelIgnore    = "Robyul_Notifications_Channel_Ignore"    // EventlogTargetTypeChannel
 	EventlogTypeRobyulVliveFeedAdd                  = "Robyul_Vlive_Feed_Add"                  // EventlogTargetTypeRobyulVliveFeed
 	EventlogTypeRobyulVliveFeedRemove               = "Robyul_Vlive_Feed_Remove"               // EventlogTargetTypeRobyulVliveFeed
@@ -67,6 +77,12 @@ const (
 	EventlogTypeRobyulAutoInspectsChannel           = "Robyul_AutoInspectsChannel"             // EventlogTargetTypeChannel
 	EventlogTypeRobyulPrefixUpdate                  = "Robyul_Prefix_Update"                   // EventlogTargetTypeGuild
 	EventlogTypeRobyulChatlogUpdate                 = "Robyul_Chatlog_Update"                  // EventlogTargetTypeGuild
+	EventlogTypeRobyulLanguageUpdate                = "Robyul_Language_Update"                 // EventlogTargetTypeGuild
+	EventlogTypeRobyulCommandCooldownUpdate         = "Robyul_Command_Cooldown_Update"         // EventlogTargetTypeGuild
+	EventlogTypeRobyulCommandAliasAdd               = "Robyul_Command_Alias_Add"               // EventlogTargetTypeGuild
+	EventlogTypeRobyulCommandAliasRemove            = "Robyul_Command_Alias_Remove"            // EventlogTargetTypeGuild
+	EventlogTypeRobyulCommandDisable                = "Robyul_Command_Disable"                 // EventlogTargetTypeGuild
+	EventlogTypeRobyulCommandEnable                 = "Robyul_Command_Enable"                  // EventlogTargetTypeGuild
 	EventlogTypeRobyulVanityInviteCreate            = "Robyul_VanityInvite_Create"             // EventlogTargetTypeGuild
 	EventlogTypeRobyulVanityInviteDelete            = "Robyul_VanityInvite_Delete"             // EventlogTargetTypeGuild
 	EventlogTypeRobyulVanityInviteUpdate            = "Robyul_VanityInvite_Update"             // EventlogTargetTypeGuild
@@ -83,6 +99,7 @@ const (
 	EventlogTypeRobyulGuildAnnouncementsBanSet      = "Robyul_GuildAnnouncements_Ban_Set"      // EventlogTargetTypeChannel
 	EventlogTypeRobyulGalleryAdd                    = "Robyul_Gallery_Add"                     // EventlogTargetTypeRobyulGallery
 	EventlogTypeRobyulGalleryRemove                 = "Robyul_Gallery_Remove"                  // EventlogTargetTypeRobyulGallery
+	EventlogTypeRobyulGalleryUpdate                 = "Robyul_Gallery_Update"                  // EventlogTargetTypeRobyulGallery
 	EventlogTypeRobyulMirrorCreate                  = "Robyul_Mirror_Create"                   // EventlogTargetTypeRobyulMirror
 	EventlogTypeRobyulMirrorDelete                  = "Robyul_Mirror_Delete"                   // EventlogTargetTypeRobyulMirror
 	EventlogTypeRobyulMirrorUpdate                  = "Robyul_Mirror_Update"                   // EventlogTargetTypeRobyulMirror
@@ -117,6 +134,15 @@ const (
 	EventlogTypeRobyulTwitterFeedAdd                = "Robyul_Twitter_Feed_Add"                // EventlogTargetTypeRobyulTwitterFeed
 	EventlogTypeRobyulTwitterFeedRemove             = "Robyul_Twitter_Feed_Remove"             // EventlogTargetTypeRobyulTwitterFeed
 	EventlogTypeRobyulActionRevert                  = "Robyul_Action_Revert"                   // EventlogTargetTypeRobyulEventlogItem
+	EventlogTypeRobyulChartsWatchAdd                = "Robyul_Charts_Watch_Add"                // EventlogTargetTypeRobyulChartsWatch
+	EventlogTypeRobyulChartsWatchRemove             = "Robyul_Charts_Watch_Remove"             // EventlogTargetTypeRobyulChartsWatch
+	EventlogTypeRobyulLastFmConfigUpdate            = "Robyul_LastFm_Config_Update"            // EventlogTargetTypeGuild
+	EventlogTypeRobyulTranslatorChannelAdd          = "Robyul_Translator_Channel_Add"          // EventlogTargetTypeRobyulTranslatorChannel
+	EventlogTypeRobyulTranslatorChannelRemove       = "Robyul_Translator_Channel_Remove"       // EventlogTargetTypeRobyulTranslatorChannel
+	EventlogTypeRobyulTranslatorConfigUpdate        = "Robyul_Translator_Config_Update"        // EventlogTargetTypeGuild
+	EventlogTypeRobyulBiasgamePoolCreate            = "Robyul_Biasgame_Pool_Create"            // EventlogTargetTypeRobyulBiasgamePool
+	EventlogTypeRobyulBiasgamePoolUpdate            = "Robyul_Biasgame_Pool_Update"            // EventlogTargetTypeRobyulBiasgamePool
+	EventlogTypeRobyulBiasgamePoolDelete            = "Robyul_Biasgame_Pool_Delete"            // EventlogTargetTypeRobyulBiasgamePool
 
 	EventlogTargetTypeRobyulBadge               = "robyul-badge"
 	EventlogTargetTypeRobyulVliveFeed           = "robyul-vlive-feed"
@@ -132,6 +158,10 @@ const (
 	EventlogTargetTypeRobyulPublicObject        = "robyul-public-object"
 	EventlogTargetTypeRobyulMirrorType          = "robyul-mirror-type"
 	EventlogTargetTypeRobyulEventlogItem        = "robyul-eventlog-item"
+	EventlogTargetTypeRobyulLevelsSeason        = "robyul-levels-season"
+	EventlogTargetTypeRobyulChartsWatch         = "robyul-charts-watch"
+	EventlogTargetTypeRobyulTranslatorChannel   = "robyul-translator-channel"
+	EventlogTargetTypeRobyulBiasgamePool        = "robyul-biasgame-pool"
 
 	AuditLogBackfillRedisList = "robyul-discord:eventlog:auditlog-backfills:v2"
 )
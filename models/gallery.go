@@ -1,17 +1,49 @@
 package models
 
 import (
+	"time"
+
 	"github.com/globalsign/mgo/bson"
 )
 
 const (
-	GalleryTable MongoDbCollection = "galleries"
+	GalleryTable       MongoDbCollection = "galleries"
+	GalleryImagesTable MongoDbCollection = "gallery_images"
+)
+
+type GalleryDuplicateMode int
+
+const (
+	// GalleryDuplicateModeIgnore indexes images but reposts duplicates anyway
+	GalleryDuplicateModeIgnore GalleryDuplicateMode = iota
+	// GalleryDuplicateModeSkip does not repost duplicates
+	GalleryDuplicateModeSkip
+	// GalleryDuplicateModeFlag reposts duplicates with a link to the original post
+	GalleryDuplicateModeFlag
 )
 
 type GalleryEntry struct {
+	ID                bson.ObjectId `bson:"_id,omitempty"`
+	SourceChannelID   string
+	TargetChannelID   string
+	GuildID           string
+	AddedByUserID     string
+	DuplicateMode     GalleryDuplicateMode
+	DuplicateDistance int
+}
+
+// GalleryImageEntry is one image in the per gallery hash index
+type GalleryImageEntry struct {
 	ID              bson.ObjectId `bson:"_id,omitempty"`
-	SourceChannelID string
-	TargetChannelID string
+	GalleryID       bson.ObjectId
 	GuildID         string
-	AddedByUserID   string
+	SourceChannelID string
+	SourceMessageID string
+	AuthorID        string
+	URL             string
+	PostedChannelID string
+	PostedMessageID string
+	HashString      string
+	Duplicates      int
+	CreatedAt       time.Time
 }
@@ -10,6 +10,23 @@ const (
 	OldIdolsTable        MongoDbCollection = "biasgame_idols"
 	IdolTable            MongoDbCollection = "idols"
 	IdolSuggestionsTable MongoDbCollection = "biasgame_suggestions"
+	IdolAuditTable       MongoDbCollection = "idols_audit"
+)
+
+const (
+	IdolAuditActionIdolUpdate        = "idol-update"
+	IdolAuditActionGroupUpdate       = "group-update"
+	IdolAuditActionImageUpdate       = "image-update"
+	IdolAuditActionImageDelete       = "image-delete"
+	IdolAuditActionIdolAliasAdd      = "idol-alias-add"
+	IdolAuditActionIdolAliasDelete   = "idol-alias-delete"
+	IdolAuditActionGroupAliasAdd     = "group-alias-add"
+	IdolAuditActionGroupAliasDelete  = "group-alias-delete"
+	IdolAuditActionSuggestionApprove = "suggestion-approve"
+	IdolAuditActionSuggestionDeny    = "suggestion-deny"
+
+	IdolAuditSourceDiscord = "discord"
+	IdolAuditSourceRest    = "rest"
 )
 
 type IdolImageEntry struct {
@@ -29,6 +46,9 @@ type IdolEntry struct {
 	BGGameWins  int
 	BGRounds    int
 	BGRoundWins int
+	// BGRating is the Elo rating from all biasgame votes, it is only set if BGRatingVotes > 0
+	BGRating      float64
+	BGRatingVotes int
 }
 
 type OldIdolEntry struct {
@@ -57,3 +77,15 @@ type IdolSuggestionEntry struct {
 	ImageHashString   string
 	ObjectName        string
 }
+
+// IdolAuditEntry is one edit of the idol database, IdolID is empty for edits of a whole group
+type IdolAuditEntry struct {
+	ID        bson.ObjectId `bson:"_id,omitempty"`
+	IdolID    bson.ObjectId `bson:",omitempty"`
+	GroupName string
+	UserID    string
+	Action    string
+	Source    string // discord, rest
+	Changes   []ElasticEventlogChange
+	CreatedAt time.Time
+}
@@ -1,11 +1,16 @@
 package models
 
 import (
+	"time"
+
 	"github.com/globalsign/mgo/bson"
 )
 
 const (
-	LastFmTable MongoDbCollection = "lastfm"
+	LastFmTable            MongoDbCollection = "lastfm"
+	LastFmArtistPlaysTable MongoDbCollection = "lastfm_artist_plays"
+	LastFmCrownsTable      MongoDbCollection = "lastfm_crowns"
+	LastFmReportsTable     MongoDbCollection = "lastfm_reports"
 )
 
 type LastFmEntry struct {
@@ -13,3 +18,42 @@ type LastFmEntry struct {
 	UserID         string
 	LastFmUsername string
 }
+
+// LastFmArtistPlaysEntry caches the top artists of a Last.fm account
+type LastFmArtistPlaysEntry struct {
+	ID             bson.ObjectId `bson:"_id,omitempty"`
+	UserID         string
+	LastFmUsername string
+	Artists        []LastFmArtistPlays
+	UpdatedAt      time.Time
+}
+
+type LastFmArtistPlays struct {
+	// Key is the lowercase name of the artist
+	Key   string
+	Name  string
+	Plays int
+}
+
+// LastFmCrownEntry is the crown of an artist on a guild, it is held by the top listener of the artist
+type LastFmCrownEntry struct {
+	ID         bson.ObjectId `bson:"_id,omitempty"`
+	GuildID    string
+	ArtistKey  string
+	ArtistName string
+	UserID     string
+	Plays      int
+	ClaimedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// LastFmReportEntry subscribes a user to the weekly listening report
+type LastFmReportEntry struct {
+	ID     bson.ObjectId `bson:"_id,omitempty"`
+	UserID string
+	// GuildID and ChannelID are empty if the report is sent by DM
+	GuildID    string
+	ChannelID  string
+	CreatedAt  time.Time
+	LastSentAt time.Time
+}
@@ -15,6 +15,17 @@ type LevelsRoleEntry struct {
 	LastLevel  int
 }
 
+type LevelsRolesMode int
+
+const (
+	// LevelsRolesModeRange grants roles only between their start and last level
+	LevelsRolesModeRange LevelsRolesMode = iota
+	// LevelsRolesModeStack keeps all roles a user earned
+	LevelsRolesModeStack
+	// LevelsRolesModeReplace only grants the highest role a user earned
+	LevelsRolesModeReplace
+)
+
 type LevelsRoleOverwriteType int
 
 const (
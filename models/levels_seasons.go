@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	LevelsPeriodExpTable MongoDbCollection = "levels_period_exp"
+	LevelsSeasonsTable   MongoDbCollection = "levels_seasons"
+)
+
+// LevelsPeriodExpEntry stores the EXP a user gained on a guild during a week, a month, or a season
+type LevelsPeriodExpEntry struct {
+	ID      bson.ObjectId `bson:"_id,omitempty"`
+	UserID  string
+	GuildID string
+	Period  string
+	Exp     int64
+	// ExpiresAt removes weekly and monthly entries some time after the last EXP was gained, empty for seasons
+	ExpiresAt time.Time `bson:",omitempty"`
+}
+
+type LevelsSeasonEntry struct {
+	ID              bson.ObjectId `bson:"_id,omitempty"`
+	GuildID         string
+	Name            string
+	CreatedByUserID string
+	StartAt         time.Time
+	EndAt           time.Time
+	RewardCount     int
+	RewardRoleID    string
+	RewardBadgeID   string
+	Archived        bool
+	ArchivedAt      time.Time
+	TopUsers        []LevelsSeasonRankEntry
+}
+
+type LevelsSeasonRankEntry struct {
+	UserID  string
+	Exp     int64
+	Ranking int
+}
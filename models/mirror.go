@@ -1,9 +1,14 @@
 package models
 
-import "github.com/globalsign/mgo/bson"
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
 
 const (
-	MirrorsTable = "mirrors"
+	MirrorsTable                          = "mirrors"
+	MirrorMessagesTable MongoDbCollection = "mirror_messages"
 )
 
 type MirrorType int
@@ -11,15 +16,32 @@ type MirrorType int
 const (
 	MirrorTypeLink MirrorType = iota
 	MirrorTypeText
+	MirrorTypeUpload
 )
 
 type MirrorEntry struct {
 	ID                bson.ObjectId `bson:"_id,omitempty"`
 	Type              MirrorType
 	ConnectedChannels []MirrorChannelEntry
+	Bidirectional     bool
 }
 
 type MirrorChannelEntry struct {
 	GuildID   string
 	ChannelID string
 }
+
+// MirrorMessageEntry maps a source message to one of its mirrored copies
+type MirrorMessageEntry struct {
+	ID              bson.ObjectId `bson:"_id,omitempty"`
+	MirrorID        bson.ObjectId
+	SourceGuildID   string
+	SourceChannelID string
+	SourceMessageID string
+	SourceAuthorID  string
+	GuildID         string
+	ChannelID       string
+	MessageID       string
+	WebhookID       string
+	CreatedAt       time.Time
+}
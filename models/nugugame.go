@@ -1,9 +1,17 @@
 package models
 
-import "github.com/globalsign/mgo/bson"
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
 
 const (
 	NuguGameTable MongoDbCollection = "nugugame"
+
+	NuguGameDailyTable   MongoDbCollection = "nugugame_daily"
+	NuguGameStreaksTable MongoDbCollection = "nugugame_streaks"
+	NuguGameRankedTable  MongoDbCollection = "nugugame_ranked"
 )
 
 type NuguGameEntry struct {
@@ -19,4 +27,35 @@ type NuguGameEntry struct {
 	IsMultigame         bool
 	Difficulty          string
 	UsersCorrectGuesses map[string][]bson.ObjectId // userid => []ids of idols they got right.  used in multi only
+	IsDaily             bool
+	IsRanked            bool
+	UsersPoints         map[string]int // userid => points they earned. used in ranked only
+}
+
+// NuguGameDailyEntry is the attempt of a user at the daily challenge, it is created when the game starts so every user only gets one try per day
+type NuguGameDailyEntry struct {
+	ID       bson.ObjectId `bson:"_id,omitempty"`
+	UserID   string
+	GuildID  string
+	Day      time.Time
+	Score    int
+	Finished bool
+}
+
+// NuguGameStreakEntry counts the days in a row a user played the daily challenge
+type NuguGameStreakEntry struct {
+	ID            bson.ObjectId `bson:"_id,omitempty"`
+	UserID        string
+	CurrentStreak int
+	LongestStreak int
+	LastDay       time.Time
+}
+
+// NuguGameRankedEntry is the rating of a user from ranked multi games
+type NuguGameRankedEntry struct {
+	ID     bson.ObjectId `bson:"_id,omitempty"`
+	UserID string
+	Rating float64
+	Games  int
+	Points int
 }
@@ -6,12 +6,23 @@ const (
 	RandompictureSourcesTable MongoDbCollection = "randompicture_sources"
 )
 
+const (
+	RandompictureSourceTypeDrive     = "drive"
+	RandompictureSourceTypeStorage   = "storage"
+	RandompictureSourceTypeChannel   = "channel"
+	RandompictureSourceTypeDirectory = "directory"
+)
+
 type RandompictureSourceEntry struct {
 	ID                 bson.ObjectId `bson:"_id,omitempty"`
 	PreviousID         string
 	GuildID            string
+	Type               string // drive, storage, channel, directory, empty for drive
 	PostToChannelIDs   []string
 	DriveFolderIDs     []string
+	ObjectNames        []string // storage and channel sources
+	UploadChannelIDs   []string // channel sources
+	Directory          string   // directory sources
 	Aliases            []string
 	BlacklistedRoleIDs []string
 }
@@ -127,8 +127,18 @@ type Rest_Status_Member struct {
 }
 
 type Rest_Ranking struct {
-	Ranks []Rest_Ranking_Rank_Item
-	Count int
+	Ranks  []Rest_Ranking_Rank_Item
+	Count  int
+	Period string
+}
+
+type Rest_Ranking_Season struct {
+	ID          string
+	Name        string
+	StartAt     time.Time
+	EndAt       time.Time
+	Archived    bool
+	RewardCount int
 }
 
 type Rest_Ranking_Rank_Item struct {
@@ -265,6 +275,56 @@ type Rest_Background struct {
 	Tags []string
 }
 
+type Rest_Idol struct {
+	ID          string
+	Name        string
+	NameAliases []string
+	GroupName   string
+	Gender      string
+	Deleted     bool
+	Images      []Rest_Idol_Image
+	BGGames     int
+	BGGameWins  int
+	BGRounds    int
+	BGRoundWins int
+	BGRating    float64
+}
+
+type Rest_Idol_Image struct {
+	ObjectName string
+	HashString string
+}
+
+type Rest_Idol_Group struct {
+	Name    string
+	Aliases []string
+	Idols   int
+}
+
+type Rest_Idol_Suggestion struct {
+	ID         string
+	UserID     string
+	ChannelID  string
+	Name       string
+	GroupName  string
+	Gender     string
+	ImageURL   string
+	Notes      string
+	GroupMatch bool
+	IdolMatch  bool
+	CreatedAt  time.Time
+}
+
+type Rest_Idol_Audit_Entry struct {
+	CreatedAt time.Time
+	IdolID    string
+	GroupName string
+	UserID    string
+	Action    string
+	Source    string
+	Changes   []ElasticEventlogChange
+}
+
 const (
 	Redis_Key_Feature_Levels_Badges  = "robyul2-discord:feature:levels-badges:server:%s"
 	Redis_Key_Feature_RandomPictures = "robyul2-discord:feature:randompictures:server:%s"
@@ -6,3 +6,7 @@ type Rest_Receive_SetSettings struct {
 		Values []string
 	}
 }
+
+type Rest_Receive_IdolSuggestionReview struct {
+	Notes string
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	TranslatorChannelsTable MongoDbCollection = "translator_channels"
+)
+
+// TranslatorChannelEntry reposts every message of the channel translated into the target language
+type TranslatorChannelEntry struct {
+	ID             bson.ObjectId `bson:"_id,omitempty"`
+	GuildID        string
+	ChannelID      string
+	TargetLanguage string
+	// TargetChannelID is the channel the translations are posted in, it can be the channel itself
+	TargetChannelID string
+	AddedByUserID   string
+	AddedAt         time.Time
+}
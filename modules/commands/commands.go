@@ -0,0 +1,216 @@
+// Package commands lets plugins declare their commands, subcommands, and typed arguments
+// instead of parsing the message content by hand.
+// Declared commands are parsed, validated, and documented by the framework, see Execute and SendHelp.
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ArgumentType is the type of a command argument, it defines how the input is parsed
+type ArgumentType int
+
+const (
+	// ArgumentTypeString is a single word
+	ArgumentTypeString ArgumentType = iota
+	// ArgumentTypeQuoted is a single word or multiple words in quotes
+	ArgumentTypeQuoted
+	// ArgumentTypeRest is everything until the end of the message, it has to be the last argument
+	ArgumentTypeRest
+	// ArgumentTypeInt is a whole number
+	ArgumentTypeInt
+	// ArgumentTypeUser is a user mention or user id
+	ArgumentTypeUser
+	// ArgumentTypeMember is a mention or id of a member of the current guild
+	ArgumentTypeMember
+	// ArgumentTypeChannel is a text channel mention or channel id
+	ArgumentTypeChannel
+	// ArgumentTypeRole is a role mention, role id, or role name of the current guild
+	ArgumentTypeRole
+	// ArgumentTypeDuration is a duration like 90s, 10m, 1h30m, 3d, or 2w
+	ArgumentTypeDuration
+	// ArgumentTypeEmoji is an unicode emoji or a custom emoji
+	ArgumentTypeEmoji
+)
+
+// String returns the name of the argument type used in help texts
+func (t ArgumentType) String() string {
+	switch t {
+	case ArgumentTypeString:
+		return "word"
+	case ArgumentTypeQuoted:
+		return "text"
+	case ArgumentTypeRest:
+		return "text"
+	case ArgumentTypeInt:
+		return "number"
+	case ArgumentTypeUser:
+		return "user"
+	case ArgumentTypeMember:
+		return "member"
+	case ArgumentTypeChannel:
+		return "channel"
+	case ArgumentTypeRole:
+		return "role"
+	case ArgumentTypeDuration:
+		return "duration"
+	case ArgumentTypeEmoji:
+		return "emoji"
+	}
+	return "unknown"
+}
+
+// Permission is the permission required to run a command
+type Permission int
+
+const (
+	PermissionEveryone Permission = iota
+	PermissionMod
+	PermissionAdmin
+	PermissionRobyulMod
+	PermissionBotAdmin
+)
+
+// String returns the name of the permission used in help texts
+func (p Permission) String() string {
+	switch p {
+	case PermissionEveryone:
+		return "Everyone"
+	case PermissionMod:
+		return "Moderators"
+	case PermissionAdmin:
+		return "Administrators"
+	case PermissionRobyulMod:
+		return "Robyul Moderators"
+	case PermissionBotAdmin:
+		return "Bot Administrators"
+	}
+	return "unknown"
+}
+
+// Argument declares a positional argument of a command
+type Argument struct {
+	Name        string
+	Description string
+	Type        ArgumentType
+	Optional    bool
+}
+
+// Handler is called with the parsed arguments once the input has been validated
+type Handler func(ctx *Context)
+
+// Command declares a command or a subcommand
+// Commands with subcommands can have a Handler, it is called if no subcommand matches
+type Command struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Permission  Permission
+	// Module is checked with helpers.ModuleIsAllowed if set
+	Module      models.ModulePermissionsModule
+	Arguments   []Argument
+	Subcommands []*Command
+	Handler     Handler
+	// Deferred commands take a while to respond, slash commands show a loading state while they are running
+	Deferred bool
+}
+
+// Matches returns true if name is the name or an alias of the command
+func (c *Command) Matches(name string) bool {
+	name = strings.ToLower(name)
+	if strings.ToLower(c.Name) == name {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if strings.ToLower(alias) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the name and all aliases of the command
+func (c *Command) Names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+// subcommand returns the subcommand matching name, or nil
+func (c *Command) subcommand(name string) *Command {
+	for _, subcommand := range c.Subcommands {
+		if subcommand.Matches(name) {
+			return subcommand
+		}
+	}
+	return nil
+}
+
+// Context holds the message and the parsed arguments of a command invocation
+type Context struct {
+	Msg     *discordgo.Message
+	Session *discordgo.Session
+	GuildID string
+	// Command is the (sub)command that has been invoked
+	Command *Command
+	// Path are the names of the invoked command and subcommands, for example ["levels", "roles", "add"]
+	Path []string
+
+	values map[string]interface{}
+}
+
+// Has returns true if the argument has been given
+func (ctx *Context) Has(name string) bool {
+	_, ok := ctx.values[name]
+	return ok
+}
+
+// String returns the value of a string, quoted, or rest argument
+func (ctx *Context) String(name string) string {
+	value, _ := ctx.values[name].(string)
+	return value
+}
+
+// Int returns the value of a number argument
+func (ctx *Context) Int(name string) int {
+	value, _ := ctx.values[name].(int)
+	return value
+}
+
+// User returns the value of a user argument
+func (ctx *Context) User(name string) *discordgo.User {
+	value, _ := ctx.values[name].(*discordgo.User)
+	return value
+}
+
+// Member returns the value of a member argument
+func (ctx *Context) Member(name string) *discordgo.Member {
+	value, _ := ctx.values[name].(*discordgo.Member)
+	return value
+}
+
+// Channel returns the value of a channel argument
+func (ctx *Context) Channel(name string) *discordgo.Channel {
+	value, _ := ctx.values[name].(*discordgo.Channel)
+	return value
+}
+
+// Role returns the value of a role argument
+func (ctx *Context) Role(name string) *discordgo.Role {
+	value, _ := ctx.values[name].(*discordgo.Role)
+	return value
+}
+
+// Duration returns the value of a duration argument
+func (ctx *Context) Duration(name string) time.Duration {
+	value, _ := ctx.values[name].(time.Duration)
+	return value
+}
+
+// Emoji returns the value of an emoji argument, unicode emoji only have the Name set
+func (ctx *Context) Emoji(name string) *discordgo.Emoji {
+	value, _ := ctx.values[name].(*discordgo.Emoji)
+	return value
+}
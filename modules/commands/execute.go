@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/bwmarrin/discordgo"
+)
+
+var errTooManyArguments = errors.New("too many arguments")
+
+// argumentError is returned if an argument is missing or could not be parsed
+type argumentError struct {
+	argument Argument
+	value    string
+	missing  bool
+}
+
+func (e *argumentError) Error() string {
+	if e.missing {
+		return "missing argument " + e.argument.Name
+	}
+	return "invalid " + e.argument.Type.String() + " " + e.value + " for argument " + e.argument.Name
+}
+
+// Execute resolves the subcommand, checks the permissions, parses the arguments, and calls the handler of a declared command
+// invokedName is the name the command has been called with, content is the message without prefix and command
+func Execute(command *Command, invokedName string, content string, msg *discordgo.Message, session *discordgo.Session) {
+	ctx := &Context{
+		Msg:     msg,
+		Session: session,
+		GuildID: msg.GuildID,
+		Command: command,
+		Path:    []string{invokedName},
+	}
+
+	tokens := tokenize(content)
+	for len(tokens) > 0 && len(ctx.Command.Subcommands) > 0 {
+		subcommand := ctx.Command.subcommand(tokens[0].value)
+		if subcommand == nil {
+			break
+		}
+		ctx.Command = subcommand
+		ctx.Path = append(ctx.Path, tokens[0].value)
+		tokens = tokens[1:]
+	}
+
+	prefix := helpers.GetPrefixForServer(ctx.GuildID)
+
+	if ctx.Command.Handler == nil {
+		_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.commands.subcommand-unknown",
+			Usage(prefix, ctx.Path, ctx.Command), prefix, strings.Join(ctx.Path, " ")))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+
+	if ctx.Command.Module != 0 && !helpers.ModuleIsAllowed(msg.ChannelID, msg.ID, msg.Author.ID, ctx.Command.Module) {
+		return
+	}
+
+	if !checkPermission(ctx.Command.Permission, msg) {
+		return
+	}
+
+	err := parseArguments(ctx, content, tokens)
+	if err != nil {
+		usage := Usage(prefix, ctx.Path, ctx.Command)
+		var errorText string
+		if errArgument, ok := err.(*argumentError); ok && errArgument.missing {
+			errorText = helpers.GetTextForMessage(msg, "bot.commands.argument-missing", errArgument.argument.Name, usage)
+		} else if ok {
+			errorText = helpers.GetTextForMessage(msg, "bot.commands.argument-invalid",
+				errArgument.value, errArgument.argument.Type.String(), errArgument.argument.Name, usage)
+		} else {
+			errorText = helpers.GetTextForMessage(msg, "bot.commands.argument-too-many", usage)
+		}
+
+		_, err = helpers.SendMessage(msg.ChannelID, errorText)
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+
+	ctx.Command.Handler(ctx)
+}
+
+// checkPermission returns true if the author of the message has the permission, tells the author otherwise
+func checkPermission(permission Permission, msg *discordgo.Message) (allowed bool) {
+	var noPermissionText string
+	switch permission {
+	case PermissionEveryone:
+		return true
+	case PermissionMod:
+		allowed = helpers.IsMod(msg)
+		noPermissionText = "mod.no_permission"
+	case PermissionAdmin:
+		allowed = helpers.IsAdmin(msg)
+		noPermissionText = "admin.no_permission"
+	case PermissionRobyulMod:
+		allowed = helpers.IsRobyulMod(msg.Author.ID)
+		noPermissionText = "robyulmod.no_permission"
+	case PermissionBotAdmin:
+		allowed = helpers.IsBotAdmin(msg.Author.ID)
+		noPermissionText = "botadmin.no_permission"
+	}
+
+	if !allowed {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, noPermissionText))
+	}
+	return allowed
+}
+
+// Usage returns the usage of a command, for example: _levels roles add <role> <start level> [last level]
+func Usage(prefix string, path []string, command *Command) string {
+	usage := prefix + strings.Join(path, " ")
+
+	if len(command.Subcommands) > 0 {
+		subcommandNames := make([]string, 0)
+		for _, subcommand := range command.Subcommands {
+			subcommandNames = append(subcommandNames, subcommand.Name)
+		}
+		usage += " <" + strings.Join(subcommandNames, "|") + ">"
+		if command.Handler == nil {
+			return usage
+		}
+	}
+
+	for _, argument := range command.Arguments {
+		if argument.Optional {
+			usage += " [" + argument.Name + "]"
+		} else {
+			usage += " <" + argument.Name + ">"
+		}
+	}
+	return usage
+}
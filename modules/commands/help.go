@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/bwmarrin/discordgo"
+)
+
+// SendHelp sends the generated help for a command, path are the names of the command and subcommands the help is requested for
+func SendHelp(command *Command, path []string, msg *discordgo.Message) {
+	if len(path) <= 0 {
+		return
+	}
+
+	resolvedPath := []string{path[0]}
+	for _, name := range path[1:] {
+		subcommand := command.subcommand(name)
+		if subcommand == nil {
+			break
+		}
+		command = subcommand
+		resolvedPath = append(resolvedPath, name)
+	}
+
+	prefix := helpers.GetPrefixForServer(msg.GuildID)
+
+	helpEmbed := &discordgo.MessageEmbed{
+		Color:       0x0FADED,
+		Title:       Usage(prefix, resolvedPath, command),
+		Description: command.Description,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   helpers.GetTextForMessage(msg, "bot.commands.help-permission"),
+				Value:  command.Permission.String(),
+				Inline: true,
+			},
+		},
+	}
+
+	if len(command.Aliases) > 0 {
+		helpEmbed.Fields = append(helpEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   helpers.GetTextForMessage(msg, "bot.commands.help-aliases"),
+			Value:  "`" + strings.Join(command.Aliases, "`, `") + "`",
+			Inline: true,
+		})
+	}
+
+	if len(command.Arguments) > 0 {
+		var argumentsText string
+		for _, argument := range command.Arguments {
+			argumentsText += "`" + argument.Name + "` (" + argument.Type.String()
+			if argument.Optional {
+				argumentsText += ", " + helpers.GetTextForMessage(msg, "bot.commands.help-optional")
+			}
+			argumentsText += ")"
+			if argument.Description != "" {
+				argumentsText += ": " + argument.Description
+			}
+			argumentsText += "\n"
+		}
+		helpEmbed.Fields = append(helpEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:  helpers.GetTextForMessage(msg, "bot.commands.help-arguments"),
+			Value: argumentsText,
+		})
+	}
+
+	if len(command.Subcommands) > 0 {
+		var subcommandsText string
+		for _, subcommand := range command.Subcommands {
+			subcommandsText += "`" + subcommand.Name + "`"
+			if subcommand.Description != "" {
+				subcommandsText += ": " + subcommand.Description
+			}
+			subcommandsText += "\n"
+		}
+		helpEmbed.Fields = append(helpEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:  helpers.GetTextForMessage(msg, "bot.commands.help-subcommands"),
+			Value: subcommandsText,
+		})
+		helpEmbed.Footer = &discordgo.MessageEmbedFooter{
+			Text: helpers.GetTextForMessage(msg, "bot.commands.help-subcommands-footer", prefix, strings.Join(resolvedPath, " ")),
+		}
+	}
+
+	_, err := helpers.SendEmbed(msg.ChannelID, helpEmbed)
+	helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+}
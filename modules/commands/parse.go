@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	roleMentionRegex   = regexp.MustCompile(`^<@&(\d+)>$`)
+	durationPartsRegex = regexp.MustCompile(`(\d+)(w|d|h|m|s)`)
+)
+
+// lookups of discord objects, replaced in tests
+var (
+	getUserFromMention    = helpers.GetUserFromMention
+	getGuildMember        = helpers.GetGuildMember
+	getChannelFromMention = helpers.GetChannelFromMention
+	getGuild              = helpers.GetGuild
+	getEmojiFromName      = helpers.GetDiscordEmojiFromName
+)
+
+// token is a word or a quoted string of the input, start is the position of the token in the input
+type token struct {
+	value string
+	start int
+}
+
+// tokenize splits the input into words, text in double quotes is kept together
+func tokenize(input string) (tokens []token) {
+	runes := []rune(input)
+	position := 0
+	for position < len(runes) {
+		if unicode.IsSpace(runes[position]) {
+			position++
+			continue
+		}
+
+		start := position
+		if runes[position] == '"' {
+			end := position + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end < len(runes) {
+				tokens = append(tokens, token{value: string(runes[start+1 : end]), start: len(string(runes[:start]))})
+				position = end + 1
+				continue
+			}
+		}
+
+		for position < len(runes) && !unicode.IsSpace(runes[position]) {
+			position++
+		}
+		tokens = append(tokens, token{value: string(runes[start:position]), start: len(string(runes[:start]))})
+	}
+	return tokens
+}
+
+// parseArguments validates the tokens against the declared arguments and stores the parsed values in the context
+func parseArguments(ctx *Context, input string, tokens []token) (err error) {
+	ctx.values = make(map[string]interface{})
+
+	for _, argument := range ctx.Command.Arguments {
+		if len(tokens) <= 0 {
+			if argument.Optional {
+				continue
+			}
+			return &argumentError{argument: argument, missing: true}
+		}
+
+		if argument.Type == ArgumentTypeRest {
+			ctx.values[argument.Name] = strings.TrimSpace(input[tokens[0].start:])
+			tokens = nil
+			continue
+		}
+
+		value, err := parseArgument(ctx, argument, tokens[0])
+		if err != nil {
+			return &argumentError{argument: argument, value: tokens[0].value}
+		}
+		ctx.values[argument.Name] = value
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) > 0 {
+		return errTooManyArguments
+	}
+	return nil
+}
+
+func parseArgument(ctx *Context, argument Argument, input token) (value interface{}, err error) {
+	switch argument.Type {
+	case ArgumentTypeString, ArgumentTypeQuoted:
+		return input.value, nil
+	case ArgumentTypeInt:
+		return strconv.Atoi(input.value)
+	case ArgumentTypeUser:
+		user, err := getUserFromMention(input.value)
+		if err != nil || user == nil || user.ID == "" {
+			return nil, errors.New("user not found")
+		}
+		return user, nil
+	case ArgumentTypeMember:
+		user, err := getUserFromMention(input.value)
+		if err != nil || user == nil || user.ID == "" {
+			return nil, errors.New("user not found")
+		}
+		member, err := getGuildMember(ctx.GuildID, user.ID)
+		if err != nil || member == nil {
+			return nil, errors.New("member not found")
+		}
+		return member, nil
+	case ArgumentTypeChannel:
+		return getChannelFromMention(ctx.Msg, input.value)
+	case ArgumentTypeRole:
+		return parseRole(ctx.GuildID, input.value)
+	case ArgumentTypeDuration:
+		return ParseDuration(input.value)
+	case ArgumentTypeEmoji:
+		return parseEmoji(ctx.GuildID, input.value)
+	}
+	return nil, errors.New("unknown argument type")
+}
+
+func parseRole(guildID string, input string) (role *discordgo.Role, err error) {
+	guild, err := getGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submatches := roleMentionRegex.FindStringSubmatch(input); len(submatches) == 2 {
+		input = submatches[1]
+	}
+
+	for _, guildRole := range guild.Roles {
+		if guildRole.ID == input || strings.ToLower(guildRole.Name) == strings.ToLower(input) {
+			return guildRole, nil
+		}
+	}
+	return nil, errors.New("role not found")
+}
+
+func parseEmoji(guildID string, input string) (emoji *discordgo.Emoji, err error) {
+	if helpers.IsDiscordEmoji(input) {
+		emojiID, emojiName, animated := helpers.ParseCustomEmoji(input)
+		return &discordgo.Emoji{ID: emojiID, Name: emojiName, Animated: animated}, nil
+	}
+	if helpers.IsUnicodeEmoji(input) {
+		return &discordgo.Emoji{Name: input}, nil
+	}
+	return getEmojiFromName(guildID, strings.Trim(input, ":"))
+}
+
+// ParseDuration parses durations like 90s, 10m, 1h30m, 3d, or 2w
+func ParseDuration(input string) (duration time.Duration, err error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	duration, err = time.ParseDuration(input)
+	if err == nil {
+		return duration, nil
+	}
+
+	parts := durationPartsRegex.FindAllStringSubmatch(input, -1)
+	if len(parts) <= 0 || len(durationPartsRegex.ReplaceAllString(input, "")) > 0 {
+		return 0, errors.New("invalid duration")
+	}
+
+	for _, part := range parts {
+		amount, err := strconv.Atoi(part[1])
+		if err != nil {
+			return 0, err
+		}
+		switch part[2] {
+		case "w":
+			duration += time.Duration(amount) * 7 * 24 * time.Hour
+		case "d":
+			duration += time.Duration(amount) * 24 * time.Hour
+		case "h":
+			duration += time.Duration(amount) * time.Hour
+		case "m":
+			duration += time.Duration(amount) * time.Minute
+		case "s":
+			duration += time.Duration(amount) * time.Second
+		}
+	}
+	return duration, nil
+}
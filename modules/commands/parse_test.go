@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	getUserFromMention = func(mention string) (*discordgo.User, error) {
+		switch mention {
+		case "<@1001>", "1001":
+			return &discordgo.User{ID: "1001", Username: "member"}, nil
+		case "<@1002>", "1002":
+			return &discordgo.User{ID: "1002", Username: "stranger"}, nil
+		}
+		return &discordgo.User{}, errors.New("user not found")
+	}
+	getGuildMember = func(guildID string, userID string) (*discordgo.Member, error) {
+		if guildID == "1" && userID == "1001" {
+			return &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}}, nil
+		}
+		return nil, errors.New("member not found")
+	}
+	getChannelFromMention = func(msg *discordgo.Message, mention string) (*discordgo.Channel, error) {
+		if mention == "<#2001>" || mention == "2001" {
+			return &discordgo.Channel{ID: "2001", Type: discordgo.ChannelTypeGuildText}, nil
+		}
+		return nil, errors.New("channel not found")
+	}
+	getGuild = func(guildID string) (*discordgo.Guild, error) {
+		return &discordgo.Guild{
+			ID:     guildID,
+			Roles:  []*discordgo.Role{{ID: "3001", Name: "Moderators"}},
+			Emojis: []*discordgo.Emoji{{ID: "4001", Name: "robyul"}},
+		}, nil
+	}
+	getEmojiFromName = func(guildID string, name string) (*discordgo.Emoji, error) {
+		guild, _ := getGuild(guildID)
+		for _, emoji := range guild.Emojis {
+			if emoji.Name == name {
+				return emoji, nil
+			}
+		}
+		return nil, errors.New("no emoji with the given name found")
+	}
+}
+
+func parseTestInput(arguments []Argument, input string) (*Context, error) {
+	ctx := &Context{
+		Msg:     &discordgo.Message{GuildID: "1", Content: input},
+		GuildID: "1",
+		Command: &Command{Name: "test", Arguments: arguments},
+	}
+	return ctx, parseArguments(ctx, input, tokenize(input))
+}
+
+func TestTokenize(t *testing.T) {
+	tokens := tokenize(`add  "two words" "unclosed quote`)
+	if len(tokens) != 4 {
+		t.Fatal("unexpected amount of tokens", tokens)
+	}
+	if tokens[0].value != "add" || tokens[1].value != "two words" || tokens[2].value != `"unclosed` || tokens[3].value != "quote" {
+		t.Fatal("unexpected tokens", tokens)
+	}
+	if tokens[1].start != 5 {
+		t.Fatal("unexpected token start", tokens[1].start)
+	}
+}
+
+func TestParseString(t *testing.T) {
+	arguments := []Argument{{Name: "word", Type: ArgumentTypeString}}
+	ctx, err := parseTestInput(arguments, "hello")
+	if err != nil || ctx.String("word") != "hello" {
+		t.Fatal("unexpected word", ctx.String("word"), err)
+	}
+	_, err = parseTestInput(arguments, "hello world")
+	if err != errTooManyArguments {
+		t.Fatal("expected too many arguments, got", err)
+	}
+}
+
+func TestParseQuoted(t *testing.T) {
+	arguments := []Argument{{Name: "first", Type: ArgumentTypeQuoted}, {Name: "second", Type: ArgumentTypeQuoted}}
+	ctx, err := parseTestInput(arguments, `"Red Velvet" Psycho`)
+	if err != nil || ctx.String("first") != "Red Velvet" || ctx.String("second") != "Psycho" {
+		t.Fatal("unexpected quoted text", ctx.String("first"), ctx.String("second"), err)
+	}
+}
+
+func TestParseRest(t *testing.T) {
+	arguments := []Argument{{Name: "first", Type: ArgumentTypeString}, {Name: "rest", Type: ArgumentTypeRest}}
+	ctx, err := parseTestInput(arguments, `one  two "three"  four `)
+	if err != nil || ctx.String("first") != "one" || ctx.String("rest") != `two "three"  four` {
+		t.Fatal("unexpected rest", ctx.String("rest"), err)
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	arguments := []Argument{{Name: "amount", Type: ArgumentTypeInt}}
+	ctx, err := parseTestInput(arguments, "42")
+	if err != nil || ctx.Int("amount") != 42 {
+		t.Fatal("unexpected number", ctx.Int("amount"), err)
+	}
+	_, err = parseTestInput(arguments, "many")
+	if errArgument, ok := err.(*argumentError); !ok || errArgument.missing || errArgument.value != "many" {
+		t.Fatal("expected invalid argument, got", err)
+	}
+}
+
+func TestParseUser(t *testing.T) {
+	arguments := []Argument{{Name: "user", Type: ArgumentTypeUser}}
+	ctx, err := parseTestInput(arguments, "<@1002>")
+	if err != nil || ctx.User("user") == nil || ctx.User("user").ID != "1002" {
+		t.Fatal("unexpected user", ctx.User("user"), err)
+	}
+	_, err = parseTestInput(arguments, "nobody")
+	if _, ok := err.(*argumentError); !ok {
+		t.Fatal("expected invalid argument, got", err)
+	}
+}
+
+func TestParseMember(t *testing.T) {
+	arguments := []Argument{{Name: "member", Type: ArgumentTypeMember}}
+	ctx, err := parseTestInput(arguments, "1001")
+	if err != nil || ctx.Member("member") == nil || ctx.Member("member").User.ID != "1001" {
+		t.Fatal("unexpected member", ctx.Member("member"), err)
+	}
+	// users who are not on the server are not members
+	_, err = parseTestInput(arguments, "<@1002>")
+	if _, ok := err.(*argumentError); !ok {
+		t.Fatal("expected invalid argument, got", err)
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	arguments := []Argument{{Name: "channel", Type: ArgumentTypeChannel}}
+	ctx, err := parseTestInput(arguments, "<#2001>")
+	if err != nil || ctx.Channel("channel") == nil || ctx.Channel("channel").ID != "2001" {
+		t.Fatal("unexpected channel", ctx.Channel("channel"), err)
+	}
+	_, err = parseTestInput(arguments, "<#2002>")
+	if _, ok := err.(*argumentError); !ok {
+		t.Fatal("expected invalid argument, got", err)
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	arguments := []Argument{{Name: "role", Type: ArgumentTypeRole}}
+	for _, input := range []string{"<@&3001>", "3001", "moderators"} {
+		ctx, err := parseTestInput(arguments, input)
+		if err != nil || ctx.Role("role") == nil || ctx.Role("role").ID != "3001" {
+			t.Fatal("unexpected role for", input, ctx.Role("role"), err)
+		}
+	}
+	_, err := parseTestInput(arguments, "admins")
+	if _, ok := err.(*argumentError); !ok {
+		t.Fatal("expected invalid argument, got", err)
+	}
+}
+
+func TestParseDurationArgument(t *testing.T) {
+	arguments := []Argument{{Name: "duration", Type: ArgumentTypeDuration, Optional: true}}
+	ctx, err := parseTestInput(arguments, "1h30m")
+	if err != nil || ctx.Duration("duration") != 90*time.Minute {
+		t.Fatal("unexpected duration", ctx.Duration("duration"), err)
+	}
+	ctx, err = parseTestInput(arguments, "")
+	if err != nil || ctx.Has("duration") {
+		t.Fatal("expected no duration", ctx.Duration("duration"), err)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		duration time.Duration
+		ok       bool
+	}{
+		{input: "90s", duration: 90 * time.Second, ok: true},
+		{input: "10m", duration: 10 * time.Minute, ok: true},
+		{input: "3d", duration: 3 * 24 * time.Hour, ok: true},
+		{input: "2w1d", duration: 15 * 24 * time.Hour, ok: true},
+		{input: "1D12H", duration: 36 * time.Hour, ok: true},
+		{input: "3 days"},
+		{input: "soon"},
+		{input: ""},
+	}
+	for _, test := range tests {
+		duration, err := ParseDuration(test.input)
+		if test.ok != (err == nil) || duration != test.duration {
+			t.Fatal("unexpected duration for", test.input, duration, err)
+		}
+	}
+}
+
+func TestParseEmoji(t *testing.T) {
+	arguments := []Argument{{Name: "emoji", Type: ArgumentTypeEmoji}}
+	ctx, err := parseTestInput(arguments, "<a:dance:4002>")
+	if err != nil || ctx.Emoji("emoji") == nil || ctx.Emoji("emoji").ID != "4002" || !ctx.Emoji("emoji").Animated {
+		t.Fatal("unexpected custom emoji", ctx.Emoji("emoji"), err)
+	}
+	ctx, err = parseTestInput(arguments, "😀")
+	if err != nil || ctx.Emoji("emoji") == nil || ctx.Emoji("emoji").Name != "😀" || ctx.Emoji("emoji").ID != "" {
+		t.Fatal("unexpected unicode emoji", ctx.Emoji("emoji"), err)
+	}
+	ctx, err = parseTestInput(arguments, ":robyul:")
+	if err != nil || ctx.Emoji("emoji") == nil || ctx.Emoji("emoji").ID != "4001" {
+		t.Fatal("unexpected emoji by name", ctx.Emoji("emoji"), err)
+	}
+	_, err = parseTestInput(arguments, "unknown")
+	if _, ok := err.(*argumentError); !ok {
+		t.Fatal("expected invalid argument, got", err)
+	}
+}
+
+func TestParseMissingArgument(t *testing.T) {
+	arguments := []Argument{{Name: "first", Type: ArgumentTypeString}, {Name: "second", Type: ArgumentTypeInt}}
+	_, err := parseTestInput(arguments, "one")
+	if errArgument, ok := err.(*argumentError); !ok || !errArgument.missing || errArgument.argument.Name != "second" {
+		t.Fatal("expected missing argument, got", err)
+	}
+}
@@ -0,0 +1,43 @@
+package modules
+
+import (
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/ratelimits"
+	"github.com/bwmarrin/discordgo"
+)
+
+// declareCooldowns registers the cooldowns of a plugin for their commands and aliases
+func declareCooldowns(plugin interface{}) {
+	cooldownPlugin, ok := plugin.(CooldownPlugin)
+	if !ok {
+		return
+	}
+
+	for _, cooldown := range cooldownPlugin.Cooldowns() {
+		cooldowns[cooldown.Command] = cooldown
+		for _, alias := range cooldown.Aliases {
+			cooldowns[alias] = cooldown
+		}
+	}
+}
+
+// GetCooldown returns the declared cooldown of a command
+// Commands without a declared cooldown get a per user cooldown without duration, guild admins can set one
+func GetCooldown(command string) ratelimits.Cooldown {
+	if cooldown, ok := cooldowns[command]; ok {
+		return cooldown
+	}
+	return ratelimits.Cooldown{
+		Command: command,
+		Scope:   ratelimits.CooldownScopeUser,
+	}
+}
+
+// takeCooldown starts the cooldown of the command, returns false if the cooldown is still running
+func takeCooldown(command string, msg *discordgo.Message) bool {
+	cooldown := GetCooldown(command)
+	if cooldown.Manual {
+		return true
+	}
+	return helpers.TakeCooldown(cooldown, command, msg)
+}
@@ -1,6 +1,8 @@
 package modules
 
 import (
+	"github.com/Seklfreak/Robyul2/modules/commands"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
 )
@@ -22,6 +24,22 @@ type Plugin interface {
 	)
 }
 
+// DeclarativePlugin is a Plugin or ExtendedPlugin which declares its commands with typed arguments
+// Declared commands are handled by the commands framework, Action is only called for commands which are not declared
+type DeclarativePlugin interface {
+	BaseModule
+
+	DeclareCommands() []*commands.Command
+}
+
+// CooldownPlugin is a Plugin or ExtendedPlugin which limits how often its commands may be used
+// Guild admins can override the durations with config set cooldown
+type CooldownPlugin interface {
+	BaseModule
+
+	Cooldowns() []ratelimits.Cooldown
+}
+
 type ExtendedPlugin interface {
 	BaseModule
 
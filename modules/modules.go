@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"github.com/Seklfreak/Robyul2/modules/commands"
 	"github.com/Seklfreak/Robyul2/modules/plugins"
 	"github.com/Seklfreak/Robyul2/modules/plugins/biasgame"
 	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
@@ -9,18 +10,20 @@ import (
 	"github.com/Seklfreak/Robyul2/modules/plugins/notifications"
 	"github.com/Seklfreak/Robyul2/modules/plugins/nugugame"
 	"github.com/Seklfreak/Robyul2/modules/plugins/youtube"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 )
 
 var (
 	pluginCache         map[string]*Plugin
 	extendedPluginCache map[string]*ExtendedPlugin
+	declaredCommands    map[string]*commands.Command
+	cooldowns           map[string]ratelimits.Cooldown
 
 	PluginList = []Plugin{
 		&notifications.Handler{},
 		&plugins.About{},
 		&plugins.Stats{},
 		&plugins.Uptime{},
-		&plugins.Translator{},
 		&plugins.UrbanDict{},
 		&plugins.Weather{},
 		&plugins.VLive{}, // Mongo performance
@@ -89,6 +92,7 @@ var (
 		&biasgame.Module{},
 		&nugugame.Module{},
 		&idols.Module{},
+		&plugins.Translator{},
 
 		// &plugins.Twitter{},
 		// &eventlog.Handler{},
@@ -327,12 +327,10 @@ func (a *AutoRoles) Action(command string, content string, msg *discordgo.Messag
 				}
 
 				users := make([]string, 0)
-				for _, botGuild := range session.State.Guilds {
-					if botGuild.ID == channel.GuildID {
-						for _, member := range botGuild.Members {
-							users = append(users, member.User.ID)
-						}
-					}
+				stateGuild, err := helpers.GetGuildWithoutApi(channel.GuildID)
+				helpers.Relax(err)
+				for _, member := range stateGuild.Members {
+					users = append(users, member.User.ID)
 				}
 
 				if helpers.ConfirmEmbed(msg.GuildID, msg.ChannelID, msg.Author, helpers.GetTextF("plugins.autorole.apply-confirm",
@@ -166,6 +166,15 @@ func checkPermissionError(err error, channelID string) bool {
 //   if they do it will return the image at the given index
 //   if not it will return a random image
 func getSemiRandomIdolImage(idol *idols.Idol, gameImageIndex *map[string]int) image.Image {
+	imageIndex := getSemiRandomIdolImageIndex(idol, gameImageIndex)
+
+	img, _, err := image.Decode(bytes.NewReader(idol.Images[imageIndex].GetResizeImgBytes(IMAGE_RESIZE_HEIGHT)))
+	helpers.Relax(err)
+	return img
+}
+
+// getSemiRandomIdolImageIndex returns the index of the image of the idol used in the game, a random image is chosen the first time
+func getSemiRandomIdolImageIndex(idol *idols.Idol, gameImageIndex *map[string]int) int {
 	var imageIndex int
 
 	// check if a random image for the idol has already been chosen for this game
@@ -176,8 +185,5 @@ func getSemiRandomIdolImage(idol *idols.Idol, gameImageIndex *map[string]int) im
 		imageIndex = rand.Intn(len(idol.Images))
 		(*gameImageIndex)[idol.NameAndGroup] = imageIndex
 	}
-
-	img, _, err := image.Decode(bytes.NewReader(idol.Images[imageIndex].GetResizeImgBytes(IMAGE_RESIZE_HEIGHT)))
-	helpers.Relax(err)
-	return img
+	return imageIndex
 }
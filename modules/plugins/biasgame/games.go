@@ -18,6 +18,8 @@ import (
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/nfnt/resize"
@@ -46,6 +48,10 @@ var currentMultiPlayerGamesMutex sync.RWMutex
 var allowedGameSizes map[int]bool
 var allowedMultiGameSizes map[int]bool
 
+// custom pools can be smaller, for example a single group
+var allowedPoolGameSizes map[int]bool
+var allowedMultiPoolGameSizes map[int]bool
+
 // top 8 bracket
 var bracketImageOffsets map[int]image.Point
 var bracketImageResizeMap map[int]uint
@@ -76,8 +82,10 @@ func createOrGetSinglePlayerGame(msg *discordgo.Message, commandArgs []string) *
 		singleGame = game
 	} else {
 		var biasChoices []*idols.Idol
+		var gamePool *models.BiasGamePoolEntry
 		gameGender := "mixed"
 		gameSize := 32
+		requestedGameSize := 0
 
 		// validate game arguments
 		if len(commandArgs) > 0 {
@@ -89,17 +97,16 @@ func createOrGetSinglePlayerGame(msg *discordgo.Message, commandArgs []string) *
 					continue
 				}
 
-				// game size check
-				if requestedGameSize, err := strconv.Atoi(arg); err == nil {
-					if _, ok := allowedGameSizes[requestedGameSize]; ok == true {
-
-						gameSize = requestedGameSize
-						continue
-					} else {
+				// game size check, the size is validated once it is known if this is a pool game
+				if size, err := strconv.Atoi(arg); err == nil {
+					requestedGameSize = size
+					continue
+				}
 
-						helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.invalid-game-size"))
-						return nil
-					}
+				// custom pool check
+				if pool, found := findBiasGamePool(msg, arg); found {
+					gamePool = &pool
+					continue
 				}
 
 				// if a arg was passed that didn't match any check, send invalid args message
@@ -108,20 +115,30 @@ func createOrGetSinglePlayerGame(msg *discordgo.Message, commandArgs []string) *
 			}
 		}
 
+		if requestedGameSize != 0 {
+			if gamePool != nil && !allowedPoolGameSizes[requestedGameSize] {
+				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.invalid-game-size-pool"))
+				return nil
+			} else if gamePool == nil && !allowedGameSizes[requestedGameSize] {
+				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.invalid-game-size"))
+				return nil
+			}
+			gameSize = requestedGameSize
+		}
+
 		// if this isn't a mixed game then filter all choices by the gender
-		if gameGender != "mixed" {
+		biasChoices = filterIdolsByGender(idols.GetActiveIdols(), gameGender)
+		if gamePool != nil {
+			biasChoices = filterIdolsByGender(getPoolIdols(*gamePool), gameGender)
 
-			for _, bias := range idols.GetActiveIdols() {
-				if bias.Gender == gameGender {
-					biasChoices = append(biasChoices, bias)
-				}
+			// pools without a size use the largest size that fits
+			if requestedGameSize == 0 {
+				gameSize = getPoolGameSize(len(biasChoices), allowedPoolGameSizes)
 			}
-		} else {
-			biasChoices = idols.GetActiveIdols()
 		}
 
 		// confirm we have enough biases to choose from for the game size this should be
-		if len(biasChoices) < gameSize {
+		if gameSize == 0 || len(biasChoices) < gameSize {
 			helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.not-enough-idols"))
 			return nil
 		}
@@ -141,6 +158,11 @@ func createOrGetSinglePlayerGame(msg *discordgo.Message, commandArgs []string) *
 			}
 		}
 
+		// only one game per user at a time
+		if !helpers.TakeCooldown(singleGameCooldown, "biasgame", msg) {
+			return nil
+		}
+
 		// create new game
 		singleGame = &singleBiasGame{
 			User:             msg.Author,
@@ -150,6 +172,9 @@ func createOrGetSinglePlayerGame(msg *discordgo.Message, commandArgs []string) *
 			Gender:           gameGender,
 			GuildID:          msg.GuildID,
 		}
+		if gamePool != nil {
+			singleGame.Pool = gamePool.Name
+		}
 		singleGame.GameImageIndex = make(map[string]int)
 
 		// get random biases for the game
@@ -203,6 +228,7 @@ func (g *singleBiasGame) processVote(reaction *discordgo.MessageReactionAdd) {
 			// record winners and losers for stats
 			g.RoundLosers = append(g.RoundLosers, g.BiasQueue[loserIndex])
 			g.RoundWinners = append(g.RoundWinners, g.BiasQueue[winnerIndex])
+			go recordVote(g.GuildID, g.BiasQueue[winnerIndex], g.BiasQueue[loserIndex])
 
 			// add winner to end of bias queue and remove first two
 			g.BiasQueue = append(g.BiasQueue, g.BiasQueue[winnerIndex])
@@ -359,6 +385,11 @@ func (g *singleBiasGame) finishSingleGame() {
 	g.GameWinnerBias = g.BiasQueue[0]
 	g.sendWinnerMessage()
 
+	// pool games are tournaments, show the whole bracket
+	if g.Pool != "" {
+		sendTournamentTree(g.ChannelID, g.Pool, g.RoundWinners, g.RoundLosers, &g.GameImageIndex)
+	}
+
 	// record game stats
 	go func(g *singleBiasGame) {
 		defer helpers.Recover()
@@ -392,6 +423,9 @@ func (g *singleBiasGame) deleteGame() {
 	defer currentSinglePlayerGamesMutex.Unlock()
 
 	delete(currentSinglePlayerGames, g.User.ID)
+
+	err := ratelimits.ResetCooldown(singleGameCooldown, g.GuildID, g.ChannelID, g.User.ID)
+	helpers.RelaxLog(err)
 }
 
 /////////////////////////////////
@@ -415,8 +449,10 @@ func startMultiPlayerGame(msg *discordgo.Message, commandArgs []string) {
 	}
 
 	commandArgs = commandArgs[1:]
+	var gamePool *models.BiasGamePoolEntry
 	gameGender := "mixed"
 	multiGameSize := 32
+	requestedGameSize := 0
 
 	// validate multi game options
 	if len(commandArgs) > 0 {
@@ -429,17 +465,16 @@ func startMultiPlayerGame(msg *discordgo.Message, commandArgs []string) {
 				continue
 			}
 
-			// game size check
-			if requestedGameSize, err := strconv.Atoi(arg); err == nil {
-				if _, ok := allowedMultiGameSizes[requestedGameSize]; ok == true {
-
-					multiGameSize = requestedGameSize
-					continue
-				} else {
+			// game size check, the size is validated once it is known if this is a pool game
+			if size, err := strconv.Atoi(arg); err == nil {
+				requestedGameSize = size
+				continue
+			}
 
-					helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.invalid-game-size-multi"))
-					return
-				}
+			// custom pool check
+			if pool, found := findBiasGamePool(msg, arg); found {
+				gamePool = &pool
+				continue
 			}
 
 			// if a arg was passed that didn't match any check, send invalid args message
@@ -448,22 +483,30 @@ func startMultiPlayerGame(msg *discordgo.Message, commandArgs []string) {
 		}
 	}
 
-	var biasChoices []*idols.Idol
+	if requestedGameSize != 0 {
+		if gamePool != nil && !allowedMultiPoolGameSizes[requestedGameSize] {
+			helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.invalid-game-size-multi-pool"))
+			return
+		} else if gamePool == nil && !allowedMultiGameSizes[requestedGameSize] {
+			helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.invalid-game-size-multi"))
+			return
+		}
+		multiGameSize = requestedGameSize
+	}
 
 	// if this isn't a mixed game then filter all choices by the gender
-	if gameGender != "mixed" {
+	biasChoices := filterIdolsByGender(idols.GetActiveIdols(), gameGender)
+	if gamePool != nil {
+		biasChoices = filterIdolsByGender(getPoolIdols(*gamePool), gameGender)
 
-		for _, bias := range idols.GetActiveIdols() {
-			if bias.Gender == gameGender {
-				biasChoices = append(biasChoices, bias)
-			}
+		// pools without a size use the largest size that fits
+		if requestedGameSize == 0 {
+			multiGameSize = getPoolGameSize(len(biasChoices), allowedMultiPoolGameSizes)
 		}
-	} else {
-		biasChoices = idols.GetActiveIdols()
 	}
 
 	// confirm we have enough biases for a multiplayer game
-	if len(biasChoices) < multiGameSize {
+	if multiGameSize == 0 || len(biasChoices) < multiGameSize {
 		helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.game.not-enough-idols"))
 		return
 	}
@@ -477,6 +520,9 @@ func startMultiPlayerGame(msg *discordgo.Message, commandArgs []string) {
 		GameIsRunning:  true,
 		guildID:        msg.GuildID,
 	}
+	if gamePool != nil {
+		multiGame.Pool = gamePool.Name
+	}
 	multiGame.GameImageIndex = make(map[string]int)
 
 	// get random biases for the game
@@ -652,6 +698,7 @@ func (g *multiBiasGame) processMultiGame() {
 		// record winners and losers for stats
 		g.RoundLosers = append(g.RoundLosers, g.BiasQueue[loserIndex])
 		g.RoundWinners = append(g.RoundWinners, g.BiasQueue[winnerIndex])
+		go recordVote(g.guildID, g.BiasQueue[winnerIndex], g.BiasQueue[loserIndex])
 
 		// add winner to end of bias queue and remove first two
 		g.BiasQueue = append(g.BiasQueue, g.BiasQueue[winnerIndex])
@@ -666,6 +713,11 @@ func (g *multiBiasGame) processMultiGame() {
 	g.GameWinnerBias = g.BiasQueue[0]
 	g.sendWinnerMessage()
 
+	// pool games are tournaments, show the whole bracket
+	if g.Pool != "" {
+		sendTournamentTree(g.ChannelID, g.Pool, g.RoundWinners, g.RoundLosers, &g.GameImageIndex)
+	}
+
 	// record game stats
 	go func(g *multiBiasGame) {
 		defer helpers.Recover()
@@ -883,7 +935,7 @@ func loadMiscImages() {
 		"crown.png",
 	}
 
-	miscImagesFolderPath := helpers.GetConfig().Path("assets_folder").Data().(string) + "biasgame/misc/"
+	miscImagesFolderPath := helpers.GetConfig().AssetsFolder + "biasgame/misc/"
 
 	// load misc images
 	for _, fileName := range validMiscImages {
@@ -937,3 +989,18 @@ func MakeVSImage(img1, img2 image.Image) image.Image {
 	img1 = helpers.CombineTwoImages(img1, versesImage)
 	return helpers.CombineTwoImages(img1, img2)
 }
+
+// filterIdolsByGender returns the idols of the gender, or all idols for mixed games
+func filterIdolsByGender(allIdols []*idols.Idol, gender string) []*idols.Idol {
+	if gender == "mixed" {
+		return allIdols
+	}
+
+	var genderIdols []*idols.Idol
+	for _, idol := range allIdols {
+		if idol.Gender == gender {
+			genderIdols = append(genderIdols, idol)
+		}
+	}
+	return genderIdols
+}
@@ -6,10 +6,15 @@ import (
 	"image"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo"
 )
 
 // module struct
@@ -17,6 +22,16 @@ type Module struct{}
 
 var gameGenders map[string]string
 
+// singleGameCooldown allows one singleplayer game per user at a time, across all processes
+// the duration is the time after which an abandoned game no longer blocks new games
+var singleGameCooldown = ratelimits.Cooldown{
+	Command:  "biasgame",
+	Aliases:  []string{"bg"},
+	Scope:    ratelimits.CooldownScopeGlobalUser,
+	Duration: 1 * time.Hour,
+	Manual:   true,
+}
+
 // used to stop commands from going through
 //  before the game is ready after a bot restart
 var moduleIsReady = false
@@ -39,6 +54,20 @@ func (m *Module) Init(session *shardmanager.Manager) {
 			32: true,
 			64: true,
 		}
+		allowedPoolGameSizes = map[int]bool{
+			8:  true,
+			16: true,
+		}
+		for size := range allowedGameSizes {
+			allowedPoolGameSizes[size] = true
+		}
+		allowedMultiPoolGameSizes = map[int]bool{
+			8:  true,
+			16: true,
+		}
+		for size := range allowedMultiGameSizes {
+			allowedMultiPoolGameSizes[size] = true
+		}
 		// allow games with the size of 10 in debug mode
 		if helpers.DEBUG_MODE {
 			allowedGameSizes[10] = true
@@ -79,11 +108,18 @@ func (m *Module) Init(session *shardmanager.Manager) {
 			11: 60, 10: 60, 9: 60, 8: 60,
 		}
 
+		// server ratings are upserted, every idol has one rating entry per server
+		err := helpers.MdbCollection(models.BiasGameRatingsTable).EnsureIndex(mgo.Index{Key: []string{"guildid", "idolid"}, Unique: true})
+		helpers.RelaxLog(err)
+
 		// load all images and information
 		loadMiscImages()
 
 		startCacheRefreshLoop()
 
+		// refresh the game stats of the idols when idols are edited, merged or deleted
+		idols.OnIdolsChanged(scheduleIdolStatsRefresh)
+
 		// get any in progress games saved in cache and immediatly delete them
 		currentSinglePlayerGamesMutex.Lock()
 		getBiasGameCache("currentSinglePlayerGames", &currentSinglePlayerGames)
@@ -129,6 +165,11 @@ func (m *Module) Commands() []string {
 	}
 }
 
+// Cooldowns returns the cooldown of singleplayer games, it is taken when a game starts and reset when it ends
+func (m *Module) Cooldowns() []ratelimits.Cooldown {
+	return []ratelimits.Cooldown{singleGameCooldown}
+}
+
 // Main Entry point for the plugin
 func (m *Module) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
 	if !helpers.ModuleIsAllowed(msg.ChannelID, msg.ID, msg.Author.ID, helpers.ModulePermGames) {
@@ -165,6 +206,12 @@ func (m *Module) Action(command string, content string, msg *discordgo.Message,
 			displayGroupStats(msg, content)
 		} else if commandArgs[0] == "idol-stats" {
 			displayIdolStats(msg, content)
+		} else if isCommandAlias(commandArgs[0], "idol-ratings") {
+
+			showIdolRatings(msg, commandArgs)
+		} else if isCommandAlias(commandArgs[0], "rating-history") {
+
+			displayIdolRatingHistory(msg, content)
 		} else if commandArgs[0] == "stats" {
 
 			// stats
@@ -188,6 +235,11 @@ func (m *Module) Action(command string, content string, msg *discordgo.Message,
 			helpers.RequireRobyulMod(msg, func() {
 				runGameMigration(msg, content)
 			})
+		} else if commandArgs[0] == "migrate-ratings" {
+
+			helpers.RequireRobyulMod(msg, func() {
+				runRatingsMigration(msg)
+			})
 		} else if commandArgs[0] == "update-stats" {
 
 			helpers.RequireRobyulMod(msg, func() {
@@ -210,6 +262,15 @@ func (m *Module) Action(command string, content string, msg *discordgo.Message,
 
 			singleGame := createOrGetSinglePlayerGame(msg, commandArgs)
 			singleGame.sendBiasGameRound()
+
+		} else if isCommandAlias(commandArgs[0], "pool") {
+
+			poolCommand(msg, content)
+
+		} else if _, found := findBiasGamePool(msg, commandArgs[0]); found {
+
+			singleGame := createOrGetSinglePlayerGame(msg, commandArgs)
+			singleGame.sendBiasGameRound()
 		}
 	}
 }
@@ -246,6 +307,16 @@ func isCommandAlias(input, targetCommand string) bool {
 		"multi":       "multi",
 		"multiplayer": "multi",
 
+		"pool":  "pool",
+		"pools": "pool",
+
+		"idol-ratings": "idol-ratings",
+		"idol-rating":  "idol-ratings",
+		"ratings":      "idol-ratings",
+
+		"rating-history": "rating-history",
+		"idol-history":   "rating-history",
+
 		"server-rankings": "server-rankings",
 		"server-ranking":  "server-rankings",
 		"server-ranks":    "server-rankings",
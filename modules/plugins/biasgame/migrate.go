@@ -3,11 +3,13 @@ package biasgame
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 	"github.com/bwmarrin/discordgo"
 	"github.com/globalsign/mgo/bson"
 )
@@ -101,6 +103,7 @@ func runGameMigration(msg *discordgo.Message, content string) {
 	currentSinglePlayerGamesMutex.Lock()
 	for k := range currentSinglePlayerGames {
 		delete(currentSinglePlayerGames, k)
+		ratelimits.ResetCooldown(singleGameCooldown, "", "", k)
 	}
 	currentSinglePlayerGamesMutex.Unlock()
 	currentMultiPlayerGamesMutex.Lock()
@@ -178,3 +181,114 @@ GameLoop:
 
 	return gamesWithoutExistingWinnerAr, len(affectedGames)
 }
+
+// ratingHistoryKey identifies the rating of an idol on a day while replaying votes
+type ratingHistoryKey struct {
+	idolID  bson.ObjectId
+	guildID string
+	day     time.Time
+}
+
+// runRatingsMigration replays the votes of all recorded games in the order they were played to seed the idol ratings.
+// This includes the games migrated from the old biasgame table. Existing ratings and the rating history are replaced,
+// votes of this process wait for the migration, votes of other processes made while it is running are lost.
+func runRatingsMigration(msg *discordgo.Message) {
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	helpers.SendMessage(msg.ChannelID, "Replaying all bias game votes...")
+
+	globalRatings := make(ratingReplay)
+	serverRatings := make(map[string]ratingReplay)
+	ratingHistory := make(map[ratingHistoryKey]float64)
+
+	var gamesReplayed, votesReplayed int
+	var game models.BiasGameEntry
+	iter := helpers.MDbIter(helpers.MdbCollection(models.BiasGameTable).Find(bson.M{}).Sort("_id"))
+	for iter.Next(&game) {
+		day := ratingDay(game.ID.Time())
+
+		for i := 0; i < len(game.RoundWinners) && i < len(game.RoundLosers); i++ {
+			winnerID, loserID := game.RoundWinners[i], game.RoundLosers[i]
+
+			winnerRating, loserRating := globalRatings.vote(winnerID, loserID)
+			ratingHistory[ratingHistoryKey{idolID: winnerID, day: day}] = winnerRating
+			ratingHistory[ratingHistoryKey{idolID: loserID, day: day}] = loserRating
+
+			if game.GuildID != "" {
+				if _, ok := serverRatings[game.GuildID]; !ok {
+					serverRatings[game.GuildID] = make(ratingReplay)
+				}
+				winnerRating, loserRating = serverRatings[game.GuildID].vote(winnerID, loserID)
+				ratingHistory[ratingHistoryKey{idolID: winnerID, guildID: game.GuildID, day: day}] = winnerRating
+				ratingHistory[ratingHistoryKey{idolID: loserID, guildID: game.GuildID, day: day}] = loserRating
+			}
+			votesReplayed++
+		}
+		gamesReplayed++
+
+		game = models.BiasGameEntry{}
+	}
+	helpers.Relax(iter.Close())
+
+	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Games replayed: %d\nVotes replayed: %d\nIdols rated: %d\nSaving ratings...", gamesReplayed, votesReplayed, len(globalRatings)))
+
+	// global ratings are stored on the idol records
+	_, err := helpers.MdbCollection(models.IdolTable).UpdateAll(bson.M{}, bson.M{"$set": bson.M{"bgrating": 0, "bgratingvotes": 0}})
+	helpers.Relax(err)
+	for idolID, rating := range globalRatings {
+		err = helpers.MdbCollection(models.IdolTable).UpdateId(idolID, bson.M{"$set": bson.M{"bgrating": rating.Rating, "bgratingvotes": rating.Votes}})
+		// idols that have been deleted since are skipped
+		if err != nil && !helpers.IsMdbNotFound(err) {
+			helpers.Relax(err)
+		}
+	}
+	for _, idol := range idols.GetAllIdols() {
+		if rating, ok := globalRatings[idol.ID]; ok {
+			idols.SetIdolRating(idol, rating.Rating, rating.Votes)
+		} else {
+			idols.SetIdolRating(idol, 0, 0)
+		}
+	}
+
+	var ratingEntries []interface{}
+	for guildID, ratings := range serverRatings {
+		for idolID, rating := range ratings {
+			ratingEntries = append(ratingEntries, models.BiasGameRatingEntry{
+				IdolID:  idolID,
+				GuildID: guildID,
+				Rating:  rating.Rating,
+				Votes:   rating.Votes,
+			})
+		}
+	}
+	_, err = helpers.MdbCollection(models.BiasGameRatingsTable).RemoveAll(bson.M{})
+	helpers.Relax(err)
+	if len(ratingEntries) > 0 {
+		bulkOperation := helpers.MdbCollection(models.BiasGameRatingsTable).Bulk()
+		bulkOperation.Insert(ratingEntries...)
+		_, err = bulkOperation.Run()
+		helpers.Relax(err)
+	}
+
+	var historyEntries []interface{}
+	for key, rating := range ratingHistory {
+		historyEntries = append(historyEntries, models.BiasGameRatingHistoryEntry{
+			IdolID:  key.idolID,
+			GuildID: key.guildID,
+			Day:     key.day,
+			Rating:  rating,
+		})
+	}
+	_, err = helpers.MdbCollection(models.BiasGameRatingHistoryTable).RemoveAll(bson.M{})
+	helpers.Relax(err)
+	if len(historyEntries) > 0 {
+		bulkOperation := helpers.MdbCollection(models.BiasGameRatingHistoryTable).Bulk()
+		bulkOperation.Insert(historyEntries...)
+		_, err = bulkOperation.Run()
+		helpers.Relax(err)
+	}
+
+	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Server ratings: %d\nRating history entries: %d", len(ratingEntries), len(historyEntries)))
+	helpers.SendMessage(msg.ChannelID, "Done.")
+}
@@ -0,0 +1,483 @@
+package biasgame
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// POOL_LIMIT is the most pools a user or a server can have
+	POOL_LIMIT = 25
+	// POOL_DEFAULT_GAME_SIZE is the game size of pool games without a size, smaller pools use the largest size that fits
+	POOL_DEFAULT_GAME_SIZE = 32
+)
+
+var poolNameRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// reservedPoolNames are biasgame commands and options, pools with these names could not be played
+var reservedPoolNames = map[string]bool{
+	"idols": true, "images": true, "suggest": true, "stats": true, "group-stats": true, "idol-stats": true,
+	"rankings": true, "ranking": true, "rank": true, "ranks": true,
+	"server-rankings": true, "server-ranking": true, "server-ranks": true, "server-rank": true,
+	"idol-ratings": true, "idol-rating": true, "ratings": true, "rating-history": true, "idol-history": true,
+	"validate-stats": true, "migrate-games": true, "migrate-ratings": true, "update-stats": true,
+	"current": true, "cur": true, "multi": true, "multiplayer": true, "pool": true, "pools": true,
+	"server": true, "single": true,
+}
+
+// isValidPoolName checks if a name can be used for a pool
+func isValidPoolName(name string) bool {
+	if !poolNameRegex.MatchString(name) || reservedPoolNames[name] {
+		return false
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		return false
+	}
+	if _, ok := gameGenders[name]; ok {
+		return false
+	}
+	return true
+}
+
+// findBiasGamePool returns the pool with the name, personal pools of the user are found before pools of the server
+func findBiasGamePool(msg *discordgo.Message, name string) (pool models.BiasGamePoolEntry, found bool) {
+	name = strings.ToLower(name)
+	if !isValidPoolName(name) {
+		return pool, false
+	}
+
+	queries := []bson.M{{"name": name, "userid": msg.Author.ID}}
+	if msg.GuildID != "" {
+		queries = append(queries, bson.M{"name": name, "guildid": msg.GuildID})
+	}
+	for _, query := range queries {
+		err := helpers.MdbOne(helpers.MdbCollection(models.BiasGamePoolsTable).Find(query), &pool)
+		if err == nil {
+			return pool, true
+		}
+		if !helpers.IsMdbNotFound(err) {
+			helpers.Relax(err)
+		}
+	}
+	return pool, false
+}
+
+// getVisiblePools returns the personal pools of the user and the pools of the server
+func getVisiblePools(msg *discordgo.Message) (pools []models.BiasGamePoolEntry) {
+	query := bson.M{"userid": msg.Author.ID}
+	if msg.GuildID != "" {
+		query = bson.M{"$or": []bson.M{{"userid": msg.Author.ID}, {"guildid": msg.GuildID}}}
+	}
+	err := helpers.MDbIter(helpers.MdbCollection(models.BiasGamePoolsTable).Find(query).Sort("name")).All(&pools)
+	helpers.Relax(err)
+	return pools
+}
+
+// isIdolInPool checks the rules of the pool for an idol, rules for idols take precedence over rules for groups
+func isIdolInPool(pool models.BiasGamePoolEntry, idol *idols.Idol) bool {
+	if containsPoolIdol(pool.ExcludeIdols, idol.ID) {
+		return false
+	}
+	if containsPoolIdol(pool.IncludeIdols, idol.ID) {
+		return true
+	}
+	if containsPoolGroup(pool.ExcludeGroups, idol.GroupName) {
+		return false
+	}
+
+	// without included groups or idols the pool starts with all idols
+	if len(pool.IncludeGroups) == 0 && len(pool.IncludeIdols) == 0 {
+		return true
+	}
+	return containsPoolGroup(pool.IncludeGroups, idol.GroupName)
+}
+
+// getPoolIdols returns the active idols in the pool
+func getPoolIdols(pool models.BiasGamePoolEntry) []*idols.Idol {
+	var poolIdols []*idols.Idol
+	for _, idol := range idols.GetActiveIdols() {
+		if isIdolInPool(pool, idol) {
+			poolIdols = append(poolIdols, idol)
+		}
+	}
+	return poolIdols
+}
+
+// getPoolGameSize returns the largest allowed game size for the amount of idols, up to the default size of pool games,
+// or 0 if there are not enough idols for any game
+func getPoolGameSize(amountOfIdols int, allowedSizes map[int]bool) int {
+	var gameSize int
+	for size := range allowedSizes {
+		if size <= POOL_DEFAULT_GAME_SIZE && size <= amountOfIdols && size > gameSize {
+			gameSize = size
+		}
+	}
+	return gameSize
+}
+
+func containsPoolGroup(groups []string, group string) bool {
+	for _, poolGroup := range groups {
+		if strings.EqualFold(poolGroup, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPoolIdol(idolIDs []bson.ObjectId, idolID bson.ObjectId) bool {
+	for _, poolIdolID := range idolIDs {
+		if poolIdolID == idolID {
+			return true
+		}
+	}
+	return false
+}
+
+func removePoolGroup(groups []string, group string) (result []string, removed bool) {
+	for _, poolGroup := range groups {
+		if strings.EqualFold(poolGroup, group) {
+			removed = true
+			continue
+		}
+		result = append(result, poolGroup)
+	}
+	return result, removed
+}
+
+func removePoolIdol(idolIDs []bson.ObjectId, idolID bson.ObjectId) (result []bson.ObjectId, removed bool) {
+	for _, poolIdolID := range idolIDs {
+		if poolIdolID == idolID {
+			removed = true
+			continue
+		}
+		result = append(result, poolIdolID)
+	}
+	return result, removed
+}
+
+// poolCommand handles the pool commands: create <name> [server], include|exclude|remove <name> "group" ["idol"],
+// delete <name>, list, and <name> to show a pool
+func poolCommand(msg *discordgo.Message, content string) {
+	commandArgs, err := helpers.ToArgv(content)
+	if err != nil {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+		return
+	}
+
+	if len(commandArgs) < 2 || commandArgs[0] == "pools" {
+		listPools(msg)
+		return
+	}
+
+	switch strings.ToLower(commandArgs[1]) {
+	case "create", "add", "new":
+		createPool(msg, commandArgs[2:])
+	case "include", "exclude", "remove":
+		updatePoolRule(msg, strings.ToLower(commandArgs[1]), commandArgs[2:])
+	case "delete":
+		deletePool(msg, commandArgs[2:])
+	case "list":
+		listPools(msg)
+	default:
+		showPool(msg, commandArgs[1])
+	}
+}
+
+// createPool creates an empty pool for the user, or for the server if server is given
+func createPool(msg *discordgo.Message, commandArgs []string) {
+	if len(commandArgs) < 1 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+		return
+	}
+
+	name := strings.ToLower(commandArgs[0])
+	if !isValidPoolName(name) {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.pool.invalid-name"))
+		return
+	}
+
+	serverPool := len(commandArgs) >= 2 && strings.ToLower(commandArgs[1]) == "server"
+	if serverPool && (msg.GuildID == "" || !helpers.IsMod(msg)) {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "mod.no_permission"))
+		return
+	}
+
+	if _, found := findBiasGamePool(msg, name); found {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.exists", name))
+		return
+	}
+
+	ownerQuery := bson.M{"userid": msg.Author.ID}
+	if serverPool {
+		ownerQuery = bson.M{"guildid": msg.GuildID}
+	}
+	amountOfPools, err := helpers.MdbCount(models.BiasGamePoolsTable, ownerQuery)
+	helpers.Relax(err)
+	if amountOfPools >= POOL_LIMIT {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.too-many", POOL_LIMIT))
+		return
+	}
+
+	pool := models.BiasGamePoolEntry{
+		Name:            name,
+		CreatedByUserID: msg.Author.ID,
+		CreatedAt:       time.Now(),
+	}
+	if serverPool {
+		pool.GuildID = msg.GuildID
+	} else {
+		pool.UserID = msg.Author.ID
+	}
+
+	newID, err := helpers.MDbInsert(models.BiasGamePoolsTable, pool)
+	helpers.Relax(err)
+
+	if serverPool {
+		_, err = helpers.EventlogLog(time.Now(), msg.GuildID, helpers.MdbIdToHuman(newID),
+			models.EventlogTargetTypeRobyulBiasgamePool, msg.Author.ID,
+			models.EventlogTypeRobyulBiasgamePoolCreate, "",
+			nil,
+			[]models.ElasticEventlogOption{
+				{
+					Key:   "biasgame_pool_name",
+					Value: name,
+				},
+			}, false)
+		helpers.RelaxLog(err)
+	}
+
+	prefix := helpers.GetPrefixForServer(msg.GuildID)
+	helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.created", name, prefix, name, prefix, name, prefix, name))
+}
+
+// getEditablePool returns the pool if the author of the message may change it
+func getEditablePool(msg *discordgo.Message, name string) (pool models.BiasGamePoolEntry, ok bool) {
+	pool, found := findBiasGamePool(msg, name)
+	if !found {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.not-found", strings.ToLower(name)))
+		return pool, false
+	}
+
+	if pool.GuildID != "" && !helpers.IsMod(msg) {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "mod.no_permission"))
+		return pool, false
+	}
+	return pool, true
+}
+
+// updatePoolRule includes or excludes a group or idol, or removes the rule for it
+func updatePoolRule(msg *discordgo.Message, action string, commandArgs []string) {
+	if len(commandArgs) < 2 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+		return
+	}
+
+	pool, ok := getEditablePool(msg, commandArgs[0])
+	if !ok {
+		return
+	}
+
+	// group and idol names are matched with their aliases
+	groupExists, groupName := idols.GetMatchingGroup(commandArgs[1], true)
+	if !groupExists {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.stats.no-matching-group"))
+		return
+	}
+
+	var targetIdol *idols.Idol
+	targetName := groupName
+	if len(commandArgs) >= 3 {
+		_, _, targetIdol = idols.GetMatchingIdolAndGroup(groupName, commandArgs[2], true)
+		if targetIdol == nil {
+			helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.stats.no-matching-idol"))
+			return
+		}
+		targetName = targetIdol.GroupName + " " + targetIdol.Name
+	}
+
+	// a group or idol can only be included or excluded
+	var removedInclude, removedExclude bool
+	if targetIdol != nil {
+		pool.IncludeIdols, removedInclude = removePoolIdol(pool.IncludeIdols, targetIdol.ID)
+		pool.ExcludeIdols, removedExclude = removePoolIdol(pool.ExcludeIdols, targetIdol.ID)
+	} else {
+		pool.IncludeGroups, removedInclude = removePoolGroup(pool.IncludeGroups, groupName)
+		pool.ExcludeGroups, removedExclude = removePoolGroup(pool.ExcludeGroups, groupName)
+	}
+
+	switch action {
+	case "include":
+		if targetIdol != nil {
+			pool.IncludeIdols = append(pool.IncludeIdols, targetIdol.ID)
+		} else {
+			pool.IncludeGroups = append(pool.IncludeGroups, groupName)
+		}
+	case "exclude":
+		if targetIdol != nil {
+			pool.ExcludeIdols = append(pool.ExcludeIdols, targetIdol.ID)
+		} else {
+			pool.ExcludeGroups = append(pool.ExcludeGroups, groupName)
+		}
+	case "remove":
+		if !removedInclude && !removedExclude {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.rule-not-found", targetName, pool.Name))
+			return
+		}
+	}
+
+	err := helpers.MDbUpdate(models.BiasGamePoolsTable, pool.ID, pool)
+	helpers.Relax(err)
+
+	if pool.GuildID != "" {
+		_, err = helpers.EventlogLog(time.Now(), pool.GuildID, helpers.MdbIdToHuman(pool.ID),
+			models.EventlogTargetTypeRobyulBiasgamePool, msg.Author.ID,
+			models.EventlogTypeRobyulBiasgamePoolUpdate, "",
+			nil,
+			[]models.ElasticEventlogOption{
+				{
+					Key:   "biasgame_pool_name",
+					Value: pool.Name,
+				},
+				{
+					Key:   "biasgame_pool_" + action,
+					Value: targetName,
+				},
+			}, false)
+		helpers.RelaxLog(err)
+	}
+
+	helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.rule-"+action, targetName, pool.Name, len(getPoolIdols(pool))))
+}
+
+// deletePool deletes a pool
+func deletePool(msg *discordgo.Message, commandArgs []string) {
+	if len(commandArgs) < 1 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+		return
+	}
+
+	pool, ok := getEditablePool(msg, commandArgs[0])
+	if !ok {
+		return
+	}
+
+	err := helpers.MDbDelete(models.BiasGamePoolsTable, pool.ID)
+	helpers.Relax(err)
+
+	if pool.GuildID != "" {
+		_, err = helpers.EventlogLog(time.Now(), pool.GuildID, helpers.MdbIdToHuman(pool.ID),
+			models.EventlogTargetTypeRobyulBiasgamePool, msg.Author.ID,
+			models.EventlogTypeRobyulBiasgamePoolDelete, "",
+			nil,
+			[]models.ElasticEventlogOption{
+				{
+					Key:   "biasgame_pool_name",
+					Value: pool.Name,
+				},
+			}, false)
+		helpers.RelaxLog(err)
+	}
+
+	helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.deleted", pool.Name))
+}
+
+// listPools lists the pools of the user and the server
+func listPools(msg *discordgo.Message) {
+	pools := getVisiblePools(msg)
+	if len(pools) == 0 {
+		prefix := helpers.GetPrefixForServer(msg.GuildID)
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.none", prefix))
+		return
+	}
+
+	var poolLines []string
+	for _, pool := range pools {
+		owner := helpers.GetText("plugins.biasgame.pool.owner-user")
+		if pool.GuildID != "" {
+			owner = helpers.GetText("plugins.biasgame.pool.owner-server")
+		}
+		poolLines = append(poolLines, helpers.GetTextF("plugins.biasgame.pool.list-line", pool.Name, len(getPoolIdols(pool)), owner))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color: 0x0FADED, // blueish
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    helpers.GetText("plugins.biasgame.pool.list-title"),
+			IconURL: cache.GetSession().SessionForGuildS(msg.GuildID).State.User.AvatarURL("512"),
+		},
+		Description: strings.Join(poolLines, "\n"),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: helpers.GetTextF("plugins.biasgame.pool.list-footer", helpers.GetPrefixForServer(msg.GuildID), helpers.GetPrefixForServer(msg.GuildID)),
+		},
+	}
+	helpers.SendEmbed(msg.ChannelID, embed)
+}
+
+// showPool shows the rules and the amount of idols of a pool
+func showPool(msg *discordgo.Message, name string) {
+	pool, found := findBiasGamePool(msg, name)
+	if !found {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.biasgame.pool.not-found", strings.ToLower(name)))
+		return
+	}
+
+	idolNames := func(idolIDs []bson.ObjectId) []string {
+		var names []string
+		for _, idolID := range idolIDs {
+			if idol := idols.GetMatchingIdolById(idolID); idol != nil {
+				names = append(names, idol.GroupName+" "+idol.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+	ruleField := func(title string, names []string) *discordgo.MessageEmbedField {
+		value := helpers.GetText("plugins.biasgame.pool.rule-none")
+		if len(names) > 0 {
+			value = strings.Join(names, ", ")
+			if len(value) > 1000 {
+				value = value[:1000] + "…"
+			}
+		}
+		return &discordgo.MessageEmbedField{Name: title, Value: value}
+	}
+
+	genderCount := make(map[string]int)
+	poolIdols := getPoolIdols(pool)
+	for _, idol := range poolIdols {
+		genderCount[idol.Gender]++
+	}
+
+	owner := helpers.GetText("plugins.biasgame.pool.owner-user")
+	if pool.GuildID != "" {
+		owner = helpers.GetText("plugins.biasgame.pool.owner-server")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color: 0x0FADED, // blueish
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: helpers.GetTextF("plugins.biasgame.pool.show-title", pool.Name, owner),
+		},
+		Description: helpers.GetTextF("plugins.biasgame.pool.show-description", len(poolIdols), genderCount["girl"], genderCount["boy"]),
+		Fields: []*discordgo.MessageEmbedField{
+			ruleField(helpers.GetText("plugins.biasgame.pool.included-groups"), pool.IncludeGroups),
+			ruleField(helpers.GetText("plugins.biasgame.pool.included-idols"), idolNames(pool.IncludeIdols)),
+			ruleField(helpers.GetText("plugins.biasgame.pool.excluded-groups"), pool.ExcludeGroups),
+			ruleField(helpers.GetText("plugins.biasgame.pool.excluded-idols"), idolNames(pool.ExcludeIdols)),
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: helpers.GetTextF("plugins.biasgame.pool.show-footer", helpers.GetPrefixForServer(msg.GuildID), pool.Name, helpers.GetPrefixForServer(msg.GuildID), pool.Name),
+		},
+	}
+	helpers.SendEmbed(msg.ChannelID, embed)
+}
@@ -0,0 +1,99 @@
+package biasgame
+
+import (
+	"testing"
+
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestIsIdolInPool(t *testing.T) {
+	nayeon := &idols.Idol{ID: bson.NewObjectId(), GroupName: "TWICE", Name: "Nayeon"}
+	jihyo := &idols.Idol{ID: bson.NewObjectId(), GroupName: "TWICE", Name: "Jihyo"}
+	nayoung := &idols.Idol{ID: bson.NewObjectId(), GroupName: "PRISTIN", Name: "Nayoung"}
+	jimin := &idols.Idol{ID: bson.NewObjectId(), GroupName: "BTS", Name: "Jimin"}
+
+	tests := []struct {
+		name     string
+		pool     models.BiasGamePoolEntry
+		expected []*idols.Idol
+	}{
+		{name: "empty pools have all idols", expected: []*idols.Idol{nayeon, jihyo, nayoung, jimin}},
+		{
+			name:     "included group",
+			pool:     models.BiasGamePoolEntry{IncludeGroups: []string{"twice"}},
+			expected: []*idols.Idol{nayeon, jihyo},
+		},
+		{
+			name:     "excluded idol of an included group",
+			pool:     models.BiasGamePoolEntry{IncludeGroups: []string{"TWICE"}, ExcludeIdols: []bson.ObjectId{jihyo.ID}},
+			expected: []*idols.Idol{nayeon},
+		},
+		{
+			name:     "included idol of an excluded group",
+			pool:     models.BiasGamePoolEntry{ExcludeGroups: []string{"TWICE"}, IncludeIdols: []bson.ObjectId{nayeon.ID}},
+			expected: []*idols.Idol{nayeon},
+		},
+		{
+			name:     "excluded group without includes",
+			pool:     models.BiasGamePoolEntry{ExcludeGroups: []string{"BTS"}},
+			expected: []*idols.Idol{nayeon, jihyo, nayoung},
+		},
+	}
+	for _, test := range tests {
+		for _, idol := range []*idols.Idol{nayeon, jihyo, nayoung, jimin} {
+			expected := false
+			for _, expectedIdol := range test.expected {
+				expected = expected || expectedIdol == idol
+			}
+			if isIdolInPool(test.pool, idol) != expected {
+				t.Fatal(test.name, ": expected", idol.GroupName, idol.Name, "in pool to be", expected)
+			}
+		}
+	}
+}
+
+func TestGetPoolGameSize(t *testing.T) {
+	sizes := map[int]bool{8: true, 16: true, 32: true, 64: true}
+	tests := map[int]int{5: 0, 8: 8, 13: 8, 16: 16, 31: 16, 500: POOL_DEFAULT_GAME_SIZE}
+	for amountOfIdols, expected := range tests {
+		if size := getPoolGameSize(amountOfIdols, sizes); size != expected {
+			t.Fatal(amountOfIdols, "idols: expected size", expected, "got", size)
+		}
+	}
+}
+
+func TestGetTournamentTreeRows(t *testing.T) {
+	var entrants []*idols.Idol
+	for i := 0; i < 8; i++ {
+		entrants = append(entrants, &idols.Idol{ID: bson.NewObjectId()})
+	}
+
+	// play a game like the bias queue does, the first idol of each pair wins
+	var winners, losers []*idols.Idol
+	queue := entrants
+	for len(queue) > 1 {
+		winners = append(winners, queue[0])
+		losers = append(losers, queue[1])
+		queue = append(queue[2:], queue[0])
+	}
+
+	rows := getTournamentTreeRows(winners, losers)
+	if len(rows) != 4 || len(rows[0]) != 1 || len(rows[1]) != 2 || len(rows[2]) != 4 || len(rows[3]) != 8 {
+		t.Fatal("unexpected rows", rows)
+	}
+	for i, idol := range rows[3] {
+		if idol != entrants[i] {
+			t.Fatal("unexpected first round at", i)
+		}
+	}
+	if rows[0][0] != entrants[0] || rows[1][1] != entrants[4] || rows[2][3] != entrants[6] {
+		t.Fatal("unexpected winners", rows)
+	}
+
+	// games which are not full tournaments have no tree
+	if getTournamentTreeRows(winners[1:], losers[1:]) != nil {
+		t.Fatal("expected no rows for 7 idols")
+	}
+}
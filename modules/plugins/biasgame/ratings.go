@@ -0,0 +1,181 @@
+package biasgame
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// RATING_BASE is the rating of idols nobody voted on yet
+	RATING_BASE = 1500.0
+	// RATING_K is the most rating points an idol can win or lose with one vote
+	RATING_K = 32.0
+)
+
+// ratingsMutex makes sure the votes of this process are applied one after another
+var ratingsMutex sync.Mutex
+
+// eloDelta returns the rating points the winner of a vote gains and the loser loses
+func eloDelta(winnerRating, loserRating float64) float64 {
+	expectedScore := 1 / (1 + math.Pow(10, (loserRating-winnerRating)/400))
+	return RATING_K * (1 - expectedScore)
+}
+
+// ratingDay returns the day a rating is stored for in the rating history
+func ratingDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// idolRating returns the global rating of an idol
+func idolRating(idol *idols.Idol) float64 {
+	rating, votes := idols.GetIdolRating(idol)
+	if votes <= 0 {
+		return RATING_BASE
+	}
+	return rating
+}
+
+// recordVote updates the global and server ratings of the winner and loser of a round
+func recordVote(guildID string, winner, loser *idols.Idol) {
+	defer helpers.Recover()
+
+	// idols of games restored after a restart are copies, update the idols in memory instead
+	if idol := idols.GetMatchingIdolById(winner.ID); idol != nil {
+		winner = idol
+	}
+	if idol := idols.GetMatchingIdolById(loser.ID); idol != nil {
+		loser = idol
+	}
+
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	delta := eloDelta(idolRating(winner), idolRating(loser))
+	helpers.RelaxLog(updateIdolRating(winner, delta))
+	helpers.RelaxLog(updateIdolRating(loser, -delta))
+
+	if guildID == "" {
+		return
+	}
+
+	winnerRating, err := getServerRating(guildID, winner.ID)
+	helpers.Relax(err)
+	loserRating, err := getServerRating(guildID, loser.ID)
+	helpers.Relax(err)
+
+	delta = eloDelta(winnerRating, loserRating)
+	helpers.RelaxLog(updateServerRating(guildID, winner.ID, delta))
+	helpers.RelaxLog(updateServerRating(guildID, loser.ID, -delta))
+}
+
+// updateIdolRating adds the delta to the global rating on the idol record
+func updateIdolRating(idol *idols.Idol, delta float64) error {
+	if _, votes := idols.GetIdolRating(idol); votes <= 0 {
+		// set the base rating before the first vote, ratings of unrated idols are ignored
+		err := helpers.MdbCollection(models.IdolTable).Update(
+			bson.M{"_id": idol.ID, "bgratingvotes": bson.M{"$in": []interface{}{0, nil}}},
+			bson.M{"$set": bson.M{"bgrating": RATING_BASE}},
+		)
+		if err != nil && !helpers.IsMdbNotFound(err) {
+			return err
+		}
+	}
+
+	// the increment is atomic, votes of other processes at the same time are kept
+	var idolEntry models.IdolEntry
+	_, err := helpers.MdbCollection(models.IdolTable).FindId(idol.ID).Apply(mgo.Change{
+		Update:    bson.M{"$inc": bson.M{"bgrating": delta, "bgratingvotes": 1}},
+		ReturnNew: true,
+	}, &idolEntry)
+	if err != nil {
+		return err
+	}
+
+	idols.SetIdolRating(idol, idolEntry.BGRating, idolEntry.BGRatingVotes)
+
+	return recordRatingHistory(idol.ID, "", idolEntry.BGRating)
+}
+
+// getServerRating returns the rating of an idol on a server
+func getServerRating(guildID string, idolID bson.ObjectId) (float64, error) {
+	var ratingEntry models.BiasGameRatingEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.BiasGameRatingsTable).Find(bson.M{"guildid": guildID, "idolid": idolID}),
+		&ratingEntry,
+	)
+	if helpers.IsMdbNotFound(err) {
+		return RATING_BASE, nil
+	}
+	return ratingEntry.Rating, err
+}
+
+// updateServerRating adds the delta to the rating of an idol on a server
+func updateServerRating(guildID string, idolID bson.ObjectId, delta float64) error {
+	selector := bson.M{"guildid": guildID, "idolid": idolID}
+
+	// the first vote for the idol on this server starts at the base rating
+	_, err := helpers.MdbCollection(models.BiasGameRatingsTable).Upsert(selector, bson.M{
+		"$setOnInsert": bson.M{"rating": RATING_BASE, "votes": 0},
+	})
+	// the unique index rejects the entry if another process inserted it at the same time
+	if err != nil && !mgo.IsDup(err) {
+		return err
+	}
+
+	var ratingEntry models.BiasGameRatingEntry
+	_, err = helpers.MdbCollection(models.BiasGameRatingsTable).Find(selector).Apply(mgo.Change{
+		Update:    bson.M{"$inc": bson.M{"rating": delta, "votes": 1}},
+		ReturnNew: true,
+	}, &ratingEntry)
+	if err != nil {
+		return err
+	}
+
+	return recordRatingHistory(idolID, guildID, ratingEntry.Rating)
+}
+
+// recordRatingHistory stores the rating as rating of the current day
+func recordRatingHistory(idolID bson.ObjectId, guildID string, rating float64) error {
+	return helpers.MDbUpsertWithoutLogging(models.BiasGameRatingHistoryTable,
+		bson.M{"idolid": idolID, "guildid": guildID, "day": ratingDay(time.Now())},
+		bson.M{"$set": bson.M{"rating": rating}},
+	)
+}
+
+// idolRatingState is the rating of an idol while replaying votes
+type idolRatingState struct {
+	Rating float64
+	Votes  int
+}
+
+// ratingReplay computes ratings from votes in the order they happened, without touching the database
+type ratingReplay map[bson.ObjectId]*idolRatingState
+
+// vote applies a vote and returns the new ratings of the winner and loser
+func (r ratingReplay) vote(winnerID, loserID bson.ObjectId) (winnerRating, loserRating float64) {
+	winner, loser := r.state(winnerID), r.state(loserID)
+
+	delta := eloDelta(winner.Rating, loser.Rating)
+	winner.Rating += delta
+	winner.Votes++
+	loser.Rating -= delta
+	loser.Votes++
+
+	return winner.Rating, loser.Rating
+}
+
+func (r ratingReplay) state(idolID bson.ObjectId) *idolRatingState {
+	state, ok := r[idolID]
+	if !ok {
+		state = &idolRatingState{Rating: RATING_BASE}
+		r[idolID] = state
+	}
+	return state
+}
@@ -0,0 +1,366 @@
+package biasgame
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/globalsign/mgo/bson"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	RATING_LEADERBOARD_SIZE = 36
+	RATING_HISTORY_DAYS     = 180
+	RATING_HISTORY_WIDTH    = 800
+	RATING_HISTORY_HEIGHT   = 300
+)
+
+var (
+	ratingHistoryBackground = color.RGBA{R: 0x36, G: 0x39, B: 0x3f, A: 0xff}
+	ratingHistoryGrid       = color.RGBA{R: 0x4f, G: 0x54, B: 0x5c, A: 0xff}
+	ratingHistoryText       = color.RGBA{R: 0xdc, G: 0xdd, B: 0xde, A: 0xff}
+	ratingHistoryLine       = color.RGBA{R: 0x0f, G: 0xad, B: 0xed, A: 0xff} // blueish, like the embeds
+)
+
+type idolRatingRank struct {
+	idol   *idols.Idol
+	rating float64
+	votes  int
+}
+
+// getIdolRatingRanks returns the rated active idols of the gender sorted by rating
+// the ratings are the ratings of the server, or the global ratings if guildID is empty
+func getIdolRatingRanks(guildID, gender string) ([]idolRatingRank, error) {
+	var ranks []idolRatingRank
+
+	if guildID == "" {
+		for _, idol := range idols.GetActiveIdols() {
+			if rating, votes := idols.GetIdolRating(idol); votes > 0 {
+				ranks = append(ranks, idolRatingRank{idol: idol, rating: rating, votes: votes})
+			}
+		}
+	} else {
+		var ratingEntries []models.BiasGameRatingEntry
+		err := helpers.MDbIter(helpers.MdbCollection(models.BiasGameRatingsTable).Find(bson.M{"guildid": guildID})).All(&ratingEntries)
+		if err != nil {
+			return nil, err
+		}
+
+		activeIdols := make(map[bson.ObjectId]*idols.Idol)
+		for _, idol := range idols.GetActiveIdols() {
+			activeIdols[idol.ID] = idol
+		}
+		for _, ratingEntry := range ratingEntries {
+			if idol, ok := activeIdols[ratingEntry.IdolID]; ok {
+				ranks = append(ranks, idolRatingRank{idol: idol, rating: ratingEntry.Rating, votes: ratingEntry.Votes})
+			}
+		}
+	}
+
+	if gender != "mixed" {
+		var genderRanks []idolRatingRank
+		for _, rank := range ranks {
+			if rank.idol.Gender == gender {
+				genderRanks = append(genderRanks, rank)
+			}
+		}
+		ranks = genderRanks
+	}
+
+	sort.Slice(ranks, func(i, j int) bool {
+		return ranks[i].rating > ranks[j].rating
+	})
+	return ranks, nil
+}
+
+// getRatingsGuild returns the guild of the channel if server is one of the arguments
+func getRatingsGuild(msg *discordgo.Message, commandArgs []string) (*discordgo.Guild, error) {
+	for _, arg := range commandArgs {
+		if strings.ToLower(arg) == "server" {
+			channel, err := helpers.GetChannel(msg.ChannelID)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.GetGuild(channel.GuildID)
+		}
+	}
+	return nil, nil
+}
+
+// showIdolRatings sends the leaderboard of the idols with the highest ratings, usage: bg idol-ratings [girl|boy|mixed] [server]
+func showIdolRatings(msg *discordgo.Message, commandArgs []string) {
+	cache.GetSession().SessionForGuildS(msg.GuildID).ChannelTyping(msg.ChannelID)
+
+	gender := "mixed"
+	for _, arg := range commandArgs[1:] {
+		if argGender, ok := gameGenders[strings.ToLower(arg)]; ok {
+			gender = argGender
+		} else if strings.ToLower(arg) != "server" {
+			helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+			return
+		}
+	}
+
+	guild, err := getRatingsGuild(msg, commandArgs[1:])
+	helpers.Relax(err)
+
+	guildID := ""
+	embedTitle := helpers.GetText("plugins.biasgame.ratings.leaderboard-title-" + gender)
+	iconURL := cache.GetSession().SessionForGuildS(msg.GuildID).State.User.AvatarURL("512")
+	if guild != nil {
+		guildID = guild.ID
+		embedTitle = fmt.Sprintf("%s - %s", guild.Name, embedTitle)
+		iconURL = guild.IconURL()
+	}
+
+	ranks, err := getIdolRatingRanks(guildID, gender)
+	helpers.Relax(err)
+
+	if len(ranks) == 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.ratings.no-ratings"))
+		return
+	}
+	if len(ranks) > RATING_LEADERBOARD_SIZE {
+		ranks = ranks[:RATING_LEADERBOARD_SIZE]
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color: 0x0FADED, // blueish
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    embedTitle,
+			IconURL: iconURL,
+		},
+	}
+
+	for i, rank := range ranks {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("Rank #%d", i+1),
+			Value: helpers.GetTextF("plugins.biasgame.ratings.leaderboard-idol",
+				rank.idol.GroupName, rank.idol.Name, humanize.Comma(int64(math.Round(rank.rating))), humanize.Comma(int64(rank.votes))),
+			Inline: true,
+		})
+	}
+
+	helpers.SendPagedMessage(msg, embed, 12)
+}
+
+// displayIdolRatingHistory sends the rating of an idol with a graph of the rating over time, usage: bg rating-history "group" "idol" [server]
+func displayIdolRatingHistory(msg *discordgo.Message, content string) {
+	cache.GetSession().SessionForGuildS(msg.GuildID).ChannelTyping(msg.ChannelID)
+
+	commandArgs, err := helpers.ToArgv(content)
+	if err != nil {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+		return
+	}
+	commandArgs = commandArgs[1:]
+
+	if len(commandArgs) < 2 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+		return
+	}
+
+	_, _, targetIdol := idols.GetMatchingIdolAndGroup(commandArgs[0], commandArgs[1], true)
+	if targetIdol == nil {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.stats.no-matching-idol"))
+		return
+	}
+
+	guild, err := getRatingsGuild(msg, commandArgs[2:])
+	helpers.Relax(err)
+
+	guildID := ""
+	embedTitle := helpers.GetTextF("plugins.biasgame.ratings.history-title", targetIdol.GroupName, targetIdol.Name)
+	if guild != nil {
+		guildID = guild.ID
+		embedTitle = fmt.Sprintf("%s - %s", guild.Name, embedTitle)
+	}
+
+	// the rank is among the idols of the same gender
+	ranks, err := getIdolRatingRanks(guildID, targetIdol.Gender)
+	helpers.Relax(err)
+
+	var idolRank int
+	var idolRating idolRatingRank
+	for i, rank := range ranks {
+		if rank.idol.ID == targetIdol.ID {
+			idolRank = i + 1
+			idolRating = rank
+			break
+		}
+	}
+	if idolRank == 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.biasgame.ratings.not-rated"))
+		return
+	}
+
+	var historyEntries []models.BiasGameRatingHistoryEntry
+	err = helpers.MDbIter(helpers.MdbCollection(models.BiasGameRatingHistoryTable).Find(bson.M{
+		"idolid":  targetIdol.ID,
+		"guildid": guildID,
+		"day":     bson.M{"$gte": ratingDay(time.Now().AddDate(0, 0, -RATING_HISTORY_DAYS))},
+	}).Sort("day")).All(&historyEntries)
+	helpers.Relax(err)
+
+	embed := &discordgo.MessageEmbed{
+		Color: 0x0FADED, // blueish
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: embedTitle,
+		},
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Rating", Value: humanize.Comma(int64(math.Round(idolRating.rating))), Inline: true},
+			{Name: fmt.Sprintf("Rank (%ss)", targetIdol.Gender), Value: fmt.Sprintf("Rank #%d", idolRank), Inline: true},
+			{Name: "Votes", Value: humanize.Comma(int64(idolRating.votes)), Inline: true},
+		},
+	}
+
+	msgSend := &discordgo.MessageSend{Embed: embed}
+	if len(historyEntries) >= 2 {
+		graph, err := renderRatingHistory(historyEntries)
+		helpers.Relax(err)
+
+		embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://rating_history.png"}
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.biasgame.ratings.history-footer", RATING_HISTORY_DAYS)}
+		msgSend.Files = []*discordgo.File{{
+			Name:   "rating_history.png",
+			Reader: bytes.NewReader(graph),
+		}}
+	} else {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: helpers.GetText("plugins.biasgame.ratings.no-history")}
+	}
+
+	helpers.SendComplex(msg.ChannelID, msgSend)
+}
+
+// renderRatingHistory draws the rating at the end of each day as PNG
+func renderRatingHistory(historyEntries []models.BiasGameRatingHistoryEntry) ([]byte, error) {
+	const left, right, top, bottom = 50, 20, 15, 30
+
+	img := image.NewRGBA(image.Rect(0, 0, RATING_HISTORY_WIDTH, RATING_HISTORY_HEIGHT))
+	draw.Draw(img, img.Bounds(), image.NewUniform(ratingHistoryBackground), image.ZP, draw.Src)
+
+	minRating, maxRating := historyEntries[0].Rating, historyEntries[0].Rating
+	for _, entry := range historyEntries {
+		minRating = math.Min(minRating, entry.Rating)
+		maxRating = math.Max(maxRating, entry.Rating)
+	}
+	// round to fifty points for readable labels
+	minRating = math.Floor(minRating/50) * 50
+	maxRating = math.Ceil(maxRating/50) * 50
+	if maxRating <= minRating {
+		maxRating = minRating + 50
+	}
+
+	firstDay, lastDay := historyEntries[0].Day, historyEntries[len(historyEntries)-1].Day
+	if !lastDay.After(firstDay) {
+		lastDay = firstDay.Add(24 * time.Hour)
+	}
+
+	plotWidth := RATING_HISTORY_WIDTH - left - right
+	plotHeight := RATING_HISTORY_HEIGHT - top - bottom
+	x := func(day time.Time) int {
+		return left + int(float64(plotWidth)*float64(day.Sub(firstDay))/float64(lastDay.Sub(firstDay)))
+	}
+	y := func(rating float64) int {
+		return top + int(float64(plotHeight)*(maxRating-rating)/(maxRating-minRating))
+	}
+
+	// rating grid
+	for i := 0; i <= 4; i++ {
+		rating := minRating + (maxRating-minRating)*float64(i)/4
+		drawRatingHistoryLine(img, left, y(rating), left+plotWidth, y(rating), 1, ratingHistoryGrid)
+		label := strconv.Itoa(int(math.Round(rating)))
+		drawRatingHistoryString(img, left-8-len(label)*7, y(rating)+4, label, ratingHistoryText)
+	}
+
+	// day labels
+	for i := 0; i <= 4; i++ {
+		labelDay := firstDay.Add(lastDay.Sub(firstDay) * time.Duration(i) / 4)
+		label := labelDay.Format("2006-01-02")
+		labelX := x(labelDay) - len(label)*7/2
+		if labelX+len(label)*7 > RATING_HISTORY_WIDTH {
+			labelX = RATING_HISTORY_WIDTH - len(label)*7
+		}
+		drawRatingHistoryString(img, labelX, RATING_HISTORY_HEIGHT-10, label, ratingHistoryText)
+	}
+
+	for i, entry := range historyEntries {
+		pointX, pointY := x(entry.Day), y(entry.Rating)
+		draw.Draw(img, image.Rect(pointX-2, pointY-2, pointX+3, pointY+3), image.NewUniform(ratingHistoryLine), image.ZP, draw.Src)
+		if i > 0 {
+			previous := historyEntries[i-1]
+			drawRatingHistoryLine(img, x(previous.Day), y(previous.Rating), pointX, pointY, 2, ratingHistoryLine)
+		}
+	}
+
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, img)
+	return buffer.Bytes(), err
+}
+
+// drawRatingHistoryLine draws a line with Bresenham's algorithm
+func drawRatingHistoryLine(img *image.RGBA, x0, y0, x1, y1, width int, lineColor color.RGBA) {
+	dx, dy := x1-x0, y0-y1
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy > 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		for offsetX := 0; offsetX < width; offsetX++ {
+			for offsetY := 0; offsetY < width; offsetY++ {
+				img.SetRGBA(x0+offsetX, y0+offsetY, lineColor)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		doubleErr := 2 * err
+		if doubleErr >= dy {
+			err += dy
+			x0 += sx
+		}
+		if doubleErr <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawRatingHistoryString draws a text, y is the baseline
+func drawRatingHistoryString(img *image.RGBA, x, y int, text string, textColor color.RGBA) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(textColor),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
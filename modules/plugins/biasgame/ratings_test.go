@@ -0,0 +1,59 @@
+package biasgame
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestEloDelta(t *testing.T) {
+	if delta := eloDelta(RATING_BASE, RATING_BASE); delta != RATING_K/2 {
+		t.Fatal("expected half of K for equal ratings, got", delta)
+	}
+
+	// beating a much higher rated idol is worth more than beating a lower rated one
+	upset := eloDelta(1400, 1800)
+	expected := eloDelta(1800, 1400)
+	if upset <= expected || upset >= RATING_K || expected <= 0 {
+		t.Fatal("unexpected deltas", upset, expected)
+	}
+	if math.Abs(upset+expected-RATING_K) > 0.000001 {
+		t.Fatal("expected the deltas of both outcomes to add up to K, got", upset+expected)
+	}
+}
+
+func TestRatingReplay(t *testing.T) {
+	replay := make(ratingReplay)
+	a, b, c := bson.NewObjectId(), bson.NewObjectId(), bson.NewObjectId()
+
+	winnerRating, loserRating := replay.vote(a, b)
+	if winnerRating != RATING_BASE+RATING_K/2 || loserRating != RATING_BASE-RATING_K/2 {
+		t.Fatal("unexpected ratings after the first vote", winnerRating, loserRating)
+	}
+
+	replay.vote(a, c)
+	replay.vote(c, b)
+
+	if replay[a].Votes != 2 || replay[b].Votes != 2 || replay[c].Votes != 2 {
+		t.Fatal("unexpected votes", replay[a].Votes, replay[b].Votes, replay[c].Votes)
+	}
+	if !(replay[a].Rating > replay[c].Rating && replay[c].Rating > replay[b].Rating) {
+		t.Fatal("unexpected order", replay[a].Rating, replay[b].Rating, replay[c].Rating)
+	}
+
+	// votes move points between idols, the total stays the same
+	total := replay[a].Rating + replay[b].Rating + replay[c].Rating
+	if math.Abs(total-3*RATING_BASE) > 0.000001 {
+		t.Fatal("expected a total of", 3*RATING_BASE, "got", total)
+	}
+}
+
+func TestRatingDay(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	day := ratingDay(time.Date(2018, 12, 25, 3, 0, 0, 0, kst))
+	if !day.Equal(time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("unexpected day", day)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
@@ -18,6 +20,37 @@ import (
 	"github.com/globalsign/mgo/bson"
 )
 
+// idolStatsRefreshKey is claimed by the process which refreshes the game stats of the idols after idols changed
+const idolStatsRefreshKey = "robyul2-discord:biasgame:idol-stats-refresh"
+
+var idolStatsRefreshTimer *time.Timer
+var idolStatsRefreshTimerMutex sync.Mutex
+
+// scheduleIdolStatsRefresh refreshes the game stats of the idols a minute after the idols changed, more changes in that time are refreshed together
+// Every process is notified about changes, only one of them refreshes the stats
+func scheduleIdolStatsRefresh() {
+	idolStatsRefreshTimerMutex.Lock()
+	defer idolStatsRefreshTimerMutex.Unlock()
+
+	if idolStatsRefreshTimer != nil {
+		idolStatsRefreshTimer.Stop()
+	}
+	idolStatsRefreshTimer = time.AfterFunc(time.Minute, func() {
+		defer helpers.Recover()
+
+		claimed, err := cache.GetRedisClient().SetNX(idolStatsRefreshKey, helpers.ProcessName(), 10*time.Minute).Result()
+		if err != nil || !claimed {
+			helpers.RelaxLog(err)
+			return
+		}
+		defer cache.GetRedisClient().Del(idolStatsRefreshKey)
+
+		totalGames, totalIdols, err := idols.RefreshAllIdolsBiasgameStats()
+		helpers.RelaxLog(err)
+		bgLog().Infof("refreshed game stats of %d idols from %d games after idols changed", totalIdols, totalGames)
+	})
+}
+
 // displayBiasGameStats will display stats for the bias game based on the stats message
 func displayBiasGameStats(msg *discordgo.Message, statsMessage string) {
 	cache.GetSession().SessionForGuildS(msg.GuildID).ChannelTyping(msg.ChannelID)
@@ -413,8 +446,12 @@ func displayCurrentGameStats(msg *discordgo.Message) {
 func recordSingleGamesStats(game *singleBiasGame) {
 
 	// get guildID from game channel
-	channel, _ := cache.GetSession().SessionForGuildS(game.GuildID).State.Channel(game.ChannelID)
-	guild, err := cache.GetSession().SessionForGuildS(game.GuildID).State.Guild(channel.GuildID)
+	channel, err := helpers.GetChannel(game.ChannelID)
+	if err != nil {
+		fmt.Println("Error getting channel when recording stats")
+		return
+	}
+	guild, err := helpers.GetGuild(channel.GuildID)
 	if err != nil {
 		fmt.Println("Error getting guild when recording stats")
 		return
@@ -427,6 +464,7 @@ func recordSingleGamesStats(game *singleBiasGame) {
 		GuildID:      guild.ID,
 		GameType:     "single",
 		Gender:       game.Gender,
+		Pool:         game.Pool,
 		RoundWinners: compileGameWinnersLosers(game.RoundWinners),
 		RoundLosers:  compileGameWinnersLosers(game.RoundLosers),
 		GameWinner:   game.GameWinnerBias.ID,
@@ -441,7 +479,7 @@ func recordSingleGamesStats(game *singleBiasGame) {
 func recordMultiGamesStats(game *multiBiasGame) {
 
 	// get guildID from game channel
-	channel, err := cache.GetSession().SessionForGuildS(game.guildID).State.Channel(game.ChannelID)
+	channel, err := helpers.GetChannel(game.ChannelID)
 	if err != nil {
 		fmt.Println("Error getting channel when recording stats")
 		return
@@ -453,6 +491,7 @@ func recordMultiGamesStats(game *multiBiasGame) {
 		GuildID:      channel.GuildID,
 		GameType:     "multi",
 		Gender:       game.Gender,
+		Pool:         game.Pool,
 		RoundWinners: compileGameWinnersLosers(game.RoundWinners),
 		RoundLosers:  compileGameWinnersLosers(game.RoundLosers),
 		GameWinner:   game.GameWinnerBias.ID,
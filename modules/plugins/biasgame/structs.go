@@ -18,6 +18,7 @@ type singleBiasGame struct {
 	LastRoundMessage *discordgo.Message
 	ReadyForReaction bool   // used to make sure multiple reactions aren't counted
 	Gender           string // girl, boy, mixed
+	Pool             string // name of the custom pool, empty for games with all idols
 	GameImageIndex   map[string]int
 }
 
@@ -32,6 +33,7 @@ type multiBiasGame struct {
 	IdolsRemaining        int
 	LastRoundMessage      *discordgo.Message
 	Gender                string // girl, boy, mixed
+	Pool                  string // name of the custom pool, empty for games with all idols
 	UserIdsInvolved       []string
 	RoundDelay            int
 	GameIsRunning         bool
@@ -0,0 +1,86 @@
+package biasgame
+
+import (
+	"bytes"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+)
+
+const (
+	// TOURNAMENT_TREE_MAX_ENTRANTS is the most idols in the first row of the tournament tree, bigger games show their last rounds
+	TOURNAMENT_TREE_MAX_ENTRANTS = 32
+	TOURNAMENT_TREE_TILE_SIZE    = 60
+)
+
+// getTournamentTreeRows returns the rows of the tournament tree, from the winner at the top down to the first round shown.
+// The first round is shown as pairs of winner and loser, the rows above are the winners in the order of the votes,
+// which puts every winner above the pair of their vote.
+// Returns nil if the amount of idols was not a power of two.
+func getTournamentTreeRows(roundWinners, roundLosers []*idols.Idol) [][]*idols.Idol {
+	entrants := len(roundWinners) + 1
+	if len(roundLosers) != len(roundWinners) || entrants < 2 || entrants&(entrants-1) != 0 {
+		return nil
+	}
+
+	// skip the early rounds of big games, a game of n idols has rounds of n/2, n/4, ... 1 votes
+	firstVote := 0
+	for entrants > TOURNAMENT_TREE_MAX_ENTRANTS {
+		firstVote += entrants / 2
+		entrants /= 2
+	}
+
+	var firstRow []*idols.Idol
+	for i := firstVote; i < firstVote+entrants/2; i++ {
+		firstRow = append(firstRow, roundWinners[i], roundLosers[i])
+	}
+
+	rows := [][]*idols.Idol{firstRow}
+	roundStart := firstVote
+	for votes := entrants / 2; votes >= 1; votes /= 2 {
+		rows = append([][]*idols.Idol{roundWinners[roundStart : roundStart+votes]}, rows...)
+		roundStart += votes
+	}
+	return rows
+}
+
+// makeTournamentTree renders the rows of the tournament tree with the images the idols had in the game
+func makeTournamentTree(rows [][]*idols.Idol, gameImageIndex *map[string]int) []byte {
+	columns := len(rows[len(rows)-1])
+
+	imagesBytes := make(map[string][]byte)
+	var tiles [][]byte
+	for _, row := range rows {
+		// idols are centered above the idols they won against, the other tiles stay empty
+		spacing := columns / len(row)
+		rowTiles := make([][]byte, columns)
+		for i, idol := range row {
+			if _, ok := imagesBytes[idol.NameAndGroup]; !ok {
+				imageIndex := getSemiRandomIdolImageIndex(idol, gameImageIndex)
+				imagesBytes[idol.NameAndGroup] = idol.Images[imageIndex].GetResizeImgBytes(TOURNAMENT_TREE_TILE_SIZE)
+			}
+			rowTiles[i*spacing+(spacing-1)/2] = imagesBytes[idol.NameAndGroup]
+		}
+		tiles = append(tiles, rowTiles...)
+	}
+
+	return helpers.CollageFromBytes(
+		tiles,
+		[]string{},
+		columns*TOURNAMENT_TREE_TILE_SIZE, len(rows)*TOURNAMENT_TREE_TILE_SIZE,
+		TOURNAMENT_TREE_TILE_SIZE, TOURNAMENT_TREE_TILE_SIZE,
+		"#36393f",
+	)
+}
+
+// sendTournamentTree sends the tournament tree of a finished game
+func sendTournamentTree(channelID, pool string, roundWinners, roundLosers []*idols.Idol, gameImageIndex *map[string]int) {
+	rows := getTournamentTreeRows(roundWinners, roundLosers)
+	if rows == nil {
+		return
+	}
+
+	_, err := helpers.SendFile(channelID, "biasgame_tournament.png", bytes.NewReader(makeTournamentTree(rows, gameImageIndex)),
+		helpers.GetTextF("plugins.biasgame.pool.tournament-tree", pool))
+	helpers.RelaxLog(err)
+}
@@ -114,23 +114,19 @@ func (bs *BotStatus) actionStart(args []string, in *discordgo.Message, out **dis
 }
 
 func (bs *BotStatus) replaceText(text string) (result string) {
-	users := make(map[string]string)
 	channels := make(map[string]string)
-	var guilds int64
-	for _, shard := range cache.GetSession().Sessions {
-		for _, guild := range shard.State.Guilds {
-			guilds++
-			for _, u := range guild.Members {
-				users[u.User.ID] = u.User.Username
-			}
-			for _, c := range guild.Channels {
-				channels[c.ID] = c.Name
-			}
+	var guilds, members int64
+	// the members of all guilds are not loaded, members on multiple guilds are counted for each guild
+	for _, guild := range helpers.AllGuildsWithoutMembers() {
+		guilds++
+		members += int64(guild.MemberCount)
+		for _, c := range guild.Channels {
+			channels[c.ID] = c.Name
 		}
 	}
 
 	text = strings.Replace(text, "{GUILD_COUNT}", humanize.Comma(guilds), -1)
-	text = strings.Replace(text, "{MEMBER_COUNT}", humanize.Comma(int64(len(users))), -1)
+	text = strings.Replace(text, "{MEMBER_COUNT}", humanize.Comma(members), -1)
 	text = strings.Replace(text, "{CHANNEL_COUNT}", humanize.Comma(int64(len(channels))), -1)
 
 	return text
@@ -0,0 +1,214 @@
+package plugins
+
+import (
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/globalsign/mgo/bson"
+)
+
+type chartsAlertType int
+
+const (
+	// chartsAlertEntry is posted the first time a song is on a chart
+	chartsAlertEntry chartsAlertType = iota
+	// chartsAlertPeak is posted if a song is higher than ever before
+	chartsAlertPeak
+	// chartsAlertNumberOne is posted if a song reaches #1
+	chartsAlertNumberOne
+	// chartsAlertAllKill is posted if a song reaches #1 and is #1 on all other all-kill charts
+	chartsAlertAllKill
+)
+
+// chartsAlert is a change of a song on a new period of a chart
+type chartsAlert struct {
+	Type chartsAlertType
+	Rank models.ChartsRank
+	// Peak is the best rank before this period, 0 if the song has not been on the chart before
+	Peak int
+}
+
+// chartsRankAlert returns the alert for a rank of a new period, only the most important alert is returned
+// peak is the best rank before this period and previousRank the rank of the previous period, 0 if the song was not on the chart
+func chartsRankAlert(rank models.ChartsRank, peak, previousRank int, allKill bool) (alert chartsAlert, ok bool) {
+	alert = chartsAlert{Rank: rank, Peak: peak}
+
+	switch {
+	case rank.Rank == 1 && previousRank != 1 && allKill:
+		alert.Type = chartsAlertAllKill
+	case rank.Rank == 1 && previousRank != 1:
+		alert.Type = chartsAlertNumberOne
+	case peak <= 0:
+		alert.Type = chartsAlertEntry
+	case rank.Rank < peak:
+		alert.Type = chartsAlertPeak
+	default:
+		return alert, false
+	}
+	return alert, true
+}
+
+// chartsWatchMatches returns true if the query of the watch is the song, the album of album charts, or one of the artists
+func chartsWatchMatches(query string, rank models.ChartsRank) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return false
+	}
+
+	if strings.ToLower(chartsRankName(rank)) == query ||
+		chartsNormalizeTitle(chartsRankName(rank)) == chartsNormalizeTitle(query) ||
+		rank.Key == query ||
+		rank.Key == chartsQueryKey(query) ||
+		strings.ToLower(rank.Artist) == query ||
+		chartsNormalizeArtist(rank.Artist) == chartsNormalizeArtist(query) {
+		return true
+	}
+
+	// collaborations list several artists
+	artists := strings.FieldsFunc(strings.ToLower(rank.Artist), func(r rune) bool {
+		return r == ',' || r == '&'
+	})
+	for _, artist := range artists {
+		if strings.TrimSpace(artist) == query || chartsNormalizeArtist(artist) == chartsNormalizeArtist(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// postChartsAlerts posts the alerts of a new snapshot to all watches matching the changed songs
+func (m *Charts) postChartsAlerts(chart chartsCollectedChart, snapshot, previous models.ChartsSnapshotEntry) {
+	var watches []models.ChartsWatchEntry
+	err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.ChartsWatchesTable).Find(nil)).All(&watches)
+	helpers.Relax(err)
+
+	if len(watches) <= 0 {
+		return
+	}
+
+	previousRanks := make(map[string]int, len(previous.Ranks))
+	for _, rank := range previous.Ranks {
+		previousRanks[rank.Key] = rank.Rank
+	}
+
+	alertsByWatch := make(map[bson.ObjectId][]chartsAlert)
+	for _, rank := range snapshot.Ranks {
+		var matchingWatches []models.ChartsWatchEntry
+		for _, watch := range watches {
+			if chartsWatchMatches(watch.Query, rank) {
+				matchingWatches = append(matchingWatches, watch)
+			}
+		}
+		if len(matchingWatches) <= 0 {
+			continue
+		}
+
+		peak, err := getChartsPeak(chart.Name, rank.Key, snapshot.Period)
+		if err != nil {
+			helpers.RelaxLog(err)
+			continue
+		}
+
+		allKill := false
+		if rank.Rank == 1 && chart.AllKill {
+			allKill, err = isChartsAllKill(chart.Name, rank.Key)
+			helpers.RelaxLog(err)
+		}
+
+		alert, ok := chartsRankAlert(rank, peak, previousRanks[rank.Key], allKill)
+		if !ok {
+			continue
+		}
+		for _, watch := range matchingWatches {
+			alertsByWatch[watch.ID] = append(alertsByWatch[watch.ID], alert)
+		}
+	}
+
+	for _, watch := range watches {
+		alerts := alertsByWatch[watch.ID]
+		if len(alerts) <= 0 {
+			continue
+		}
+
+		var alertsText string
+		for _, alert := range alerts {
+			alertsText += chartsAlertText(watch.GuildID, chart, alert) + "\n"
+		}
+
+		_, err = helpers.SendMessage(watch.ChannelID, alertsText)
+		if err != nil {
+			cache.GetLogger().WithField("module", "charts").WithField("channelID", watch.ChannelID).Warnf(
+				"posting chart alerts failed: %s", err.Error())
+		}
+	}
+}
+
+// chartsAlertText returns the text of an alert in the language of the guild
+func chartsAlertText(guildID string, chart chartsCollectedChart, alert chartsAlert) string {
+	name := chartsRankName(alert.Rank)
+	switch alert.Type {
+	case chartsAlertAllKill:
+		var allKillCharts []string
+		for _, allKillChart := range chartsCollectedCharts {
+			if allKillChart.AllKill {
+				allKillCharts = append(allKillCharts, allKillChart.Title())
+			}
+		}
+		return helpers.GetTextForGuild(guildID, "plugins.charts.alert-all-kill",
+			name, alert.Rank.Artist, strings.Join(allKillCharts, ", "))
+	case chartsAlertNumberOne:
+		return helpers.GetTextForGuild(guildID, "plugins.charts.alert-number-one",
+			name, alert.Rank.Artist, chart.Title())
+	case chartsAlertPeak:
+		return helpers.GetTextForGuild(guildID, "plugins.charts.alert-peak",
+			name, alert.Rank.Artist, alert.Rank.Rank, chart.Title(), alert.Peak)
+	}
+	return helpers.GetTextForGuild(guildID, "plugins.charts.alert-entry",
+		name, alert.Rank.Artist, chart.Title(), alert.Rank.Rank)
+}
+
+// getChartsPeak returns the best rank of the song on the chart before the period, 0 if the song has not been on the chart
+func getChartsPeak(chart, key, period string) (peak int, err error) {
+	var snapshots []models.ChartsSnapshotEntry
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.ChartsSnapshotsTable).Find(bson.M{
+		"chart":     chart,
+		"period":    bson.M{"$ne": period},
+		"ranks.key": key,
+	}).Select(bson.M{"ranks.$": 1})).All(&snapshots)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, snapshot := range snapshots {
+		for _, rank := range snapshot.Ranks {
+			if rank.Key == key && (peak <= 0 || rank.Rank < peak) {
+				peak = rank.Rank
+			}
+		}
+	}
+	return peak, nil
+}
+
+// isChartsAllKill returns true if the song is #1 on the latest snapshots of all all-kill charts except the given chart
+func isChartsAllKill(chart, key string) (bool, error) {
+	for _, allKillChart := range chartsCollectedCharts {
+		if !allKillChart.AllKill || allKillChart.Name == chart {
+			continue
+		}
+
+		snapshot, err := getLatestChartsSnapshot(allKillChart.Name)
+		if helpers.IsMdbNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if len(snapshot.Ranks) <= 0 || snapshot.Ranks[0].Rank != 1 || snapshot.Ranks[0].Key != key {
+			return false, nil
+		}
+	}
+	return true, nil
+}
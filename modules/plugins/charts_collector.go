@@ -0,0 +1,339 @@
+package plugins
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/services/charts"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// chartsSnapshotSize is the number of ranks stored for each snapshot, some charts contain fewer ranks
+	chartsSnapshotSize = 100
+	// chartsCheckKey is claimed by the process which checks a chart for a new period, %s is the name of the chart
+	chartsCheckKey = "robyul2-discord:charts:check:%s"
+	// chartsKeysMigrationKey is claimed by the process which normalizes the keys of old snapshots
+	chartsKeysMigrationKey = "robyul2-discord:charts:migration:keys"
+)
+
+// chartsCollectedChart is a chart which is stored by the collector every time the chart shows a new period
+type chartsCollectedChart struct {
+	Name     string
+	Provider string
+	Type     charts.ChartType
+	// Interval is how often the chart is checked for a new period
+	Interval time.Duration
+	// AllKill charts count for all-kills, a song has an all-kill while it is #1 on all of them
+	AllKill bool
+	// Color is the color of the chart in history graphs
+	Color color.RGBA
+}
+
+var chartsCollectedCharts = []chartsCollectedChart{
+	{
+		Name:     "melon-realtime",
+		Provider: "melon",
+		Type:     charts.ChartTypeRealtime,
+		Interval: 15 * time.Minute,
+		AllKill:  true,
+		Color:    color.RGBA{R: 0x43, G: 0xc8, B: 0x5d, A: 0xff},
+	},
+	{
+		Name:     "melon-daily",
+		Provider: "melon",
+		Type:     charts.ChartTypeDaily,
+		Interval: time.Hour,
+		AllKill:  true,
+		Color:    color.RGBA{R: 0xf5, G: 0xa6, B: 0x23, A: 0xff},
+	},
+	{
+		Name:     "ichart-realtime",
+		Provider: "ichart",
+		Type:     charts.ChartTypeRealtime,
+		Interval: 15 * time.Minute,
+		AllKill:  true,
+		Color:    color.RGBA{R: 0x4a, G: 0x90, B: 0xe2, A: 0xff},
+	},
+	{
+		Name:     "ichart-weekly",
+		Provider: "ichart",
+		Type:     charts.ChartTypeWeekly,
+		Interval: 6 * time.Hour,
+		Color:    color.RGBA{R: 0xbd, G: 0x10, B: 0xe0, A: 0xff},
+	},
+	{
+		Name:     "genie-realtime",
+		Provider: "genie",
+		Type:     charts.ChartTypeRealtime,
+		Interval: 15 * time.Minute,
+		AllKill:  true,
+		Color:    color.RGBA{R: 0x4a, G: 0xa5, B: 0xe8, A: 0xff},
+	},
+	{
+		Name:     "bugs-realtime",
+		Provider: "bugs",
+		Type:     charts.ChartTypeRealtime,
+		Interval: 15 * time.Minute,
+		AllKill:  true,
+		Color:    color.RGBA{R: 0xff, G: 0x3b, B: 0x28, A: 0xff},
+	},
+	{
+		Name:     "flo-realtime",
+		Provider: "flo",
+		Type:     charts.ChartTypeRealtime,
+		Interval: time.Hour,
+		AllKill:  true,
+		Color:    color.RGBA{R: 0x3f, G: 0x3f, B: 0xff, A: 0xff},
+	},
+	{
+		Name:     "gaon-weekly",
+		Provider: "gaon",
+		Type:     charts.ChartTypeAlbumsWeekly,
+		Interval: 6 * time.Hour,
+		Color:    color.RGBA{R: 0xe9, G: 0x4b, B: 0x3c, A: 0xff},
+	},
+	{
+		Name:     "billboard-weekly",
+		Provider: "billboard",
+		Type:     charts.ChartTypeWeekly,
+		Interval: 6 * time.Hour,
+		Color:    color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff},
+	},
+	{
+		Name:     "billboard-global-weekly",
+		Provider: "billboard-global",
+		Type:     charts.ChartTypeWeekly,
+		Interval: 6 * time.Hour,
+		Color:    color.RGBA{R: 0x9b, G: 0x9b, B: 0x9b, A: 0xff},
+	},
+}
+
+// Title returns the display name of the chart, like Melon Realtime
+func (c chartsCollectedChart) Title() string {
+	provider, ok := charts.GetProvider(c.Provider)
+	if !ok {
+		return c.Name
+	}
+	return provider.Title() + " " + c.Type.Title()
+}
+
+// getCollectedChart returns the collected chart with the name
+func getCollectedChart(name string) (chart chartsCollectedChart, ok bool) {
+	for _, chart := range chartsCollectedCharts {
+		if chart.Name == name {
+			return chart, true
+		}
+	}
+	return chart, false
+}
+
+func (m *Charts) collectChartsLoop() {
+	defer helpers.Recover()
+	defer func() {
+		go func() {
+			cache.GetLogger().WithField("module", "charts").Error("The collectChartsLoop died. Please investigate! Will be restarted in 60 seconds")
+			time.Sleep(60 * time.Second)
+			m.collectChartsLoop()
+		}()
+	}()
+
+	for {
+		for _, chart := range chartsCollectedCharts {
+			err := m.collectChart(chart)
+			if charts.IsUnavailable(err) {
+				cache.GetLogger().WithField("module", "charts").WithField("chart", chart.Name).Infof("chart is unavailable: %s", err.Error())
+				continue
+			}
+			if err != nil {
+				cache.GetLogger().WithField("module", "charts").WithField("chart", chart.Name).Warnf("collecting chart failed: %s", err.Error())
+			}
+		}
+
+		time.Sleep(60 * time.Second)
+	}
+}
+
+// collectChart stores a snapshot of the chart if it shows a new period, and posts the alerts for the new period
+// Every chart is checked by one process per interval
+func (m *Charts) collectChart(chart chartsCollectedChart) (err error) {
+	provider, ok := charts.GetProvider(chart.Provider)
+	if !ok {
+		return fmt.Errorf("unknown chart provider %s", chart.Provider)
+	}
+
+	claimed, err := cache.GetRedisClient().SetNX(fmt.Sprintf(chartsCheckKey, chart.Name), helpers.ProcessName(), chart.Interval).Result()
+	if err != nil || !claimed {
+		return err
+	}
+	// release the claim if the check failed, so the chart is retried in the next minute instead of after the interval
+	defer func() {
+		if err != nil {
+			helpers.RelaxLog(cache.GetRedisClient().Del(fmt.Sprintf(chartsCheckKey, chart.Name)).Err())
+		}
+	}()
+
+	ranking, err := provider.Fetch(chart.Type, chartsSnapshotSize)
+	if err != nil {
+		return err
+	}
+
+	previous, err := getLatestChartsSnapshot(chart.Name)
+	if err != nil && !helpers.IsMdbNotFound(err) {
+		return err
+	}
+	if previous.Period == ranking.Period {
+		return nil
+	}
+
+	snapshot := models.ChartsSnapshotEntry{
+		Chart:       chart.Name,
+		Period:      ranking.Period,
+		CollectedAt: time.Now(),
+		Ranks:       chartsRanks(ranking.Entries),
+	}
+	_, err = helpers.MDbInsertWithoutLogging(models.ChartsSnapshotsTable, snapshot)
+	if err != nil {
+		return err
+	}
+	cache.GetLogger().WithField("module", "charts").WithField("chart", chart.Name).Infof(
+		"stored snapshot for period %s with %d ranks", snapshot.Period, len(snapshot.Ranks))
+
+	// the first snapshot of a chart has nothing to compare with
+	if previous.ID == "" {
+		return nil
+	}
+
+	go func() {
+		defer helpers.Recover()
+
+		m.postChartsAlerts(chart, snapshot, previous)
+	}()
+	return nil
+}
+
+// getLatestChartsSnapshot returns the most recent snapshot of the chart
+func getLatestChartsSnapshot(chart string) (snapshot models.ChartsSnapshotEntry, err error) {
+	err = helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.ChartsSnapshotsTable).Find(bson.M{"chart": chart}).Sort("-collectedat"),
+		&snapshot,
+	)
+	return snapshot, err
+}
+
+var (
+	// chartsRomanizationRegex matches the parenthesized romanization some providers add to artists, for example 아이유(IU)
+	chartsRomanizationRegex = regexp.MustCompile(`\s*[(\[][^)\]]*[)\]]\s*$`)
+	// chartsFeaturingRegex matches featured artists and producers of a title, for example 에잇(Prod.&Feat. SUGA of BTS) or Lilac feat. IU
+	chartsFeaturingRegex = regexp.MustCompile(`\s*[(\[]\s*(feat|ft|prod|with)\b[^)\]]*[)\]]|\s+(feat|ft)\.?\s.*$`)
+)
+
+// chartsKey identifies a song or album across periods and charts
+// Providers spell artists and titles differently, so only the first artist without romanization and the title without featured artists are used
+func chartsKey(artist, name string) string {
+	return chartsNormalizeArtist(artist) + " - " + chartsNormalizeTitle(name)
+}
+
+// chartsNormalizeArtist returns the lowercase first artist without the parenthesized romanization
+func chartsNormalizeArtist(artist string) string {
+	artist = strings.ToLower(artist)
+	if index := strings.IndexAny(artist, ",&"); index >= 0 {
+		artist = artist[:index]
+	}
+	if stripped := chartsRomanizationRegex.ReplaceAllString(artist, ""); strings.TrimSpace(stripped) != "" {
+		artist = stripped
+	}
+	return strings.Join(strings.Fields(artist), " ")
+}
+
+// chartsNormalizeTitle returns the lowercase title without featured artists and producers
+func chartsNormalizeTitle(title string) string {
+	title = strings.ToLower(title)
+	if stripped := chartsFeaturingRegex.ReplaceAllString(title, ""); strings.TrimSpace(stripped) != "" {
+		title = stripped
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// chartsQueryKey returns the key of an artist - title query, or an empty string if the query contains no artist
+func chartsQueryKey(query string) string {
+	parts := strings.SplitN(query, " - ", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return chartsKey(parts[0], parts[1])
+}
+
+// migrateChartsKeys updates the keys of snapshots stored before keys were normalized, only the first process to start runs it
+func migrateChartsKeys() error {
+	claimed, err := cache.GetRedisClient().SetNX(chartsKeysMigrationKey, helpers.ProcessName(), 0).Result()
+	if err != nil || !claimed {
+		return err
+	}
+
+	err = updateChartsKeys()
+	if err != nil {
+		// try again with the next start
+		cache.GetRedisClient().Del(chartsKeysMigrationKey)
+	}
+	return err
+}
+
+func updateChartsKeys() error {
+	var snapshot models.ChartsSnapshotEntry
+	iter := helpers.MDbIter(helpers.MdbCollection(models.ChartsSnapshotsTable).Find(nil))
+	for iter.Next(&snapshot) {
+		changed := false
+		for i, rank := range snapshot.Ranks {
+			key := chartsKey(rank.Artist, chartsRankName(rank))
+			if key != rank.Key {
+				snapshot.Ranks[i].Key = key
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		err := helpers.MdbCollection(models.ChartsSnapshotsTable).UpdateId(snapshot.ID, bson.M{"$set": bson.M{"ranks": snapshot.Ranks}})
+		if err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return iter.Close()
+}
+
+// chartsRanks converts the entries of a ranking, songs are identified by their title and albums by the album
+func chartsRanks(entries []charts.Entry) (ranks []models.ChartsRank) {
+	for _, entry := range entries {
+		name := entry.Title
+		if name == "" {
+			name = entry.Album
+		}
+
+		ranks = append(ranks, models.ChartsRank{
+			Key:      chartsKey(entry.Artist, name),
+			Rank:     entry.Rank,
+			PastRank: entry.PastRank,
+			Title:    entry.Title,
+			Artist:   entry.Artist,
+			Album:    entry.Album,
+		})
+	}
+	return ranks
+}
+
+// chartsRankName returns the title of a song, or the album for album charts
+func chartsRankName(rank models.ChartsRank) string {
+	if rank.Title != "" {
+		return rank.Title
+	}
+	return rank.Album
+}
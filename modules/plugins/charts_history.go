@@ -0,0 +1,257 @@
+package plugins
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/globalsign/mgo/bson"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	chartsHistoryDays   = 30
+	chartsHistoryWidth  = 900
+	chartsHistoryHeight = 400
+)
+
+var (
+	chartsHistoryBackground = color.RGBA{R: 0x36, G: 0x39, B: 0x3f, A: 0xff}
+	chartsHistoryGrid       = color.RGBA{R: 0x4f, G: 0x54, B: 0x5c, A: 0xff}
+	chartsHistoryText       = color.RGBA{R: 0xdc, G: 0xdd, B: 0xde, A: 0xff}
+)
+
+// chartsHistorySeries are the ranks of a song on one chart
+type chartsHistorySeries struct {
+	Title  string
+	Color  color.RGBA
+	Points []chartsHistoryPoint
+}
+
+type chartsHistoryPoint struct {
+	Time time.Time
+	Rank int
+}
+
+// getChartsHistory returns the ranks of the song on all charts since the given time, the song is a title, album, or artist - title
+// If several songs match, the song which charted most recently is returned
+func getChartsHistory(song string, since time.Time) (rank models.ChartsRank, series []chartsHistorySeries, err error) {
+	song = strings.ToLower(strings.TrimSpace(song))
+	songRegex := bson.RegEx{Pattern: "^" + regexp.QuoteMeta(song) + "$", Options: "i"}
+
+	var snapshots []models.ChartsSnapshotEntry
+	err = helpers.MDbIter(helpers.MdbCollection(models.ChartsSnapshotsTable).Find(bson.M{
+		"collectedat": bson.M{"$gte": since},
+		"ranks": bson.M{"$elemMatch": bson.M{"$or": []bson.M{
+			{"key": song},
+			{"key": chartsQueryKey(song)},
+			{"title": songRegex},
+			{"album": songRegex},
+		}}},
+	}).Select(bson.M{"chart": 1, "collectedat": 1, "ranks.$": 1}).Sort("collectedat")).All(&snapshots)
+	if err != nil || len(snapshots) <= 0 {
+		return rank, nil, err
+	}
+
+	latest := snapshots[len(snapshots)-1]
+	if len(latest.Ranks) <= 0 {
+		return rank, nil, nil
+	}
+	rank = latest.Ranks[0]
+
+	seriesByChart := make(map[string]*chartsHistorySeries)
+	for _, snapshot := range snapshots {
+		if len(snapshot.Ranks) <= 0 || snapshot.Ranks[0].Key != rank.Key {
+			continue
+		}
+
+		chartSeries, ok := seriesByChart[snapshot.Chart]
+		if !ok {
+			chart, ok := getCollectedChart(snapshot.Chart)
+			if !ok {
+				continue
+			}
+			chartSeries = &chartsHistorySeries{Title: chart.Title(), Color: chart.Color}
+			seriesByChart[snapshot.Chart] = chartSeries
+		}
+		chartSeries.Points = append(chartSeries.Points, chartsHistoryPoint{
+			Time: snapshot.CollectedAt,
+			Rank: snapshot.Ranks[0].Rank,
+		})
+	}
+
+	// keep the order of the collected charts
+	for _, chart := range chartsCollectedCharts {
+		if chartSeries, ok := seriesByChart[chart.Name]; ok {
+			series = append(series, *chartSeries)
+		}
+	}
+	return rank, series, nil
+}
+
+// renderChartsHistory draws the ranks over time as PNG, #1 is at the top
+func renderChartsHistory(series []chartsHistorySeries) ([]byte, error) {
+	const left, right, bottom = 50, 20, 30
+
+	// the legend wraps into more lines if the song is on many charts
+	top := 40
+	legendX := left
+	for _, chartSeries := range series {
+		legendWidth := 14 + len(chartSeries.Title)*7
+		if legendX > left && legendX+legendWidth > chartsHistoryWidth-right {
+			legendX = left
+			top += 18
+		}
+		legendX += legendWidth + 20
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, chartsHistoryWidth, chartsHistoryHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(chartsHistoryBackground), image.ZP, draw.Src)
+
+	maxRank := 10
+	var firstTime, lastTime time.Time
+	for _, chartSeries := range series {
+		for _, point := range chartSeries.Points {
+			if point.Rank > maxRank {
+				maxRank = point.Rank
+			}
+			if firstTime.IsZero() || point.Time.Before(firstTime) {
+				firstTime = point.Time
+			}
+			if point.Time.After(lastTime) {
+				lastTime = point.Time
+			}
+		}
+	}
+	// round to the next ten for readable labels
+	maxRank = (maxRank + 9) / 10 * 10
+	if !lastTime.After(firstTime) {
+		lastTime = firstTime.Add(time.Hour)
+	}
+
+	plotWidth := chartsHistoryWidth - left - right
+	plotHeight := chartsHistoryHeight - top - bottom
+	x := func(t time.Time) int {
+		return left + int(float64(plotWidth)*float64(t.Sub(firstTime))/float64(lastTime.Sub(firstTime)))
+	}
+	y := func(rank int) int {
+		return top + int(float64(plotHeight)*float64(rank-1)/float64(maxRank-1))
+	}
+
+	// rank grid
+	step := maxRank / 5
+	for rank := 0; rank <= maxRank; rank += step {
+		labelRank := rank
+		if labelRank < 1 {
+			labelRank = 1
+		}
+		chartsHistoryLine(img, left, y(labelRank), left+plotWidth, y(labelRank), 1, chartsHistoryGrid)
+		label := "#" + strconv.Itoa(labelRank)
+		chartsHistoryString(img, left-8-len(label)*7, y(labelRank)+4, label, chartsHistoryText)
+	}
+
+	// time labels in KST, like the charts
+	kst := time.FixedZone("KST", 9*60*60)
+	timeFormat := "01-02 15:04"
+	if lastTime.Sub(firstTime) > 72*time.Hour {
+		timeFormat = "01-02"
+	}
+	for i := 0; i <= 4; i++ {
+		labelTime := firstTime.Add(lastTime.Sub(firstTime) * time.Duration(i) / 4)
+		label := labelTime.In(kst).Format(timeFormat)
+		labelX := x(labelTime) - len(label)*7/2
+		if labelX+len(label)*7 > chartsHistoryWidth {
+			labelX = chartsHistoryWidth - len(label)*7
+		}
+		chartsHistoryString(img, labelX, chartsHistoryHeight-10, label, chartsHistoryText)
+	}
+
+	// legend
+	legendX, legendY := left, 24
+	for _, chartSeries := range series {
+		legendWidth := 14 + len(chartSeries.Title)*7
+		if legendX > left && legendX+legendWidth > chartsHistoryWidth-right {
+			legendX = left
+			legendY += 18
+		}
+		draw.Draw(img, image.Rect(legendX, legendY-10, legendX+10, legendY), image.NewUniform(chartSeries.Color), image.ZP, draw.Src)
+		chartsHistoryString(img, legendX+14, legendY, chartSeries.Title, chartsHistoryText)
+		legendX += legendWidth + 20
+	}
+
+	for _, chartSeries := range series {
+		for i, point := range chartSeries.Points {
+			pointX, pointY := x(point.Time), y(point.Rank)
+			draw.Draw(img, image.Rect(pointX-2, pointY-2, pointX+3, pointY+3), image.NewUniform(chartSeries.Color), image.ZP, draw.Src)
+			if i > 0 {
+				previous := chartSeries.Points[i-1]
+				chartsHistoryLine(img, x(previous.Time), y(previous.Rank), pointX, pointY, 2, chartSeries.Color)
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, img)
+	return buffer.Bytes(), err
+}
+
+// chartsHistoryLine draws a line with Bresenham's algorithm
+func chartsHistoryLine(img *image.RGBA, x0, y0, x1, y1, width int, lineColor color.RGBA) {
+	dx, dy := chartsHistoryAbs(x1-x0), -chartsHistoryAbs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		for offsetX := 0; offsetX < width; offsetX++ {
+			for offsetY := 0; offsetY < width; offsetY++ {
+				img.SetRGBA(x0+offsetX, y0+offsetY, lineColor)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		doubleErr := 2 * err
+		if doubleErr >= dy {
+			err += dy
+			x0 += sx
+		}
+		if doubleErr <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// chartsHistoryString draws a text, y is the baseline
+func chartsHistoryString(img *image.RGBA, x, y int, text string, textColor color.RGBA) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(textColor),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+func chartsHistoryAbs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,124 @@
+package plugins
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/services/charts"
+)
+
+func TestChartsRanks(t *testing.T) {
+	ranks := chartsRanks([]charts.Entry{
+		{Rank: 1, PastRank: 3, Title: "Psycho", Artist: "Red Velvet", Album: "'The ReVe Festival' Finale"},
+		{Rank: 2, PastRank: 4, Artist: "아이유", Album: "Love poem"},
+	})
+	if ranks[0].Key != "red velvet - psycho" || ranks[0].PastRank != 3 || chartsRankName(ranks[0]) != "Psycho" {
+		t.Fatal("unexpected song rank", ranks[0])
+	}
+	if ranks[1].Key != "아이유 - love poem" || ranks[1].Title != "" || chartsRankName(ranks[1]) != "Love poem" {
+		t.Fatal("unexpected album rank", ranks[1])
+	}
+}
+
+func TestChartsRankAlert(t *testing.T) {
+	tests := []struct {
+		name         string
+		rank         int
+		peak         int
+		previousRank int
+		allKill      bool
+		alert        chartsAlertType
+		ok           bool
+	}{
+		{name: "entry", rank: 40, alert: chartsAlertEntry, ok: true},
+		{name: "new peak", rank: 5, peak: 8, previousRank: 8, alert: chartsAlertPeak, ok: true},
+		{name: "below peak", rank: 9, peak: 8, previousRank: 12},
+		{name: "same as peak", rank: 8, peak: 8, previousRank: 10},
+		{name: "number one", rank: 1, peak: 2, previousRank: 2, alert: chartsAlertNumberOne, ok: true},
+		{name: "entry at number one", rank: 1, alert: chartsAlertNumberOne, ok: true},
+		{name: "still number one", rank: 1, peak: 1, previousRank: 1, allKill: true},
+		{name: "all-kill", rank: 1, peak: 1, previousRank: 3, allKill: true, alert: chartsAlertAllKill, ok: true},
+	}
+
+	for _, test := range tests {
+		alert, ok := chartsRankAlert(models.ChartsRank{Rank: test.rank}, test.peak, test.previousRank, test.allKill)
+		if ok != test.ok || (ok && alert.Type != test.alert) {
+			t.Fatal(test.name, ": unexpected alert", alert.Type, ok)
+		}
+	}
+}
+
+func TestChartsKeyAcrossProviders(t *testing.T) {
+	for _, song := range [][2]string{
+		{"아이유", "에잇(Prod.&Feat. SUGA of BTS)"},
+		{"아이유(IU)", "에잇 (Prod.&Feat. SUGA of BTS)"},
+		{"아이유 (IU), SUGA", "에잇 [Prod. & Feat. SUGA of BTS]"},
+		{"아이유", "에잇 feat. SUGA"},
+	} {
+		if key := chartsKey(song[0], song[1]); key != "아이유 - 에잇" {
+			t.Fatalf("unexpected key %q for %s - %s", key, song[0], song[1])
+		}
+	}
+
+	// titles which are only parentheses are kept
+	if key := chartsKey("(G)I-DLE", "(Feat. Someone)"); key != "(g)i-dle - (feat. someone)" {
+		t.Fatalf("unexpected key %q for a title without a name", key)
+	}
+	if key := chartsQueryKey("아이유(IU) - 에잇"); key != "아이유 - 에잇" {
+		t.Fatalf("unexpected key %q for an artist - title query", key)
+	}
+}
+
+func TestChartsWatchMatches(t *testing.T) {
+	songs := chartsRanks([]charts.Entry{
+		{Title: "에잇(Prod.&Feat. SUGA of BTS)", Artist: "아이유, SUGA", Rank: 1},
+		{Title: "Psycho", Artist: "Red Velvet", Rank: 2},
+	})
+
+	if !chartsWatchMatches("SUGA", songs[0]) || !chartsWatchMatches("아이유", songs[0]) {
+		t.Fatal("expected every artist of a collaboration to match")
+	}
+	if !chartsWatchMatches("psycho", songs[1]) || !chartsWatchMatches("red velvet - psycho", songs[1]) {
+		t.Fatal("expected the title and the key to match")
+	}
+	if !chartsWatchMatches("에잇", songs[0]) || !chartsWatchMatches("아이유(IU) - 에잇", songs[0]) {
+		t.Fatal("expected the title without featured artists to match")
+	}
+	if chartsWatchMatches("red", songs[1]) || chartsWatchMatches("", songs[1]) {
+		t.Fatal("parts of names should not match")
+	}
+}
+
+func TestRenderChartsHistory(t *testing.T) {
+	start := time.Date(2018, 12, 24, 14, 0, 0, 0, time.UTC)
+	graph, err := renderChartsHistory([]chartsHistorySeries{
+		{
+			Title: "Melon Realtime",
+			Color: chartsCollectedCharts[0].Color,
+			Points: []chartsHistoryPoint{
+				{Time: start, Rank: 48},
+				{Time: start.Add(time.Hour), Rank: 12},
+				{Time: start.Add(2 * time.Hour), Rank: 1},
+			},
+		},
+		{
+			Title:  "Melon Daily",
+			Color:  chartsCollectedCharts[1].Color,
+			Points: []chartsHistoryPoint{{Time: start.Add(2 * time.Hour), Rank: 3}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(graph))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != chartsHistoryWidth || img.Bounds().Dy() != chartsHistoryHeight {
+		t.Fatal("unexpected size", img.Bounds())
+	}
+}
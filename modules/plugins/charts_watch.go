@@ -0,0 +1,143 @@
+package plugins
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/commands"
+	"github.com/globalsign/mgo/bson"
+)
+
+// [p]charts watch <artist or song> <channel>
+func (m *Charts) actionWatch(ctx *commands.Context) {
+	query := strings.ToLower(strings.TrimSpace(ctx.String("query")))
+	channel := ctx.Channel("channel")
+
+	count, err := helpers.MdbCount(models.ChartsWatchesTable, bson.M{"channelid": channel.ID, "query": query})
+	helpers.Relax(err)
+	if count > 0 {
+		_, err = helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watch-duplicate", query, channel.ID))
+		helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+		return
+	}
+
+	newID, err := helpers.MDbInsert(
+		models.ChartsWatchesTable,
+		models.ChartsWatchEntry{
+			GuildID:   channel.GuildID,
+			ChannelID: channel.ID,
+			UserID:    ctx.Msg.Author.ID,
+			Query:     query,
+			CreatedAt: time.Now(),
+		},
+	)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(newID),
+		models.EventlogTargetTypeRobyulChartsWatch, ctx.Msg.Author.ID,
+		models.EventlogTypeRobyulChartsWatchAdd, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "charts_watch_query",
+				Value: query,
+			},
+			{
+				Key:   "charts_watch_channelid",
+				Value: channel.ID,
+				Type:  models.EventlogTargetTypeChannel,
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	_, err = helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watch-added", query, channel.ID))
+	helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+	cache.GetLogger().WithField("module", "charts").Info(fmt.Sprintf("Added chart watch %s to Channel #%s on Guild #%s", query, channel.ID, channel.GuildID))
+}
+
+// [p]charts unwatch <id>
+func (m *Charts) actionUnwatch(ctx *commands.Context) {
+	var entryBucket models.ChartsWatchEntry
+	err := helpers.MdbOne(
+		helpers.MdbCollection(models.ChartsWatchesTable).Find(bson.M{"guildid": ctx.GuildID, "_id": helpers.HumanToMdbId(ctx.String("id"))}),
+		&entryBucket,
+	)
+	if helpers.IsMdbNotFound(err) {
+		_, err = helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watch-not-found"))
+		helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+		return
+	}
+	helpers.Relax(err)
+
+	err = helpers.MDbDelete(models.ChartsWatchesTable, entryBucket.ID)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), entryBucket.GuildID, helpers.MdbIdToHuman(entryBucket.ID),
+		models.EventlogTargetTypeRobyulChartsWatch, ctx.Msg.Author.ID,
+		models.EventlogTypeRobyulChartsWatchRemove, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "charts_watch_query",
+				Value: entryBucket.Query,
+			},
+			{
+				Key:   "charts_watch_channelid",
+				Value: entryBucket.ChannelID,
+				Type:  models.EventlogTargetTypeChannel,
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	_, err = helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watch-removed", entryBucket.Query))
+	helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+}
+
+// [p]charts watches
+func (m *Charts) actionWatches(ctx *commands.Context) {
+	var entryBucket []models.ChartsWatchEntry
+	err := helpers.MDbIter(helpers.MdbCollection(models.ChartsWatchesTable).Find(bson.M{"guildid": ctx.GuildID})).All(&entryBucket)
+	helpers.Relax(err)
+
+	if len(entryBucket) <= 0 {
+		_, err = helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watches-none"))
+		helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+		return
+	}
+
+	var resultMessage string
+	for _, entry := range entryBucket {
+		resultMessage += helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watches-entry",
+			helpers.MdbIdToHuman(entry.ID), entry.Query, entry.ChannelID) + "\n"
+	}
+	resultMessage += helpers.GetTextForMessage(ctx.Msg, "plugins.charts.watches-total", len(entryBucket))
+
+	_, err = helpers.SendMessage(ctx.Msg.ChannelID, resultMessage)
+	helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+}
+
+// [p]charts history <song>
+func (m *Charts) actionHistory(ctx *commands.Context) {
+	ctx.Session.ChannelTyping(ctx.Msg.ChannelID)
+
+	rank, series, err := getChartsHistory(ctx.String("song"), time.Now().AddDate(0, 0, -chartsHistoryDays))
+	helpers.Relax(err)
+
+	if len(series) <= 0 {
+		_, err = helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetTextForMessage(ctx.Msg, "plugins.charts.history-not-found", ctx.String("song")))
+		helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+		return
+	}
+
+	graph, err := renderChartsHistory(series)
+	helpers.Relax(err)
+
+	_, err = helpers.SendFile(ctx.Msg.ChannelID, "charts-history.png", bytes.NewReader(graph),
+		helpers.GetTextForMessage(ctx.Msg, "plugins.charts.history-message", chartsRankName(rank), rank.Artist, chartsHistoryDays))
+	helpers.RelaxMessage(err, ctx.Msg.ChannelID, ctx.Msg.ID)
+}
@@ -4,22 +4,19 @@ import (
 	"fmt"
 	"math/rand"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/modules/commands"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
 )
 
 type Choice struct{}
 
+// Commands returns no commands, all commands of this plugin are declared in DeclareCommands
 func (c *Choice) Commands() []string {
-	return []string{
-		"choose",
-		"choice",
-		"roll",
-	}
+	return []string{}
 }
 
 var (
@@ -30,41 +27,63 @@ func (c *Choice) Init(session *shardmanager.Manager) {
 	splitChooseRegex = regexp.MustCompile(`'.*?'|".*?"|\S+`)
 }
 
+func (c *Choice) DeclareCommands() []*commands.Command {
+	return []*commands.Command{
+		{
+			Name:        "choose",
+			Aliases:     []string{"choice"},
+			Description: "Chooses one of the given options. Use quotes for options with spaces.",
+			Module:      helpers.ModulePermChoice,
+			Arguments: []commands.Argument{
+				{Name: "options", Description: "two or more options", Type: commands.ArgumentTypeRest},
+			},
+			Handler: c.actionChoose,
+		},
+		{
+			Name:        "roll",
+			Description: "Rolls a number between 1 and the maximum.",
+			Module:      helpers.ModulePermChoice,
+			Arguments: []commands.Argument{
+				{Name: "max", Description: "the highest possible number, default: 100", Type: commands.ArgumentTypeInt, Optional: true},
+			},
+			Handler: c.actionRoll,
+		},
+	}
+}
+
 func (c *Choice) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
-	if !helpers.ModuleIsAllowed(msg.ChannelID, msg.ID, msg.Author.ID, helpers.ModulePermChoice) {
+}
+
+// [p]choose <option a> <option b> [...]
+func (c *Choice) actionChoose(ctx *commands.Context) {
+	choices := splitChooseRegex.FindAllString(ctx.String("options"), -1)
+
+	if len(choices) <= 1 {
+		_, err := helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+		helpers.Relax(err)
 		return
 	}
 
-	switch command {
-	case "choose", "choice": // [p]choose <option a> <option b> [...]
-		choices := splitChooseRegex.FindAllString(content, -1)
+	choice := choices[rand.Intn(len(choices))]
+	choice = strings.Trim(choice, "\"")
+	choice = strings.Trim(choice, "'")
+
+	_, err := helpers.SendMessage(ctx.Msg.ChannelID, "I've chosen `"+choice+"` <a:ablobsmile:393869335312990209>")
+	helpers.Relax(err)
+}
 
-		if len(choices) <= 1 {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+// [p]roll [<max numb, default: 100>]
+func (c *Choice) actionRoll(ctx *commands.Context) {
+	maxN := 100
+	if ctx.Has("max") {
+		maxN = ctx.Int("max")
+		if maxN < 1 {
+			_, err := helpers.SendMessage(ctx.Msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
 			helpers.Relax(err)
 			return
 		}
-
-		choice := choices[rand.Intn(len(choices))]
-		choice = strings.Trim(choice, "\"")
-		choice = strings.Trim(choice, "\"")
-
-		_, err := helpers.SendMessage(msg.ChannelID, "I've chosen `"+choice+"` <a:ablobsmile:393869335312990209>")
-		helpers.Relax(err)
-		return
-	case "roll": // [p]roll [<max numb, default: 100>]
-		var err error
-		maxN := 100
-		if content != "" {
-			maxN, err = strconv.Atoi(content)
-			if err != nil || maxN < 1 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
-				helpers.Relax(err)
-				return
-			}
-		}
-		_, err = helpers.SendMessage(msg.ChannelID, fmt.Sprintf("<@%s> :game_die: %d :game_die:", msg.Author.ID, rand.Intn(maxN)+1))
-		helpers.Relax(err)
-		return
 	}
+
+	_, err := helpers.SendMessage(ctx.Msg.ChannelID, fmt.Sprintf("<@%s> :game_die: %d :game_die:", ctx.Msg.Author.ID, rand.Intn(maxN)+1))
+	helpers.Relax(err)
 }
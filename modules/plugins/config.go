@@ -2,12 +2,15 @@ package plugins
 
 import (
 	"strings"
+	"time"
 
 	"fmt"
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/helpers/dgwidgets"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/commands"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
 	"github.com/sirupsen/logrus"
@@ -20,6 +23,8 @@ type Config struct{}
 func (m *Config) Commands() []string {
 	return []string{
 		"config",
+		"language",
+		"alias",
 	}
 }
 
@@ -27,6 +32,11 @@ func (m *Config) Init(session *shardmanager.Manager) {
 }
 
 func (m *Config) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
+	if command == "language" {
+		m.actionUserLanguage(strings.Fields(content), msg)
+		return
+	}
+
 	if !helpers.ModuleIsAllowed(msg.ChannelID, msg.ID, msg.Author.ID, helpers.ModulePermMod) {
 		return
 	}
@@ -35,6 +45,9 @@ func (m *Config) Action(command string, content string, msg *discordgo.Message,
 	args := strings.Fields(content)
 
 	action := m.actionStart
+	if command == "alias" {
+		action = m.actionAliasStart
+	}
 	for action != nil {
 		action = action(args, msg, &result)
 	}
@@ -47,7 +60,7 @@ func (m *Config) actionStart(args []string, in *discordgo.Message, out **discord
 		switch args[0] {
 		case "set":
 			if len(args) < 2 {
-				*out = m.newMsg(helpers.GetText("bot.arguments.too-few"))
+				*out = m.newMsg(in, "bot.arguments.too-few")
 				return m.actionFinish
 			}
 			switch args[1] {
@@ -55,8 +68,16 @@ func (m *Config) actionStart(args []string, in *discordgo.Message, out **discord
 				return m.actionSetAdmin
 			case "mod":
 				return m.actionSetMod
+			case "language", "lang":
+				return m.actionSetLanguage
+			case "cooldown":
+				return m.actionSetCooldown
 			}
 			break
+		case "disable":
+			return m.actionDisableCommand
+		case "enable":
+			return m.actionEnableCommand
 		}
 	}
 
@@ -66,12 +87,12 @@ func (m *Config) actionStart(args []string, in *discordgo.Message, out **discord
 // [p]config set admin <role name or id>
 func (m *Config) actionSetAdmin(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
 	if !helpers.IsAdmin(in) {
-		*out = m.newMsg("admin.no_permission")
+		*out = m.newMsg(in, "admin.no_permission")
 		return m.actionFinish
 	}
 
 	if len(args) < 3 {
-		*out = m.newMsg(helpers.GetText("bot.arguments.too-few"))
+		*out = m.newMsg(in, "bot.arguments.too-few")
 		return m.actionFinish
 	}
 
@@ -125,7 +146,7 @@ func (m *Config) actionSetAdmin(args []string, in *discordgo.Message, out **disc
 	}
 
 	if !roleAdded && !roleRemoved {
-		*out = m.newMsg("bot.arguments.invalid")
+		*out = m.newMsg(in, "bot.arguments.invalid")
 		return m.actionFinish
 	}
 
@@ -136,11 +157,11 @@ func (m *Config) actionSetAdmin(args []string, in *discordgo.Message, out **disc
 	// TODO: eventlog
 
 	if roleAdded {
-		*out = m.newMsg("plugins.config.admin-role-added")
+		*out = m.newMsg(in, "plugins.config.admin-role-added")
 		return m.actionFinish
 	}
 	if roleRemoved {
-		*out = m.newMsg("plugins.config.admin-role-removed")
+		*out = m.newMsg(in, "plugins.config.admin-role-removed")
 		return m.actionFinish
 	}
 	return nil
@@ -149,12 +170,12 @@ func (m *Config) actionSetAdmin(args []string, in *discordgo.Message, out **disc
 // [p]config set mod <role name or id>
 func (m *Config) actionSetMod(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
 	if !helpers.IsAdmin(in) {
-		*out = m.newMsg("admin.no_permission")
+		*out = m.newMsg(in, "admin.no_permission")
 		return m.actionFinish
 	}
 
 	if len(args) < 3 {
-		*out = m.newMsg(helpers.GetText("bot.arguments.too-few"))
+		*out = m.newMsg(in, "bot.arguments.too-few")
 		return m.actionFinish
 	}
 
@@ -208,7 +229,7 @@ func (m *Config) actionSetMod(args []string, in *discordgo.Message, out **discor
 	}
 
 	if !roleAdded && !roleRemoved {
-		*out = m.newMsg("bot.arguments.invalid")
+		*out = m.newMsg(in, "bot.arguments.invalid")
 		return m.actionFinish
 	}
 
@@ -217,16 +238,472 @@ func (m *Config) actionSetMod(args []string, in *discordgo.Message, out **discor
 	helpers.Relax(err)
 
 	if roleAdded {
-		*out = m.newMsg("plugins.config.mod-role-added")
+		*out = m.newMsg(in, "plugins.config.mod-role-added")
 		return m.actionFinish
 	}
 	if roleRemoved {
-		*out = m.newMsg("plugins.config.mod-role-removed")
+		*out = m.newMsg(in, "plugins.config.mod-role-removed")
 		return m.actionFinish
 	}
 	return nil
 }
 
+// [p]config set language [<language>]
+func (m *Config) actionSetLanguage(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+
+	if len(args) < 3 {
+		*out = m.newMsg(in, "plugins.config.language-status",
+			helpers.GetLocaleForGuild(in.GuildID), strings.Join(helpers.GetLocales(), ", "),
+			helpers.GetPrefixForServer(in.GuildID))
+		return m.actionFinish
+	}
+
+	if !helpers.IsAdmin(in) {
+		*out = m.newMsg(in, "admin.no_permission")
+		return m.actionFinish
+	}
+
+	newLanguage := strings.ToLower(args[2])
+	if !helpers.IsLocale(newLanguage) {
+		*out = m.newMsg(in, "plugins.config.language-invalid", strings.Join(helpers.GetLocales(), ", "))
+		return m.actionFinish
+	}
+	if newLanguage == helpers.DefaultLocale {
+		newLanguage = ""
+	}
+
+	oldLanguage := guildConfig.Language
+	guildConfig.Language = newLanguage
+
+	err := helpers.GuildSettingsSet(in.GuildID, guildConfig)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), in.GuildID, in.GuildID,
+		models.EventlogTargetTypeGuild, in.Author.ID,
+		models.EventlogTypeRobyulLanguageUpdate, "",
+		[]models.ElasticEventlogChange{
+			{
+				Key:      "language",
+				OldValue: oldLanguage,
+				NewValue: guildConfig.Language,
+			},
+		},
+		nil, false)
+	helpers.RelaxLog(err)
+
+	if guildConfig.Language == "" {
+		*out = m.newMsg(in, "plugins.config.language-reset")
+		return m.actionFinish
+	}
+	*out = m.newMsg(in, "plugins.config.language-set", guildConfig.Language)
+	return m.actionFinish
+}
+
+// [p]config set cooldown <command> <duration, off, or default>
+func (m *Config) actionSetCooldown(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	if !helpers.IsAdmin(in) {
+		*out = m.newMsg(in, "admin.no_permission")
+		return m.actionFinish
+	}
+
+	if len(args) < 4 {
+		*out = m.newMsg(in, "bot.arguments.too-few")
+		return m.actionFinish
+	}
+
+	command := strings.ToLower(strings.TrimPrefix(args[2], helpers.GetPrefixForServer(in.GuildID)))
+	if !m.isCommand(command) {
+		*out = m.newMsg(in, "plugins.config.cooldown-unknown-command")
+		return m.actionFinish
+	}
+
+	var reset bool
+	var duration time.Duration
+	switch strings.ToLower(args[3]) {
+	case "default", "reset":
+		reset = true
+	case "off", "none", "disable", "0":
+		duration = 0
+	default:
+		var err error
+		duration, err = commands.ParseDuration(args[3])
+		if err != nil || duration <= 0 {
+			*out = m.newMsg(in, "bot.arguments.invalid")
+			return m.actionFinish
+		}
+	}
+
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+
+	oldValue := "default"
+	newCooldowns := make([]models.CommandCooldown, 0)
+	for _, override := range guildConfig.CommandCooldowns {
+		if override.Command == command {
+			oldValue = override.Duration.String()
+			continue
+		}
+		newCooldowns = append(newCooldowns, override)
+	}
+
+	newValue := "default"
+	if !reset {
+		newCooldowns = append(newCooldowns, models.CommandCooldown{
+			Command:  command,
+			Duration: duration,
+		})
+		newValue = duration.String()
+	}
+	guildConfig.CommandCooldowns = newCooldowns
+
+	err := helpers.GuildSettingsSet(in.GuildID, guildConfig)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), in.GuildID, in.GuildID,
+		models.EventlogTargetTypeGuild, in.Author.ID,
+		models.EventlogTypeRobyulCommandCooldownUpdate, "",
+		[]models.ElasticEventlogChange{
+			{
+				Key:      "cooldown_" + command,
+				OldValue: oldValue,
+				NewValue: newValue,
+			},
+		},
+		nil, false)
+	helpers.RelaxLog(err)
+
+	if reset {
+		*out = m.newMsg(in, "plugins.config.cooldown-reset", command)
+		return m.actionFinish
+	}
+	if duration <= 0 {
+		*out = m.newMsg(in, "plugins.config.cooldown-disabled", command)
+		return m.actionFinish
+	}
+	*out = m.newMsg(in, "plugins.config.cooldown-set", command, helpers.HumanizeDuration(duration))
+	return m.actionFinish
+}
+
+// isCommand returns true if a plugin reacts to the command
+func (m *Config) isCommand(command string) bool {
+	for _, pluginCommand := range cache.GetPluginList() {
+		if pluginCommand == command {
+			return true
+		}
+	}
+	for _, pluginCommand := range cache.GetPluginExtendedList() {
+		if pluginCommand == command {
+			return true
+		}
+	}
+	return false
+}
+
+// [p]config disable <command>
+func (m *Config) actionDisableCommand(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	if !helpers.IsAdmin(in) {
+		*out = m.newMsg(in, "admin.no_permission")
+		return m.actionFinish
+	}
+
+	command := strings.ToLower(strings.TrimPrefix(args[1], helpers.GetPrefixForServer(in.GuildID)))
+	if !m.isCommand(command) {
+		*out = m.newMsg(in, "plugins.config.command-unknown")
+		return m.actionFinish
+	}
+	if m.isProtectedCommand(command) {
+		*out = m.newMsg(in, "plugins.config.command-disable-protected", command)
+		return m.actionFinish
+	}
+
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+
+	if helpers.IsCommandDisabled(in.GuildID, command) {
+		*out = m.newMsg(in, "plugins.config.command-already-disabled", command)
+		return m.actionFinish
+	}
+
+	guildConfig.DisabledCommands = append(guildConfig.DisabledCommands, command)
+
+	err := helpers.GuildSettingsSet(in.GuildID, guildConfig)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), in.GuildID, in.GuildID,
+		models.EventlogTargetTypeGuild, in.Author.ID,
+		models.EventlogTypeRobyulCommandDisable, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "command",
+				Value: command,
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	*out = m.newMsg(in, "plugins.config.command-disabled", command)
+	return m.actionFinish
+}
+
+// [p]config enable <command>
+func (m *Config) actionEnableCommand(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	if !helpers.IsAdmin(in) {
+		*out = m.newMsg(in, "admin.no_permission")
+		return m.actionFinish
+	}
+
+	command := strings.ToLower(strings.TrimPrefix(args[1], helpers.GetPrefixForServer(in.GuildID)))
+
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+
+	newDisabledCommands := make([]string, 0)
+	for _, disabledCommand := range guildConfig.DisabledCommands {
+		if disabledCommand == command {
+			continue
+		}
+		newDisabledCommands = append(newDisabledCommands, disabledCommand)
+	}
+	if len(newDisabledCommands) == len(guildConfig.DisabledCommands) {
+		*out = m.newMsg(in, "plugins.config.command-not-disabled", command)
+		return m.actionFinish
+	}
+	guildConfig.DisabledCommands = newDisabledCommands
+
+	err := helpers.GuildSettingsSet(in.GuildID, guildConfig)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), in.GuildID, in.GuildID,
+		models.EventlogTargetTypeGuild, in.Author.ID,
+		models.EventlogTypeRobyulCommandEnable, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "command",
+				Value: command,
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	*out = m.newMsg(in, "plugins.config.command-enabled", command)
+	return m.actionFinish
+}
+
+func (m *Config) actionAliasStart(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	cache.GetSession().SessionForGuildS(in.GuildID).ChannelTyping(in.ChannelID)
+
+	if len(args) >= 1 {
+		switch args[0] {
+		case "add":
+			return m.actionAliasAdd
+		case "remove", "delete":
+			return m.actionAliasRemove
+		}
+	}
+
+	return m.actionAliasList
+}
+
+// [p]alias add <alias> <command> [<arguments>]
+func (m *Config) actionAliasAdd(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	if !helpers.IsAdmin(in) {
+		*out = m.newMsg(in, "admin.no_permission")
+		return m.actionFinish
+	}
+
+	if len(args) < 3 {
+		*out = m.newMsg(in, "bot.arguments.too-few")
+		return m.actionFinish
+	}
+
+	prefix := helpers.GetPrefixForServer(in.GuildID)
+	alias := strings.ToLower(strings.TrimPrefix(args[1], prefix))
+	command := strings.ToLower(strings.TrimPrefix(args[2], prefix))
+	arguments := strings.Join(args[3:], " ")
+
+	if m.isCommand(alias) {
+		*out = m.newMsg(in, "plugins.config.alias-is-command", alias)
+		return m.actionFinish
+	}
+	if !m.isCommand(command) {
+		*out = m.newMsg(in, "plugins.config.command-unknown")
+		return m.actionFinish
+	}
+	if helpers.IsCommandDisabled(in.GuildID, command) {
+		*out = m.newMsg(in, "plugins.config.alias-command-disabled", command, prefix)
+		return m.actionFinish
+	}
+	if _, ok := helpers.GetCommandAlias(in.GuildID, alias); ok {
+		*out = m.newMsg(in, "plugins.config.alias-already-exists", alias)
+		return m.actionFinish
+	}
+
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+	guildConfig.CommandAliases = append(guildConfig.CommandAliases, models.CommandAlias{
+		Alias:     alias,
+		Command:   command,
+		Arguments: arguments,
+	})
+
+	err := helpers.GuildSettingsSet(in.GuildID, guildConfig)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), in.GuildID, in.GuildID,
+		models.EventlogTargetTypeGuild, in.Author.ID,
+		models.EventlogTypeRobyulCommandAliasAdd, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "alias",
+				Value: alias,
+			},
+			{
+				Key:   "command",
+				Value: strings.TrimSpace(command + " " + arguments),
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	*out = m.newMsg(in, "plugins.config.alias-added", prefix, alias, prefix, strings.TrimSpace(command+" "+arguments))
+	return m.actionFinish
+}
+
+// [p]alias remove <alias>
+func (m *Config) actionAliasRemove(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	if !helpers.IsAdmin(in) {
+		*out = m.newMsg(in, "admin.no_permission")
+		return m.actionFinish
+	}
+
+	if len(args) < 2 {
+		*out = m.newMsg(in, "bot.arguments.too-few")
+		return m.actionFinish
+	}
+
+	prefix := helpers.GetPrefixForServer(in.GuildID)
+	alias := strings.ToLower(strings.TrimPrefix(args[1], prefix))
+
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+
+	var removedAlias models.CommandAlias
+	newAliases := make([]models.CommandAlias, 0)
+	for _, commandAlias := range guildConfig.CommandAliases {
+		if commandAlias.Alias == alias {
+			removedAlias = commandAlias
+			continue
+		}
+		newAliases = append(newAliases, commandAlias)
+	}
+	if removedAlias.Alias == "" {
+		*out = m.newMsg(in, "plugins.config.alias-not-found", alias)
+		return m.actionFinish
+	}
+	guildConfig.CommandAliases = newAliases
+
+	err := helpers.GuildSettingsSet(in.GuildID, guildConfig)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), in.GuildID, in.GuildID,
+		models.EventlogTargetTypeGuild, in.Author.ID,
+		models.EventlogTypeRobyulCommandAliasRemove, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "alias",
+				Value: removedAlias.Alias,
+			},
+			{
+				Key:   "command",
+				Value: strings.TrimSpace(removedAlias.Command + " " + removedAlias.Arguments),
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	*out = m.newMsg(in, "plugins.config.alias-removed", prefix, alias)
+	return m.actionFinish
+}
+
+// [p]alias [list]
+func (m *Config) actionAliasList(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
+	prefix := helpers.GetPrefixForServer(in.GuildID)
+	guildConfig := helpers.GuildSettingsGetCached(in.GuildID)
+
+	if len(guildConfig.CommandAliases) <= 0 && len(guildConfig.DisabledCommands) <= 0 {
+		*out = m.newMsg(in, "plugins.config.alias-list-empty", prefix)
+		return m.actionFinish
+	}
+
+	var listText string
+	for _, commandAlias := range guildConfig.CommandAliases {
+		listText += fmt.Sprintf("`%s%s` → `%s%s`\n",
+			prefix, commandAlias.Alias, prefix, strings.TrimSpace(commandAlias.Command+" "+commandAlias.Arguments))
+	}
+	if len(guildConfig.DisabledCommands) > 0 {
+		listText += helpers.GetTextForMessage(in, "plugins.config.alias-list-disabled",
+			prefix+strings.Join(guildConfig.DisabledCommands, "`, `"+prefix))
+	}
+
+	for _, page := range helpers.Pagify(listText, "\n") {
+		_, err := helpers.SendMessage(in.ChannelID, page)
+		helpers.RelaxMessage(err, in.ChannelID, in.ID)
+	}
+	return nil
+}
+
+// isProtectedCommand returns true if the command can not be disabled, because it is needed to enable commands again
+func (m *Config) isProtectedCommand(command string) bool {
+	for _, protectedCommand := range m.Commands() {
+		if protectedCommand == command {
+			return true
+		}
+	}
+	return false
+}
+
+// [p]language [set <language>|reset]
+func (m *Config) actionUserLanguage(args []string, in *discordgo.Message) {
+	prefix := helpers.GetPrefixForServer(in.GuildID)
+
+	if len(args) < 1 {
+		_, err := helpers.SendMessage(in.ChannelID, helpers.GetTextForMessage(in, "plugins.config.language-user-status",
+			helpers.GetLocaleForUser(in.GuildID, in.Author.ID), strings.Join(helpers.GetLocales(), ", "), prefix, prefix))
+		helpers.RelaxMessage(err, in.ChannelID, in.ID)
+		return
+	}
+
+	switch args[0] {
+	case "set":
+		if len(args) < 2 {
+			_, err := helpers.SendMessage(in.ChannelID, helpers.GetTextForMessage(in, "bot.arguments.too-few"))
+			helpers.RelaxMessage(err, in.ChannelID, in.ID)
+			return
+		}
+
+		newLanguage := strings.ToLower(args[1])
+		if !helpers.IsLocale(newLanguage) {
+			_, err := helpers.SendMessage(in.ChannelID, helpers.GetTextForMessage(in, "plugins.config.language-invalid",
+				strings.Join(helpers.GetLocales(), ", ")))
+			helpers.RelaxMessage(err, in.ChannelID, in.ID)
+			return
+		}
+
+		err := helpers.SetLocaleForUser(in.Author.ID, newLanguage)
+		helpers.Relax(err)
+
+		_, err = helpers.SendMessage(in.ChannelID, helpers.GetTextForMessage(in, "plugins.config.language-user-set", newLanguage))
+		helpers.RelaxMessage(err, in.ChannelID, in.ID)
+		return
+	case "reset":
+		err := helpers.SetLocaleForUser(in.Author.ID, "")
+		helpers.Relax(err)
+
+		_, err = helpers.SendMessage(in.ChannelID, helpers.GetTextForMessage(in, "plugins.config.language-user-reset"))
+		helpers.RelaxMessage(err, in.ChannelID, in.ID)
+		return
+	}
+
+	_, err := helpers.SendMessage(in.ChannelID, helpers.GetTextForMessage(in, "bot.arguments.invalid"))
+	helpers.RelaxMessage(err, in.ChannelID, in.ID)
+}
+
 // [p]config
 func (m *Config) actionStatus(args []string, in *discordgo.Message, out **discordgo.MessageSend) configAction {
 	channel, err := helpers.GetChannel(in.ChannelID)
@@ -243,7 +720,7 @@ func (m *Config) actionStatus(args []string, in *discordgo.Message, out **discor
 	}
 
 	if !helpers.IsModByID(targetGuild.ID, in.Author.ID) && !helpers.IsRobyulMod(in.Author.ID) {
-		*out = m.newMsg("mod.no_permission")
+		*out = m.newMsg(in, "mod.no_permission")
 		return m.actionFinish
 	}
 
@@ -377,6 +854,25 @@ func (m *Config) actionStatus(args []string, in *discordgo.Message, out **discor
 		customCommandsText = "<@&" + guildConfig.CustomCommandsAddRoleID + "> and Moderators can add commands"
 	}
 
+	cooldownsText := "Default"
+	if len(guildConfig.CommandCooldowns) > 0 {
+		cooldownsText = ""
+		for _, override := range guildConfig.CommandCooldowns {
+			if override.Duration <= 0 {
+				cooldownsText += fmt.Sprintf("`%s%s`: Disabled\n", prefix, override.Command)
+				continue
+			}
+			cooldownsText += fmt.Sprintf("`%s%s`: %s\n", prefix, override.Command, helpers.HumanizeDuration(override.Duration))
+		}
+	}
+
+	commandsText := fmt.Sprintf("%d Aliases, %d Disabled Commands", len(guildConfig.CommandAliases), len(guildConfig.DisabledCommands))
+
+	languageText := helpers.GetLocaleForGuild(targetGuild.ID)
+	if guildConfig.Language == "" {
+		languageText += " (Default)"
+	}
+
 	// TODO: info if blacklisted, or limited guild
 
 	pages = append(pages, &discordgo.MessageEmbed{
@@ -435,6 +931,18 @@ func (m *Config) actionStatus(args []string, in *discordgo.Message, out **discor
 				Name:  "Custom Commands",
 				Value: customCommandsText,
 			},
+			{
+				Name:  "Cooldowns",
+				Value: cooldownsText + fmt.Sprintf("\n`%sconfig set cooldown <command> <duration, off, or default>`", prefix),
+			},
+			{
+				Name:  "Commands",
+				Value: commandsText + fmt.Sprintf("\n`%salias list`", prefix),
+			},
+			{
+				Name:  "Language",
+				Value: languageText + fmt.Sprintf("\n`%sconfig set language <language>`", prefix),
+			},
 		},
 	})
 
@@ -468,8 +976,8 @@ func (m *Config) actionFinish(args []string, in *discordgo.Message, out **discor
 	return nil
 }
 
-func (m *Config) newMsg(content string) *discordgo.MessageSend {
-	return &discordgo.MessageSend{Content: helpers.GetText(content)}
+func (m *Config) newMsg(in *discordgo.Message, content string, replacements ...interface{}) *discordgo.MessageSend {
+	return &discordgo.MessageSend{Content: helpers.GetTextForMessage(in, content, replacements...)}
 }
 
 func (m *Config) Relax(err error) {
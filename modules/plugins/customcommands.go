@@ -93,16 +93,16 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 					if guildConfig.CustomCommandsEveryoneCanAdd {
 						guildConfig.CustomCommandsEveryoneCanAdd = false
 						guildConfig.CustomCommandsAddRoleID = ""
-						message = helpers.GetText("plugins.customcommands.disabled-everyone-canadd")
+						message = helpers.GetTextForMessage(msg, "plugins.customcommands.disabled-everyone-canadd")
 					} else {
 						guildConfig.CustomCommandsEveryoneCanAdd = true
 						guildConfig.CustomCommandsAddRoleID = ""
-						message = helpers.GetText("plugins.customcommands.enabled-everyone-canadd")
+						message = helpers.GetTextForMessage(msg, "plugins.customcommands.enabled-everyone-canadd")
 					}
 				} else {
 					guildConfig.CustomCommandsEveryoneCanAdd = false
 					guildConfig.CustomCommandsAddRoleID = targetRole.ID
-					message = helpers.GetTextF("plugins.customcommands.role-canadd", targetRole.Name)
+					message = helpers.GetTextForMessage(msg, "plugins.customcommands.role-canadd", targetRole.Name)
 				}
 
 				err = helpers.GuildSettingsSet(channel.GuildID, guildConfig)
@@ -120,17 +120,17 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 			helpers.Relax(err)
 
 			if !cc.canAddCommand(channel.GuildID, msg.Author.ID, nil) {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("mod.no_permission"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "mod.no_permission"))
 				return
 			}
 
 			if len(args) < 3 && (len(msg.Attachments) <= 0 && len(args) < 2) {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 				return
 			}
 
 			if helpers.CommandExists(args[1]) {
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.add-command-already-exists"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.add-command-already-exists"))
 				helpers.Relax(err)
 				return
 			}
@@ -141,7 +141,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				&entryBucket,
 			)
 			if err == nil {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.add-keyword-already-exists"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.add-keyword-already-exists"))
 				helpers.Relax(err)
 				return
 			} else {
@@ -162,12 +162,12 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				if cc.isAllowedFiletype(filetype) {
 					// user is allowed to upload files?
 					if helpers.UseruploadsIsDisabled(msg.Author.ID) {
-						helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.errors.useruploads-disabled"))
+						helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.errors.useruploads-disabled"))
 						return
 					}
 					// <= 20 MB
 					if msg.Attachments[0].Size > 20e+6 {
-						helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.fileupload-too-big"))
+						helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.fileupload-too-big"))
 						return
 					}
 					// upload file
@@ -184,7 +184,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 			content := strings.TrimSpace(strings.Replace(content, strings.Join(args[:2], " "), "", 1))
 
 			if content == "" && objectName == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 				return
 			}
 
@@ -220,7 +220,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				}, false)
 			helpers.RelaxLog(err)
 
-			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.add-success"))
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.add-success"))
 			helpers.Relax(err)
 			customCommandsCacheLock.Lock()
 			defer customCommandsCacheLock.Unlock()
@@ -238,7 +238,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				[]bson.M{{"$match": bson.M{"guildid": channel.GuildID}}, {"$sample": bson.M{"size": 1}}},
 				&entryBucket)
 			if helpers.IsMdbNotFound(err) {
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.list-empty"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.list-empty"))
 				helpers.Relax(err)
 				return
 			}
@@ -298,7 +298,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 									[]bson.M{{"$match": bson.M{"guildid": channel.GuildID}}, {"$sample": bson.M{"size": 1}}},
 									&entryBucket)
 								if helpers.IsMdbNotFound(err) {
-									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.list-empty"))
+									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.list-empty"))
 									helpers.Relax(err)
 									return
 								}
@@ -360,7 +360,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 			}
 
 			if len(entryBucket) <= 0 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.list-empty"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.list-empty"))
 				helpers.Relax(err)
 				return
 			} else if err != nil {
@@ -377,7 +377,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 			}
 			commandListText += fmt.Sprintf("There are **%s** custom commands on this server.", humanize.Comma(int64(len(entryBucket))))
 
-			helpers.SendMessage(msg.ChannelID, helpers.GetTextF("bot.check-your-dms", msg.Author.ID))
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.check-your-dms", msg.Author.ID))
 
 			for _, page := range helpers.Pagify(commandListText, "\n") {
 				_, err = helpers.SendMessage(dmChannel.ID, page)
@@ -387,7 +387,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 		case "delete", "del", "remove": // [p]commands delete <command name>
 			session.ChannelTyping(msg.ChannelID)
 			if len(args) < 2 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 				helpers.Relax(err)
 				return
 			}
@@ -400,14 +400,14 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				&entryBucket,
 			)
 			if helpers.IsMdbNotFound(err) {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.delete-not-found"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.delete-not-found"))
 				helpers.Relax(err)
 				return
 			}
 			helpers.Relax(err)
 
 			if !cc.canAddCommand(channel.GuildID, msg.Author.ID, &entryBucket) {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("mod.no_permission"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "mod.no_permission"))
 				return
 			}
 
@@ -436,7 +436,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				}, false)
 			helpers.RelaxLog(err)
 
-			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.delete-success"))
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.delete-success"))
 			helpers.Relax(err)
 			customCommandsCacheLock.Lock()
 			defer customCommandsCacheLock.Unlock()
@@ -446,7 +446,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 		case "replace", "edit": // [p]commands edit <command name> <new content>
 			session.ChannelTyping(msg.ChannelID)
 			if len(args) < 3 && (len(msg.Attachments) <= 0 && len(args) < 2) {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 				return
 			}
 			channel, err := helpers.GetChannel(msg.ChannelID)
@@ -458,14 +458,14 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				&entryBucket,
 			)
 			if helpers.IsMdbNotFound(err) {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.edit-not-found"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.edit-not-found"))
 				helpers.Relax(err)
 				return
 			}
 			helpers.Relax(err)
 
 			if !cc.canAddCommand(channel.GuildID, msg.Author.ID, &entryBucket) {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("mod.no_permission"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "mod.no_permission"))
 				return
 			}
 
@@ -488,12 +488,12 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				if cc.isAllowedFiletype(filetype) {
 					// user is allowed to upload files?
 					if helpers.UseruploadsIsDisabled(msg.Author.ID) {
-						helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.errors.useruploads-disabled"))
+						helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.errors.useruploads-disabled"))
 						return
 					}
 					// <= 20 MB
 					if msg.Attachments[0].Size > 20e+6 {
-						helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.fileupload-too-big"))
+						helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.fileupload-too-big"))
 						return
 					}
 					// upload file
@@ -515,7 +515,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 			content := strings.TrimSpace(strings.Replace(content, strings.Join(args[:2], " "), "", 1))
 
 			if content == "" && objectName == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 				return
 			}
 
@@ -550,7 +550,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				}, false)
 			helpers.RelaxLog(err)
 
-			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.edit-success"))
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.edit-success"))
 			helpers.Relax(err)
 			customCommandsCacheLock.Lock()
 			defer customCommandsCacheLock.Unlock()
@@ -565,14 +565,14 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				defer customCommandsCacheLock.Unlock()
 				customCommandsCache, err = cc.getAllCustomCommands()
 				helpers.Relax(err)
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.refreshed-commands"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.refreshed-commands"))
 				helpers.Relax(err)
 			})
 			return
 		case "search": // [p]commands search <text>
 			session.ChannelTyping(msg.ChannelID)
 			if len(args) < 2 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 				helpers.Relax(err)
 				return
 			}
@@ -583,7 +583,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 			err = helpers.MDbIter(helpers.MdbCollection(models.CustomCommandsTable).Find(bson.M{"guildid": channel.GuildID, "keyword": bson.M{"$regex": bson.RegEx{Pattern: `.*` + args[1] + `.*`, Options: "i"}}}).Sort("keyword")).All(&entryBucket)
 			helpers.Relax(err)
 			if len(entryBucket) <= 0 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.customcommands.search-empty", args[1]))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.search-empty", args[1]))
 				helpers.Relax(err)
 				return
 			}
@@ -607,7 +607,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 		case "info": // [p]commands info <command name>
 			session.ChannelTyping(msg.ChannelID)
 			if len(args) < 2 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 				helpers.Relax(err)
 				return
 			}
@@ -621,7 +621,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				&entryBucket,
 			)
 			if helpers.IsMdbNotFound(err) {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.customcommands.info-not-found"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.customcommands.info-not-found"))
 				helpers.Relax(err)
 				return
 			}
@@ -662,7 +662,7 @@ func (cc *CustomCommands) Action(command string, content string, msg *discordgo.
 				session.ChannelTyping(msg.ChannelID)
 
 				if len(msg.Attachments) <= 0 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 					helpers.Relax(err)
 					return
 				}
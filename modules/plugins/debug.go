@@ -85,7 +85,7 @@ func (d *Debug) Action(command string, content string, msg *discordgo.Message, s
 			ctx := context.Background()
 			os.Setenv(
 				"GOOGLE_APPLICATION_CREDENTIALS",
-				helpers.GetConfig().Path("google.client_credentials_json_location").Data().(string),
+				helpers.GetConfig().Google.ClientCredentialsJSONLocation,
 			)
 
 			visionClient, err := vision.NewImageAnnotatorClient(ctx)
@@ -9,6 +9,7 @@ import (
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/bwmarrin/discordgo"
 )
@@ -733,6 +734,7 @@ func auditlogBackfillLoop() {
 			len(backfills),
 			successfulBackfills, elapsed)
 		metrics.EventlogAuditLogBackfillTime.Set(elapsed.Seconds())
+		prom.FeedLoopDuration.WithLabelValues("eventlog_auditlog_backfill").Observe(elapsed.Seconds())
 	}
 }
 
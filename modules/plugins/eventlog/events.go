@@ -9,10 +9,17 @@ import (
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/ratelimits"
 	"github.com/bwmarrin/discordgo"
 	jsoniter "github.com/json-iterator/go"
 )
 
+const (
+	// how many reverts a moderator can do in the window
+	revertLimit  = 5
+	revertWindow = 75 * time.Second
+)
+
 func (h *Handler) OnMessage(content string, msg *discordgo.Message, session *discordgo.Session) {
 	if !strings.Contains(content, "discord.gg/") && !strings.Contains(content, "discordapp.com/invite/") {
 		return
@@ -153,8 +160,8 @@ func (h *Handler) OnReactionAdd(reaction *discordgo.MessageReactionAdd, session
 		return
 	}
 
-	err = Container.Drain(1, reaction.UserID)
-	if err != nil {
+	retryAfter, err := ratelimits.Take(ratelimits.Key("eventlog", "revert", reaction.UserID), revertLimit, revertWindow)
+	if err == nil && retryAfter > 0 {
 		cache.GetSession().SessionForGuildS(reaction.GuildID).MessageReactionRemove(reaction.ChannelID, reaction.MessageID, reaction.Emoji.Name, reaction.UserID)
 		helpers.SendMessage(reaction.ChannelID, "<@"+reaction.UserID+"> You are undoing too fast.\nPlease wait a bit.")
 		return
@@ -27,8 +27,6 @@ func (h *Handler) Commands() []string {
 func (h *Handler) Init(session *shardmanager.Manager) {
 	defer helpers.Recover()
 
-	Container.Init()
-
 	session.AddHandler(h.OnChannelCreate)
 	session.AddHandler(h.OnChannelDelete)
 	session.AddHandler(h.OnGuildRoleCreate)
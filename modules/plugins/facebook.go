@@ -8,6 +8,7 @@ import (
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
@@ -75,6 +76,8 @@ func (m *Facebook) checkFacebookFeedsLoop() {
 	var bundledEntries map[string][]models.FacebookEntry
 
 	for {
+		start := time.Now()
+
 		err := helpers.MDbIter(helpers.MdbCollection(models.FacebookTable).Find(nil)).All(&entries)
 		helpers.Relax(err)
 
@@ -147,6 +150,8 @@ func (m *Facebook) checkFacebookFeedsLoop() {
 			time.Sleep(10 * time.Second)
 		}
 
+		prom.FeedLoopDuration.WithLabelValues("facebook").Observe(time.Since(start).Seconds())
+
 		if len(entries) <= 10 {
 			time.Sleep(1 * time.Minute)
 		}
@@ -358,7 +363,7 @@ func (m *Facebook) lookupFacebookPage(siteName string) (Facebook_Page, error) {
 	var facebookPage Facebook_Page
 	facebookPageResult, err := fb.Get(fmt.Sprintf("/%s", siteName), fb.Params{
 		"fields":       "id,name,about,fan_count,username,is_verified,picture,website",
-		"access_token": helpers.GetConfig().Path("facebook.access_token").Data().(string),
+		"access_token": helpers.GetConfig().Facebook.AccessToken,
 	})
 	if err != nil {
 		return facebookPage, err
@@ -403,7 +408,7 @@ func (m *Facebook) lookupFacebookPage(siteName string) (Facebook_Page, error) {
 	facebookPostsResultData, err := fb.Get(fmt.Sprintf("/%s/posts", siteName), fb.Params{
 		"fields":       "id,message,created_time,picture,permalink_url", // TODO: ,child_attachments (better image quality(?))
 		"limit":        10,
-		"access_token": helpers.GetConfig().Path("facebook.access_token").Data().(string),
+		"access_token": helpers.GetConfig().Facebook.AccessToken,
 	})
 	if err != nil {
 		return facebookPage, err
@@ -35,22 +35,22 @@ var (
 )
 
 func (f *Feedback) Init(session *shardmanager.Manager) {
-	if helpers.GetConfig().Path("trello.token").Data().(string) == "" {
+	if helpers.GetConfig().Trello.Token == "" {
 		return
 	}
 
 	var err error
-	token := helpers.GetConfig().Path("trello.token").Data().(string)
+	token := helpers.GetConfig().Trello.Token
 	trelloClient, err = trello.NewAuthClient(
-		helpers.GetConfig().Path("trello.key").Data().(string),
+		helpers.GetConfig().Trello.Key,
 		&token)
 	helpers.Relax(err)
 
 	trelloListSuggestions, err = trelloClient.List(
-		helpers.GetConfig().Path("trello.board-ids.suggestions").Data().(string))
+		helpers.GetConfig().Trello.BoardIDs.Suggestions)
 	helpers.Relax(err)
 	trelloListIssues, err = trelloClient.List(
-		helpers.GetConfig().Path("trello.board-ids.issues").Data().(string))
+		helpers.GetConfig().Trello.BoardIDs.Issues)
 	helpers.Relax(err)
 }
 
@@ -2,7 +2,10 @@ package plugins
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"time"
@@ -13,6 +16,7 @@ import (
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/sirupsen/logrus"
 	"github.com/vmihailenco/msgpack"
@@ -28,18 +32,58 @@ func (g *Gallery) Commands() []string {
 
 const (
 	galleryUrlRegexText = `(<?https?:\/\/[^\s]+>?)`
+
+	galleryDefaultDuplicateDistance = 2
+	galleryMaxDuplicateDistance     = 20
+	galleryDuplicatesReportLimit    = 10
+
+	// galleriesCacheName is used to reload the galleries in all processes
+	galleriesCacheName = "galleries"
+	// galleryDistanceMigrationKey is set once the galleries added before duplicate detection got a distance
+	galleryDistanceMigrationKey = "robyul2-discord:gallery:migration:duplicatedistance"
 )
 
 var (
 	galleryUrlRegex *regexp.Regexp
 	galleries       []models.GalleryEntry
+
+	galleryImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif"}
 )
 
 func (g *Gallery) Init(session *shardmanager.Manager) {
 	galleryUrlRegex = regexp.MustCompile(galleryUrlRegexText)
-	var err error
+
+	err := migrateGalleryDuplicateDistance()
+	helpers.RelaxLog(err)
+
+	err = helpers.MdbCollection(models.GalleryImagesTable).EnsureIndex(mgo.Index{Key: []string{"galleryid", "hashstring"}})
+	helpers.RelaxLog(err)
+
 	galleries, err = g.GetGalleries()
 	helpers.Relax(err)
+
+	helpers.OnCacheInvalidation(galleriesCacheName, func() (err error) {
+		galleries, err = g.GetGalleries()
+		return err
+	})
+}
+
+// migrateGalleryDuplicateDistance sets the default distance on galleries added before duplicate detection, only the first process to start runs it
+func migrateGalleryDuplicateDistance() error {
+	claimed, err := cache.GetRedisClient().SetNX(galleryDistanceMigrationKey, helpers.ProcessName(), 0).Result()
+	if err != nil || !claimed {
+		return err
+	}
+
+	_, err = helpers.MdbCollection(models.GalleryTable).UpdateAll(
+		bson.M{"duplicatedistance": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"duplicatedistance": galleryDefaultDuplicateDistance}},
+	)
+	if err != nil {
+		// try again with the next start
+		cache.GetRedisClient().Del(galleryDistanceMigrationKey)
+	}
+	return err
 }
 
 func (g *Gallery) Uninit(session *shardmanager.Manager) {
@@ -79,10 +123,12 @@ func (g *Gallery) Action(command string, content string, msg *discordgo.Message,
 				}
 
 				newID, err := helpers.MDbInsert(models.GalleryTable, models.GalleryEntry{
-					SourceChannelID: sourceChannel.ID,
-					TargetChannelID: targetChannel.ID,
-					GuildID:         channel.GuildID,
-					AddedByUserID:   msg.Author.ID,
+					SourceChannelID:   sourceChannel.ID,
+					TargetChannelID:   targetChannel.ID,
+					GuildID:           channel.GuildID,
+					AddedByUserID:     msg.Author.ID,
+					DuplicateMode:     models.GalleryDuplicateModeIgnore,
+					DuplicateDistance: galleryDefaultDuplicateDistance,
 				})
 				helpers.Relax(err)
 
@@ -109,7 +155,7 @@ func (g *Gallery) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.gallery.add-success"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 
-				galleries, err = g.GetGalleries()
+				err = helpers.InvalidateCache(galleriesCacheName)
 				helpers.RelaxLog(err)
 				return
 			})
@@ -162,6 +208,9 @@ func (g *Gallery) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbDelete(models.GalleryTable, entryBucket.ID)
 				helpers.Relax(err)
 
+				_, err = helpers.MdbCollection(models.GalleryImagesTable).RemoveAll(bson.M{"galleryid": entryBucket.ID})
+				helpers.RelaxLog(err)
+
 				_, err = helpers.EventlogLog(time.Now(), entryBucket.GuildID, helpers.MdbIdToHuman(entryBucket.ID),
 					models.EventlogTargetTypeRobyulGallery, msg.Author.ID,
 					models.EventlogTypeRobyulGalleryRemove, "",
@@ -183,15 +232,49 @@ func (g *Gallery) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.gallery.delete-success"))
 				helpers.Relax(err)
 
-				galleries, err = g.GetGalleries()
+				err = helpers.InvalidateCache(galleriesCacheName)
 				helpers.RelaxLog(err)
 				return
 			})
+		case "duplicates", "duplicate": // [p]gallery duplicates <gallery id> [<ignore|skip|flag> [<distance>]]
+			session.ChannelTyping(msg.ChannelID)
+			if len(args) < 2 {
+				helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				return
+			}
+
+			channel, err := helpers.GetChannel(msg.ChannelID)
+			helpers.Relax(err)
+
+			var entryBucket models.GalleryEntry
+			err = helpers.MdbOne(
+				helpers.MdbCollection(models.GalleryTable).Find(bson.M{"guildid": channel.GuildID, "_id": helpers.HumanToMdbId(args[1])}),
+				&entryBucket,
+			)
+			if helpers.IsMdbNotFound(err) {
+				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.gallery.delete-not-found"))
+				return
+			}
+			helpers.Relax(err)
+
+			if len(args) >= 3 {
+				helpers.RequireMod(msg, func() {
+					g.setDuplicateMode(msg, entryBucket, args[2:])
+				})
+				return
+			}
+
+			resultMessage, err := g.getDuplicatesReport(entryBucket)
+			helpers.Relax(err)
+
+			_, err = helpers.SendMessage(msg.ChannelID, resultMessage)
+			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+			return
 		case "refresh": // [p]gallery refresh
 			helpers.RequireBotAdmin(msg, func() {
 				session.ChannelTyping(msg.ChannelID)
 				var err error
-				galleries, err = g.GetGalleries()
+				err = helpers.InvalidateCache(galleriesCacheName)
 				helpers.RelaxLog(err)
 				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.gallery.refreshed-config"))
 				helpers.Relax(err)
@@ -250,13 +333,36 @@ func (g *Gallery) OnMessage(content string, msg *discordgo.Message, session *dis
 				// post mirror links
 				if len(linksToRepost) > 0 {
 					for _, linkToRepost := range linksToRepost {
+						// check for duplicates
+						var duplicateNote string
+						imageHash := g.getImageHash(linkToRepost)
+						var originalImage *models.GalleryImageEntry
+						if imageHash != "" {
+							originalImage, err = g.findDuplicate(gallery, imageHash)
+							helpers.RelaxLog(err)
+						}
+						if originalImage != nil {
+							err = helpers.MDbUpdateQueryWithoutLogging(models.GalleryImagesTable,
+								bson.M{"_id": originalImage.ID}, bson.M{"$inc": bson.M{"duplicates": 1}})
+							helpers.RelaxLog(err)
+							metrics.GalleryDuplicatesFound.Add(1)
+
+							switch gallery.DuplicateMode {
+							case models.GalleryDuplicateModeSkip:
+								continue
+							case models.GalleryDuplicateModeFlag:
+								duplicateNote = " " + helpers.GetTextF("plugins.gallery.duplicate-flag",
+									helpers.MessageDeeplink(originalImage.PostedChannelID, originalImage.PostedMessageID))
+							}
+						}
+
 						var newMessage *discordgo.Message
 						if webhook != nil && webhook.ID != "" && webhook.Token != "" {
 							newMessage, err = helpers.WebhookExecuteWithResult(
 								webhook.ID,
 								webhook.Token,
 								&discordgo.WebhookParams{
-									Content:   fmt.Sprintf("posted %s in <#%s>", linkToRepost, gallery.SourceChannelID),
+									Content:   fmt.Sprintf("posted %s in <#%s>%s", linkToRepost, gallery.SourceChannelID, duplicateNote),
 									Username:  msg.Author.Username,
 									AvatarURL: helpers.GetAvatarUrl(msg.Author),
 								},
@@ -267,7 +373,7 @@ func (g *Gallery) OnMessage(content string, msg *discordgo.Message, session *dis
 							}
 						} else {
 							newMessages, err := helpers.SendMessage(gallery.TargetChannelID,
-								fmt.Sprintf("%s posted %s in <#%s>", msg.Author.Username, linkToRepost, gallery.SourceChannelID))
+								fmt.Sprintf("%s posted %s in <#%s>%s", msg.Author.Username, linkToRepost, gallery.SourceChannelID, duplicateNote))
 							if err != nil {
 								helpers.RelaxLog(err)
 								continue
@@ -277,6 +383,24 @@ func (g *Gallery) OnMessage(content string, msg *discordgo.Message, session *dis
 						err = g.rememberPostedMessage(msg, newMessage)
 						helpers.RelaxLog(err)
 						metrics.GalleryPostsSent.Add(1)
+
+						// add new images to the index
+						if imageHash != "" && originalImage == nil {
+							newImage := models.GalleryImageEntry{
+								GalleryID:       gallery.ID,
+								GuildID:         gallery.GuildID,
+								SourceChannelID: msg.ChannelID,
+								SourceMessageID: msg.ID,
+								AuthorID:        msg.Author.ID,
+								URL:             linkToRepost,
+								PostedChannelID: newMessage.ChannelID,
+								PostedMessageID: newMessage.ID,
+								HashString:      imageHash,
+								CreatedAt:       time.Now(),
+							}
+							newImage.ID, err = helpers.MDbInsertWithoutLogging(models.GalleryImagesTable, newImage)
+							helpers.RelaxLog(err)
+						}
 					}
 				}
 			}
@@ -284,6 +408,210 @@ func (g *Gallery) OnMessage(content string, msg *discordgo.Message, session *dis
 	}()
 }
 
+// getImageHash downloads the image at the link and returns its hash string, returns an empty string if the link is not an image
+func (g *Gallery) getImageHash(link string) string {
+	parsedLink, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	var isImage bool
+	extension := strings.ToLower(filepath.Ext(parsedLink.Path))
+	for _, imageExtension := range galleryImageExtensions {
+		if extension == imageExtension {
+			isImage = true
+			break
+		}
+	}
+	if !isImage {
+		return ""
+	}
+
+	imageData, err := helpers.NetGetUAWithErrorAndTimeout(link, helpers.DEFAULT_UA, 15*time.Second)
+	if err != nil {
+		return ""
+	}
+
+	decodedImage, _, err := helpers.DecodeImageBytes(imageData)
+	if err != nil {
+		return ""
+	}
+
+	imageHash, err := helpers.GetImageHashString(decodedImage)
+	if err != nil {
+		return ""
+	}
+
+	return imageHash
+}
+
+// findDuplicate returns the closest image in the gallery index within the gallery's duplicate distance, or nil if there is none
+// Identical hashes are looked up by the index, only the hashes are compared if similar images count as duplicates
+func (g *Gallery) findDuplicate(gallery models.GalleryEntry, imageHash string) (*models.GalleryImageEntry, error) {
+	var originalImage models.GalleryImageEntry
+	err := helpers.MdbOneWithoutLogging(helpers.MdbCollection(models.GalleryImagesTable).Find(
+		bson.M{"galleryid": gallery.ID, "hashstring": imageHash},
+	), &originalImage)
+	if err == nil {
+		return &originalImage, nil
+	}
+	if !helpers.IsMdbNotFound(err) {
+		return nil, err
+	}
+	if gallery.DuplicateDistance <= 0 {
+		return nil, nil
+	}
+
+	var indexedHashes []models.GalleryImageEntry
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.GalleryImagesTable).Find(
+		bson.M{"galleryid": gallery.ID},
+	).Select(bson.M{"_id": 1, "hashstring": 1})).All(&indexedHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	closestImage := closestGalleryImage(indexedHashes, imageHash, gallery.DuplicateDistance)
+	if closestImage == nil {
+		return nil, nil
+	}
+	err = helpers.MdbOneWithoutLogging(helpers.MdbCollection(models.GalleryImagesTable).Find(
+		bson.M{"_id": closestImage.ID},
+	), &originalImage)
+	if err != nil {
+		return nil, err
+	}
+	return &originalImage, nil
+}
+
+// closestGalleryImage returns the image with the closest hash within the distance, or nil if there is none
+func closestGalleryImage(indexedImages []models.GalleryImageEntry, imageHash string, maxDistance int) *models.GalleryImageEntry {
+	var closestImage *models.GalleryImageEntry
+	closestDistance := -1
+	for i := range indexedImages {
+		distance, err := helpers.ImageHashStringComparison(imageHash, indexedImages[i].HashString)
+		if err != nil {
+			continue
+		}
+
+		if distance <= maxDistance && (closestDistance < 0 || distance < closestDistance) {
+			closestImage = &indexedImages[i]
+			closestDistance = distance
+		}
+	}
+
+	return closestImage
+}
+
+func (g *Gallery) setDuplicateMode(msg *discordgo.Message, gallery models.GalleryEntry, args []string) {
+	newMode, ok := galleryDuplicateModeFromText(args[0])
+	if !ok {
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+		return
+	}
+
+	newDistance := gallery.DuplicateDistance
+	if len(args) >= 2 {
+		var err error
+		newDistance, err = strconv.Atoi(args[1])
+		if err != nil || newDistance < 0 || newDistance > galleryMaxDuplicateDistance {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.gallery.duplicates-invalid-distance", galleryMaxDuplicateDistance))
+			return
+		}
+	}
+
+	beforeMode := gallery.DuplicateMode
+	beforeDistance := gallery.DuplicateDistance
+
+	gallery.DuplicateMode = newMode
+	gallery.DuplicateDistance = newDistance
+	err := helpers.MDbUpdate(models.GalleryTable, gallery.ID, gallery)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), gallery.GuildID, helpers.MdbIdToHuman(gallery.ID),
+		models.EventlogTargetTypeRobyulGallery, msg.Author.ID,
+		models.EventlogTypeRobyulGalleryUpdate, "",
+		[]models.ElasticEventlogChange{
+			{
+				Key:      "gallery_duplicatemode",
+				OldValue: galleryDuplicateModeText(beforeMode),
+				NewValue: galleryDuplicateModeText(gallery.DuplicateMode),
+			},
+			{
+				Key:      "gallery_duplicatedistance",
+				OldValue: strconv.Itoa(beforeDistance),
+				NewValue: strconv.Itoa(gallery.DuplicateDistance),
+			},
+		},
+		nil, false)
+	helpers.RelaxLog(err)
+
+	err = helpers.InvalidateCache(galleriesCacheName)
+	helpers.RelaxLog(err)
+
+	_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.gallery.duplicates-set-success",
+		galleryDuplicateModeText(gallery.DuplicateMode), gallery.DuplicateDistance))
+	helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+}
+
+func (g *Gallery) getDuplicatesReport(gallery models.GalleryEntry) (string, error) {
+	indexedCount, err := helpers.MdbCount(models.GalleryImagesTable, bson.M{"galleryid": gallery.ID})
+	if err != nil {
+		return "", err
+	}
+
+	var duplicatedImages []models.GalleryImageEntry
+	err = helpers.MDbIter(helpers.MdbCollection(models.GalleryImagesTable).Find(
+		bson.M{"galleryid": gallery.ID, "duplicates": bson.M{"$gt": 0}},
+	).Sort("-duplicates")).All(&duplicatedImages)
+	if err != nil {
+		return "", err
+	}
+
+	var duplicatesCount int
+	for _, duplicatedImage := range duplicatedImages {
+		duplicatesCount += duplicatedImage.Duplicates
+	}
+
+	resultMessage := helpers.GetTextF("plugins.gallery.duplicates-report",
+		helpers.MdbIdToHuman(gallery.ID), gallery.SourceChannelID, gallery.TargetChannelID,
+		galleryDuplicateModeText(gallery.DuplicateMode), gallery.DuplicateDistance,
+		indexedCount, duplicatesCount) + "\n"
+
+	for i, duplicatedImage := range duplicatedImages {
+		if i >= galleryDuplicatesReportLimit {
+			break
+		}
+		resultMessage += fmt.Sprintf("`%dx` <%s> by <@%s>\n",
+			duplicatedImage.Duplicates,
+			helpers.MessageDeeplink(duplicatedImage.PostedChannelID, duplicatedImage.PostedMessageID),
+			duplicatedImage.AuthorID)
+	}
+
+	return resultMessage, nil
+}
+
+func galleryDuplicateModeText(mode models.GalleryDuplicateMode) string {
+	switch mode {
+	case models.GalleryDuplicateModeSkip:
+		return "skip"
+	case models.GalleryDuplicateModeFlag:
+		return "flag"
+	}
+	return "ignore"
+}
+
+func galleryDuplicateModeFromText(text string) (models.GalleryDuplicateMode, bool) {
+	switch strings.ToLower(text) {
+	case "ignore", "off":
+		return models.GalleryDuplicateModeIgnore, true
+	case "skip":
+		return models.GalleryDuplicateModeSkip, true
+	case "flag":
+		return models.GalleryDuplicateModeFlag, true
+	}
+	return models.GalleryDuplicateModeIgnore, false
+}
+
 type Gallery_PostedMessage struct {
 	ChannelID string
 	MessageID string
@@ -376,6 +704,9 @@ func (g *Gallery) OnMessageDelete(msg *discordgo.MessageDelete, session *discord
 				rememberedMessages, err = g.getRememberedMessages(msg.Message)
 				helpers.Relax(err)
 
+				_, err = helpers.MdbCollection(models.GalleryImagesTable).RemoveAll(bson.M{"galleryid": gallery.ID, "sourcemessageid": msg.ID})
+				helpers.RelaxLog(err)
+
 				for _, messageData := range rememberedMessages {
 					err = session.ChannelMessageDelete(messageData.ChannelID, messageData.MessageID)
 					if err != nil {
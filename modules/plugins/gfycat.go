@@ -200,8 +200,8 @@ func (m *Gfycat) getAccessToken() string {
 		`{"grant_type": "client_credentials",
     "client_id": "%s",
     "client_secret": "%s"}`,
-		helpers.GetConfig().Path("gfycat.client_id").Data().(string),
-		helpers.GetConfig().Path("gfycat.client_secret").Data().(string),
+		helpers.GetConfig().Gfycat.ClientID,
+		helpers.GetConfig().Gfycat.ClientSecret,
 	)))
 	helpers.Relax(err)
 	request, err := http.NewRequest("POST", getTokenEndpoint, strings.NewReader(postData.String()))
@@ -115,6 +115,17 @@ func addIdolAlias(msg *discordgo.Message, targetGroup string, targetName string,
 	err = helpers.MDbUpsertID(models.IdolTable, mongoIdol.ID, mongoIdol)
 	helpers.Relax(err)
 
+	recordIdolAudit(models.IdolAuditEntry{
+		IdolID:    mongoIdol.ID,
+		GroupName: targetIdol.GroupName,
+		UserID:    msg.Author.ID,
+		Action:    models.IdolAuditActionIdolAliasAdd,
+		Changes: []models.ElasticEventlogChange{
+			auditChange("Alias", "", newAliasName),
+		},
+	})
+	idolsChanged()
+
 	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("The alias *%s* has been added for %s %s", newAliasName, targetIdol.GroupName, targetIdol.Name))
 }
 
@@ -153,6 +164,16 @@ func addGroupAlias(msg *discordgo.Message, targetGroup string, newAliasName stri
 	// save to redis
 	setModuleCache(GROUP_ALIAS_KEY, getGroupAliases(), 0)
 
+	recordIdolAudit(models.IdolAuditEntry{
+		GroupName: targetGroup,
+		UserID:    msg.Author.ID,
+		Action:    models.IdolAuditActionGroupAliasAdd,
+		Changes: []models.ElasticEventlogChange{
+			auditChange("Alias", "", newAliasName),
+		},
+	})
+	idolsChanged()
+
 	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("The alias *%s* has been added for the group **%s**", newAliasName, targetGroup))
 }
 
@@ -205,6 +226,17 @@ func deleteIdolAlias(msg *discordgo.Message, commandArgs []string) {
 	err = helpers.MDbUpsertID(models.IdolTable, mongoIdol.ID, mongoIdol)
 	helpers.Relax(err)
 
+	recordIdolAudit(models.IdolAuditEntry{
+		IdolID:    mongoIdol.ID,
+		GroupName: targetIdol.GroupName,
+		UserID:    msg.Author.ID,
+		Action:    models.IdolAuditActionIdolAliasDelete,
+		Changes: []models.ElasticEventlogChange{
+			auditChange("Alias", aliasToDelete, ""),
+		},
+	})
+	idolsChanged()
+
 	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Deleted the alias *%s* from %s %s", aliasToDelete, targetIdol.GroupName, targetIdol.Name))
 }
 
@@ -216,6 +248,7 @@ func deleteGroupAlias(msg *discordgo.Message, commandArgs []string) {
 
 	// find and delete alias if one exists
 	aliasDeleted := false
+	var deletedFromGroup string
 	regToDelete := strings.ToLower(alphaNumericRegex.ReplaceAllString(aliasToDelete, ""))
 	groupAliasMutex.Lock()
 GroupAliasLoop:
@@ -234,6 +267,8 @@ GroupAliasLoop:
 				}
 
 				aliasDeleted = true
+				aliasToDelete = alias
+				deletedFromGroup = curGroup
 				helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Deleted the alias *%s* from the group **%s**", alias, curGroup))
 				break GroupAliasLoop
 			}
@@ -245,6 +280,16 @@ GroupAliasLoop:
 	if aliasDeleted {
 		// save to redis
 		setModuleCache(GROUP_ALIAS_KEY, getGroupAliases(), 0)
+
+		recordIdolAudit(models.IdolAuditEntry{
+			GroupName: deletedFromGroup,
+			UserID:    msg.Author.ID,
+			Action:    models.IdolAuditActionGroupAliasDelete,
+			Changes: []models.ElasticEventlogChange{
+				auditChange("Alias", aliasToDelete, ""),
+			},
+		})
+		idolsChanged()
 	} else {
 		helpers.SendMessage(msg.ChannelID, "Alias not found, no alias was deleted")
 	}
@@ -0,0 +1,147 @@
+package idols
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	IDOLS_CACHE_NAME       = "idols"
+	SUGGESTIONS_CACHE_NAME = "idol-suggestions"
+)
+
+var idolsChangedListeners []func()
+var idolsChangedListenersMutex sync.RWMutex
+
+// OnIdolsChanged registers a function that is called in every process after idols, images or aliases changed
+func OnIdolsChanged(listener func()) {
+	idolsChangedListenersMutex.Lock()
+	defer idolsChangedListenersMutex.Unlock()
+
+	idolsChangedListeners = append(idolsChangedListeners, listener)
+}
+
+// initCacheInvalidation reloads idols and suggestions when another process changed them
+func initCacheInvalidation() {
+	helpers.OnCacheInvalidation(IDOLS_CACHE_NAME, func() error {
+		refreshIdols(true)
+		initAliases()
+
+		idolsChangedListenersMutex.RLock()
+		listeners := idolsChangedListeners
+		idolsChangedListenersMutex.RUnlock()
+		for _, listener := range listeners {
+			listener()
+		}
+		return nil
+	})
+
+	helpers.OnCacheInvalidation(SUGGESTIONS_CACHE_NAME, func() error {
+		reloadSuggestionQueue()
+		return nil
+	})
+}
+
+// idolsChanged reloads the idols in all processes and lets other modules refresh their caches
+func idolsChanged() {
+	helpers.RelaxLog(helpers.InvalidateCache(IDOLS_CACHE_NAME))
+}
+
+// recordIdolAudit saves an edit of the idol database to the audit history
+func recordIdolAudit(entry models.IdolAuditEntry) {
+	if entry.Source == "" {
+		entry.Source = models.IdolAuditSourceDiscord
+	}
+	entry.CreatedAt = time.Now()
+
+	_, err := helpers.MDbInsertWithoutLogging(models.IdolAuditTable, entry)
+	helpers.RelaxLog(err)
+}
+
+// auditChange returns a change for the audit history
+func auditChange(key, oldValue, newValue string) models.ElasticEventlogChange {
+	return models.ElasticEventlogChange{
+		Key:      key,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+}
+
+// GetAuditHistory returns the newest edits of an idol or a group, returns the newest edits of all idols if both are empty
+func GetAuditHistory(idolID bson.ObjectId, groupName string, limit int) ([]models.IdolAuditEntry, error) {
+	query := bson.M{}
+	if idolID != "" {
+		query["idolid"] = idolID
+	}
+	if groupName != "" {
+		query["groupname"] = groupName
+	}
+
+	var entries []models.IdolAuditEntry
+	err := helpers.MDbIter(helpers.MdbCollection(models.IdolAuditTable).Find(query).Sort("-createdat").Limit(limit)).All(&entries)
+	return entries, err
+}
+
+// SearchIdols returns the idols matching the search in their name, group or aliases, sorted by group and name
+func SearchIdols(search, group, gender string, includeDeleted bool) []*Idol {
+	if group != "" {
+		exists, realGroupName := GetMatchingGroup(group, !includeDeleted)
+		if !exists {
+			return nil
+		}
+		group = realGroupName
+	}
+
+	var result []*Idol
+	for _, idol := range GetAllIdols() {
+		if !includeDeleted && (idol.Deleted || len(idol.Images) == 0) {
+			continue
+		}
+		if group != "" && idol.GroupName != group {
+			continue
+		}
+		if gender != "" && idol.Gender != gender {
+			continue
+		}
+		if !idolMatchesSearch(idol, search) {
+			continue
+		}
+
+		result = append(result, idol)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].GroupName != result[j].GroupName {
+			return strings.ToLower(result[i].GroupName) < strings.ToLower(result[j].GroupName)
+		}
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// idolMatchesSearch checks if the loose search is part of the name, group or aliases of the idol
+func idolMatchesSearch(idol *Idol, search string) bool {
+	search = strings.ToLower(alphaNumericRegex.ReplaceAllString(search, ""))
+	if search == "" {
+		return true
+	}
+
+	names := []string{idol.Name, idol.GroupName, idol.GroupName + idol.Name}
+	names = append(names, idol.NameAliases...)
+	if hasAliases, groupAliases := GetAlisesForGroup(idol.GroupName); hasAliases {
+		names = append(names, groupAliases...)
+	}
+
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(alphaNumericRegex.ReplaceAllString(name, "")), search) {
+			return true
+		}
+	}
+	return false
+}
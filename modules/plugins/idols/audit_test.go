@@ -0,0 +1,30 @@
+package idols
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestIdolMatchesSearch(t *testing.T) {
+	alphaNumericRegex = regexp.MustCompile("[^a-zA-Z0-9가-힣]+")
+
+	idol := &Idol{
+		Name:        "Seulgi",
+		GroupName:   "Red Velvet",
+		NameAliases: []string{"Bear"},
+	}
+
+	for search, expected := range map[string]bool{
+		"":                 true,
+		"seulgi":           true,
+		"red-velvet":       true,
+		"redvelvet seulgi": true,
+		"bea":              true,
+		"wendy":            false,
+		"twice":            false,
+	} {
+		if idolMatchesSearch(idol, search) != expected {
+			t.Fatalf("search %q should return %v", search, expected)
+		}
+	}
+}
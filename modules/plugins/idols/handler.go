@@ -25,6 +25,9 @@ func (i *Module) Init(session *shardmanager.Manager) {
 		alphaNumericRegex, err = regexp.Compile("[^a-zA-Z0-9가-힣]+")
 		helpers.Relax(err)
 
+		// reload idols and suggestions when they are changed by another process
+		initCacheInvalidation()
+
 		// load all idol images and information
 		refreshIdols(false)
 
@@ -112,7 +115,7 @@ func (i *Module) Action(command string, content string, msg *discordgo.Message,
 			helpers.RequireRobyulMod(msg, func() {
 				newMessages, err := helpers.SendMessage(msg.ChannelID, "Refreshign Idols...")
 				helpers.Relax(err)
-				refreshIdols(true)
+				idolsChanged()
 
 				cache.GetSession().SessionForGuildS(msg.GuildID).ChannelMessageDelete(msg.ChannelID, newMessages[0].ID)
 				helpers.SendMessage(msg.ChannelID, "Idol info and images have been refreshed.")
@@ -64,6 +64,20 @@ func GetActiveIdols() []*Idol {
 	return activeIdols
 }
 
+// GetIdolRating returns the global bias game rating and votes of the idol
+func GetIdolRating(idol *Idol) (rating float64, votes int) {
+	allIdolsMutex.RLock()
+	defer allIdolsMutex.RUnlock()
+	return idol.BGRating, idol.BGRatingVotes
+}
+
+// SetIdolRating sets the global bias game rating and votes of the idol
+func SetIdolRating(idol *Idol, rating float64, votes int) {
+	allIdolsMutex.Lock()
+	defer allIdolsMutex.Unlock()
+	idol.BGRating, idol.BGRatingVotes = rating, votes
+}
+
 ////////////////////////
 //  Public Functions  //
 ////////////////////////
@@ -319,16 +333,18 @@ func refreshIdols(skipCache bool) {
 func makeIdolFromIdolEntry(entry models.IdolEntry) Idol {
 	// create new idol from the idol entry in mongo
 	newIdol := Idol{
-		ID:           entry.ID,
-		Name:         entry.Name,
-		GroupName:    entry.GroupName,
-		NameAndGroup: entry.Name + entry.GroupName,
-		NameAliases:  entry.NameAliases,
-		Gender:       entry.Gender,
-		BGGames:      entry.BGGames,
-		BGGameWins:   entry.BGGameWins,
-		BGRounds:     entry.BGRounds,
-		BGRoundWins:  entry.BGRoundWins,
+		ID:            entry.ID,
+		Name:          entry.Name,
+		GroupName:     entry.GroupName,
+		NameAndGroup:  entry.Name + entry.GroupName,
+		NameAliases:   entry.NameAliases,
+		Gender:        entry.Gender,
+		BGGames:       entry.BGGames,
+		BGGameWins:    entry.BGGameWins,
+		BGRounds:      entry.BGRounds,
+		BGRoundWins:   entry.BGRoundWins,
+		BGRating:      entry.BGRating,
+		BGRatingVotes: entry.BGRatingVotes,
 	}
 
 	// convert idol entry images
@@ -414,6 +430,16 @@ func updateGroupInfo(msg *discordgo.Message, content string) {
 	if idolsUpdated == 0 {
 		helpers.SendMessage(msg.ChannelID, "No Idols found in the given group.")
 	} else {
+		recordIdolAudit(models.IdolAuditEntry{
+			GroupName: newGroup,
+			UserID:    msg.Author.ID,
+			Action:    models.IdolAuditActionGroupUpdate,
+			Changes: []models.ElasticEventlogChange{
+				auditChange("Group", targetGroup, newGroup),
+			},
+		})
+		idolsChanged()
+
 		helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Group Information updated. \nIdols Updated: %d", idolsUpdated))
 	}
 }
@@ -447,6 +473,17 @@ func updateIdolInfoFromMsg(msg *discordgo.Message, content string) {
 	newName := contentArgs[3]
 	newGender := contentArgs[4]
 
+	// remember the idol before the update for the audit history
+	var targetIdolID bson.ObjectId
+	var oldGender string
+	for _, idol := range GetAllIdols() {
+		if idol.GroupName == targetGroup && idol.Name == targetName {
+			targetIdolID = idol.ID
+			oldGender = idol.Gender
+			break
+		}
+	}
+
 	// update idol
 	recordsUpdated := updateIdolInfo(targetGroup, targetName, newGroup, newName, newGender)
 
@@ -454,6 +491,19 @@ func updateIdolInfoFromMsg(msg *discordgo.Message, content string) {
 	if recordsUpdated == 0 {
 		helpers.SendMessage(msg.ChannelID, "No Idols found with that exact group and name.")
 	} else {
+		recordIdolAudit(models.IdolAuditEntry{
+			IdolID:    targetIdolID,
+			GroupName: newGroup,
+			UserID:    msg.Author.ID,
+			Action:    models.IdolAuditActionIdolUpdate,
+			Changes: []models.ElasticEventlogChange{
+				auditChange("Group", targetGroup, newGroup),
+				auditChange("Name", targetName, newName),
+				auditChange("Gender", oldGender, newGender),
+			},
+		})
+		idolsChanged()
+
 		helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Idol Information updated. \nOld: **%s** %s \nNew: **%s** %s", targetGroup, targetName, newGroup, newName))
 	}
 }
@@ -534,6 +584,9 @@ func updateIdolInfo(targetGroup, targetName, newGroup, newName, newGender string
 			// save target idol with new images
 			err := helpers.MDbUpsertID(models.IdolTable, targetIdol.ID, targetIdol)
 			helpers.Relax(err)
+
+			// the games of the merged idols are now counted for the target idol
+			helpers.RelaxLog(updateIdolBiasgameStats(targetIdol.ID))
 		}
 	}
 
@@ -589,6 +642,7 @@ func updateImageInfo(msg *discordgo.Message, content string) {
 	allIdols := GetAllIdols()
 	allIdolsMutex.Lock()
 	imageFound := false
+	var oldIdol Idol
 
 	// find and delete target image by object name
 IdolsLoop:
@@ -597,6 +651,7 @@ IdolsLoop:
 		// check if image has not been found and deleted, no need to loop through images if it has
 		for i, img := range idol.Images {
 			if img.ObjectName == targetObjectName {
+				oldIdol = *idol
 
 				// IMPORTANT: it is important that we do not delete the last image from the idol AND the idol from the all idols array. it MUST be one OR the other.
 
@@ -646,6 +701,7 @@ IdolsLoop:
 
 		// if the idol has no images left, delete it. else update it
 		var deleteIdolId bson.ObjectId
+		var updatedIdolID bson.ObjectId
 		var err error
 		if len(mongoRecordToUpdate.Images) == 0 {
 			deleteIdolId = mongoRecordToUpdate.ID
@@ -665,6 +721,7 @@ IdolsLoop:
 			targetIdol.Images = append(targetIdol.Images, mdbImageRecord)
 			err := helpers.MDbUpsertID(models.IdolTable, targetIdol.ID, targetIdol)
 			helpers.Relax(err)
+			updatedIdolID = targetIdol.ID
 
 			// if an idol was deleted update the biasgame stats to be for the new idol
 			if deleteIdolId != "" {
@@ -694,6 +751,7 @@ IdolsLoop:
 
 			newIdol := makeIdolFromIdolEntry(newIdolEntry)
 			setAllIdols(append(GetAllIdols(), &newIdol))
+			updatedIdolID = newIdol.ID
 
 			if deleteIdolId != "" {
 				_, err = updateBiasgame(deleteIdolId, newIdol.ID)
@@ -701,6 +759,24 @@ IdolsLoop:
 			}
 		}
 
+		// the games of a deleted idol are now counted for the idol the image was moved to
+		if deleteIdolId != "" {
+			helpers.RelaxLog(updateIdolBiasgameStats(updatedIdolID))
+		}
+
+		recordIdolAudit(models.IdolAuditEntry{
+			IdolID:    updatedIdolID,
+			GroupName: newGroup,
+			UserID:    msg.Author.ID,
+			Action:    models.IdolAuditActionImageUpdate,
+			Changes: []models.ElasticEventlogChange{
+				auditChange("Image", targetObjectName, targetObjectName),
+				auditChange("Group", oldIdol.GroupName, newGroup),
+				auditChange("Name", oldIdol.Name, newName),
+				auditChange("Gender", oldIdol.Gender, newGender),
+			},
+		})
+
 	} else {
 		// oh boy... these should not happen
 		if mongoRecordToUpdate.Name != "" {
@@ -715,6 +791,7 @@ IdolsLoop:
 	if len(GetAllIdols()) > 0 {
 		setModuleCache(ALL_IDOLS_CACHE_KEY, GetAllIdols(), time.Hour*24*7)
 	}
+	idolsChanged()
 
 	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Image Update. Object Name: %s | Idol: %s %s", targetObjectName, newGroup, newName))
 
@@ -740,6 +817,7 @@ func deleteImage(msg *discordgo.Message, content string) {
 	allIdols := GetAllIdols()
 	allIdolsMutex.Lock()
 	imageFound := false
+	var deletedFromIdol Idol
 
 	// find and delete target image by object name
 IdolLoop:
@@ -748,6 +826,7 @@ IdolLoop:
 		// check if image has not been found and deleted, no need to loop through images if it has
 		for i, bImg := range idol.Images {
 			if bImg.ObjectName == targetObjectName {
+				deletedFromIdol = *idol
 
 				// IMPORTANT: it is important that we do not delete the last image from the idol AND the idol from the all idols array. it MUST be one OR the other.
 
@@ -816,6 +895,17 @@ IdolLoop:
 		setModuleCache(ALL_IDOLS_CACHE_KEY, GetAllIdols(), time.Hour*24*7)
 	}
 
+	recordIdolAudit(models.IdolAuditEntry{
+		IdolID:    deletedFromIdol.ID,
+		GroupName: deletedFromIdol.GroupName,
+		UserID:    msg.Author.ID,
+		Action:    models.IdolAuditActionImageDelete,
+		Changes: []models.ElasticEventlogChange{
+			auditChange("Image", targetObjectName, ""),
+		},
+	})
+	idolsChanged()
+
 	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Deleted image with object name: %s", targetObjectName))
 
 }
@@ -1034,6 +1124,23 @@ func RefreshIdolBiasgameStats(targetIdol *Idol) {
 	}
 }
 
+// updateIdolBiasgameStats counts the games of the idol again and saves the stats
+func updateIdolBiasgameStats(idolID bson.ObjectId) error {
+	targetIdol := GetMatchingIdolById(idolID)
+	if targetIdol == nil {
+		return nil
+	}
+
+	RefreshIdolBiasgameStats(targetIdol)
+
+	return helpers.MdbCollection(models.IdolTable).UpdateId(idolID, bson.M{"$set": bson.M{
+		"bggames":     targetIdol.BGGames,
+		"bggamewins":  targetIdol.BGGameWins,
+		"bgrounds":    targetIdol.BGRounds,
+		"bgroundwins": targetIdol.BGRoundWins,
+	}})
+}
+
 // RefreshIdolBiasgameStats is a slow operation that will check every game the
 // idol has ever been in and update its game stats
 func RefreshAllIdolBiasgameStats(msg *discordgo.Message) {
@@ -1041,13 +1148,24 @@ func RefreshAllIdolBiasgameStats(msg *discordgo.Message) {
 
 	helpers.SendMessage(msg.ChannelID, "Refreshing biasgame info on idol records...")
 
-	var allGames []models.BiasGameEntry
-	helpers.MDbIter(helpers.MdbCollection(models.BiasGameTable).Find(bson.M{})).All(&allGames)
+	totalGames, totalIdols, err := RefreshAllIdolsBiasgameStats()
+	helpers.Relax(err)
 
-	totalIdols := len(GetAllIdols())
-	helpers.SendMessage(msg.ChannelID, fmt.Sprintln("Total games found: ", len(allGames)))
+	helpers.SendMessage(msg.ChannelID, fmt.Sprintln("Total games found: ", totalGames))
 	helpers.SendMessage(msg.ChannelID, fmt.Sprintln("Idols found: ", totalIdols))
-	helpers.SendMessage(msg.ChannelID, "Processing...")
+	helpers.SendMessage(msg.ChannelID, "Done.")
+}
+
+// RefreshAllIdolsBiasgameStats recalculates the game stats of every idol from all biasgames
+// Called by refresh-biasgames, and by the biasgame after idols changed
+func RefreshAllIdolsBiasgameStats() (totalGames, totalIdols int, err error) {
+	var allGames []models.BiasGameEntry
+	err = helpers.MDbIter(helpers.MdbCollection(models.BiasGameTable).Find(bson.M{})).All(&allGames)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	totalIdols = len(GetAllIdols())
 
 	idolsProcessed := 0
 	log().Infof("Idols processed: %d / %d", idolsProcessed, totalIdols)
@@ -1095,22 +1213,26 @@ func RefreshAllIdolBiasgameStats(msg *discordgo.Message) {
 		log().Infof("Idols processed: %d / %d", idolsProcessed, totalIdols)
 
 		var targetIdolEntry models.IdolEntry
-		err := helpers.MdbOne(helpers.MdbCollection(models.IdolTable).Find(bson.M{"groupname": targetIdol.GroupName, "name": targetIdol.Name}), &targetIdolEntry)
-		helpers.Relax(err)
+		err = helpers.MdbOne(helpers.MdbCollection(models.IdolTable).Find(bson.M{"groupname": targetIdol.GroupName, "name": targetIdol.Name}), &targetIdolEntry)
+		if err != nil {
+			return len(allGames), totalIdols, err
+		}
 
 		targetIdolEntry.BGGames = targetIdol.BGGames
 		targetIdolEntry.BGGameWins = targetIdol.BGGameWins
 		targetIdolEntry.BGRounds = targetIdol.BGRounds
 		targetIdolEntry.BGRoundWins = targetIdol.BGRoundWins
 		err = helpers.MDbUpsertID(models.IdolTable, targetIdolEntry.ID, targetIdolEntry)
-		helpers.Relax(err)
+		if err != nil {
+			return len(allGames), totalIdols, err
+		}
 	}
 
 	// update cache
 	if len(GetAllIdols()) > 0 {
 		setModuleCache(ALL_IDOLS_CACHE_KEY, GetAllIdols(), time.Hour*24*7)
 	}
-	helpers.SendMessage(msg.ChannelID, "Done.")
+	return len(allGames), totalIdols, nil
 }
 
 // UpdateIdolGameStats is called every time a biasgame finished
@@ -11,16 +11,18 @@ type IdolImage struct {
 }
 
 type Idol struct {
-	ID           bson.ObjectId
-	Name         string
-	NameAliases  []string
-	GroupName    string
-	Gender       string
-	NameAndGroup string
-	Images       []IdolImage
-	Deleted      bool
-	BGGames      int
-	BGGameWins   int
-	BGRounds     int
-	BGRoundWins  int
+	ID            bson.ObjectId
+	Name          string
+	NameAliases   []string
+	GroupName     string
+	Gender        string
+	NameAndGroup  string
+	Images        []IdolImage
+	Deleted       bool
+	BGGames       int
+	BGGameWins    int
+	BGRounds      int
+	BGRoundWins   int
+	BGRating      float64
+	BGRatingVotes int
 }
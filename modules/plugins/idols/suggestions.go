@@ -41,6 +41,11 @@ var suggestionQueueCountMessageId string
 var quoteReplacer = strings.NewReplacer("“", "\"", "”", "\"", "‘", "'", "’", "'")
 var predefinedDenyMessages map[int]string
 
+var (
+	ErrSuggestionNotFound     = errors.New("suggestion not found or already resolved")
+	ErrSuggestionNotesMissing = errors.New("a note must be set before denying a suggestion")
+)
+
 func initSuggestionChannel() {
 	var err error
 
@@ -52,7 +57,7 @@ func initSuggestionChannel() {
 		5: "Bad cropping.",
 	}
 
-	imageSuggestionChannlId = helpers.GetConfig().Path("biasgame.suggestion_channel_id").Data().(string)
+	imageSuggestionChannlId = helpers.GetConfig().Biasgame.SuggestionChannelID
 	imageSuggestionChannel, err = helpers.GetChannel(imageSuggestionChannlId)
 	helpers.Relax(err)
 
@@ -264,7 +269,6 @@ func processImageSuggestion(msg *discordgo.Message, msgContent string) {
 
 // checkSuggestionReaction will check if the reaction was added to a suggestion message
 func checkSuggestionReaction(reaction *discordgo.MessageReactionAdd) {
-	var userResponseMessage string
 
 	// check if the reaction added was valid
 	if CHECKMARK_EMOJI != reaction.Emoji.Name && X_EMOJI != reaction.Emoji.Name && NAV_NUMBERS_EMOJI != reaction.Emoji.Name {
@@ -288,11 +292,10 @@ func checkSuggestionReaction(reaction *discordgo.MessageReactionAdd) {
 				defer cache.GetSession().SessionForGuildS(reaction.GuildID).ChannelMessageDelete(imageSuggestionChannlId, msg[0].ID)
 			}
 
-			addSuggestionToGame(cs)
-
-			// set image accepted image
-			userResponseMessage = fmt.Sprintf("**Idol Suggestion Approved** <:blobthumbsup:317043177028714497>\nIdol: %s %s\nImage: <%s>", cs.GrouopName, cs.Name, cs.ImageURL)
-			cs.Status = "approved"
+			err = resolveSuggestion(cs, reaction.UserID, true, models.IdolAuditSourceDiscord)
+			if err != nil && err != ErrSuggestionNotFound {
+				helpers.Relax(err)
+			}
 
 		} else if X_EMOJI == reaction.Emoji.Name || NAV_NUMBERS_EMOJI == reaction.Emoji.Name {
 
@@ -307,8 +310,8 @@ func checkSuggestionReaction(reaction *discordgo.MessageReactionAdd) {
 				}
 			}
 
-			// confirm a note is set before denying a suggestion
-			if cs.Notes == "" {
+			err := resolveSuggestion(cs, reaction.UserID, false, models.IdolAuditSourceDiscord)
+			if err == ErrSuggestionNotesMissing {
 				// remove the x reaction just added
 				cache.GetSession().SessionForGuildS(reaction.GuildID).MessageReactionRemove(reaction.ChannelID, reaction.MessageID, reaction.Emoji.Name, reaction.UserID)
 
@@ -318,41 +321,172 @@ func checkSuggestionReaction(reaction *discordgo.MessageReactionAdd) {
 				helpers.DeleteMessageWithDelay(msgs[0], time.Second*15)
 				return
 			}
+			if err != nil && err != ErrSuggestionNotFound {
+				helpers.Relax(err)
+			}
+		}
+	}
 
-			// image was denied
-			userResponseMessage = fmt.Sprintf("**Idol Suggestion Denied** <:notlikeblob:349342777978519562>\nIdol: %s %s\nImage: <%s>", cs.GrouopName, cs.Name, cs.ImageURL)
-			cs.Status = "denied"
+	return
+}
+
+// GetUnresolvedSuggestions returns the suggestions waiting for a review, in the order of the queue
+func GetUnresolvedSuggestions() ([]models.IdolSuggestionEntry, error) {
+	var suggestions []models.IdolSuggestionEntry
+	err := helpers.MDbIter(helpers.MdbCollection(models.IdolSuggestionsTable).Find(bson.M{"status": ""}).Sort("_id")).All(&suggestions)
+	return suggestions, err
+}
+
+// ApproveSuggestion adds the image of an unresolved suggestion to the idol
+func ApproveSuggestion(suggestionID bson.ObjectId, userID string) error {
+	suggestion, err := getUnresolvedSuggestion(suggestionID)
+	if err != nil {
+		return err
+	}
+
+	return resolveSuggestion(suggestion, userID, true, models.IdolAuditSourceRest)
+}
 
-			// remove file from objectstorage
-			//  important note: only delete if the image was denied. when an image
-			//                  is accepted the same object storage file is used for the game
-			go helpers.DeleteFile(cs.ObjectName)
+// DenySuggestion denies an unresolved suggestion, the notes are sent to the user who made the suggestion
+func DenySuggestion(suggestionID bson.ObjectId, userID, notes string) error {
+	suggestion, err := getUnresolvedSuggestion(suggestionID)
+	if err != nil {
+		return err
+	}
+
+	if notes != "" {
+		suggestion.Notes = notes
+	}
+	return resolveSuggestion(suggestion, userID, false, models.IdolAuditSourceRest)
+}
+
+func getUnresolvedSuggestion(suggestionID bson.ObjectId) (*models.IdolSuggestionEntry, error) {
+	var suggestion models.IdolSuggestionEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.IdolSuggestionsTable).Find(bson.M{"_id": suggestionID, "status": ""}),
+		&suggestion,
+	)
+	if helpers.IsMdbNotFound(err) {
+		return nil, ErrSuggestionNotFound
+	}
+	return &suggestion, err
+}
+
+// resolveSuggestion approves or denies a suggestion, saves it to the audit history and lets the user know
+func resolveSuggestion(suggestion *models.IdolSuggestionEntry, userID string, approved bool, source string) error {
+	if !approved && suggestion.Notes == "" {
+		return ErrSuggestionNotesMissing
+	}
+
+	status := "denied"
+	if approved {
+		status = "approved"
+	}
+
+	// only resolve suggestions that are still unresolved, a reaction and the rest api could resolve it at the same time
+	err := helpers.MdbCollection(models.IdolSuggestionsTable).Update(
+		bson.M{"_id": suggestion.ID, "status": ""},
+		bson.M{"$set": bson.M{
+			"status":            status,
+			"processedbyuserid": userID,
+			"lastmodifiedon":    time.Now(),
+			"notes":             suggestion.Notes,
+		}},
+	)
+	if helpers.IsMdbNotFound(err) {
+		return ErrSuggestionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	suggestion.Status = status
+	suggestion.ProcessedByUserId = userID
+
+	auditEntry := models.IdolAuditEntry{
+		GroupName: suggestion.GrouopName,
+		UserID:    userID,
+		Source:    source,
+		Changes: []models.ElasticEventlogChange{
+			auditChange("Suggestion", "", suggestion.ID.Hex()),
+		},
+	}
+
+	var userResponseMessage string
+	if approved {
+		auditEntry.IdolID, err = addSuggestionToGame(suggestion)
+		if err != nil {
+			// put the suggestion back into the queue, so it can be approved again
+			helpers.RelaxLog(helpers.MdbCollection(models.IdolSuggestionsTable).Update(
+				bson.M{"_id": suggestion.ID, "status": status},
+				bson.M{"$set": bson.M{"status": "", "processedbyuserid": ""}},
+			))
+			suggestion.Status = ""
+			suggestion.ProcessedByUserId = ""
+			return err
 		}
+		auditEntry.Action = models.IdolAuditActionSuggestionApprove
+		auditEntry.Changes = append(auditEntry.Changes, auditChange("Image", "", suggestion.ObjectName))
 
-		// update db record
-		cs.ProcessedByUserId = reaction.UserID
-		cs.LastModifiedOn = time.Now()
-		go helpers.MDbUpsertID(models.IdolSuggestionsTable, cs.ID, cs)
+		userResponseMessage = fmt.Sprintf("**Idol Suggestion Approved** <:blobthumbsup:317043177028714497>\nIdol: %s %s\nImage: <%s>", suggestion.GrouopName, suggestion.Name, suggestion.ImageURL)
+	} else {
+		if _, _, idol := GetMatchingIdolAndGroup(suggestion.GrouopName, suggestion.Name, false); idol != nil {
+			auditEntry.IdolID = idol.ID
+		}
+		auditEntry.Action = models.IdolAuditActionSuggestionDeny
+		auditEntry.Changes = append(auditEntry.Changes, auditChange("Notes", "", suggestion.Notes))
 
-		// send a message to the user who suggested the image
-		dmChannel, err := cache.GetSession().SessionForGuildS(reaction.GuildID).UserChannelCreate(cs.UserID)
-		if err == nil {
-			// set notes if there are any
-			if cs.Notes != "" {
-				userResponseMessage += "\nNotes: " + cs.Notes
-			}
-			go helpers.SendMessage(dmChannel.ID, userResponseMessage)
+		// remove file from objectstorage
+		// important note: only delete if the image was denied. when an image is accepted the same object storage file is used for the game
+		go helpers.DeleteFile(suggestion.ObjectName)
+
+		userResponseMessage = fmt.Sprintf("**Idol Suggestion Denied** <:notlikeblob:349342777978519562>\nIdol: %s %s\nImage: <%s>", suggestion.GrouopName, suggestion.Name, suggestion.ImageURL)
+	}
+	recordIdolAudit(auditEntry)
+
+	// send a message to the user who suggested the image
+	dmChannel, err := cache.GetSession().Session(0).UserChannelCreate(suggestion.UserID)
+	if err == nil {
+		// set notes if there are any
+		if suggestion.Notes != "" {
+			userResponseMessage += "\nNotes: " + suggestion.Notes
 		}
+		go helpers.SendMessage(dmChannel.ID, userResponseMessage)
+	}
 
-		// delete first suggestion and process queue again
-		suggestionQueue = suggestionQueue[1:]
-		go func() {
-			defer helpers.Recover()
-			updateCurrentSuggestionEmbed()
-		}()
+	// reload the queue in all processes, this shows the next suggestion
+	return helpers.InvalidateCache(SUGGESTIONS_CACHE_NAME)
+}
+
+// reloadSuggestionQueue loads the unresolved suggestions and shows the next one if the current one was resolved
+func reloadSuggestionQueue() {
+	var currentSuggestionID bson.ObjectId
+	if len(suggestionQueue) > 0 {
+		currentSuggestionID = suggestionQueue[0].ID
 	}
 
-	return
+	var newSuggestionQueue []*models.IdolSuggestionEntry
+	err := helpers.MDbIter(helpers.MdbCollection(models.IdolSuggestionsTable).Find(bson.M{"status": ""}).Sort("_id")).All(&newSuggestionQueue)
+	helpers.Relax(err)
+	suggestionQueue = newSuggestionQueue
+
+	// only the process running the suggestion channel updates the messages
+	if imageSuggestionChannel == nil || !cache.GetSession().OwnsGuild(imageSuggestionChannel.GuildID) {
+		return
+	}
+
+	var nextSuggestionID bson.ObjectId
+	if len(suggestionQueue) > 0 {
+		nextSuggestionID = suggestionQueue[0].ID
+	}
+	if nextSuggestionID == currentSuggestionID {
+		updateSuggestionQueueCount()
+		return
+	}
+
+	go func() {
+		defer helpers.Recover()
+		updateCurrentSuggestionEmbed()
+	}()
 }
 
 // updateSuggestionDetails update the details of the current suggestion in queue
@@ -644,14 +778,14 @@ func clearSuggestionsChannel() {
 	}
 }
 
-// addSuggestionToGame will add the given suggestion entry to the available idols
-func addSuggestionToGame(suggestion *models.IdolSuggestionEntry) {
+// addSuggestionToGame will add the given suggestion entry to the available idols, returns the id of the idol
+func addSuggestionToGame(suggestion *models.IdolSuggestionEntry) (bson.ObjectId, error) {
 
 	// check if an idol with the suggested name and group already exists
 	var idolEntry models.IdolEntry
 	err := helpers.MdbOne(helpers.MdbCollection(models.IdolTable).Find(bson.M{"name": suggestion.Name, "groupname": suggestion.GrouopName}), &idolEntry)
 	if err != nil && err.Error() != "not found" {
-		helpers.Relax(err)
+		return "", err
 	}
 
 	newIdolImage := models.IdolImageEntry{
@@ -673,7 +807,9 @@ func addSuggestionToGame(suggestion *models.IdolSuggestionEntry) {
 
 		// insert file to mongodb
 		newIdolId, err := helpers.MDbInsert(models.IdolTable, idolEntry)
-		helpers.Relax(err)
+		if err != nil {
+			return "", err
+		}
 		idolEntry.ID = newIdolId
 
 	} else {
@@ -681,7 +817,9 @@ func addSuggestionToGame(suggestion *models.IdolSuggestionEntry) {
 		idolEntry.Images = append(idolEntry.Images, newIdolImage)
 		idolEntry.Deleted = false
 		err := helpers.MDbUpsertID(models.IdolTable, idolEntry.ID, idolEntry)
-		helpers.Relax(err)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	newIdol := makeIdolFromIdolEntry(idolEntry)
@@ -717,6 +855,9 @@ func addSuggestionToGame(suggestion *models.IdolSuggestionEntry) {
 	if len(GetAllIdols()) > 0 {
 		setModuleCache(ALL_IDOLS_CACHE_KEY, GetAllIdols(), time.Hour*24*7)
 	}
+	idolsChanged()
+
+	return idolEntry.ID, nil
 }
 
 // getUserInputPage waits for the user to enter a number
@@ -11,6 +11,7 @@ import (
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/globalsign/mgo/bson"
 )
@@ -78,6 +79,7 @@ func (m *Handler) checkInstagramPublicFeedLoop() {
 			"checked graphql feed on %d accounts for %d feeds with %d workers, took %s",
 			len(bundledEntries), entriesCount, InstagramGraphQlWorkers, elapsed)
 		metrics.InstagramGraphQlFeedRefreshTime.Set(elapsed.Seconds())
+		prom.FeedLoopDuration.WithLabelValues("instagram").Observe(elapsed.Seconds())
 
 		if entriesCount <= 10 {
 			time.Sleep(60 * time.Second)
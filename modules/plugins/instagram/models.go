@@ -357,7 +357,7 @@ func (m *Handler) getBundledEntries() (bundledEntries map[string][]models.Instag
 		}
 
 		// check if we can send messages
-		channelPermission, err := cache.GetSession().State.UserChannelPermissions(cache.GetSession().State.User.ID, channel.ID)
+		channelPermission, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 		if err != nil {
 			continue
 		}
@@ -86,6 +86,8 @@ func (m *LastFm) Init(session *shardmanager.Manager) {
 	lastfmCombinedGuildStats = make([]LastFMCombinedGuildStats, 0)
 
 	go m.generateDiscordStats()
+	go m.cacheArtistPlaysLoop()
+	go m.sendReportsLoop()
 }
 
 func (m *LastFm) generateDiscordStats() {
@@ -184,21 +186,12 @@ func (m *LastFm) generateDiscordStats() {
 				newCombinedGuildStat.GuildID = guild.ID
 				newCombinedGuildStat.NumberOfUsers = 0
 
-				members := make([]*discordgo.Member, 0)
-				for _, subShard := range cache.GetSession().Sessions {
-					for _, botGuild := range subShard.State.Guilds {
-						if botGuild.ID == guild.ID {
-							for _, member := range guild.Members {
-								members = append(members, member)
-							}
-						}
-					}
-				}
-
-				if len(members) <= 0 {
+				// members are tracked by the robyul state, not by the discordgo state
+				stateGuild, err := helpers.GetGuildWithoutApi(guild.ID)
+				if err != nil || len(stateGuild.Members) <= 0 {
 					continue
 				}
-				for _, member := range members {
+				for _, member := range stateGuild.Members {
 					for _, cachedStat := range lastfmCachedStats {
 						if cachedStat.UserID == member.User.ID {
 							// User is on Guild
@@ -351,9 +344,9 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				)
 				helpers.Relax(err)
 
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.set-username-success", lastfmUsername))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.set-username-success", lastfmUsername))
 			} else {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 				return
 			}
 		case "np", "nowplaying":
@@ -371,7 +364,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.Relax(err)
 
 			if lastfmUsername == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
 				return
 			}
 			session.ChannelTyping(msg.ChannelID)
@@ -409,9 +402,9 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			}
 			if lastfmRecentTracks.Total > 0 {
 				lastTrack := lastfmRecentTracks.Tracks[0]
-				lastTrackEmbedTitle := helpers.GetTextF("plugins.lastfm.lasttrack-embed-title-last", lastfmUsername)
+				lastTrackEmbedTitle := helpers.GetTextForMessage(msg, "plugins.lastfm.lasttrack-embed-title-last", lastfmUsername)
 				if lastTrack.NowPlaying == "true" {
-					lastTrackEmbedTitle = helpers.GetTextF("plugins.lastfm.lasttrack-embed-title-np", lastfmUsername)
+					lastTrackEmbedTitle = helpers.GetTextForMessage(msg, "plugins.lastfm.lasttrack-embed-title-np", lastfmUsername)
 				}
 				var heartText string
 				if lastTrack.Loved == "1" {
@@ -424,8 +417,8 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 						helpers.EscapeLinkForMarkdown(lastTrack.Url),
 						heartText),
 					Footer: &discordgo.MessageEmbedFooter{
-						Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-						IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+						Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+						IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 					},
 					Author: &discordgo.MessageEmbedAuthor{
 						URL:     fmt.Sprintf(lastfmFriendlyUser, lastfmUsername),
@@ -475,12 +468,12 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendEmbed(msg.ChannelID, lastTrackEmbed)
 				helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 			} else {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-recent-tracks"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
 				return
 			}
 		case "yt", "youtube":
 			if !youtube.HasYouTubeService() {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("lastfm.no-youtube"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "lastfm.no-youtube"))
 				return
 			}
 			if len(args) >= 2 {
@@ -495,7 +488,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.Relax(err)
 
 			if lastfmUsername == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
 				return
 			}
 			session.ChannelTyping(msg.ChannelID)
@@ -516,7 +509,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 					[]string{lastTrack.Artist.Name, lastTrack.Name}, "video")
 				helpers.RelaxLog(err)
 				if err != nil || searchResult == nil || searchResult.Snippet == nil {
-					helpers.SendMessage(msg.ChannelID, helpers.GetText("lastfm.no-youtube"))
+					helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "lastfm.no-youtube"))
 					return
 				}
 				messageContent := "**" + searchResult.Snippet.Title + "** on " + searchResult.Snippet.ChannelTitle + "\n"
@@ -524,7 +517,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendMessage(msg.ChannelID, messageContent)
 				helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 			} else {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-recent-tracks"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
 				return
 			}
 		case "topalbums", "topalbum", "tal":
@@ -577,7 +570,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.Relax(err)
 
 			if lastfmUsername == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
 				return
 			}
 			session.ChannelTyping(msg.ChannelID)
@@ -624,12 +617,12 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 					topAlbumsEmbed := &discordgo.MessageEmbed{
 						Description: description,
 						Footer: &discordgo.MessageEmbedFooter{
-							Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-							IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+							Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+							IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 						},
 						Color: helpers.GetDiscordColorFromHex(lastfmHexColor),
 						Author: &discordgo.MessageEmbedAuthor{
-							Name: helpers.GetTextF("plugins.lastfm.topalbums-embed-title", lastfmUsername) + " of " + timeString,
+							Name: helpers.GetTextForMessage(msg, "plugins.lastfm.topalbums-embed-title", lastfmUsername) + " of " + timeString,
 							URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmTopAlbums.User),
 						},
 						Image: &discordgo.MessageEmbedImage{
@@ -656,13 +649,13 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				topAlbumsEmbed := &discordgo.MessageEmbed{
 					Description: "of **" + timeString + "**",
 					Footer: &discordgo.MessageEmbedFooter{
-						Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-						IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+						Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+						IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 					},
 					Fields: []*discordgo.MessageEmbedField{},
 					Color:  helpers.GetDiscordColorFromHex(lastfmHexColor),
 					Author: &discordgo.MessageEmbedAuthor{
-						Name: helpers.GetTextF("plugins.lastfm.topalbums-embed-title", lastfmUsername),
+						Name: helpers.GetTextForMessage(msg, "plugins.lastfm.topalbums-embed-title", lastfmUsername),
 						URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmTopAlbums.User),
 					},
 				}
@@ -683,7 +676,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendEmbed(msg.ChannelID, topAlbumsEmbed)
 				helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 			} else {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-recent-tracks"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
 				return
 			}
 		case "topartists", "topartist", "top", "ta":
@@ -736,7 +729,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.Relax(err)
 
 			if lastfmUsername == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
 				return
 			}
 			session.ChannelTyping(msg.ChannelID)
@@ -780,13 +773,13 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 
 					topArtistsEmbed := &discordgo.MessageEmbed{
 						Footer: &discordgo.MessageEmbedFooter{
-							Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-							IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+							Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+							IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 						},
 						Fields: []*discordgo.MessageEmbedField{},
 						Color:  helpers.GetDiscordColorFromHex(lastfmHexColor),
 						Author: &discordgo.MessageEmbedAuthor{
-							Name: helpers.GetTextF("plugins.lastfm.topartists-embed-title", lastfmUsername) + " of " + timeString,
+							Name: helpers.GetTextForMessage(msg, "plugins.lastfm.topartists-embed-title", lastfmUsername) + " of " + timeString,
 							URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmTopArtists.User),
 						},
 						Image: &discordgo.MessageEmbedImage{
@@ -814,13 +807,13 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				topArtistsEmbed := &discordgo.MessageEmbed{
 					Description: "of **" + timeString + "**",
 					Footer: &discordgo.MessageEmbedFooter{
-						Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-						IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+						Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+						IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 					},
 					Fields: []*discordgo.MessageEmbedField{},
 					Color:  helpers.GetDiscordColorFromHex(lastfmHexColor),
 					Author: &discordgo.MessageEmbedAuthor{
-						Name: helpers.GetTextF("plugins.lastfm.topartists-embed-title", lastfmUsername),
+						Name: helpers.GetTextForMessage(msg, "plugins.lastfm.topartists-embed-title", lastfmUsername),
 						URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmTopArtists.User),
 					},
 				}
@@ -841,7 +834,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendEmbed(msg.ChannelID, topArtistsEmbed)
 				helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 			} else {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-recent-tracks"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
 				return
 			}
 		case "toptracks", "topsongs", "toptrack", "topsong", "tt", "ts":
@@ -894,7 +887,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.Relax(err)
 
 			if lastfmUsername == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
 				return
 			}
 			session.ChannelTyping(msg.ChannelID)
@@ -938,12 +931,12 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 
 					topTracksEmbed := &discordgo.MessageEmbed{
 						Footer: &discordgo.MessageEmbedFooter{
-							Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-							IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+							Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+							IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 						},
 						Color: helpers.GetDiscordColorFromHex(lastfmHexColor),
 						Author: &discordgo.MessageEmbedAuthor{
-							Name: helpers.GetTextF("plugins.lastfm.toptracks-embed-title", lastfmUsername) + " of " + timeString,
+							Name: helpers.GetTextForMessage(msg, "plugins.lastfm.toptracks-embed-title", lastfmUsername) + " of " + timeString,
 							URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmTopTracks.User),
 						},
 						Image: &discordgo.MessageEmbedImage{
@@ -971,13 +964,13 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				topTracksEmbed := &discordgo.MessageEmbed{
 					Description: "of **" + timeString + "**",
 					Footer: &discordgo.MessageEmbedFooter{
-						Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-						IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+						Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+						IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 					},
 					Fields: []*discordgo.MessageEmbedField{},
 					Color:  helpers.GetDiscordColorFromHex(lastfmHexColor),
 					Author: &discordgo.MessageEmbedAuthor{
-						Name: helpers.GetTextF("plugins.lastfm.toptracks-embed-title", lastfmUsername),
+						Name: helpers.GetTextForMessage(msg, "plugins.lastfm.toptracks-embed-title", lastfmUsername),
 						URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmTopTracks.User),
 					},
 				}
@@ -998,12 +991,12 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				_, err = helpers.SendEmbed(msg.ChannelID, topTracksEmbed)
 				helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 			} else {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-recent-tracks"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
 				return
 			}
 		case "discord-top", "server-top", "servertop", "discordtop":
 			if !helpers.FeatureEnabled(featureFlagServerStats, featureFlagServerStatsFallback) {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-stats-available"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-stats-available"))
 				return
 			}
 
@@ -1020,7 +1013,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			}
 
 			if combinedStats.GuildID == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-stats-available-yet"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-stats-available-yet"))
 				return
 			}
 
@@ -1057,14 +1050,14 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			}
 
 			topTracksEmbed := &discordgo.MessageEmbed{
-				Title:       helpers.GetTextF("plugins.lastfm.toptracks-embed-title", fmt.Sprintf("%s Server", guild.Name)),
+				Title:       helpers.GetTextForMessage(msg, "plugins.lastfm.toptracks-embed-title", fmt.Sprintf("%s Server", guild.Name)),
 				Description: fmt.Sprintf("of **%s**", timeString),
 				Footer: &discordgo.MessageEmbedFooter{
 					Text: fmt.Sprintf(
 						"%s | %d last.fm users on this server",
-						helpers.GetText("plugins.lastfm.embed-footer"),
+						helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
 						combinedStats.NumberOfUsers),
-					IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+					IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 				},
 				Fields: []*discordgo.MessageEmbedField{},
 				Color:  helpers.GetDiscordColorFromHex(lastfmHexColor),
@@ -1104,7 +1097,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.Relax(err)
 
 			if lastfmUsername == "" {
-				helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
 				return
 			}
 			session.ChannelTyping(msg.ChannelID)
@@ -1122,7 +1115,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			}
 
 			if len(lastfmRecentTracks.Tracks) <= 0 {
-				helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.lastfm.no-recent-tracks"))
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
 				return
 			}
 
@@ -1150,12 +1143,12 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 
 			recentsEmbed := &discordgo.MessageEmbed{
 				Footer: &discordgo.MessageEmbedFooter{
-					Text:    helpers.GetText("plugins.lastfm.embed-footer") + playcountText,
-					IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+					Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer") + playcountText,
+					IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 				},
 				Author: &discordgo.MessageEmbedAuthor{
 					URL:     fmt.Sprintf(lastfmFriendlyUser, lastfmUsername),
-					Name:    helpers.GetTextF("plugins.lastfm.recents-embed-title", lastfmUsername),
+					Name:    helpers.GetTextForMessage(msg, "plugins.lastfm.recents-embed-title", lastfmUsername),
 					IconURL: lastfmAvatar,
 				},
 				//Fields: []*discordgo.MessageEmbedField{},
@@ -1194,6 +1187,21 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			_, err = helpers.SendEmbed(msg.ChannelID, recentsEmbed)
 			helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 			return
+		case "whoknows", "wk":
+			m.actionWhoKnows(args[1:], msg, session, lastfmUsername)
+			return
+		case "whoknows-track", "wkt":
+			m.actionWhoKnowsTrack(args[1:], msg, session, lastfmUsername)
+			return
+		case "crowns", "crown":
+			m.actionCrowns(args[1:], msg, session)
+			return
+		case "taste", "compatibility", "compat":
+			m.actionTaste(args[1:], msg, session, lastfmUsername)
+			return
+		case "report", "weekly":
+			m.actionReport(args[1:], msg, session, lastfmUsername)
+			return
 		default:
 			var err error
 			targetUser := msg.Author
@@ -1218,14 +1226,14 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 				scrobblesCount, err = strconv.Atoi(lastfmUser.PlayCount)
 				helpers.Relax(err)
 			}
-			embedTitle := helpers.GetTextF("plugins.lastfm.profile-embed-title", lastfmUser.Name)
+			embedTitle := helpers.GetTextForMessage(msg, "plugins.lastfm.profile-embed-title", lastfmUser.Name)
 			if lastfmUser.RealName != "" {
-				embedTitle = helpers.GetTextF("plugins.lastfm.profile-embed-title-realname", lastfmUser.RealName, lastfmUser.Name)
+				embedTitle = helpers.GetTextForMessage(msg, "plugins.lastfm.profile-embed-title-realname", lastfmUser.RealName, lastfmUser.Name)
 			}
 			accountEmbed := &discordgo.MessageEmbed{
 				Footer: &discordgo.MessageEmbedFooter{
-					Text:    helpers.GetText("plugins.lastfm.embed-footer"),
-					IconURL: helpers.GetText("plugins.lastfm.embed-footer-imageurl"),
+					Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+					IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
 				},
 				Fields: []*discordgo.MessageEmbedField{
 					{Name: "Scrobbles", Value: humanize.Comma(int64(scrobblesCount)), Inline: true}},
@@ -1271,7 +1279,7 @@ func (m *LastFm) Action(command string, content string, msg *discordgo.Message,
 			helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 		}
 	} else {
-		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("bot.arguments.too-few"))
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 		return
 	}
 
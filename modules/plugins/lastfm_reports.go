@@ -0,0 +1,401 @@
+package plugins
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/lastfm-go/lastfm"
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// lastfmReportWeekday and lastfmReportHour are when the weekly reports are sent, in the timezone of the user
+	lastfmReportWeekday = time.Monday
+	lastfmReportHour    = 10
+	// lastfmReportArtistsLimit is the number of top artists of the week checked for discoveries
+	lastfmReportArtistsLimit = 200
+	// lastfmReportDiscoveriesSize is the number of discoveries shown in a report
+	lastfmReportDiscoveriesSize = 10
+	lastfmReportCollageName     = "Robyul-LastFM-Report.png"
+)
+
+// lastfmReport is the listening of an account in the last seven days
+type lastfmReport struct {
+	Scrobbles    int
+	Artists      int
+	TopArtist    models.LastFmArtistPlays
+	TopArtistURL string
+	// Discoveries are the artists listened to for the first time
+	Discoveries []string
+	// CollageImageUrls and CollageTitles are the covers of the top albums
+	CollageImageUrls []string
+	CollageTitles    []string
+}
+
+// [p]lf report [dm|here|stop]
+func (m *LastFm) actionReport(args []string, msg *discordgo.Message, session *discordgo.Session, lastfmUsername string) {
+	channel, err := helpers.GetChannel(msg.ChannelID)
+	helpers.Relax(err)
+
+	var subCommand string
+	if len(args) >= 1 {
+		subCommand = args[0]
+	}
+
+	if subCommand == "stop" || subCommand == "unsubscribe" {
+		err = helpers.MdbDeleteQuery(models.LastFmReportsTable, bson.M{"userid": msg.Author.ID})
+		if err != nil && !helpers.IsMdbNotFound(err) {
+			helpers.Relax(err)
+		}
+
+		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.report-unsubscribed"))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+
+	if lastfmUsername == "" {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+		return
+	}
+
+	switch subCommand {
+	case "dm", "here", "channel":
+		entry := models.LastFmReportEntry{
+			UserID:     msg.Author.ID,
+			CreatedAt:  time.Now(),
+			LastSentAt: time.Now(),
+		}
+		successText := helpers.GetTextForMessage(msg, "plugins.lastfm.report-subscribed-dm")
+		if subCommand != "dm" {
+			entry.GuildID = channel.GuildID
+			entry.ChannelID = channel.ID
+			successText = helpers.GetTextForMessage(msg, "plugins.lastfm.report-subscribed-channel", channel.ID)
+		}
+
+		err = helpers.MDbUpsert(models.LastFmReportsTable, bson.M{"userid": msg.Author.ID}, entry)
+		helpers.Relax(err)
+
+		_, err = helpers.SendMessage(msg.ChannelID, successText)
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	case "":
+		break
+	default:
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+		return
+	}
+
+	// without arguments the report of the current week is shown
+	session.ChannelTyping(msg.ChannelID)
+
+	report, err := getLastFmReport(msg.Author.ID, lastfmUsername)
+	if err != nil {
+		if e, ok := err.(*lastfm.LastfmError); ok {
+			helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Error: `%s`", e.Message))
+			return
+		}
+		helpers.Relax(err)
+	}
+
+	if report.Scrobbles <= 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
+		return
+	}
+
+	reportMessage := lastfmReportMessage(helpers.GetLocaleForUser(channel.GuildID, msg.Author.ID), lastfmUsername, report)
+
+	var entry models.LastFmReportEntry
+	err = helpers.MdbOne(helpers.MdbCollection(models.LastFmReportsTable).Find(bson.M{"userid": msg.Author.ID}), &entry)
+	if err != nil && !helpers.IsMdbNotFound(err) {
+		helpers.Relax(err)
+	}
+	switch {
+	case entry.ID == "":
+		reportMessage.Content = helpers.GetTextForMessage(msg, "plugins.lastfm.report-status-none",
+			helpers.GetPrefixForServer(channel.GuildID), helpers.GetPrefixForServer(channel.GuildID))
+	case entry.ChannelID == "":
+		reportMessage.Content = helpers.GetTextForMessage(msg, "plugins.lastfm.report-status-dm")
+	default:
+		reportMessage.Content = helpers.GetTextForMessage(msg, "plugins.lastfm.report-status-channel", entry.ChannelID)
+	}
+
+	_, err = helpers.SendComplex(msg.ChannelID, reportMessage)
+	helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+}
+
+// getLastFmReport requests the listening of the account in the last seven days
+func getLastFmReport(userID, lastfmUsername string) (report lastfmReport, err error) {
+	recentTracks, err := helpers.GetLastFmClient().User.GetRecentTracks(lastfm.P{
+		"limit": 1,
+		"user":  lastfmUsername,
+		"from":  time.Now().Add(-7 * 24 * time.Hour).Unix(),
+	})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		return report, err
+	}
+	report.Scrobbles = recentTracks.Total
+	if report.Scrobbles <= 0 {
+		return report, nil
+	}
+
+	topArtists, err := helpers.GetLastFmClient().User.GetTopArtists(lastfm.P{
+		"limit":  lastfmReportArtistsLimit,
+		"period": "7day",
+		"user":   lastfmUsername,
+	})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		return report, err
+	}
+	report.Artists = topArtists.Total
+
+	weeklyArtists := lastfmArtistPlays(topArtists)
+	if len(weeklyArtists) > 0 {
+		report.TopArtist = weeklyArtists[0]
+		report.TopArtistURL = topArtists.Artists[0].Url
+	}
+
+	// refreshing the cached top artists includes the plays of this week
+	overallArtists, err := refreshLastFmArtistPlays(models.LastFmEntry{UserID: userID, LastFmUsername: lastfmUsername})
+	if err != nil {
+		return report, err
+	}
+	report.Discoveries = lastfmDiscoveries(weeklyArtists, overallArtists.Artists)
+
+	topAlbums, err := helpers.GetLastFmClient().User.GetTopAlbums(lastfm.P{
+		"limit":  9,
+		"period": "7day",
+		"user":   lastfmUsername,
+	})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		return report, err
+	}
+	for _, topAlbum := range topAlbums.Albums {
+		for _, image := range topAlbum.Images {
+			if image.Size == "extralarge" && image.Url != "" {
+				report.CollageImageUrls = append(report.CollageImageUrls, image.Url)
+				report.CollageTitles = append(report.CollageTitles, topAlbum.Artist.Name+" - "+topAlbum.Name)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// lastfmDiscoveries returns the artists of the period which have no plays before the period
+func lastfmDiscoveries(period, overall []models.LastFmArtistPlays) (discoveries []string) {
+	overallPlays := make(map[string]int, len(overall))
+	for _, artist := range overall {
+		overallPlays[artist.Key] = artist.Plays
+	}
+
+	for _, artist := range period {
+		// artists missing in the overall top artists are not counted, their plays before the period are unknown
+		if plays, ok := overallPlays[artist.Key]; ok && plays <= artist.Plays {
+			discoveries = append(discoveries, artist.Name)
+		}
+	}
+	return discoveries
+}
+
+// lastfmReportMessage returns the report embed, with a collage of the top albums if there are album covers
+func lastfmReportMessage(locale, lastfmUsername string, report lastfmReport) *discordgo.MessageSend {
+	reportEmbed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: helpers.GetTextForLocale(locale, "plugins.lastfm.report-embed-title", lastfmUsername),
+			URL:  fmt.Sprintf(lastfmFriendlyUser, lastfmUsername),
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text:    helpers.GetTextForLocale(locale, "plugins.lastfm.embed-footer"),
+			IconURL: helpers.GetTextForLocale(locale, "plugins.lastfm.embed-footer-imageurl"),
+		},
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   helpers.GetTextForLocale(locale, "plugins.lastfm.report-scrobbles"),
+				Value:  humanize.Comma(int64(report.Scrobbles)),
+				Inline: true,
+			},
+			{
+				Name:   helpers.GetTextForLocale(locale, "plugins.lastfm.report-artists"),
+				Value:  humanize.Comma(int64(report.Artists)),
+				Inline: true,
+			},
+		},
+		Color: helpers.GetDiscordColorFromHex(lastfmHexColor),
+	}
+
+	if report.TopArtist.Name != "" {
+		reportEmbed.Fields = append(reportEmbed.Fields, &discordgo.MessageEmbedField{
+			Name: helpers.GetTextForLocale(locale, "plugins.lastfm.report-top-artist"),
+			Value: fmt.Sprintf("[**%s**](%s) (%s plays)",
+				report.TopArtist.Name, helpers.EscapeLinkForMarkdown(report.TopArtistURL), humanize.Comma(int64(report.TopArtist.Plays))),
+		})
+	}
+
+	if len(report.Discoveries) > 0 {
+		discoveries := report.Discoveries
+		var moreText string
+		if len(discoveries) > lastfmReportDiscoveriesSize {
+			moreText = " " + helpers.GetTextForLocale(locale, "plugins.lastfm.report-discoveries-more", len(discoveries)-lastfmReportDiscoveriesSize)
+			discoveries = discoveries[:lastfmReportDiscoveriesSize]
+		}
+		reportEmbed.Fields = append(reportEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:  helpers.GetTextForLocale(locale, "plugins.lastfm.report-discoveries", len(report.Discoveries)),
+			Value: strings.Join(discoveries, ", ") + moreText,
+		})
+	}
+
+	reportMessage := &discordgo.MessageSend{
+		Embed: reportEmbed,
+	}
+
+	if len(report.CollageImageUrls) > 0 {
+		collageBytes := lastfmCollage(report.CollageImageUrls, report.CollageTitles)
+		if len(collageBytes) > 0 {
+			reportEmbed.Image = &discordgo.MessageEmbedImage{
+				URL: "attachment://" + lastfmReportCollageName,
+			}
+			reportMessage.Files = []*discordgo.File{{
+				Name:   lastfmReportCollageName,
+				Reader: bytes.NewReader(collageBytes),
+			}}
+		}
+	}
+
+	return reportMessage
+}
+
+// lastfmReportDue returns if a report which was sent last at lastSentAt is due,
+// reports are due every week on lastfmReportWeekday at lastfmReportHour in the location
+func lastfmReportDue(now time.Time, location *time.Location, lastSentAt time.Time) bool {
+	return lastSentAt.Before(lastfmReportScheduled(now, location))
+}
+
+// lastfmReportScheduled returns the last time before now a report was scheduled in the location
+func lastfmReportScheduled(now time.Time, location *time.Location) time.Time {
+	local := now.In(location)
+
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), lastfmReportHour, 0, 0, 0, location)
+	scheduled = scheduled.AddDate(0, 0, -((int(local.Weekday()) - int(lastfmReportWeekday) + 7) % 7))
+	if scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, -7)
+	}
+	return scheduled
+}
+
+// claimLastFmReport marks the report as sent if it has not been sent since it was scheduled,
+// returns false if another process sent it already
+func claimLastFmReport(entry models.LastFmReportEntry, scheduled time.Time) (claimed bool, err error) {
+	_, err = helpers.MdbCollection(models.LastFmReportsTable).Find(bson.M{
+		"_id":        entry.ID,
+		"lastsentat": bson.M{"$lt": scheduled},
+	}).Apply(mgo.Change{
+		Update: bson.M{"$set": bson.M{"lastsentat": time.Now()}},
+	}, nil)
+	if helpers.IsMdbNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getLastFmReportLocation returns the timezone set in the profile of the user, or UTC
+func getLastFmReportLocation(userID string) *time.Location {
+	userData, err := helpers.GetUserUserdata(userID)
+	if err == nil && userData.Timezone != "" {
+		location, err := time.LoadLocation(userData.Timezone)
+		if err == nil {
+			return location
+		}
+	}
+	return time.UTC
+}
+
+// sendReportsLoop sends the due weekly reports every five minutes
+func (m *LastFm) sendReportsLoop() {
+	defer helpers.Recover()
+	defer func() {
+		go func() {
+			cache.GetLogger().WithField("module", "lastfm").Error("The sendReportsLoop died. Please investigate! Will be restarted in 60 seconds")
+			time.Sleep(60 * time.Second)
+			m.sendReportsLoop()
+		}()
+	}()
+
+	for {
+		time.Sleep(5 * time.Minute)
+
+		var entries []models.LastFmReportEntry
+		err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LastFmReportsTable).Find(nil)).All(&entries)
+		helpers.Relax(err)
+
+		for _, entry := range entries {
+			scheduled := lastfmReportScheduled(time.Now(), getLastFmReportLocation(entry.UserID))
+			if !entry.LastSentAt.Before(scheduled) {
+				continue
+			}
+
+			// every process checks all reports, the report is sent by the process which marks it as sent first
+			claimed, err := claimLastFmReport(entry, scheduled)
+			if err != nil {
+				helpers.RelaxLog(err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			err = m.sendReport(entry)
+			if err != nil {
+				cache.GetLogger().WithField("module", "lastfm").WithField("userID", entry.UserID).Warnf(
+					"sending weekly report failed: %s", err.Error())
+			}
+		}
+	}
+}
+
+// sendReport sends the weekly report of the subscription, subscriptions of users without an account
+// or who left the guild of the channel are removed
+func (m *LastFm) sendReport(entry models.LastFmReportEntry) (err error) {
+	lastfmUsername := helpers.GetLastFmUsername(entry.UserID)
+	if lastfmUsername == "" {
+		return helpers.MDbDeleteWithoutLogging(models.LastFmReportsTable, entry.ID)
+	}
+	if entry.GuildID != "" {
+		// members missing from the state are confirmed with Discord, the state does not cache every member
+		_, err = helpers.GetGuildMember(entry.GuildID, entry.UserID)
+		if errD, ok := err.(*discordgo.RESTError); ok && errD.Message != nil && errD.Message.Code == discordgo.ErrCodeUnknownMember {
+			return helpers.MDbDeleteWithoutLogging(models.LastFmReportsTable, entry.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	channelID := entry.ChannelID
+	if channelID == "" {
+		dmChannel, err := cache.GetSession().Session(0).UserChannelCreate(entry.UserID)
+		if err != nil {
+			return err
+		}
+		channelID = dmChannel.ID
+	}
+
+	report, err := getLastFmReport(entry.UserID, lastfmUsername)
+	if err != nil || report.Scrobbles <= 0 {
+		return err
+	}
+
+	_, err = helpers.SendComplex(channelID, lastfmReportMessage(helpers.GetLocaleForUser(entry.GuildID, entry.UserID), lastfmUsername, report))
+	return err
+}
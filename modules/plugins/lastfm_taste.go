@@ -0,0 +1,185 @@
+package plugins
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/lastfm-go/lastfm"
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// lastfmTasteArtistsLimit is the number of top artists compared for every period
+	lastfmTasteArtistsLimit = 100
+	// lastfmTasteSharedSize is the number of shared artists shown by taste
+	lastfmTasteSharedSize = 10
+)
+
+// lastfmTastePeriods are the periods compared by taste, the score is the average of all periods
+var lastfmTastePeriods = []struct {
+	Period string
+	Key    string
+}{
+	{Period: "1month", Key: "plugins.lastfm.taste-period-month"},
+	{Period: "6month", Key: "plugins.lastfm.taste-period-six-months"},
+	{Period: "overall", Key: "plugins.lastfm.taste-period-overall"},
+}
+
+// lastfmSharedArtist is an artist in the top artists of both accounts
+type lastfmSharedArtist struct {
+	Name   string
+	PlaysA int
+	PlaysB int
+	// Overlap is the smaller share of plays of both accounts
+	Overlap float64
+}
+
+// [p]lf taste <user a> [<user b>]
+func (m *LastFm) actionTaste(args []string, msg *discordgo.Message, session *discordgo.Session, lastfmUsername string) {
+	if len(args) < 1 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+		return
+	}
+
+	usernames := []string{lastfmUsername}
+	if len(args) >= 2 {
+		usernames = nil
+	}
+	for _, arg := range args {
+		if len(usernames) >= 2 {
+			break
+		}
+		username := arg
+		targetUser, err := helpers.GetUserFromMention(arg)
+		if err == nil {
+			username = helpers.GetLastFmUsername(targetUser.ID)
+			if username == "" {
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.taste-no-account", targetUser.Username))
+				return
+			}
+		}
+		usernames = append(usernames, username)
+	}
+	if usernames[0] == "" {
+		channel, err := helpers.GetChannel(msg.ChannelID)
+		helpers.Relax(err)
+
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+		return
+	}
+	session.ChannelTyping(msg.ChannelID)
+
+	tasteEmbed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: helpers.GetTextForMessage(msg, "plugins.lastfm.taste-embed-title", usernames[0], usernames[1]),
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text:    helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer"),
+			IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
+		},
+		Color: helpers.GetDiscordColorFromHex(lastfmHexColor),
+	}
+
+	var score float64
+	var shared []lastfmSharedArtist
+	for _, period := range lastfmTastePeriods {
+		var topArtists [2][]models.LastFmArtistPlays
+		for i, username := range usernames {
+			lastfmTopArtists, err := helpers.GetLastFmClient().User.GetTopArtists(lastfm.P{
+				"limit":  lastfmTasteArtistsLimit,
+				"period": period.Period,
+				"user":   username,
+			})
+			metrics.LastFmRequests.Add(1)
+			if err != nil {
+				if e, ok := err.(*lastfm.LastfmError); ok {
+					helpers.SendMessage(msg.ChannelID, fmt.Sprintf("Error: `%s`", e.Message))
+					return
+				}
+				helpers.Relax(err)
+			}
+			topArtists[i] = lastfmArtistPlays(lastfmTopArtists)
+		}
+
+		overlap, periodShared := lastfmTasteOverlap(topArtists[0], topArtists[1])
+		score += overlap / float64(len(lastfmTastePeriods))
+		tasteEmbed.Description += helpers.GetTextForMessage(msg, period.Key) + ": **" + lastfmTastePercent(overlap) + "**\n"
+
+		// the shared artists of all time are highlighted
+		if period.Period == "overall" {
+			shared = periodShared
+		}
+	}
+
+	tasteEmbed.Description = helpers.GetTextForMessage(msg, "plugins.lastfm.taste-score", lastfmTastePercent(score)) +
+		"\n\n" + tasteEmbed.Description
+
+	if len(shared) > 0 {
+		var sharedText string
+		for i, artist := range shared {
+			if i >= lastfmTasteSharedSize {
+				break
+			}
+			sharedText += fmt.Sprintf("**%s** - %s vs. %s plays\n",
+				artist.Name, humanize.Comma(int64(artist.PlaysA)), humanize.Comma(int64(artist.PlaysB)))
+		}
+		tasteEmbed.Fields = append(tasteEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:  helpers.GetTextForMessage(msg, "plugins.lastfm.taste-shared-artists", len(shared)),
+			Value: sharedText,
+		})
+	}
+
+	_, err := helpers.SendEmbed(msg.ChannelID, tasteEmbed)
+	helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
+}
+
+// lastfmTasteOverlap compares the top artists of two accounts, the overlap is the share of plays
+// both accounts spend on the same artists, from 0 for no shared artists to 1 for the same artists with the same shares
+// The shared artists are sorted by their overlap
+func lastfmTasteOverlap(a, b []models.LastFmArtistPlays) (overlap float64, shared []lastfmSharedArtist) {
+	var totalA, totalB int
+	for _, artist := range a {
+		totalA += artist.Plays
+	}
+	for _, artist := range b {
+		totalB += artist.Plays
+	}
+	if totalA <= 0 || totalB <= 0 {
+		return 0, nil
+	}
+
+	playsB := make(map[string]int, len(b))
+	for _, artist := range b {
+		playsB[artist.Key] += artist.Plays
+	}
+
+	for _, artist := range a {
+		plays, ok := playsB[artist.Key]
+		if !ok || artist.Plays <= 0 || plays <= 0 {
+			continue
+		}
+
+		sharedArtist := lastfmSharedArtist{
+			Name:    artist.Name,
+			PlaysA:  artist.Plays,
+			PlaysB:  plays,
+			Overlap: math.Min(float64(artist.Plays)/float64(totalA), float64(plays)/float64(totalB)),
+		}
+		overlap += sharedArtist.Overlap
+		shared = append(shared, sharedArtist)
+	}
+
+	sort.SliceStable(shared, func(i, j int) bool {
+		return shared[i].Overlap > shared[j].Overlap
+	})
+	return math.Min(overlap, 1), shared
+}
+
+func lastfmTastePercent(overlap float64) string {
+	return fmt.Sprintf("%.0f%%", overlap*100)
+}
@@ -0,0 +1,91 @@
+package plugins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/models"
+)
+
+func TestLastFmReportDue(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday, 2018-12-24 10:00 in Seoul is 01:00 UTC
+	scheduled := time.Date(2018, 12, 24, 1, 0, 0, 0, time.UTC)
+	if !lastfmReportScheduled(scheduled.Add(time.Hour), seoul).Equal(scheduled) {
+		t.Fatal("unexpected schedule", lastfmReportScheduled(scheduled.Add(time.Hour), seoul))
+	}
+
+	for _, test := range []struct {
+		now        time.Time
+		location   *time.Location
+		lastSentAt time.Time
+		due        bool
+	}{
+		{scheduled.Add(time.Minute), seoul, scheduled.AddDate(0, 0, -7), true},
+		{scheduled.Add(-time.Minute), seoul, scheduled.AddDate(0, 0, -7), false},
+		{scheduled.Add(time.Hour), seoul, scheduled.Add(time.Minute), false},
+		{scheduled.AddDate(0, 0, 6), seoul, scheduled.Add(time.Minute), false},
+		{scheduled.AddDate(0, 0, 7), seoul, scheduled.Add(time.Minute), true},
+		// in UTC the report is not due before 10:00 UTC
+		{scheduled.Add(time.Hour), time.UTC, scheduled.AddDate(0, 0, -6), false},
+		{scheduled.Add(9 * time.Hour), time.UTC, scheduled.AddDate(0, 0, -6), true},
+		// reports which were never sent are due
+		{scheduled.Add(time.Minute), time.UTC, time.Time{}, true},
+	} {
+		if lastfmReportDue(test.now, test.location, test.lastSentAt) != test.due {
+			t.Fatalf("lastfmReportDue(%s, %s, %s) should be %t", test.now, test.location, test.lastSentAt, test.due)
+		}
+	}
+}
+
+func TestLastFmDiscoveries(t *testing.T) {
+	period := []models.LastFmArtistPlays{
+		{Key: "iu", Name: "IU", Plays: 30},
+		{Key: "red velvet", Name: "Red Velvet", Plays: 20},
+		{Key: "twice", Name: "TWICE", Plays: 10},
+	}
+	overall := []models.LastFmArtistPlays{
+		{Key: "iu", Name: "IU", Plays: 30},
+		{Key: "red velvet", Name: "Red Velvet", Plays: 500},
+	}
+
+	discoveries := lastfmDiscoveries(period, overall)
+	if len(discoveries) != 1 || discoveries[0] != "IU" {
+		t.Fatal("unexpected discoveries", discoveries)
+	}
+}
+
+func TestLastFmTasteOverlap(t *testing.T) {
+	a := []models.LastFmArtistPlays{
+		{Key: "iu", Name: "IU", Plays: 50},
+		{Key: "red velvet", Name: "Red Velvet", Plays: 25},
+		{Key: "twice", Name: "TWICE", Plays: 25},
+	}
+	b := []models.LastFmArtistPlays{
+		{Key: "red velvet", Name: "Red Velvet", Plays: 50},
+		{Key: "iu", Name: "IU", Plays: 10},
+		{Key: "bts", Name: "BTS", Plays: 40},
+	}
+
+	overlap, shared := lastfmTasteOverlap(a, b)
+	// IU: min(0.5, 0.1), Red Velvet: min(0.25, 0.5)
+	if overlap < 0.349 || overlap > 0.351 {
+		t.Fatal("unexpected overlap", overlap)
+	}
+	if len(shared) != 2 || shared[0].Name != "Red Velvet" || shared[0].PlaysB != 50 || shared[1].Name != "IU" {
+		t.Fatal("unexpected shared artists", shared)
+	}
+
+	overlap, _ = lastfmTasteOverlap(a, a)
+	if overlap != 1 {
+		t.Fatal("expected the same artists to overlap completely", overlap)
+	}
+	overlap, shared = lastfmTasteOverlap(a, nil)
+	if overlap != 0 || len(shared) != 0 {
+		t.Fatal("expected no overlap without artists", overlap, shared)
+	}
+}
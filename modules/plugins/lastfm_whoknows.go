@@ -0,0 +1,684 @@
+package plugins
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/lastfm-go/lastfm"
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// lastfmCrownsDefaultMinimumPlays is the number of plays required to hold a crown if the guild did not set a minimum
+	lastfmCrownsDefaultMinimumPlays = 30
+	// lastfmArtistPlaysMaxAge is how long the cached top artists of an account are used before they are refreshed
+	lastfmArtistPlaysMaxAge = 24 * time.Hour
+	// lastfmArtistPlaysLimit is the number of top artists cached for every account
+	lastfmArtistPlaysLimit = 1000
+	// lastfmArtistPlaysRefreshKey is claimed by the process which refreshes the cached top artists
+	lastfmArtistPlaysRefreshKey = "robyul2-discord:lastfm:artist-plays:refresh"
+	// lastfmArtistPlaysRefreshSize is the number of accounts refreshed every ten minutes
+	lastfmArtistPlaysRefreshSize = 50
+	// lastfmTrackPlaysKey caches the plays of a track by an account, %s is the Last.fm username and %s the lowercase artist - track
+	lastfmTrackPlaysKey = "robyul2-discord:lastfm:track-plays:%s:%s"
+	// lastfmWhoKnowsSize is the number of listeners shown by whoknows
+	lastfmWhoKnowsSize = 15
+	// lastfmCrownsSize is the number of crowns shown by crowns
+	lastfmCrownsSize = 20
+	// lastfmGuildMembersKey caches the IDs of all members of a guild, %s is the guild ID
+	lastfmGuildMembersKey = "robyul2-discord:lastfm:guild-members:%s"
+	// lastfmGuildMembersMaxAge is how long the member IDs of a guild are cached
+	lastfmGuildMembersMaxAge = time.Hour
+	// lastfmGuildAccountsBatchSize is the number of member IDs looked up in one query
+	lastfmGuildAccountsBatchSize = 1000
+	// lastfmFetchConcurrency is the number of Last.fm requests for whoknows running at the same time
+	lastfmFetchConcurrency = 5
+)
+
+// lastfmFetchSlots limits the Last.fm requests for whoknows running at the same time
+var lastfmFetchSlots = make(chan struct{}, lastfmFetchConcurrency)
+
+// lastfmListener are the plays of a guild member
+type lastfmListener struct {
+	UserID         string
+	LastFmUsername string
+	Plays          int
+}
+
+// [p]lf whoknows [<artist>]
+func (m *LastFm) actionWhoKnows(args []string, msg *discordgo.Message, session *discordgo.Session, lastfmUsername string) {
+	channel, err := helpers.GetChannel(msg.ChannelID)
+	helpers.Relax(err)
+
+	artistName := strings.Join(args, " ")
+	if artistName == "" {
+		if lastfmUsername == "" {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+			return
+		}
+		artistName, _, err = getLastFmLastTrack(lastfmUsername)
+		if err != nil || artistName == "" {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
+			return
+		}
+	}
+	session.ChannelTyping(msg.ChannelID)
+
+	artistInfo, err := helpers.GetLastFmClient().Artist.GetInfo(lastfm.P{"artist": artistName, "autocorrect": 1})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		if _, ok := err.(*lastfm.LastfmError); ok {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.artist-not-found", artistName))
+			return
+		}
+		helpers.Relax(err)
+	}
+
+	listeners, err := getLastFmArtistListeners(getLastFmGuildAccounts(channel.GuildID), strings.ToLower(artistInfo.Name))
+	helpers.Relax(err)
+
+	if len(listeners) <= 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.whoknows-nobody", artistInfo.Name))
+		return
+	}
+
+	crown, previousCrown, err := updateLastFmCrown(channel.GuildID, artistInfo.Name, listeners)
+	helpers.Relax(err)
+
+	guild, err := helpers.GetGuild(channel.GuildID)
+	helpers.Relax(err)
+
+	_, err = helpers.SendEmbed(msg.ChannelID, lastfmWhoKnowsEmbed(msg, guild,
+		artistInfo.Name, artistInfo.Url, listeners, crown.UserID))
+	helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
+
+	if crown.ID != "" && crown.UserID != previousCrown.UserID {
+		if previousCrown.UserID == "" {
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.crown-claimed",
+				crown.UserID, crown.ArtistName, humanize.Comma(int64(crown.Plays))))
+		} else {
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.crown-stolen",
+				crown.UserID, crown.ArtistName, previousCrown.UserID,
+				humanize.Comma(int64(crown.Plays)), humanize.Comma(int64(lastfmListenerPlays(listeners, previousCrown.UserID)))))
+		}
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+	}
+}
+
+// [p]lf whoknows-track [<artist> - <track>]
+func (m *LastFm) actionWhoKnowsTrack(args []string, msg *discordgo.Message, session *discordgo.Session, lastfmUsername string) {
+	channel, err := helpers.GetChannel(msg.ChannelID)
+	helpers.Relax(err)
+
+	var artistName, trackName string
+	if len(args) > 0 {
+		parts := strings.SplitN(strings.Join(args, " "), " - ", 2)
+		if len(parts) < 2 {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.whoknows-track-format"))
+			return
+		}
+		artistName, trackName = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	} else {
+		if lastfmUsername == "" {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.too-few", helpers.GetPrefixForServer(channel.GuildID)))
+			return
+		}
+		artistName, trackName, err = getLastFmLastTrack(lastfmUsername)
+		if err != nil || trackName == "" {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.no-recent-tracks"))
+			return
+		}
+	}
+	session.ChannelTyping(msg.ChannelID)
+
+	trackInfo, err := helpers.GetLastFmClient().Track.GetInfo(lastfm.P{"artist": artistName, "track": trackName, "autocorrect": 1})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		if _, ok := err.(*lastfm.LastfmError); ok {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.track-not-found", artistName, trackName))
+			return
+		}
+		helpers.Relax(err)
+	}
+
+	var listeners []lastfmListener
+	var listenersLock sync.Mutex
+	fetchLastFmAccounts(getLastFmGuildAccounts(channel.GuildID), func(account models.LastFmEntry) {
+		plays, err := getLastFmTrackPlays(account.LastFmUsername, trackInfo.Artist.Name, trackInfo.Name)
+		if err != nil {
+			cache.GetLogger().WithField("module", "lastfm").WithField("userID", account.UserID).Warnf(
+				"getting track plays failed: %s", err.Error())
+			return
+		}
+		if plays > 0 {
+			listenersLock.Lock()
+			listeners = append(listeners, lastfmListener{UserID: account.UserID, LastFmUsername: account.LastFmUsername, Plays: plays})
+			listenersLock.Unlock()
+		}
+	})
+	sortLastFmListeners(listeners)
+
+	title := fmt.Sprintf("%s by %s", trackInfo.Name, trackInfo.Artist.Name)
+	if len(listeners) <= 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.whoknows-nobody", title))
+		return
+	}
+
+	guild, err := helpers.GetGuild(channel.GuildID)
+	helpers.Relax(err)
+
+	_, err = helpers.SendEmbed(msg.ChannelID, lastfmWhoKnowsEmbed(msg, guild, title, trackInfo.Url, listeners, ""))
+	helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
+}
+
+// [p]lf crowns [<@user>] or [p]lf crowns minimum <plays>
+func (m *LastFm) actionCrowns(args []string, msg *discordgo.Message, session *discordgo.Session) {
+	channel, err := helpers.GetChannel(msg.ChannelID)
+	helpers.Relax(err)
+
+	if len(args) >= 1 && (args[0] == "minimum" || args[0] == "min") {
+		m.actionCrownsMinimum(args[1:], msg, channel.GuildID)
+		return
+	}
+
+	targetUser := msg.Author
+	if len(args) >= 1 {
+		targetUser, err = helpers.GetUserFromMention(args[0])
+		if err != nil {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+			return
+		}
+	}
+
+	query := helpers.MdbCollection(models.LastFmCrownsTable).Find(bson.M{"guildid": channel.GuildID, "userid": targetUser.ID})
+	total, err := query.Count()
+	helpers.Relax(err)
+
+	if total <= 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.crowns-none", targetUser.Username))
+		return
+	}
+
+	var crowns []models.LastFmCrownEntry
+	err = helpers.MDbIter(query.Sort("-plays").Limit(lastfmCrownsSize)).All(&crowns)
+	helpers.Relax(err)
+
+	crownsEmbed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    helpers.GetTextForMessage(msg, "plugins.lastfm.crowns-embed-title", targetUser.Username),
+			IconURL: targetUser.AvatarURL("64"),
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer") + " | " +
+				helpers.GetTextForMessage(msg, "plugins.lastfm.crowns-embed-footer", total, getLastFmCrownsMinimumPlays(channel.GuildID)),
+			IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
+		},
+		Color: helpers.GetDiscordColorFromHex(lastfmHexColor),
+	}
+	for i, crown := range crowns {
+		crownsEmbed.Description += fmt.Sprintf("%d. **%s** - %s plays\n", i+1, crown.ArtistName, humanize.Comma(int64(crown.Plays)))
+	}
+
+	_, err = helpers.SendEmbed(msg.ChannelID, crownsEmbed)
+	helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
+}
+
+// [p]lf crowns minimum <plays>
+func (m *LastFm) actionCrownsMinimum(args []string, msg *discordgo.Message, guildID string) {
+	if !helpers.IsMod(msg) {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "mod.no_permission"))
+		return
+	}
+
+	if len(args) < 1 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+		return
+	}
+
+	newMinimum, err := strconv.Atoi(args[0])
+	if err != nil || newMinimum < 1 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+		return
+	}
+
+	guildSettings := helpers.GuildSettingsGetCached(guildID)
+	oldMinimum := getLastFmCrownsMinimumPlays(guildID)
+	guildSettings.LastFmCrownsMinimumPlays = newMinimum
+	err = helpers.GuildSettingsSet(guildID, guildSettings)
+	helpers.Relax(err)
+
+	_, err = helpers.EventlogLog(time.Now(), guildID, guildID,
+		models.EventlogTargetTypeGuild, msg.Author.ID,
+		models.EventlogTypeRobyulLastFmConfigUpdate, "",
+		[]models.ElasticEventlogChange{
+			{
+				Key:      "lastfm_crowns_minimum_plays",
+				OldValue: strconv.Itoa(oldMinimum),
+				NewValue: strconv.Itoa(newMinimum),
+			},
+		},
+		nil, false)
+	helpers.RelaxLog(err)
+
+	_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.lastfm.crowns-minimum-success", newMinimum))
+	helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+}
+
+// lastfmWhoKnowsEmbed lists the top listeners, crownUserID is marked with a crown
+func lastfmWhoKnowsEmbed(msg *discordgo.Message, guild *discordgo.Guild, title, url string, listeners []lastfmListener, crownUserID string) *discordgo.MessageEmbed {
+	whoKnowsEmbed := &discordgo.MessageEmbed{
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: helpers.GetTextForMessage(msg, "plugins.lastfm.whoknows-embed-title", title, guild.Name),
+			URL:  url,
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer") + " | " +
+				helpers.GetTextForMessage(msg, "plugins.lastfm.whoknows-embed-footer", len(listeners)),
+			IconURL: helpers.GetTextForMessage(msg, "plugins.lastfm.embed-footer-imageurl"),
+		},
+		Color: helpers.GetDiscordColorFromHex(lastfmHexColor),
+	}
+	if guild.Icon != "" {
+		whoKnowsEmbed.Author.IconURL = guild.IconURL()
+	}
+
+	for i, listener := range listeners {
+		if i >= lastfmWhoKnowsSize {
+			break
+		}
+
+		name := listener.LastFmUsername
+		if user, err := helpers.GetUserWithoutAPI(listener.UserID); err == nil && user != nil {
+			name = user.Username
+		}
+		var crownText string
+		if listener.UserID == crownUserID {
+			crownText = "👑 "
+		}
+		whoKnowsEmbed.Description += fmt.Sprintf("%d. %s[**%s**](%s) - %s plays\n",
+			i+1, crownText, name, helpers.EscapeLinkForMarkdown(fmt.Sprintf(lastfmFriendlyUser, listener.LastFmUsername)),
+			humanize.Comma(int64(listener.Plays)))
+	}
+
+	return whoKnowsEmbed
+}
+
+// getLastFmLastTrack returns the track the account is listening to, or listened to last
+func getLastFmLastTrack(lastfmUsername string) (artist, track string, err error) {
+	recentTracks, err := helpers.GetLastFmClient().User.GetRecentTracks(lastfm.P{"limit": 1, "user": lastfmUsername})
+	metrics.LastFmRequests.Add(1)
+	if err != nil || len(recentTracks.Tracks) <= 0 {
+		return "", "", err
+	}
+
+	return recentTracks.Tracks[0].Artist.Name, recentTracks.Tracks[0].Name, nil
+}
+
+// getLastFmGuildAccounts returns the Last.fm accounts of the members of the guild
+func getLastFmGuildAccounts(guildID string) (accounts []models.LastFmEntry) {
+	memberIDs, err := getLastFmGuildMemberIDs(guildID)
+	helpers.Relax(err)
+
+	for len(memberIDs) > 0 {
+		batch := memberIDs
+		if len(batch) > lastfmGuildAccountsBatchSize {
+			batch = batch[:lastfmGuildAccountsBatchSize]
+		}
+		memberIDs = memberIDs[len(batch):]
+
+		var entryBucket []models.LastFmEntry
+		err = helpers.MDbIter(helpers.MdbCollection(models.LastFmTable).Find(
+			bson.M{"userid": bson.M{"$in": batch}},
+		)).All(&entryBucket)
+		helpers.Relax(err)
+
+		for _, entry := range entryBucket {
+			if entry.LastFmUsername == "" {
+				continue
+			}
+			accounts = append(accounts, entry)
+		}
+	}
+	return accounts
+}
+
+// getLastFmGuildMemberIDs returns the IDs of all members of the guild
+// The member list is requested from Discord and cached, the state only has the members which are cached by the member caching
+// Members from the state are added, so members who joined after the list has been cached are included
+func getLastFmGuildMemberIDs(guildID string) (memberIDs []string, err error) {
+	key := fmt.Sprintf(lastfmGuildMembersKey, guildID)
+
+	memberIDs, err = cache.GetRedisClient().SMembers(key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(memberIDs) <= 0 {
+		var after string
+		for {
+			members, err := cache.GetSession().SessionForGuildS(guildID).GuildMembers(guildID, after, 1000)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				memberIDs = append(memberIDs, member.User.ID)
+			}
+			if len(members) < 1000 {
+				break
+			}
+			after = members[len(members)-1].User.ID
+		}
+
+		if len(memberIDs) > 0 {
+			fields := make([]interface{}, len(memberIDs))
+			for i, memberID := range memberIDs {
+				fields[i] = memberID
+			}
+			pipeline := cache.GetRedisClient().TxPipeline()
+			pipeline.SAdd(key, fields...)
+			pipeline.Expire(key, lastfmGuildMembersMaxAge)
+			_, err = pipeline.Exec()
+			helpers.RelaxLog(err)
+		}
+	}
+
+	guild, err := helpers.GetGuildWithoutApi(guildID)
+	if err != nil {
+		return memberIDs, nil
+	}
+	knownMemberIDs := make(map[string]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		knownMemberIDs[memberID] = true
+	}
+	for _, member := range guild.Members {
+		if member.User != nil && !knownMemberIDs[member.User.ID] {
+			memberIDs = append(memberIDs, member.User.ID)
+		}
+	}
+	return memberIDs, nil
+}
+
+// fetchLastFmAccounts calls fetch for every account, at most lastfmFetchConcurrency fetches run at the same time across all guilds
+func fetchLastFmAccounts(accounts []models.LastFmEntry, fetch func(account models.LastFmEntry)) {
+	var wg sync.WaitGroup
+	for _, account := range accounts {
+		wg.Add(1)
+		lastfmFetchSlots <- struct{}{}
+		go func(account models.LastFmEntry) {
+			defer helpers.Recover()
+			defer wg.Done()
+			defer func() { <-lastfmFetchSlots }()
+
+			fetch(account)
+		}(account)
+	}
+	wg.Wait()
+}
+
+// getLastFmArtistListeners returns the accounts which played the artist, sorted by plays
+// The cached top artists of the accounts are used, accounts without cached top artists are requested
+func getLastFmArtistListeners(accounts []models.LastFmEntry, artistKey string) (listeners []lastfmListener, err error) {
+	if len(accounts) <= 0 {
+		return nil, nil
+	}
+
+	userIDs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		userIDs = append(userIDs, account.UserID)
+	}
+
+	var entries []models.LastFmArtistPlaysEntry
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LastFmArtistPlaysTable).Find(
+		bson.M{"userid": bson.M{"$in": userIDs}},
+	).Select(bson.M{
+		"userid":         1,
+		"lastfmusername": 1,
+		"artists":        bson.M{"$elemMatch": bson.M{"key": artistKey}},
+	})).All(&entries)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedEntries := make(map[string]models.LastFmArtistPlaysEntry, len(entries))
+	for _, entry := range entries {
+		cachedEntries[entry.UserID] = entry
+	}
+
+	// the cache is outdated if the user set a different account
+	outdatedAccounts := make([]models.LastFmEntry, 0)
+	for _, account := range accounts {
+		entry, ok := cachedEntries[account.UserID]
+		if !ok || entry.LastFmUsername != account.LastFmUsername {
+			outdatedAccounts = append(outdatedAccounts, account)
+		}
+	}
+	var refreshedLock sync.Mutex
+	fetchLastFmAccounts(outdatedAccounts, func(account models.LastFmEntry) {
+		entry, err := refreshLastFmArtistPlays(account)
+		refreshedLock.Lock()
+		defer refreshedLock.Unlock()
+		if err != nil {
+			cache.GetLogger().WithField("module", "lastfm").WithField("userID", account.UserID).Warnf(
+				"refreshing artist plays failed: %s", err.Error())
+			delete(cachedEntries, account.UserID)
+			return
+		}
+		cachedEntries[account.UserID] = entry
+	})
+
+	for _, account := range accounts {
+		entry, ok := cachedEntries[account.UserID]
+		if !ok {
+			continue
+		}
+
+		for _, artist := range entry.Artists {
+			if artist.Key == artistKey && artist.Plays > 0 {
+				listeners = append(listeners, lastfmListener{
+					UserID:         account.UserID,
+					LastFmUsername: account.LastFmUsername,
+					Plays:          artist.Plays,
+				})
+			}
+		}
+	}
+
+	sortLastFmListeners(listeners)
+	return listeners, nil
+}
+
+// refreshLastFmArtistPlays requests and caches the top artists of the account
+func refreshLastFmArtistPlays(account models.LastFmEntry) (entry models.LastFmArtistPlaysEntry, err error) {
+	topArtists, err := helpers.GetLastFmClient().User.GetTopArtists(lastfm.P{
+		"limit":  lastfmArtistPlaysLimit,
+		"user":   account.LastFmUsername,
+		"period": "overall",
+	})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		return entry, err
+	}
+
+	entry = models.LastFmArtistPlaysEntry{
+		UserID:         account.UserID,
+		LastFmUsername: account.LastFmUsername,
+		Artists:        lastfmArtistPlays(topArtists),
+		UpdatedAt:      time.Now(),
+	}
+
+	err = helpers.MDbUpsertWithoutLogging(models.LastFmArtistPlaysTable, bson.M{"userid": account.UserID}, entry)
+	return entry, err
+}
+
+// lastfmArtistPlays converts the top artists of an account
+func lastfmArtistPlays(topArtists lastfm.UserGetTopArtists) (artists []models.LastFmArtistPlays) {
+	for _, artist := range topArtists.Artists {
+		plays, err := strconv.Atoi(artist.PlayCount)
+		if err != nil {
+			continue
+		}
+		artists = append(artists, models.LastFmArtistPlays{
+			Key:   strings.ToLower(artist.Name),
+			Name:  artist.Name,
+			Plays: plays,
+		})
+	}
+	return artists
+}
+
+// cacheArtistPlaysLoop refreshes the oldest cached top artists every ten minutes
+func (m *LastFm) cacheArtistPlaysLoop() {
+	defer helpers.Recover()
+	defer func() {
+		go func() {
+			cache.GetLogger().WithField("module", "lastfm").Error("The cacheArtistPlaysLoop died. Please investigate! Will be restarted in 60 seconds")
+			time.Sleep(60 * time.Second)
+			m.cacheArtistPlaysLoop()
+		}()
+	}()
+
+	for {
+		time.Sleep(10 * time.Minute)
+
+		claimed, err := cache.GetRedisClient().SetNX(lastfmArtistPlaysRefreshKey, helpers.ProcessName(), 9*time.Minute).Result()
+		if err != nil || !claimed {
+			continue
+		}
+
+		var entries []models.LastFmArtistPlaysEntry
+		err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LastFmArtistPlaysTable).Find(
+			bson.M{"updatedat": bson.M{"$lt": time.Now().Add(-lastfmArtistPlaysMaxAge)}},
+		).Select(bson.M{"userid": 1}).Sort("updatedat").Limit(lastfmArtistPlaysRefreshSize)).All(&entries)
+		helpers.Relax(err)
+
+		for _, entry := range entries {
+			lastfmUsername := helpers.GetLastFmUsername(entry.UserID)
+			if lastfmUsername == "" {
+				// the user removed the account
+				err = helpers.MdbDeleteQueryWithoutLogging(models.LastFmArtistPlaysTable, bson.M{"userid": entry.UserID})
+				helpers.RelaxLog(err)
+				continue
+			}
+
+			_, err = refreshLastFmArtistPlays(models.LastFmEntry{UserID: entry.UserID, LastFmUsername: lastfmUsername})
+			if err != nil {
+				cache.GetLogger().WithField("module", "lastfm").WithField("userID", entry.UserID).Warnf(
+					"refreshing artist plays failed: %s", err.Error())
+			}
+		}
+	}
+}
+
+// getLastFmTrackPlays returns the plays of the track by the account, the plays are cached for an hour
+func getLastFmTrackPlays(lastfmUsername, artist, track string) (plays int, err error) {
+	key := fmt.Sprintf(lastfmTrackPlaysKey, strings.ToLower(lastfmUsername), strings.ToLower(artist+" - "+track))
+
+	plays, err = cache.GetRedisClient().Get(key).Int()
+	if err == nil {
+		return plays, nil
+	}
+
+	trackInfo, err := helpers.GetLastFmClient().Track.GetInfo(lastfm.P{"artist": artist, "track": track, "username": lastfmUsername})
+	metrics.LastFmRequests.Add(1)
+	if err != nil {
+		return 0, err
+	}
+	plays, _ = strconv.Atoi(trackInfo.UserPlayCount)
+
+	err = cache.GetRedisClient().Set(key, plays, time.Hour).Err()
+	return plays, err
+}
+
+// sortLastFmListeners sorts by plays, listeners with the same plays are sorted by their username
+func sortLastFmListeners(listeners []lastfmListener) {
+	sort.SliceStable(listeners, func(i, j int) bool {
+		if listeners[i].Plays != listeners[j].Plays {
+			return listeners[i].Plays > listeners[j].Plays
+		}
+		return strings.ToLower(listeners[i].LastFmUsername) < strings.ToLower(listeners[j].LastFmUsername)
+	})
+}
+
+func lastfmListenerPlays(listeners []lastfmListener, userID string) int {
+	for _, listener := range listeners {
+		if listener.UserID == userID {
+			return listener.Plays
+		}
+	}
+	return 0
+}
+
+// lastfmCrownHolder returns the listener who should hold the crown, the current holder keeps the crown on a tie
+// ok is false if nobody has the minimum plays
+func lastfmCrownHolder(currentUserID string, listeners []lastfmListener, minimumPlays int) (holder lastfmListener, ok bool) {
+	if len(listeners) <= 0 || listeners[0].Plays < minimumPlays {
+		return holder, false
+	}
+
+	holder = listeners[0]
+	for _, listener := range listeners {
+		if listener.Plays < holder.Plays {
+			break
+		}
+		if listener.UserID == currentUserID {
+			return listener, true
+		}
+	}
+	return holder, true
+}
+
+// getLastFmCrownsMinimumPlays returns the number of plays required to hold a crown on the guild
+func getLastFmCrownsMinimumPlays(guildID string) int {
+	minimum := helpers.GuildSettingsGetCached(guildID).LastFmCrownsMinimumPlays
+	if minimum > 0 {
+		return minimum
+	}
+	return lastfmCrownsDefaultMinimumPlays
+}
+
+// updateLastFmCrown gives the crown of the artist to the top listener, previous is the crown before the update
+// crown has no ID if nobody holds the crown
+func updateLastFmCrown(guildID, artistName string, listeners []lastfmListener) (crown, previous models.LastFmCrownEntry, err error) {
+	artistKey := strings.ToLower(artistName)
+
+	err = helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.LastFmCrownsTable).Find(bson.M{"guildid": guildID, "artistkey": artistKey}),
+		&previous,
+	)
+	if err != nil && !helpers.IsMdbNotFound(err) {
+		return crown, previous, err
+	}
+	crown = previous
+
+	holder, ok := lastfmCrownHolder(previous.UserID, listeners, getLastFmCrownsMinimumPlays(guildID))
+	if !ok {
+		return crown, previous, nil
+	}
+
+	if crown.UserID != holder.UserID {
+		crown.ClaimedAt = time.Now()
+	}
+	crown.GuildID = guildID
+	crown.ArtistKey = artistKey
+	crown.ArtistName = artistName
+	crown.UserID = holder.UserID
+	crown.Plays = holder.Plays
+	crown.UpdatedAt = time.Now()
+
+	if crown.ID == "" {
+		crown.ID, err = helpers.MDbInsertWithoutLogging(models.LastFmCrownsTable, crown)
+		return crown, previous, err
+	}
+	err = helpers.MDbUpdateWithoutLogging(models.LastFmCrownsTable, crown.ID, crown)
+	return crown, previous, err
+}
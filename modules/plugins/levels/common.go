@@ -11,8 +11,10 @@ import (
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
 	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/robyulstate"
 	"github.com/bwmarrin/discordgo"
 	raven "github.com/getsentry/raven-go"
+	"github.com/globalsign/mgo/bson"
 	redisCache "github.com/go-redis/cache"
 )
 
@@ -222,21 +224,20 @@ func processExpStackLoop() {
 			expBefore := levelsServerUser.Exp
 			levelBefore := GetLevelFromExp(levelsServerUser.Exp)
 
-			levelsServerUser.Exp += getRandomExpForMessage()
+			expGained := getRandomExpForMessage()
+			levelsServerUser.Exp += expGained
 
 			levelAfter := GetLevelFromExp(levelsServerUser.Exp)
 
 			err = helpers.MDbUpdateWithoutLogging(models.LevelsServerusersTable, levelsServerUser.ID, levelsServerUser)
 			helpers.Relax(err)
 
+			addPeriodExp(expItem.GuildID, expItem.UserID, expGained)
+
 			if expBefore <= 0 || levelBefore != levelAfter {
 				// apply roles
-				err := applyLevelsRoles(expItem.GuildID, expItem.UserID, levelAfter)
-				if errD, ok := err.(*discordgo.RESTError); !ok || (errD.Message.Message != "404: Not Found" &&
-					errD.Message.Code != discordgo.ErrCodeUnknownMember &&
-					errD.Message.Code != discordgo.ErrCodeMissingAccess) {
-					helpers.RelaxLog(err)
-				}
+				err := applyLevelsRolesForExpChange(expItem.GuildID, expItem.UserID, expBefore, levelsServerUser.Exp)
+				helpers.RelaxLog(err)
 				guildSettings := helpers.GuildSettingsGetCached(expItem.GuildID)
 				// send level notifications
 				if levelAfter > levelBefore && guildSettings.LevelsNotificationCode != "" {
@@ -332,24 +333,57 @@ func (p PairList) Len() int           { return len(p) }
 func (p PairList) Less(i, j int) bool { return p[i].Value < p[j].Value }
 func (p PairList) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
-func (b *Levels) BucketInit() {
-	b.Lock()
-	b.buckets = make(map[string]int8)
-	b.Unlock()
+func applyLevelsRoles(guildID string, userID string, level int) (err error) {
+	toApply, toRemove, err := getLevelsRolesChanges(guildID, userID, level)
+	if err != nil {
+		// cache.GetLogger().WithField("module", "levels").Warnf("failed to get guild member to apply level roles: %s", err.Error())
+		return nil
+	}
 
-	go b.BucketRefiller()
+	return applyLevelsRolesChanges(guildID, userID, toApply, toRemove)
 }
 
-func applyLevelsRoles(guildID string, userID string, level int) (err error) {
-	apply, remove := getLevelsRoles(guildID, level)
+// applyLevelsRolesChanges adds and removes the roles returned by getLevelsRolesChanges
+func applyLevelsRolesChanges(guildID string, userID string, toApply []*discordgo.Role, toRemove []*discordgo.Role) (err error) {
+	session := cache.GetSession()
+
+	for _, toApplyRole := range toApply {
+		errRole := session.SessionForGuildS(guildID).GuildMemberRoleAdd(guildID, userID, toApplyRole.ID)
+		if errRole != nil {
+			cache.GetLogger().WithField("module", "levels").Warnf("failed to add role applying level roles: %s", errRole.Error())
+			err = errRole
+		}
+	}
+
+	for _, toRemoveRole := range toRemove {
+		errRole := session.SessionForGuildS(guildID).GuildMemberRoleRemove(guildID, userID, toRemoveRole.ID)
+		if errRole != nil {
+			cache.GetLogger().WithField("module", "levels").Warnf("failed to remove role applying level roles: %s", errRole.Error())
+			err = errRole
+		}
+	}
+
+	return
+}
+
+// getLevelsRolesChanges returns the roles which have to be added to or removed from the member to match their level
+func getLevelsRolesChanges(guildID string, userID string, level int) (toApply []*discordgo.Role, toRemove []*discordgo.Role, err error) {
 	member, err := helpers.GetGuildMemberWithoutApi(guildID, userID)
 	if err != nil {
-		// cache.GetLogger().WithField("module", "levels").Warnf("failed to get guild member to apply level roles: %s", err.Error())
-		return nil
+		return nil, nil, err
 	}
 
-	toRemove := make([]*discordgo.Role, 0)
-	toApply := make([]*discordgo.Role, 0)
+	toApply, toRemove = getLevelsRolesChangesForMember(guildID, member, level)
+	return toApply, toRemove, nil
+}
+
+// getLevelsRolesChangesForMember returns the roles which have to be added to or removed from the member to match their level
+func getLevelsRolesChangesForMember(guildID string, member *discordgo.Member, level int) (toApply []*discordgo.Role, toRemove []*discordgo.Role) {
+	apply, remove := getLevelsRoles(guildID, level)
+	userID := member.User.ID
+
+	toRemove = make([]*discordgo.Role, 0)
+	toApply = make([]*discordgo.Role, 0)
 
 	for _, removeRole := range remove {
 		for _, memberRole := range member.Roles {
@@ -435,21 +469,125 @@ func applyLevelsRoles(guildID string, userID string, level int) (err error) {
 		}
 	}
 
-	for _, toApplyRole := range toApply {
-		errRole := session.SessionForGuildS(guildID).GuildMemberRoleAdd(guildID, userID, toApplyRole.ID)
-		if errRole != nil {
-			cache.GetLogger().WithField("module", "levels").Warnf("failed to add role applying level roles: %s", errRole.Error())
-			err = errRole
+	return toApply, toRemove
+}
+
+// levelsRolesReconcileLoop applies the levels roles of all members on all guilds with levels roles regularly,
+// this fixes roles edited manually or while the bot was down
+func levelsRolesReconcileLoop() {
+	log := cache.GetLogger()
+
+	defer helpers.Recover()
+	defer func() {
+		go func() {
+			log.WithField("module", "levels").Error("The levelsRolesReconcileLoop died. Please investigate! Will be restarted in 60 seconds")
+			time.Sleep(60 * time.Second)
+			levelsRolesReconcileLoop()
+		}()
+	}()
+
+	// give the state some time to fill up after starting
+	time.Sleep(10 * time.Minute)
+
+	for {
+		var entryBucket []models.LevelsRoleEntry
+		err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LevelsRolesTable).Find(nil)).All(&entryBucket)
+		if err != nil {
+			helpers.RelaxLog(err)
+			time.Sleep(60 * time.Second)
+			continue
+		}
+
+		guildIDs := make(map[string]bool, 0)
+		for _, entry := range entryBucket {
+			guildIDs[entry.GuildID] = true
+		}
+
+		for guildID := range guildIDs {
+			// every guild is reconciled by the process running it
+			if !cache.GetRobyulstate().IsLocal(guildID) {
+				continue
+			}
+
+			changed, failed := reconcileLevelsRoles(guildID)
+			if changed <= 0 && failed <= 0 {
+				continue
+			}
+
+			log.WithField("module", "levels").Infof("reconciled levels roles on #%s: %d members changed, %d errors",
+				guildID, changed, failed)
+
+			_, err = helpers.EventlogLog(time.Now(), guildID, guildID,
+				models.EventlogTargetTypeGuild, cache.GetSession().SessionForGuildS(guildID).State.User.ID,
+				models.EventlogTypeRobyulLevelsRoleReconcile, "",
+				nil,
+				[]models.ElasticEventlogOption{
+					{
+						Key:   "members_changed",
+						Value: strconv.Itoa(changed),
+					},
+					{
+						Key:   "roles_errors",
+						Value: strconv.Itoa(failed),
+					},
+				}, false)
+			helpers.RelaxLog(err)
 		}
+
+		time.Sleep(6 * time.Hour)
 	}
+}
 
-	for _, toRemoveRole := range toRemove {
-		errRole := session.SessionForGuildS(guildID).GuildMemberRoleRemove(guildID, userID, toRemoveRole.ID)
-		if errRole != nil {
-			cache.GetLogger().WithField("module", "levels").Warnf("failed to remove role applying level roles: %s", errRole.Error())
-			err = errRole
+// reconcileLevelsRoles applies the levels roles to all members of a guild which are not up to date
+func reconcileLevelsRoles(guildID string) (changed int, failed int) {
+	members, err := getLevelsReconcileMembers(guildID)
+	if err != nil {
+		helpers.RelaxLog(err)
+		return 0, 0
+	}
+
+	var levelsUsers []models.LevelsServerusersEntry
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LevelsServerusersTable).Find(bson.M{"guildid": guildID})).All(&levelsUsers)
+	if err != nil {
+		helpers.RelaxLog(err)
+		return 0, 0
+	}
+
+	levelsByUserID := make(map[string]int, len(levelsUsers))
+	for _, levelsUser := range levelsUsers {
+		levelsByUserID[levelsUser.UserID] = GetLevelFromExp(levelsUser.Exp)
+	}
+
+	for _, member := range members {
+		if member.User == nil || member.User.Bot {
+			continue
+		}
+
+		toApply, toRemove := getLevelsRolesChangesForMember(guildID, member, levelsByUserID[member.User.ID])
+		if len(toApply) <= 0 && len(toRemove) <= 0 {
+			continue
+		}
+
+		err = applyLevelsRolesChanges(guildID, member.User.ID, toApply, toRemove)
+		if err != nil {
+			failed++
+			continue
 		}
+		changed++
 	}
 
-	return
+	return changed, failed
+}
+
+// getLevelsReconcileMembers returns all members of the guild, from the state if it caches all members, otherwise from Discord
+func getLevelsReconcileMembers(guildID string) ([]*discordgo.Member, error) {
+	if cache.GetRobyulstate().Members != robyulstate.MemberCachingAll {
+		return helpers.GetAllGuildMembers(guildID)
+	}
+
+	guild, err := helpers.GetGuildWithoutApi(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return guild.Members, nil
 }
@@ -0,0 +1,332 @@
+package levels
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// the maximum amount of users in a single levels import
+	levelsImportMaxEntries = 50000
+	// the amount of users written to the database at once during a levels import
+	levelsImportBatchSize = 1000
+)
+
+var (
+	// levels imports are only downloaded from Discord to not request internal addresses
+	levelsImportAllowedHosts = []string{"cdn.discordapp.com", "media.discordapp.net"}
+)
+
+type levelsImportEntry struct {
+	UserID string
+	Exp    int64
+}
+
+// applyLevelsRolesForExpChange updates the levels roles of a member if their level changed, members that left are ignored
+func applyLevelsRolesForExpChange(guildID string, userID string, expBefore int64, expAfter int64) (err error) {
+	levelAfter := GetLevelFromExp(expAfter)
+	if expBefore > 0 && GetLevelFromExp(expBefore) == levelAfter {
+		return nil
+	}
+
+	err = applyLevelsRoles(guildID, userID, levelAfter)
+	if errD, ok := err.(*discordgo.RESTError); ok && (errD.Message.Message == "404: Not Found" ||
+		errD.Message.Code == discordgo.ErrCodeUnknownMember ||
+		errD.Message.Code == discordgo.ErrCodeMissingAccess) {
+		return nil
+	}
+	return err
+}
+
+// updateLevelsExp sets the EXP of a member on a guild and applies the levels roles for the new level
+func updateLevelsExp(guildID string, userID string, newExp int64) (expBefore int64, err error) {
+	if newExp < 0 {
+		newExp = 0
+	}
+
+	levelsServerUser, err := getLevelsServerUserOrCreateNewWithoutLogging(guildID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	expBefore = levelsServerUser.Exp
+	levelsServerUser.Exp = newExp
+	err = helpers.MDbUpdate(models.LevelsServerusersTable, levelsServerUser.ID, levelsServerUser)
+	if err != nil {
+		return expBefore, err
+	}
+
+	return expBefore, applyLevelsRolesForExpChange(guildID, userID, expBefore, newExp)
+}
+
+// isLevelsImportURLAllowed returns true if the file is an attachment uploaded to Discord
+func isLevelsImportURLAllowed(fileURL string) bool {
+	parsedURL, err := url.Parse(fileURL)
+	if err != nil || parsedURL.Scheme != "https" {
+		return false
+	}
+
+	for _, host := range levelsImportAllowedHosts {
+		if strings.ToLower(parsedURL.Hostname()) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// importLevelsExp sets, or adds, the EXP of the imported users and applies the levels roles of all members whose level changed
+func importLevelsExp(guildID string, entries []levelsImportEntry, addExp bool) (imported int, failed int, totalExp int64) {
+	members, err := getLevelsReconcileMembers(guildID)
+	if err != nil {
+		helpers.RelaxLog(err)
+	}
+	membersByUserID := make(map[string]*discordgo.Member, len(members))
+	for _, member := range members {
+		if member.User != nil {
+			membersByUserID[member.User.ID] = member
+		}
+	}
+
+	for start := 0; start < len(entries); start += levelsImportBatchSize {
+		end := start + levelsImportBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		batch := entries[start:end]
+
+		userIDs := make([]string, 0, len(batch))
+		for _, entry := range batch {
+			userIDs = append(userIDs, entry.UserID)
+		}
+
+		var levelsUsers []models.LevelsServerusersEntry
+		err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LevelsServerusersTable).Find(
+			bson.M{"guildid": guildID, "userid": bson.M{"$in": userIDs}},
+		)).All(&levelsUsers)
+		if err != nil {
+			helpers.RelaxLog(err)
+			failed += len(batch)
+			continue
+		}
+		expBefore := make(map[string]int64, len(levelsUsers))
+		for _, levelsUser := range levelsUsers {
+			expBefore[levelsUser.UserID] = levelsUser.Exp
+		}
+
+		bulk := helpers.MdbCollection(models.LevelsServerusersTable).Bulk()
+		bulk.Unordered()
+		expAfter := make(map[string]int64, len(batch))
+		for _, entry := range batch {
+			if addExp {
+				expAfter[entry.UserID] = expBefore[entry.UserID] + entry.Exp
+				bulk.Upsert(bson.M{"guildid": guildID, "userid": entry.UserID}, bson.M{"$inc": bson.M{"exp": entry.Exp}})
+			} else {
+				expAfter[entry.UserID] = entry.Exp
+				bulk.Upsert(bson.M{"guildid": guildID, "userid": entry.UserID}, bson.M{"$set": bson.M{"exp": entry.Exp}})
+			}
+		}
+		_, err = bulk.Run()
+		if err != nil {
+			helpers.RelaxLog(err)
+			failed += len(batch)
+			continue
+		}
+
+		for _, entry := range batch {
+			imported++
+			totalExp += entry.Exp
+
+			member, ok := membersByUserID[entry.UserID]
+			if !ok || member.User.Bot {
+				continue
+			}
+			levelAfter := GetLevelFromExp(expAfter[entry.UserID])
+			if expBefore[entry.UserID] > 0 && GetLevelFromExp(expBefore[entry.UserID]) == levelAfter {
+				continue
+			}
+
+			toApply, toRemove := getLevelsRolesChangesForMember(guildID, member, levelAfter)
+			if len(toApply) <= 0 && len(toRemove) <= 0 {
+				continue
+			}
+			err = applyLevelsRolesChanges(guildID, entry.UserID, toApply, toRemove)
+			if err != nil {
+				cache.GetLogger().WithField("module", "levels").Warnf("failed to apply levels roles after import: %s", err.Error())
+			}
+		}
+	}
+
+	return imported, failed, totalExp
+}
+
+// parseLevelsImport reads user IDs and EXP from a CSV or JSON file
+// CSV files need an user id and an exp column, JSON files can be a list of users or an object with a players or users list
+func parseLevelsImport(data []byte) (entries []levelsImportEntry, err error) {
+	data = bytes.TrimSpace(data)
+	if len(data) <= 0 {
+		return nil, errors.New("the file is empty")
+	}
+
+	// the errors are shown to the user, they must not include the content of the file
+	if data[0] == '[' || data[0] == '{' {
+		entries, err = parseLevelsImportJSON(data)
+	} else {
+		entries, err = parseLevelsImportCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) <= 0 {
+		return nil, errors.New("no users found in the file")
+	}
+	if len(entries) > levelsImportMaxEntries {
+		return nil, errors.New("too many users in the file, the maximum is " + strconv.Itoa(levelsImportMaxEntries))
+	}
+
+	return entries, nil
+}
+
+func parseLevelsImportJSON(data []byte) (entries []levelsImportEntry, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var items []map[string]interface{}
+	if data[0] == '{' {
+		var container map[string]json.RawMessage
+		err = decoder.Decode(&container)
+		if err != nil {
+			return nil, errors.New("invalid JSON file")
+		}
+
+		var list json.RawMessage
+		for _, key := range []string{"players", "users", "members"} {
+			if container[key] != nil {
+				list = container[key]
+				break
+			}
+		}
+		if list == nil {
+			return nil, errors.New("unable to find a list of users in the file")
+		}
+
+		decoder = json.NewDecoder(bytes.NewReader(list))
+		decoder.UseNumber()
+	}
+
+	err = decoder.Decode(&items)
+	if err != nil {
+		return nil, errors.New("invalid JSON file")
+	}
+
+	for i, item := range items {
+		userID := levelsImportJSONValue(item, "id", "user_id", "userid", "userID")
+		expText := levelsImportJSONValue(item, "xp", "exp", "experience")
+		if userID == "" || expText == "" {
+			return nil, errors.New("missing user id or exp for user #" + strconv.Itoa(i+1))
+		}
+
+		entry, err := newLevelsImportEntry(userID, expText)
+		if err != nil {
+			return nil, errors.New(err.Error() + " for user #" + strconv.Itoa(i+1))
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func levelsImportJSONValue(item map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch value := item[key].(type) {
+		case string:
+			return value
+		case json.Number:
+			return value.String()
+		}
+	}
+	return ""
+}
+
+func parseLevelsImportCSV(data []byte) (entries []levelsImportEntry, err error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	userIDColumn := 0
+	expColumn := 1
+	firstRow := true
+	line := 0
+	for {
+		line++
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if errCSV, ok := err.(*csv.ParseError); ok {
+				line = errCSV.Line
+			}
+			return nil, errors.New("invalid CSV file in line " + strconv.Itoa(line))
+		}
+
+		if firstRow {
+			firstRow = false
+			// detect header rows like "user_id,exp"
+			if len(record) >= 2 {
+				if _, err = strconv.ParseInt(record[0], 10, 64); err != nil {
+					for i, column := range record {
+						switch strings.ToLower(strings.TrimSpace(column)) {
+						case "id", "user_id", "userid", "user id":
+							userIDColumn = i
+						case "xp", "exp", "experience":
+							expColumn = i
+						}
+					}
+					continue
+				}
+			}
+		}
+
+		if len(record) <= userIDColumn || len(record) <= expColumn {
+			return nil, errors.New("invalid line " + strconv.Itoa(line))
+		}
+
+		entry, err := newLevelsImportEntry(record[userIDColumn], record[expColumn])
+		if err != nil {
+			return nil, errors.New(err.Error() + " in line " + strconv.Itoa(line))
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func newLevelsImportEntry(userID string, expText string) (entry levelsImportEntry, err error) {
+	userID = strings.TrimSpace(userID)
+	if _, err = strconv.ParseUint(userID, 10, 64); err != nil {
+		return entry, errors.New("invalid user id")
+	}
+
+	exp, err := strconv.ParseFloat(strings.TrimSpace(expText), 64)
+	if err != nil || exp < 0 {
+		return entry, errors.New("invalid exp")
+	}
+
+	return levelsImportEntry{
+		UserID: userID,
+		Exp:    int64(exp),
+	}, nil
+}
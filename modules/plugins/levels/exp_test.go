@@ -0,0 +1,81 @@
+package levels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLevelsImportCSV(t *testing.T) {
+	entries, err := parseLevelsImport([]byte("user_id,exp\n116620585638821891, 1500\n157834823594016768,20.5\n"))
+	if err != nil {
+		t.Fatal("levels.parseLevelsImport() failed to parse a CSV file:", err)
+	}
+
+	if len(entries) != 2 ||
+		entries[0].UserID != "116620585638821891" || entries[0].Exp != 1500 ||
+		entries[1].UserID != "157834823594016768" || entries[1].Exp != 20 {
+		t.Fatal("levels.parseLevelsImport() read invalid entries from a CSV file:", entries)
+	}
+
+	entries, err = parseLevelsImport([]byte("exp,id\n1500,116620585638821891\n"))
+	if err != nil {
+		t.Fatal("levels.parseLevelsImport() failed to parse a CSV file with swapped columns:", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != "116620585638821891" || entries[0].Exp != 1500 {
+		t.Fatal("levels.parseLevelsImport() ignored the header of a CSV file:", entries)
+	}
+}
+
+func TestParseLevelsImportJSON(t *testing.T) {
+	entries, err := parseLevelsImport([]byte(`[{"id": "116620585638821891", "xp": 1500}]`))
+	if err != nil {
+		t.Fatal("levels.parseLevelsImport() failed to parse a JSON list:", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != "116620585638821891" || entries[0].Exp != 1500 {
+		t.Fatal("levels.parseLevelsImport() read invalid entries from a JSON list:", entries)
+	}
+
+	entries, err = parseLevelsImport([]byte(`{"guild": {}, "players": [{"id": 157834823594016768, "exp": "42"}]}`))
+	if err != nil {
+		t.Fatal("levels.parseLevelsImport() failed to parse a JSON object:", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != "157834823594016768" || entries[0].Exp != 42 {
+		t.Fatal("levels.parseLevelsImport() read invalid entries from a JSON object:", entries)
+	}
+}
+
+func TestParseLevelsImportErrors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"user_id,exp\n",
+		"secret-token-1234,100\n",
+		"116620585638821891,secret-token-1234\n",
+		`[{"id": "secret-token-1234", "xp": 100}]`,
+		`{"secret-token-1234": [}`,
+		`{"items": []}`,
+	} {
+		_, err := parseLevelsImport([]byte(data))
+		if err == nil {
+			t.Fatalf("levels.parseLevelsImport() accepted the invalid file %q", data)
+		}
+		if strings.Contains(err.Error(), "secret-token-1234") {
+			t.Fatalf("levels.parseLevelsImport() included the content of the file in the error %q", err.Error())
+		}
+	}
+}
+
+func TestIsLevelsImportURLAllowed(t *testing.T) {
+	for fileURL, allowed := range map[string]bool{
+		"https://cdn.discordapp.com/attachments/1/2/levels.csv":   true,
+		"https://media.discordapp.net/attachments/1/2/levels.csv": true,
+		"http://cdn.discordapp.com/attachments/1/2/levels.csv":    false,
+		"https://cdn.discordapp.com.example.com/levels.csv":       false,
+		"https://127.0.0.1/levels.csv":                            false,
+		"http://169.254.169.254/latest/meta-data/":                false,
+		"https://example.com/levels.csv":                          false,
+	} {
+		if isLevelsImportURLAllowed(fileURL) != allowed {
+			t.Fatalf("levels.isLevelsImportURLAllowed(%q) should be %t", fileURL, allowed)
+		}
+	}
+}
@@ -40,11 +40,7 @@ import (
 	lane "gopkg.in/oleiade/lane.v1"
 )
 
-type Levels struct {
-	sync.RWMutex
-
-	buckets map[string]int8
-}
+type Levels struct{}
 
 type ProcessExpInfo struct {
 	GuildID   string
@@ -53,19 +49,10 @@ type ProcessExpInfo struct {
 }
 
 var (
-	LevelsBucket = &ratelimits.BucketContainer{}
-
-	// How many keys a bucket may contain when created
-	BUCKET_INITIAL_FILL int8 = 1
-
-	// The maximum amount of keys a user may possess
-	BUCKET_UPPER_BOUND int8 = 1
-
-	// How often new keys drip into the buckets
-	DROP_INTERVAL = 60 * time.Second
-
-	// How many keys may drop at a time
-	DROP_SIZE int8 = 1
+	// how often members can gain EXP in a guild
+	expInterval = 60 * time.Second
+	// checked before the shared limiter, so only one message per interval and member reaches Redis
+	expLocalLimiter = ratelimits.NewMemoryLimiter()
 
 	temporaryIgnoredGuilds []string
 
@@ -86,6 +73,21 @@ func (m *Levels) Commands() []string {
 	}
 }
 
+func (m *Levels) Cooldowns() []ratelimits.Cooldown {
+	return []ratelimits.Cooldown{
+		{
+			Command:  "profile",
+			Scope:    ratelimits.CooldownScopeUser,
+			Duration: 10 * time.Second,
+		},
+		{
+			Command:  "gif-profile",
+			Scope:    ratelimits.CooldownScopeUser,
+			Duration: 30 * time.Second,
+		},
+	}
+}
+
 type Cache_Levels_top struct {
 	GuildID string
 	Levels  PairList
@@ -115,12 +117,10 @@ const (
 )
 
 func (m *Levels) Init(session *shardmanager.Manager) {
-	m.BucketInit()
-
 	log := cache.GetLogger()
 
-	cachePath = helpers.GetConfig().Path("cache_folder").Data().(string)
-	assetsPath = helpers.GetConfig().Path("assets_folder").Data().(string)
+	cachePath = helpers.GetConfig().CacheFolder
+	assetsPath = helpers.GetConfig().AssetsFolder
 	htmlTemplate, err := ioutil.ReadFile(assetsPath + "profile.html")
 	helpers.Relax(err)
 	htmlTemplateString = string(htmlTemplate)
@@ -134,6 +134,10 @@ func (m *Levels) Init(session *shardmanager.Manager) {
 	activeBadgePickerUserIDs = make(map[string]string, 0)
 
 	go setServerFeaturesLoop()
+
+	go levelsRolesReconcileLoop()
+	go levelsSeasonsLoop()
+	log.WithField("module", "levels").Info("Started levelsRolesReconcileLoop")
 }
 
 func (l *Levels) Uninit(session *shardmanager.Manager) {
@@ -161,15 +165,15 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				timeUntil := time.Until(userData.LastRepped.Add(time.Hour * 12))
 				if timeUntil.Minutes() < 1 {
 					helpers.SendMessage(msg.ChannelID,
-						helpers.GetTextF("plugins.levels.rep-next-rep-seconds", int(math.Floor(timeUntil.Seconds()))))
+						helpers.GetTextForMessage(msg, "plugins.levels.rep-next-rep-seconds", int(math.Floor(timeUntil.Seconds()))))
 				} else {
 					helpers.SendMessage(msg.ChannelID,
-						helpers.GetTextF("plugins.levels.rep-next-rep",
+						helpers.GetTextForMessage(msg, "plugins.levels.rep-next-rep",
 							int(math.Floor(timeUntil.Hours())),
 							int(math.Floor(timeUntil.Minutes()))-(int(math.Floor(timeUntil.Hours()))*60)))
 				}
 			} else {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.rep-target"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.rep-target"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			}
 			return
@@ -179,10 +183,10 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 			timeUntil := time.Until(userData.LastRepped.Add(time.Hour * 12))
 			if timeUntil.Minutes() < 1 {
 				helpers.SendMessage(msg.ChannelID,
-					helpers.GetTextF("plugins.levels.rep-error-timelimit-seconds", int(math.Floor(timeUntil.Seconds()))))
+					helpers.GetTextForMessage(msg, "plugins.levels.rep-error-timelimit-seconds", int(math.Floor(timeUntil.Seconds()))))
 			} else {
 				helpers.SendMessage(msg.ChannelID,
-					helpers.GetTextF("plugins.levels.rep-error-timelimit",
+					helpers.GetTextForMessage(msg, "plugins.levels.rep-error-timelimit",
 						int(math.Floor(timeUntil.Hours())),
 						int(math.Floor(timeUntil.Minutes()))-(int(math.Floor(timeUntil.Hours()))*60)))
 			}
@@ -191,24 +195,24 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 		targetUser, err := helpers.GetUserFromMention(args[0])
 		if err != nil || targetUser == nil || targetUser.ID == "" {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
 
 		// Don't rep this bot account, other bots, or oneself
 		if targetUser.ID == session.State.User.ID {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.rep-error-session"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.rep-error-session"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
 		if targetUser.ID == msg.Author.ID {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.rep-error-self"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.rep-error-self"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
 		if targetUser.Bot == true {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.rep-error-bot"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.rep-error-bot"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
@@ -224,7 +228,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		helpers.Relax(err)
 
 		_, err = helpers.SendMessage(msg.ChannelID,
-			helpers.GetTextF("plugins.levels.rep-success", targetUser.Username))
+			helpers.GetTextForMessage(msg, "plugins.levels.rep-success", targetUser.Username))
 		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 		return
 	case "profile", "gif-profile": // [p]profile
@@ -233,7 +237,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		if _, ok := activeBadgePickerUserIDs[msg.Author.ID]; ok {
 			if activeBadgePickerUserIDs[msg.Author.ID] != msg.ChannelID {
 				_, err := helpers.SendMessage(
-					msg.ChannelID, helpers.GetTextF("plugins.levels.badge-picker-session-duplicate", helpers.GetPrefixForServer(channel.GuildID)))
+					msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-picker-session-duplicate", helpers.GetPrefixForServer(channel.GuildID)))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			}
 			return
@@ -253,10 +257,10 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				var message string
 				if userUserdata.HideLastFm {
 					userUserdata.HideLastFm = false
-					message = helpers.GetText("plugins.levels.profile-lastfm-shown")
+					message = helpers.GetTextForMessage(msg, "plugins.levels.profile-lastfm-shown")
 				} else {
 					userUserdata.HideLastFm = true
-					message = helpers.GetText("plugins.levels.profile-lastfm-hidden")
+					message = helpers.GetTextForMessage(msg, "plugins.levels.profile-lastfm-hidden")
 				}
 				err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 				helpers.Relax(err)
@@ -276,7 +280,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 				helpers.Relax(err)
 
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-title-set-success"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-title-set-success"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			case "bio":
@@ -293,9 +297,9 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 				helpers.Relax(err)
 
-				message := helpers.GetText("plugins.levels.profile-bio-set-success")
+				message := helpers.GetTextForMessage(msg, "plugins.levels.profile-bio-set-success")
 				if oldBioText != "" && oldBioText != " " && bioText == " " {
-					message = helpers.GetTextF("plugins.levels.profile-bio-reset-success", oldBioText)
+					message = helpers.GetTextForMessage(msg, "plugins.levels.profile-bio-reset-success", oldBioText)
 				}
 
 				_, err = helpers.SendMessage(msg.ChannelID, message)
@@ -307,17 +311,17 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						userUserdata, err := helpers.GetUserUserdata(msg.Author.ID)
 
 						if userUserdata.Background != "" {
-							helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.new-profile-background-help-withbackground", userUserdata.Background))
+							helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.new-profile-background-help-withbackground", userUserdata.Background))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
 						if userUserdata.BackgroundObjectName != "" {
-							helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.new-profile-background-help-withbackground", m.GetProfileBackgroundUrl(userUserdata)))
+							helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.new-profile-background-help-withbackground", m.GetProfileBackgroundUrl(userUserdata)))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.new-profile-background-help"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.new-profile-background-help"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -327,7 +331,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 					if helpers.UseruploadsIsDisabled(msg.Author.ID) {
 						quitChannel <- 0
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.errors.useruploads-disabled"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.errors.useruploads-disabled"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -335,7 +339,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					// <= 2 MB, 400x300px?
 					if msg.Attachments[0].Size > 2e+6 || msg.Attachments[0].Width < 400 || msg.Attachments[0].Height < 300 {
 						quitChannel <- 0
-						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-background-wrong-dimensions"))
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-background-wrong-dimensions"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -349,7 +353,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					// check 400x300px again on Robyul
 					if imageConfig.Width < 400 || imageConfig.Height < 300 {
 						quitChannel <- 0
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-background-wrong-dimensions"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-background-wrong-dimensions"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -371,7 +375,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								helpers.RelaxLog(err)
 							}
 						}()
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-background-not-safe"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-background-not-safe"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -382,7 +386,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					}, "levels", true)
 					if err != nil {
 						helpers.RelaxLog(err)
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-background-upload-failed"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-background-upload-failed"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -414,7 +418,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 					quitChannel <- 0
 					_, err = helpers.SendMessage(msg.ChannelID,
-						helpers.GetTextF("plugins.levels.user-background-success",
+						helpers.GetTextForMessage(msg, "plugins.levels.user-background-success",
 							helpers.GetPrefixForServer(channel.GuildID)))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
@@ -424,7 +428,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				case "force":
 					helpers.RequireRobyulMod(msg, func() {
 						if !((len(args) >= 3 && len(msg.Attachments) > 0) || len(args) >= 4) {
-							helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							return
 						}
 
@@ -444,7 +448,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 						// reject smaller than 400x300px
 						if imageConfig.Width < 400 || imageConfig.Height < 300 {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-background-wrong-dimensions"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-background-wrong-dimensions"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -461,7 +465,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}, "levels", true)
 						if err != nil {
 							helpers.RelaxLog(err)
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-background-upload-failed"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-background-upload-failed"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -480,7 +484,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.Relax(err)
 
 						_, err = helpers.SendMessage(msg.ChannelID,
-							helpers.GetTextF("plugins.levels.user-force-background-success",
+							helpers.GetTextForMessage(msg, "plugins.levels.user-force-background-success",
 								userToChange.Username))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
@@ -489,7 +493,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				case "reset":
 					helpers.RequireRobyulMod(msg, func() {
 						if len(args) < 3 {
-							helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							return
 						}
 
@@ -503,7 +507,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.Relax(err)
 
 						_, err = helpers.SendMessage(msg.ChannelID,
-							helpers.GetTextF("plugins.levels.user-reset-success",
+							helpers.GetTextForMessage(msg, "plugins.levels.user-reset-success",
 								userToReset.Username))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
@@ -526,7 +530,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						_, err = helpers.SendMessage(msg.ChannelID,
-							helpers.GetText("plugins.levels.background-setlog-success"))
+							helpers.GetTextForMessage(msg, "plugins.levels.background-setlog-success"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					})
@@ -534,7 +538,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				case "add":
 					helpers.RequireRobyulMod(msg, func() {
 						if len(args) < 5 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -558,13 +562,13 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						if len(tags) <= 0 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
 
 						if helpers.UseruploadsIsDisabled(msg.Author.ID) {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.errors.useruploads-disabled"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.errors.useruploads-disabled"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -591,7 +595,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							&entryBucket,
 						)
 						if !helpers.IsMdbNotFound(err) {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.new-profile-background-add-error-duplicate"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.new-profile-background-add-error-duplicate"))
 							return
 						}
 
@@ -606,7 +610,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						)
 						helpers.Relax(err)
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.new-profile-background-add-success",
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.new-profile-background-add-success",
 							backgroundName, strings.Join(tags, ", ")))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
@@ -615,7 +619,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				case "delete":
 					helpers.RequireRobyulMod(msg, func() {
 						if len(args) < 3 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -627,19 +631,19 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							&entryBucket,
 						)
 						if helpers.IsMdbNotFound(err) {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-background-delete-error-not-found"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-background-delete-error-not-found"))
 							return
 						}
 						backgroundUrl := m.GetProfileBackgroundUrlByName(backgroundName)
 
 						if helpers.ConfirmEmbed(
-							msg.GuildID, msg.ChannelID, msg.Author, helpers.GetTextF("plugins.levels.profile-background-delete-confirm",
+							msg.GuildID, msg.ChannelID, msg.Author, helpers.GetTextForMessage(msg, "plugins.levels.profile-background-delete-confirm",
 								backgroundName, backgroundUrl),
 							"✅", "🚫") == true {
 							err = helpers.MDbDelete(models.ProfileBackgroundsTable, entryBucket.ID)
 							helpers.Relax(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-background-delete-success"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-background-delete-success"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						}
 						return
@@ -655,7 +659,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						searchResult := m.ProfileBackgroundSearch(args[1])
 
 						if len(searchResult) <= 0 {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-background-set-error-not-found"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-background-set-error-not-found"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						} else {
 							backgroundNamesText := ""
@@ -663,7 +667,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								backgroundNamesText += "`" + entry.Name + "` "
 							}
 							backgroundNamesText = strings.TrimSpace(backgroundNamesText)
-							resultText := helpers.GetText("plugins.levels.profile-background-set-error-not-found") + "\n"
+							resultText := helpers.GetTextForMessage(msg, "plugins.levels.profile-background-set-error-not-found") + "\n"
 							resultText += fmt.Sprintf("Maybe I can interest you in one of these backgrounds: %s", backgroundNamesText)
 
 							_, err = helpers.SendMessage(msg.ChannelID, resultText)
@@ -685,7 +689,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 					helpers.Relax(err)
 
-					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-background-set-success"))
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-background-set-success"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
@@ -696,12 +700,12 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.RequireAdmin(msg, func() {
 							session.ChannelTyping(msg.ChannelID)
 							if len(args) < 7 {
-								helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+								helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 								return
 							}
 
 							if helpers.UseruploadsIsDisabled(msg.Author.ID) {
-								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.errors.useruploads-disabled"))
+								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.errors.useruploads-disabled"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -715,7 +719,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							badgeData, err := helpers.NetGetUAWithError(args[4], helpers.DEFAULT_UA)
 							if err != nil {
 								if strings.Contains(err.Error(), "expected status 200; got 404") {
-									helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+									helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 									return
 								}
 							}
@@ -731,7 +735,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							}, "levels", true)
 							if err != nil {
 								if _, ok := err.(*url.Error); ok {
-									helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+									helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 									return
 								}
 							}
@@ -758,14 +762,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 									}
 
 									if matchedRole == nil || matchedRole.ID == "" {
-										_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+										_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 										helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 										return
 									}
 
 									newBadge.RoleRequirement = matchedRole.ID
 								} else {
-									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 									helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 									return
 								}
@@ -775,7 +779,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 										if helpers.IsBotAdmin(msg.Author.ID) {
 											newBadge.GuildID = "global"
 										} else {
-											_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+											_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 											helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 											return
 										}
@@ -785,7 +789,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 							badgeFound := getBadge(newBadge.Category, newBadge.Name, channel.GuildID)
 							if badgeFound.ID != "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.create-badge-error-duplicate"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.create-badge-error-duplicate"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -793,7 +797,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							serverBadges := getServerOnlyBadges(channel.GuildID)
 							badgeLimit := helpers.GetMaxBadgesForGuild(channel.GuildID)
 							if len(serverBadges) >= badgeLimit {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.create-badge-error-too-many", helpers.GetStaffUsernamesText()))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.create-badge-error-too-many", helpers.GetStaffUsernamesText()))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -851,7 +855,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.create-badge-success"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.create-badge-success"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						})
@@ -860,7 +864,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.RequireAdmin(msg, func() {
 							session.ChannelTyping(msg.ChannelID)
 							if len(args) < 4 {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -869,12 +873,12 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 							badgeFound := getBadge(args[2], args[3], channel.GuildID)
 							if badgeFound.ID == "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.badge-error-not-found"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-error-not-found"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
 							if badgeFound.GuildID == "global" && !helpers.IsBotAdmin(msg.Author.ID) {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.delete-badge-error-not-allowed"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.delete-badge-error-not-allowed"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -929,7 +933,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.delete-badge-success"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.delete-badge-success"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						})
@@ -945,7 +949,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							categoryBadges := getCategoryBadges(categoryName, channel.GuildID)
 
 							if categoryBadges == nil || len(categoryBadges) <= 0 {
-								helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.list-category-badge-error-none"))
+								helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.list-category-badge-error-none"))
 								return
 							}
 
@@ -990,7 +994,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						serverBadges := getServerBadges(channel.GuildID)
 
 						if len(serverBadges) <= 0 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.list-badge-error-none"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.list-badge-error-none"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -1028,14 +1032,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.RequireMod(msg, func() {
 							session.ChannelTyping(msg.ChannelID)
 							if len(args) < 5 {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
 
 							targetUser, err := helpers.GetUserFromMention(args[2])
 							if err != nil || targetUser.ID == "" {
-								helpers.SendMessage(msg.ChannelID, helpers.GetTextF("bot.arguments.invalid"))
+								helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 								return
 							}
 
@@ -1044,13 +1048,13 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 							badgeToAllow := getBadge(args[3], args[4], channel.GuildID)
 							if badgeToAllow.ID == "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.badge-error-not-found"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-error-not-found"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
 
 							if badgeToAllow.GuildID == "global" && !helpers.IsBotAdmin(msg.Author.ID) {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.edit-badge-error-not-allowed"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.edit-badge-error-not-allowed"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -1118,7 +1122,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 									}, false)
 								helpers.RelaxLog(err)
 
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.allow-badge-success-allowed",
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.allow-badge-success-allowed",
 									targetUser.Username, badgeToAllow.Name, badgeToAllow.Category))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
@@ -1183,7 +1187,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 									}, false)
 								helpers.RelaxLog(err)
 
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.allow-badge-success-not-allowed",
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.allow-badge-success-not-allowed",
 									targetUser.Username, badgeToAllow.Name, badgeToAllow.Category))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
@@ -1194,14 +1198,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.RequireMod(msg, func() {
 							session.ChannelTyping(msg.ChannelID)
 							if len(args) < 5 {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
 
 							targetUser, err := helpers.GetUserFromMention(args[2])
 							if err != nil || targetUser.ID == "" {
-								helpers.SendMessage(msg.ChannelID, helpers.GetTextF("bot.arguments.invalid"))
+								helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 								return
 							}
 
@@ -1210,13 +1214,13 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 							badgeToDeny := getBadge(args[3], args[4], channel.GuildID)
 							if badgeToDeny.ID == "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.badge-error-not-found"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-error-not-found"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
 
 							if badgeToDeny.GuildID == "global" && !helpers.IsBotAdmin(msg.Author.ID) {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.edit-badge-error-not-allowed"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.edit-badge-error-not-allowed"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -1284,7 +1288,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 									}, false)
 								helpers.RelaxLog(err)
 
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.deny-badge-success-denied",
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.deny-badge-success-denied",
 									targetUser.Username, badgeToDeny.Name, badgeToDeny.Category))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
@@ -1349,7 +1353,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 									}, false)
 								helpers.RelaxLog(err)
 
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.deny-badge-success-not-denied",
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.deny-badge-success-not-denied",
 									targetUser.Username, badgeToDeny.Name, badgeToDeny.Category))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
@@ -1359,7 +1363,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					case "move": // [p]profile badge move <category name> <badge name> <#>
 						session.ChannelTyping(msg.ChannelID)
 						if len(args) < 5 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -1367,7 +1371,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						badgeName := args[3]
 						newSpot, err := strconv.Atoi(args[4])
 						if err != nil {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -1384,7 +1388,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						if idToMove == "" {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.badge-error-not-found"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-error-not-found"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -1407,7 +1411,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						err = helpers.MDbUpdate(models.ProfileUserdataTable, userData.ID, userData)
 						helpers.Relax(err)
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.move-badge-success"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.move-badge-success"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 
 						return
@@ -1418,7 +1422,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				availableBadges := getBadgesAvailable(msg.Author, channel.GuildID)
 
 				if len(availableBadges) <= 0 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.badge-error-none"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-error-none"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
@@ -1591,7 +1595,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 			case "color", "colour":
 				session.ChannelTyping(msg.ChannelID)
 				if len(args) < 2 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
@@ -1619,20 +1623,20 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						userUserdata.TextColor = ""
 					}
 				default:
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
 				err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 				helpers.Relax(err)
 
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-color-set-success"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-color-set-success"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			case "opacity":
 				session.ChannelTyping(msg.ChannelID)
 				if len(args) < 2 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
@@ -1648,7 +1652,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				if len(args) >= 3 {
 					opacity, err := strconv.ParseFloat(args[2], 64)
 					if err != nil {
-						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -1667,7 +1671,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				case "avatar":
 					userUserdata.AvatarOpacity = opacityText
 				default:
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
@@ -1675,7 +1679,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 				helpers.Relax(err)
 
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-opacity-set-success"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-opacity-set-success"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			case "timezone":
@@ -1686,14 +1690,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				timeInTimezone := ""
 				if len(args) < 2 {
 					if userUserdata.Timezone == "" {
-						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-timezone-list"))
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-timezone-list"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
 				} else {
 					loc, err := time.LoadLocation(args[1])
 					if err != nil {
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-timezone-set-error")+"\n"+helpers.GetText("plugins.levels.profile-timezone-list"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-timezone-set-error")+"\n"+helpers.GetTextForMessage(msg, "plugins.levels.profile-timezone-list"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -1705,10 +1709,10 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				helpers.Relax(err)
 
 				if timeInTimezone != "" {
-					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.profile-timezone-set-success",
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-timezone-set-success",
 						newTimezoneString, timeInTimezone))
 				} else {
-					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.profile-timezone-reset-success"))
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-timezone-reset-success"))
 				}
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
@@ -1719,7 +1723,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				if len(args) >= 2 {
 					_, err = time.Parse(TimeBirthdayFormat, args[1])
 					if err != nil {
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-birthday-set-error-format"))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-birthday-set-error-format"))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
 					}
@@ -1732,14 +1736,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbUpdate(models.ProfileUserdataTable, userUserdata.ID, userUserdata)
 				helpers.Relax(err)
 
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-birthday-set-success"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-birthday-set-success"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			}
 
 			targetUser, err = helpers.GetUserFromMention(args[0])
 			if targetUser == nil || targetUser.ID == "" {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			}
@@ -1748,7 +1752,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		targetMember, err := helpers.GetGuildMember(channel.GuildID, targetUser.ID)
 		if errD, ok := err.(*discordgo.RESTError); ok {
 			if errD.Message.Code == 10007 {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			} else {
@@ -1766,7 +1770,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		jpgBytes, ext, err := m.GetProfile(targetMember, guild, gifP)
 		if err != nil {
 			cache.GetLogger().WithField("module", "levels").Error(fmt.Sprintf("Profile generation failed: %#v", err))
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-error-exit1"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-error-exit1"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
@@ -1783,7 +1787,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 			})
 		if err != nil {
 			if errD, ok := err.(*discordgo.RESTError); ok && errD.Message.Code == 20009 {
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.profile-error-sending"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.profile-error-sending"))
 				return
 			}
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
@@ -1811,17 +1815,17 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				helpers.Relax(err)
 
 				if levelsServersUsers == nil || len(levelsServersUsers) <= 0 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.top-server-no-stats"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.top-server-no-stats"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				} else if err != nil {
 					helpers.Relax(err)
 				}
 
-				rankingUrl := helpers.GetConfig().Path("website.ranking_base_url").Data().(string) + "/" + channel.GuildID
+				rankingUrl := helpers.GetConfig().Website.RankingBaseURL + "/" + channel.GuildID
 				topLevelEmbed := &discordgo.MessageEmbed{
 					Color:       0x0FADED,
-					Title:       helpers.GetTextF("plugins.levels.top-server-embed-title", guild.Name),
+					Title:       helpers.GetTextForMessage(msg, "plugins.levels.top-server-embed-title", guild.Name),
 					Description: "View the leaderboard for this server [here](" + rankingUrl + ").",
 					Fields:      []*discordgo.MessageEmbedField{},
 					URL:         rankingUrl,
@@ -1909,18 +1913,18 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				}
 
 				if len(rankedTotalExpMap) <= 0 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.no-stats-available-yet"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.no-stats-available-yet"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
 
-				rankingUrl := helpers.GetConfig().Path("website.ranking_base_url").Data().(string)
+				rankingUrl := helpers.GetConfig().Website.RankingBaseURL
 				globalTopLevelEmbed := &discordgo.MessageEmbed{
 					Color:       0x0FADED,
-					Title:       helpers.GetText("plugins.levels.global-top-server-embed-title"),
+					Title:       helpers.GetTextForMessage(msg, "plugins.levels.global-top-server-embed-title"),
 					Description: "View the global leaderboard [here](" + rankingUrl + ").",
-					Footer: &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.levels.embed-footer",
-						len(helpers.AllGuilds()),
+					Footer: &discordgo.MessageEmbedFooter{Text: helpers.GetTextForMessage(msg, "plugins.levels.embed-footer",
+						helpers.GuildCount(),
 					)},
 					Fields: []*discordgo.MessageEmbedField{},
 					URL:    rankingUrl,
@@ -1981,7 +1985,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					switch args[1] {
 					case "user": // [p]levels reset user <user>
 						if len(args) < 3 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -1989,7 +1993,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.RequireAdmin(msg, func() {
 							targetUser, err = helpers.GetUserFromMention(args[2])
 							if targetUser == nil || targetUser.ID == "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -2001,6 +2005,11 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 							levelsServerUser.Exp = 0
 							err = helpers.MDbUpdate(models.LevelsServerusersTable, levelsServerUser.ID, levelsServerUser)
+							helpers.Relax(err)
+
+							// remove roles the user does not qualify for anymore
+							err = applyLevelsRoles(channel.GuildID, targetUser.ID, GetLevelFromExp(levelsServerUser.Exp))
+							helpers.RelaxLog(err)
 
 							_, err = helpers.EventlogLog(time.Now(), channel.GuildID, targetUser.ID,
 								models.EventlogTargetTypeUser, msg.Author.ID,
@@ -2015,7 +2024,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								nil, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.user-resetted"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.user-resetted"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						})
@@ -2068,7 +2077,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						return
 					case "user": // [p]levels ignore user <user>
 						if len(args) < 3 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2076,7 +2085,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						helpers.RequireAdmin(msg, func() {
 							targetUser, err = helpers.GetUserFromMention(args[2])
 							if targetUser == nil || targetUser.ID == "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -2111,7 +2120,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 										}, false)
 									helpers.RelaxLog(err)
 
-									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.ignore-user-removed"))
+									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.ignore-user-removed"))
 									helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 									return
 								}
@@ -2141,14 +2150,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.ignore-user-added", helpers.GetPrefixForServer(channel.GuildID)))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.ignore-user-added", helpers.GetPrefixForServer(channel.GuildID)))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						})
 						return
 					case "channel": // [p]levels ignore channel <channel>
 						if len(args) < 3 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2157,7 +2166,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							targetChannel, err := helpers.GetChannelFromMention(msg, args[2])
 							helpers.Relax(err)
 							if targetChannel == nil || targetChannel.ID == "" {
-								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -2192,7 +2201,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 										}, false)
 									helpers.RelaxLog(err)
 
-									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.ignore-channel-removed"))
+									_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.ignore-channel-removed"))
 									helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 									return
 								}
@@ -2222,7 +2231,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.ignore-channel-added"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.ignore-channel-added"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						})
@@ -2255,6 +2264,10 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							levelsServerUser.Exp = 0
 							err = helpers.MDbUpdate(models.LevelsServerusersTable, levelsServerUser.ID, levelsServerUser)
 							helpers.Relax(err)
+
+							// remove roles the user does not qualify for anymore
+							err = applyLevelsRoles(channel.GuildID, levelsServerUser.UserID, GetLevelFromExp(levelsServerUser.Exp))
+							helpers.RelaxLog(err)
 						}
 					}
 					_, err = helpers.SendMessage(dmChannel.ID, fmt.Sprintf("Resetted the EXP for every User on `%s`.", guild.Name))
@@ -2351,7 +2364,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 				return
 			case "role", "roles":
 				if len(args) < 2 {
-					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					return
 				}
@@ -2361,12 +2374,12 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					helpers.RequireMod(msg, func() {
 						// [p]levels role add <role name or id> <start level> [<last level>]
 						if len(args) < 4 {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
 						if _, err = strconv.Atoi(args[len(args)-1]); err != nil {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2374,7 +2387,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						serverRoles, err := session.GuildRoles(channel.GuildID)
 						if err != nil {
 							if errD, ok := err.(*discordgo.RESTError); ok && errD.Message.Code == discordgo.ErrCodeMissingPermissions {
-								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("bot.permissions.required", "Manage Roles"))
+								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.permissions.required", "Manage Roles"))
 								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 								return
 							}
@@ -2408,7 +2421,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						if targetRole == nil || targetRole.ID == "" || startLevel < 0 || (lastLevel < 0 && lastLevel != -1) || (lastLevel != -1 && startLevel > lastLevel) {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2437,21 +2450,21 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							options, false)
 						helpers.RelaxLog(err)
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.levels-role-add-success", targetRole.Name))
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-add-success", targetRole.Name))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 					})
 					return
 				case "apply":
 					// [p]levels role apply
 					helpers.RequireMod(msg, func() {
-						if helpers.ConfirmEmbed(msg.GuildID, msg.ChannelID, msg.Author, helpers.GetText("plugins.levels.levels-role-apply-confirm"), "✅", "🚫") {
+						if helpers.ConfirmEmbed(msg.GuildID, msg.ChannelID, msg.Author, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-apply-confirm"), "✅", "🚫") {
 							errors := make([]error, 0)
 							var success int
 
 							guild, err := helpers.GetGuild(channel.GuildID)
 							helpers.Relax(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.levels-role-apply-start"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-apply-start"))
 
 							for _, member := range guild.Members {
 								if member.User.Bot == true {
@@ -2482,13 +2495,103 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.levels-role-apply-result", msg.Author.ID, success, len(errors)))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-apply-result", msg.Author.ID, success, len(errors)))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
 						return
 					})
 					return
+				case "mode":
+					// [p]levels role mode [<range|stack|replace>]
+					helpers.RequireMod(msg, func() {
+						guildConfig := helpers.GuildSettingsGetCached(channel.GuildID)
+
+						if len(args) < 3 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-mode-current",
+								getLevelsRolesModeText(guildConfig.LevelsRolesMode)))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						newMode, ok := getLevelsRolesModeFromText(args[2])
+						if !ok {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						beforeMode := guildConfig.LevelsRolesMode
+						guildConfig.LevelsRolesMode = newMode
+						err = helpers.GuildSettingsSet(channel.GuildID, guildConfig)
+						helpers.Relax(err)
+
+						_, err = helpers.EventlogLog(time.Now(), channel.GuildID, channel.GuildID,
+							models.EventlogTargetTypeGuild, msg.Author.ID,
+							models.EventlogTypeRobyulLevelsRoleMode, "",
+							[]models.ElasticEventlogChange{
+								{
+									Key:      "levels_roles_mode",
+									OldValue: getLevelsRolesModeText(beforeMode),
+									NewValue: getLevelsRolesModeText(newMode),
+								},
+							},
+							nil, false)
+						helpers.RelaxLog(err)
+
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-mode-set",
+							getLevelsRolesModeText(newMode), helpers.GetPrefixForServer(channel.GuildID)))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					})
+					return
+				case "preview":
+					// [p]levels role preview <user>
+					helpers.RequireMod(msg, func() {
+						if len(args) < 3 {
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						targetUser, err = helpers.GetUserFromMention(args[2])
+						if err != nil || targetUser == nil || targetUser.ID == "" {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						targetLevel := getLevelForUser(targetUser.ID, channel.GuildID)
+						toApply, toRemove, err := getLevelsRolesChanges(channel.GuildID, targetUser.ID, targetLevel)
+						if err != nil {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						if len(toApply) <= 0 && len(toRemove) <= 0 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-preview-nothing",
+								targetUser.Username, targetLevel))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						message := helpers.GetTextForMessage(msg, "plugins.levels.levels-role-preview-title", targetUser.Username, targetLevel,
+							getLevelsRolesModeText(helpers.GuildSettingsGetCached(channel.GuildID).LevelsRolesMode)) + "\n"
+						for _, role := range toApply {
+							message += fmt.Sprintf(":heavy_plus_sign: `%s` (`#%s`)\n", role.Name, role.ID)
+						}
+						for _, role := range toRemove {
+							message += fmt.Sprintf(":heavy_minus_sign: `%s` (`#%s`)\n", role.Name, role.ID)
+						}
+
+						for _, page := range helpers.Pagify(message, "\n") {
+							_, err = helpers.SendMessage(msg.ChannelID, page)
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						}
+						return
+					})
+					return
 				case "list":
 					// [p]levels role list
 					helpers.RequireMod(msg, func() {
@@ -2500,7 +2603,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						if len(entries) <= 0 {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.levels-role-list-empty"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-list-empty"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						}
 
@@ -2562,7 +2665,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					// levels role remove <connection id>
 					helpers.RequireMod(msg, func() {
 						if len(args) < 3 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2572,7 +2675,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							if !strings.Contains(err.Error(), "no levels role entry") {
 								helpers.Relax(err)
 							}
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2607,7 +2710,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							options, false)
 						helpers.RelaxLog(err)
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.levels-role-delete-success",
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.levels-role-delete-success",
 							role.Name, entry.RoleID))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
@@ -2617,7 +2720,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					// TODO: apply roles on join, show overwrites in list
 					helpers.RequireMod(msg, func() {
 						if len(args) < 4 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2627,7 +2730,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 						targetUser, err := helpers.GetUserFromMention(args[2])
 						if err != nil || targetUser == nil || targetUser.ID == "" {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2642,7 +2745,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						if targetRole == nil || targetRole.ID == "" {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2650,7 +2753,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						previousGrant, previousDeny, grant := m.getLevelsRolesUserRoleOverwrite(guild.ID, targetRole.ID, targetUser.ID)
 
 						if previousDeny {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.roles-grant-error-denying"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.roles-grant-error-denying"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2674,7 +2777,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.roles-grant-remove-success",
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.roles-grant-remove-success",
 								targetUser.Username, targetUser.ID, targetRole.Name, targetRole.ID))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
@@ -2699,7 +2802,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							}, false)
 						helpers.RelaxLog(err)
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.roles-grant-create-success",
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.roles-grant-create-success",
 							targetUser.Username, targetUser.ID, targetRole.Name, targetRole.ID))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
@@ -2709,7 +2812,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					// [p]levels roles deny <@user or user id> <role name or id>
 					helpers.RequireMod(msg, func() {
 						if len(args) < 4 {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2719,7 +2822,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 						targetUser, err := helpers.GetUserFromMention(args[2])
 						if err != nil || targetUser == nil || targetUser.ID == "" {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2734,7 +2837,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						}
 
 						if targetRole == nil || targetRole.ID == "" {
-							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2742,7 +2845,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						previousGrant, previousDeny, grant := m.getLevelsRolesUserRoleOverwrite(guild.ID, targetRole.ID, targetUser.ID)
 
 						if previousGrant {
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.roles-deny-error-granting"))
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.roles-deny-error-granting"))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
 						}
@@ -2766,7 +2869,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 								}, false)
 							helpers.RelaxLog(err)
 
-							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.roles-deny-remove-success",
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.roles-deny-remove-success",
 								targetUser.Username, targetUser.ID, targetRole.Name, targetRole.ID))
 							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 							return
@@ -2791,7 +2894,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 							}, false)
 						helpers.RelaxLog(err)
 
-						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.roles-deny-create-success",
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.roles-deny-create-success",
 							targetUser.Username, targetUser.ID, targetRole.Name, targetRole.ID))
 						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 						return
@@ -2799,7 +2902,560 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 					return
 				}
 
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+				return
+			case "exp", "xp":
+				// [p]levels exp <give|take|set> <user> <amount>
+				helpers.RequireAdmin(msg, func() {
+					if len(args) < 4 {
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					targetUser, err := helpers.GetUserFromMention(args[2])
+					amount, errAmount := strconv.ParseInt(args[3], 10, 64)
+					if err != nil || targetUser == nil || targetUser.ID == "" || errAmount != nil || amount < 0 {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					levelsServerUser, err := getLevelsServerUserOrCreateNewWithoutLogging(channel.GuildID, targetUser.ID)
+					helpers.Relax(err)
+
+					var newExp int64
+					var eventType string
+					switch args[1] {
+					case "give", "add":
+						newExp = levelsServerUser.Exp + amount
+						eventType = models.EventlogTypeRobyulLevelsExpGive
+					case "take", "remove":
+						newExp = levelsServerUser.Exp - amount
+						eventType = models.EventlogTypeRobyulLevelsExpTake
+					case "set":
+						newExp = amount
+						eventType = models.EventlogTypeRobyulLevelsExpSet
+					default:
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+					if newExp < 0 {
+						newExp = 0
+					}
+
+					expBefore, err := updateLevelsExp(channel.GuildID, targetUser.ID, newExp)
+					helpers.Relax(err)
+
+					_, err = helpers.EventlogLog(time.Now(), channel.GuildID, targetUser.ID,
+						models.EventlogTargetTypeUser, msg.Author.ID,
+						eventType, "",
+						[]models.ElasticEventlogChange{
+							{
+								Key:      "levels_exp",
+								OldValue: strconv.FormatInt(expBefore, 10),
+								NewValue: strconv.FormatInt(newExp, 10),
+							},
+						},
+						[]models.ElasticEventlogOption{
+							{
+								Key:   "levels_exp_amount",
+								Value: strconv.FormatInt(amount, 10),
+							},
+						}, false)
+					helpers.RelaxLog(err)
+
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.exp-update-success",
+						targetUser.Username, expBefore, GetLevelFromExp(expBefore), newExp, GetLevelFromExp(newExp)))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					return
+				})
+				return
+			case "transfer":
+				// [p]levels transfer <from user> <to user>
+				helpers.RequireAdmin(msg, func() {
+					if len(args) < 3 {
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					fromUser, err := helpers.GetUserFromMention(args[1])
+					if err != nil || fromUser == nil || fromUser.ID == "" {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+					toUser, err := helpers.GetUserFromMention(args[2])
+					if err != nil || toUser == nil || toUser.ID == "" || toUser.ID == fromUser.ID {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					fromLevelsServerUser, err := getLevelsServerUserOrCreateNewWithoutLogging(channel.GuildID, fromUser.ID)
+					helpers.Relax(err)
+					if fromLevelsServerUser.Exp <= 0 {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.transfer-error-no-exp", fromUser.Username))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					if !helpers.ConfirmEmbed(msg.GuildID, msg.ChannelID, msg.Author, helpers.GetTextForMessage(msg, "plugins.levels.transfer-confirm",
+						fromLevelsServerUser.Exp, fromUser.Username, toUser.Username), "✅", "🚫") {
+						return
+					}
+
+					toLevelsServerUser, err := getLevelsServerUserOrCreateNewWithoutLogging(channel.GuildID, toUser.ID)
+					helpers.Relax(err)
+
+					toExpBefore, err := updateLevelsExp(channel.GuildID, toUser.ID, toLevelsServerUser.Exp+fromLevelsServerUser.Exp)
+					helpers.Relax(err)
+					fromExpBefore, err := updateLevelsExp(channel.GuildID, fromUser.ID, 0)
+					helpers.Relax(err)
+
+					_, err = helpers.EventlogLog(time.Now(), channel.GuildID, toUser.ID,
+						models.EventlogTargetTypeUser, msg.Author.ID,
+						models.EventlogTypeRobyulLevelsExpTransfer, "",
+						[]models.ElasticEventlogChange{
+							{
+								Key:      "levels_exp",
+								OldValue: strconv.FormatInt(toExpBefore, 10),
+								NewValue: strconv.FormatInt(toExpBefore+fromExpBefore, 10),
+							},
+							{
+								Key:      "levels_from_exp",
+								OldValue: strconv.FormatInt(fromExpBefore, 10),
+								NewValue: "0",
+							},
+						},
+						[]models.ElasticEventlogOption{
+							{
+								Key:   "levels_from_userid",
+								Value: fromUser.ID,
+								Type:  models.EventlogTargetTypeUser,
+							},
+						}, false)
+					helpers.RelaxLog(err)
+
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.transfer-success",
+						fromExpBefore, fromUser.Username, toUser.Username, GetLevelFromExp(toExpBefore+fromExpBefore)))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					return
+				})
+				return
+			case "import":
+				// [p]levels import [<set|add>] <attachment or link to a CSV or JSON file>
+				helpers.RequireAdmin(msg, func() {
+					addExp := false
+					importMode := "set"
+					if len(args) >= 2 {
+						switch args[1] {
+						case "add":
+							addExp = true
+							importMode = "add"
+						case "set":
+						default:
+							if !strings.HasPrefix(args[1], "http") {
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+								return
+							}
+						}
+					}
+
+					var fileURL string
+					if len(msg.Attachments) > 0 {
+						fileURL = msg.Attachments[0].URL
+					} else if strings.HasPrefix(args[len(args)-1], "http") {
+						fileURL = args[len(args)-1]
+					}
+					if fileURL == "" {
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.import-error-no-file",
+							helpers.GetPrefixForServer(channel.GuildID)))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					if !isLevelsImportURLAllowed(fileURL) {
+						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.import-error-url"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					fileData, err := helpers.NetGetUAWithErrorAndTimeout(fileURL, helpers.DEFAULT_UA, time.Second*30)
+					if err != nil {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.import-error-download"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					entries, err := parseLevelsImport(fileData)
+					if err != nil {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.import-error-parse", err.Error()))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					confirmText := helpers.GetTextForMessage(msg, "plugins.levels.import-confirm-set", len(entries))
+					if addExp {
+						confirmText = helpers.GetTextForMessage(msg, "plugins.levels.import-confirm-add", len(entries))
+					}
+					if !helpers.ConfirmEmbed(msg.GuildID, msg.ChannelID, msg.Author, confirmText, "✅", "🚫") {
+						return
+					}
+
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.import-start"))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+
+					go func() {
+						defer helpers.Recover()
+
+						imported, failed, totalExp := importLevelsExp(channel.GuildID, entries, addExp)
+
+						_, err := helpers.EventlogLog(time.Now(), channel.GuildID, channel.GuildID,
+							models.EventlogTargetTypeGuild, msg.Author.ID,
+							models.EventlogTypeRobyulLevelsExpImport, "",
+							nil,
+							[]models.ElasticEventlogOption{
+								{
+									Key:   "levels_import_mode",
+									Value: importMode,
+								},
+								{
+									Key:   "levels_import_users",
+									Value: strconv.Itoa(imported),
+								},
+								{
+									Key:   "levels_import_failed",
+									Value: strconv.Itoa(failed),
+								},
+								{
+									Key:   "levels_import_exp",
+									Value: strconv.FormatInt(totalExp, 10),
+								},
+								{
+									Key:   "levels_import_file",
+									Value: fileURL,
+								},
+							}, false)
+						helpers.RelaxLog(err)
+
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.import-result",
+							msg.Author.ID, imported, failed))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					}()
+				})
+				return
+			case "season", "seasons":
+				if len(args) < 2 {
+					_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					return
+				}
+
+				switch args[1] {
+				case "list":
+					// [p]levels season list
+					seasons, err := GetSeasons(channel.GuildID)
+					helpers.Relax(err)
+
+					if len(seasons) <= 0 {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-list-none"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					listText := helpers.GetTextForMessage(msg, "plugins.levels.season-list-title", guild.Name) + "\n"
+					for _, season := range seasons {
+						status := helpers.GetTextForMessage(msg, "plugins.levels.season-list-status-active", season.EndAt.Format(time.ANSIC))
+						if season.Archived {
+							status = helpers.GetTextForMessage(msg, "plugins.levels.season-list-status-ended", season.ArchivedAt.Format(time.ANSIC))
+						}
+						listText += fmt.Sprintf("`%s`: %s - %s\n", season.Name, season.StartAt.Format(time.ANSIC), status)
+					}
+
+					_, err = helpers.SendMessage(msg.ChannelID, listText)
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					return
+				case "start":
+					// [p]levels season start <name> <days>
+					helpers.RequireMod(msg, func() {
+						if len(args) < 4 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						seasonName := strings.ToLower(args[2])
+						days, err := strconv.Atoi(args[3])
+						if err != nil || days <= 0 || days > 365 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						existingSeason, err := GetSeason(channel.GuildID, seasonName)
+						if err != nil && !helpers.IsMdbNotFound(err) {
+							helpers.Relax(err)
+						}
+						if err == nil && !existingSeason.Archived {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-start-error-exists"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						season := models.LevelsSeasonEntry{
+							GuildID:         channel.GuildID,
+							Name:            seasonName,
+							CreatedByUserID: msg.Author.ID,
+							StartAt:         time.Now(),
+							EndAt:           time.Now().Add(time.Duration(days) * 24 * time.Hour),
+						}
+						season.ID, err = helpers.MDbInsert(models.LevelsSeasonsTable, season)
+						helpers.Relax(err)
+
+						err = refreshActiveSeasons()
+						helpers.RelaxLog(err)
+
+						_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(season.ID),
+							models.EventlogTargetTypeRobyulLevelsSeason, msg.Author.ID,
+							models.EventlogTypeRobyulLevelsSeasonStart, "",
+							nil,
+							[]models.ElasticEventlogOption{
+								{
+									Key:   "season_name",
+									Value: season.Name,
+								},
+								{
+									Key:   "season_endat",
+									Value: season.EndAt.Format(models.ISO8601),
+								},
+							}, false)
+						helpers.RelaxLog(err)
+
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-start-success",
+							season.Name, season.EndAt.Format(time.ANSIC), helpers.GetPrefixForServer(channel.GuildID)))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					})
+					return
+				case "end":
+					// [p]levels season end <name>
+					helpers.RequireMod(msg, func() {
+						if len(args) < 3 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						season, err := GetSeason(channel.GuildID, args[2])
+						if err != nil || season.Archived {
+							if err != nil && !helpers.IsMdbNotFound(err) {
+								helpers.Relax(err)
+							}
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-not-found"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						// the season will be archived and rewarded by the next run of the seasons loop
+						season.EndAt = time.Now()
+						err = helpers.MDbUpdate(models.LevelsSeasonsTable, season.ID, season)
+						helpers.Relax(err)
+
+						err = refreshActiveSeasons()
+						helpers.RelaxLog(err)
+
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-end-success", season.Name))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					})
+					return
+				case "reward", "reward-role":
+					// [p]levels season reward <name> <top count> <role name or id, or none>
+					helpers.RequireMod(msg, func() {
+						if len(args) < 5 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						season, err := GetSeason(channel.GuildID, args[2])
+						if err != nil || season.Archived {
+							if err != nil && !helpers.IsMdbNotFound(err) {
+								helpers.Relax(err)
+							}
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-not-found"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						rewardCount, err := strconv.Atoi(args[3])
+						if err != nil || rewardCount <= 0 || rewardCount > levelsSeasonArchiveSize {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						roleNameToMatch := strings.TrimSpace(strings.Join(args[4:], " "))
+						var targetRoleID, targetRoleName string
+						if strings.ToLower(roleNameToMatch) != "none" {
+							serverRoles, err := session.GuildRoles(channel.GuildID)
+							helpers.Relax(err)
+							for _, role := range serverRoles {
+								if strings.ToLower(role.Name) == strings.ToLower(roleNameToMatch) || role.ID == roleNameToMatch {
+									targetRoleID = role.ID
+									targetRoleName = role.Name
+								}
+							}
+							if targetRoleID == "" {
+								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+								return
+							}
+						}
+
+						beforeSeason := season
+						season.RewardCount = rewardCount
+						season.RewardRoleID = targetRoleID
+						err = helpers.MDbUpdate(models.LevelsSeasonsTable, season.ID, season)
+						helpers.Relax(err)
+
+						err = refreshActiveSeasons()
+						helpers.RelaxLog(err)
+
+						_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(season.ID),
+							models.EventlogTargetTypeRobyulLevelsSeason, msg.Author.ID,
+							models.EventlogTypeRobyulLevelsSeasonUpdate, "",
+							[]models.ElasticEventlogChange{
+								{
+									Key:      "season_rewardcount",
+									OldValue: strconv.Itoa(beforeSeason.RewardCount),
+									NewValue: strconv.Itoa(season.RewardCount),
+								},
+								{
+									Key:      "season_rewardroleid",
+									OldValue: beforeSeason.RewardRoleID,
+									NewValue: season.RewardRoleID,
+									Type:     models.EventlogTargetTypeRole,
+								},
+							},
+							[]models.ElasticEventlogOption{
+								{
+									Key:   "season_name",
+									Value: season.Name,
+								},
+							}, false)
+						helpers.RelaxLog(err)
+
+						if targetRoleID == "" {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-reward-role-removed", season.Name))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-reward-role-success",
+							season.Name, rewardCount, targetRoleName))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					})
+					return
+				case "reward-badge":
+					// [p]levels season reward-badge <name> <top count> <category name> <badge name, or none>
+					helpers.RequireMod(msg, func() {
+						if len(args) < 5 {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						season, err := GetSeason(channel.GuildID, args[2])
+						if err != nil || season.Archived {
+							if err != nil && !helpers.IsMdbNotFound(err) {
+								helpers.Relax(err)
+							}
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-not-found"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						rewardCount, err := strconv.Atoi(args[3])
+						if err != nil || rewardCount <= 0 || rewardCount > levelsSeasonArchiveSize {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						var targetBadge models.ProfileBadgeEntry
+						if strings.ToLower(args[4]) != "none" {
+							if len(args) < 6 {
+								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+								return
+							}
+
+							targetBadge = getBadge(args[4], args[5], channel.GuildID)
+							if targetBadge.ID == "" {
+								_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.badge-error-not-found"))
+								helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+								return
+							}
+						}
+
+						beforeSeason := season
+						season.RewardCount = rewardCount
+						season.RewardBadgeID = helpers.MdbIdToHuman(targetBadge.ID)
+						if targetBadge.ID == "" {
+							season.RewardBadgeID = ""
+						}
+						err = helpers.MDbUpdate(models.LevelsSeasonsTable, season.ID, season)
+						helpers.Relax(err)
+
+						err = refreshActiveSeasons()
+						helpers.RelaxLog(err)
+
+						_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(season.ID),
+							models.EventlogTargetTypeRobyulLevelsSeason, msg.Author.ID,
+							models.EventlogTypeRobyulLevelsSeasonUpdate, "",
+							[]models.ElasticEventlogChange{
+								{
+									Key:      "season_rewardcount",
+									OldValue: strconv.Itoa(beforeSeason.RewardCount),
+									NewValue: strconv.Itoa(season.RewardCount),
+								},
+								{
+									Key:      "season_rewardbadgeid",
+									OldValue: beforeSeason.RewardBadgeID,
+									NewValue: season.RewardBadgeID,
+									Type:     models.EventlogTargetTypeRobyulBadge,
+								},
+							},
+							[]models.ElasticEventlogOption{
+								{
+									Key:   "season_name",
+									Value: season.Name,
+								},
+							}, false)
+						helpers.RelaxLog(err)
+
+						if targetBadge.ID == "" {
+							_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-reward-badge-removed", season.Name))
+							helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+							return
+						}
+
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-reward-badge-success",
+							season.Name, rewardCount, targetBadge.Category, targetBadge.Name))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					})
+					return
+				}
+
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			case "set-level-notification", "set-level-notifications", "set-level-noti", "set-level-notis":
@@ -2815,13 +3471,13 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						embedCode := strings.TrimSpace(strings.Replace(content, strings.Join(args[:1], " "), "", 1))
 						if embedCode != "" {
 							guildConfig.LevelsNotificationCode = embedCode
-							message = helpers.GetText("plugins.levels.level-notification-enabled")
+							message = helpers.GetTextForMessage(msg, "plugins.levels.level-notification-enabled")
 						}
 					}
 
 					if message == "" {
 						guildConfig.LevelsNotificationCode = ""
-						message = helpers.GetText("plugins.levels.level-notification-disabled")
+						message = helpers.GetTextForMessage(msg, "plugins.levels.level-notification-disabled")
 					}
 
 					err = helpers.GuildSettingsSet(channel.GuildID, guildConfig)
@@ -2845,13 +3501,13 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 						deleteAfterN, err := strconv.Atoi(args[1])
 						if err == nil && deleteAfterN > 0 {
 							guildConfig.LevelsNotificationDeleteAfter = deleteAfterN
-							message = helpers.GetTextF("plugins.levels.level-notification-autodelete-enabled", deleteAfterN)
+							message = helpers.GetTextForMessage(msg, "plugins.levels.level-notification-autodelete-enabled", deleteAfterN)
 						}
 					}
 
 					if message == "" {
 						guildConfig.LevelsNotificationDeleteAfter = 0
-						message = helpers.GetText("plugins.levels.level-notification-autodelete-disabled")
+						message = helpers.GetTextForMessage(msg, "plugins.levels.level-notification-autodelete-disabled")
 					}
 
 					err = helpers.GuildSettingsSet(channel.GuildID, guildConfig)
@@ -2865,7 +3521,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 			}
 			targetUser, err = helpers.GetUserFromMention(args[0])
 			if targetUser == nil || targetUser.ID == "" {
-				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+				_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			}
@@ -2876,7 +3532,7 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		helpers.Relax(err)
 
 		if levelsServersUser == nil {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.level-no-stats"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.level-no-stats"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		} else if err != nil {
@@ -2893,14 +3549,14 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		}
 
 		if totalExp <= 0 {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.levels.level-no-stats"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.level-no-stats"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
 
 		currentMember, _ := helpers.GetGuildMember(channel.GuildID, targetUser.ID)
 		if currentMember == nil || currentMember.User == nil || currentMember.User.ID == "" {
-			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+			_, err := helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			return
 		}
@@ -2918,10 +3574,10 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 
 		userLevelEmbed := &discordgo.MessageEmbed{
 			Color:       0x0FADED,
-			Title:       helpers.GetTextF("plugins.levels.user-embed-title", fullUsername),
-			Description: "View the leaderboard for this server [here](" + helpers.GetConfig().Path("website.ranking_base_url").Data().(string) + "/" + channel.GuildID + ").",
-			Footer: &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.levels.embed-footer",
-				len(helpers.AllGuilds()),
+			Title:       helpers.GetTextForMessage(msg, "plugins.levels.user-embed-title", fullUsername),
+			Description: "View the leaderboard for this server [here](" + helpers.GetConfig().Website.RankingBaseURL + "/" + channel.GuildID + ").",
+			Footer: &discordgo.MessageEmbedFooter{Text: helpers.GetTextForMessage(msg, "plugins.levels.embed-footer",
+				helpers.GuildCount(),
 			)},
 			Fields: []*discordgo.MessageEmbedField{
 				{
@@ -2967,12 +3623,72 @@ func (m *Levels) Action(command string, content string, msg *discordgo.Message,
 		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 		return
 	case "leaderboard", "leaderboards", "ranking", "rankings":
+		// [p]leaderboard [<weekly|monthly|season <name>|seasons>]
 		channel, err := helpers.GetChannel(msg.ChannelID)
 		helpers.Relax(err)
 
-		link := helpers.GetConfig().Path("website.ranking_base_url").Data().(string) + "/" + channel.GuildID
+		args := strings.Fields(content)
+		if len(args) >= 1 {
+			guild, err := helpers.GetGuild(channel.GuildID)
+			helpers.Relax(err)
+
+			var title string
+			var ranking []models.LevelsPeriodExpEntry
+			switch strings.ToLower(args[0]) {
+			case "weekly", "week":
+				title = helpers.GetTextForMessage(msg, "plugins.levels.leaderboard-weekly-title", guild.Name)
+				ranking, err = GetPeriodRanking(channel.GuildID, GetPeriodWeekly(time.Now()), 10)
+				helpers.Relax(err)
+			case "monthly", "month":
+				title = helpers.GetTextForMessage(msg, "plugins.levels.leaderboard-monthly-title", guild.Name)
+				ranking, err = GetPeriodRanking(channel.GuildID, GetPeriodMonthly(time.Now()), 10)
+				helpers.Relax(err)
+			case "season":
+				if len(args) < 2 {
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					return
+				}
+
+				season, err := GetSeason(channel.GuildID, args[1])
+				if err != nil {
+					if !helpers.IsMdbNotFound(err) {
+						helpers.Relax(err)
+					}
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.season-not-found"))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+					return
+				}
+
+				title = helpers.GetTextForMessage(msg, "plugins.levels.leaderboard-season-title", season.Name, guild.Name)
+				if season.Archived {
+					title = helpers.GetTextForMessage(msg, "plugins.levels.leaderboard-season-archived-title", season.Name, guild.Name)
+				}
+				ranking, err = GetSeasonRanking(season, 10)
+				helpers.Relax(err)
+			case "seasons":
+				m.Action("levels", "season list", msg, session)
+				return
+			default:
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+				return
+			}
+
+			if len(ranking) <= 0 {
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.top-server-no-stats"))
+				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+				return
+			}
+
+			_, err = helpers.SendEmbed(msg.ChannelID, getPeriodLeaderboardEmbed(guild, title, ranking))
+			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+			return
+		}
 
-		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.levels.ranking-text", link))
+		link := helpers.GetConfig().Website.RankingBaseURL + "/" + channel.GuildID
+
+		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.levels.ranking-text", link))
 		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 		return
 	}
@@ -3549,14 +4265,19 @@ func (m *Levels) ProcessMessage(msg *discordgo.Message, session *discordgo.Sessi
 		}
 	}
 
-	// check if bucket is empty
-	if !m.BucketHasKeys(channel.GuildID + msg.Author.ID) {
-		//m.BucketSet(channel.GuildID+msg.Author.ID, -1)
+	// only gain EXP once per interval
+	expKey := ratelimits.Key("levels", "exp", channel.GuildID, msg.Author.ID)
+	retryAfter, _ := expLocalLimiter.Take(expKey, 1, expInterval)
+	if retryAfter > 0 {
+		return
+	}
+	retryAfter, err = ratelimits.Take(expKey, 1, expInterval)
+	if err != nil {
+		// the interval has been checked locally already, do not stop EXP gain if the shared limiter is unavailable
+		cache.GetLogger().WithField("module", "levels").Warnf("failed to take exp interval: %s", err.Error())
+	} else if retryAfter > 0 {
 		return
 	}
-
-	err = m.BucketDrain(1, channel.GuildID+msg.Author.ID)
-	helpers.Relax(err)
 
 	expStack.Push(ProcessExpInfo{UserID: msg.Author.ID, GuildID: channel.GuildID, ChannelID: msg.ChannelID})
 }
@@ -3600,100 +4321,6 @@ func (m *Levels) getLevelsServerUserOrCreateNew(guildid string, userid string) (
 	return serveruser, err
 }
 
-// Refills user buckets in a set interval
-func (b *Levels) BucketRefiller() {
-	for {
-		b.Lock()
-		for user, keys := range b.buckets {
-			// Chill zone
-			if keys == -1 {
-				b.buckets[user]++
-				continue
-			}
-
-			// Chill zone exit
-			if keys == 0 {
-				b.buckets[user] = BUCKET_INITIAL_FILL
-				continue
-			}
-
-			// More free keys for nice users :3
-			if keys < BUCKET_UPPER_BOUND {
-				b.buckets[user] += DROP_SIZE
-				continue
-			}
-		}
-		b.Unlock()
-
-		time.Sleep(DROP_INTERVAL)
-	}
-}
-
-// Check if the user has a bucket. If not create one
-func (b *Levels) CreateBucketIfNotExists(user string) {
-	if b.buckets == nil {
-		return
-	}
-
-	b.RLock()
-	_, e := b.buckets[user]
-	b.RUnlock()
-
-	if !e {
-		b.Lock()
-		b.buckets[user] = BUCKET_INITIAL_FILL
-		b.Unlock()
-	}
-}
-
-// Drains $amount from $user if he has enough keys left
-func (b *Levels) BucketDrain(amount int8, user string) error {
-	b.CreateBucketIfNotExists(user)
-
-	// Check if there are enough keys left
-	b.RLock()
-	userAmount := b.buckets[user]
-	b.RUnlock()
-
-	if amount > userAmount {
-		return errors.New("No keys left")
-	}
-
-	// Remove keys from bucket
-	b.Lock()
-	b.buckets[user] -= amount
-	b.Unlock()
-
-	return nil
-}
-
-// Check if the user still has keys
-func (b *Levels) BucketHasKeys(user string) bool {
-	b.CreateBucketIfNotExists(user)
-
-	b.RLock()
-	defer b.RUnlock()
-
-	return b.buckets[user] > 0
-}
-
-func (b *Levels) BucketGet(user string) int8 {
-	b.RLock()
-	defer b.RUnlock()
-
-	return b.buckets[user]
-}
-
-func (b *Levels) BucketSet(user string, value int8) {
-	if b.buckets == nil {
-		return
-	}
-
-	b.Lock()
-	b.buckets[user] = value
-	b.Unlock()
-}
-
 func (b *Levels) OnReactionAdd(reaction *discordgo.MessageReactionAdd, session *discordgo.Session) {
 
 }
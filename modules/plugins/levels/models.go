@@ -42,13 +42,35 @@ func getLevelsRoles(guildID string, currentLevel int) (apply []*discordgo.Role,
 		return
 	}
 
+	mode := helpers.GuildSettingsGetCached(guildID).LevelsRolesMode
+
+	// the highest start level a user reached is required to only grant the highest role
+	highestStartLevel := -1
+	if mode == models.LevelsRolesModeReplace {
+		for _, entry := range entryBucket {
+			if currentLevel >= entry.StartLevel && entry.StartLevel > highestStartLevel {
+				highestStartLevel = entry.StartLevel
+			}
+		}
+	}
+
 	for _, entry := range entryBucket {
 		role, err := cache.GetSession().SessionForGuildS(guildID).State.Role(guildID, entry.RoleID)
 		if err != nil {
 			continue
 		}
 
-		if currentLevel >= entry.StartLevel && (entry.LastLevel < 0 || currentLevel <= entry.LastLevel) {
+		var earned bool
+		switch mode {
+		case models.LevelsRolesModeStack:
+			earned = currentLevel >= entry.StartLevel
+		case models.LevelsRolesModeReplace:
+			earned = currentLevel >= entry.StartLevel && entry.StartLevel == highestStartLevel
+		default:
+			earned = currentLevel >= entry.StartLevel && (entry.LastLevel < 0 || currentLevel <= entry.LastLevel)
+		}
+
+		if earned {
 			apply = append(apply, role)
 		} else {
 			remove = append(remove, role)
@@ -58,6 +80,28 @@ func getLevelsRoles(guildID string, currentLevel int) (apply []*discordgo.Role,
 	return
 }
 
+func getLevelsRolesModeText(mode models.LevelsRolesMode) string {
+	switch mode {
+	case models.LevelsRolesModeStack:
+		return "stack"
+	case models.LevelsRolesModeReplace:
+		return "replace"
+	}
+	return "range"
+}
+
+func getLevelsRolesModeFromText(text string) (models.LevelsRolesMode, bool) {
+	switch strings.ToLower(text) {
+	case "range":
+		return models.LevelsRolesModeRange, true
+	case "stack":
+		return models.LevelsRolesModeStack, true
+	case "replace":
+		return models.LevelsRolesModeReplace, true
+	}
+	return models.LevelsRolesModeRange, false
+}
+
 func getLevelsRolesUserOverwrites(guildID string, userID string) (overwrites []models.LevelsRoleOverwriteEntry) {
 	err := helpers.MDbIter(helpers.MdbCollection(models.LevelsRoleOverwritesTable).Find(bson.M{"userid": userID, "guildid": guildID})).All(&overwrites)
 	if err != nil {
@@ -0,0 +1,413 @@
+package levels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// the amount of members stored in the archive of a season
+	levelsSeasonArchiveSize = 100
+	// how long weekly and monthly EXP is kept after the last EXP was gained
+	levelsWeeklyExpRetention  = 12 * 7 * 24 * time.Hour
+	levelsMonthlyExpRetention = 13 * 31 * 24 * time.Hour
+	// how long archived seasons are kept
+	levelsArchivedSeasonRetention = 2 * 365 * 24 * time.Hour
+)
+
+var (
+	activeSeasons     = make(map[string][]models.LevelsSeasonEntry, 0)
+	activeSeasonsLock sync.RWMutex
+)
+
+// GetPeriodWeekly returns the period key for the week of the given time
+func GetPeriodWeekly(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("week-%d-%02d", year, week)
+}
+
+// GetPeriodMonthly returns the period key for the month of the given time
+func GetPeriodMonthly(t time.Time) string {
+	return t.UTC().Format("month-2006-01")
+}
+
+// GetPeriodSeason returns the period key for the season
+func GetPeriodSeason(season models.LevelsSeasonEntry) string {
+	return "season-" + helpers.MdbIdToHuman(season.ID)
+}
+
+// addPeriodExp adds EXP gained to the current week, month, and all active seasons of the guild
+func addPeriodExp(guildID string, userID string, exp int64) {
+	now := time.Now()
+	periodExpirations := map[string]time.Time{
+		GetPeriodWeekly(now):  now.Add(levelsWeeklyExpRetention),
+		GetPeriodMonthly(now): now.Add(levelsMonthlyExpRetention),
+	}
+	// season EXP is removed when the season gets archived
+	for _, season := range getActiveSeasons(guildID) {
+		periodExpirations[GetPeriodSeason(season)] = time.Time{}
+	}
+
+	// all periods are updated in one round trip
+	bulk := helpers.MdbCollection(models.LevelsPeriodExpTable).Bulk()
+	bulk.Unordered()
+	for period, expiresAt := range periodExpirations {
+		update := bson.M{"$inc": bson.M{"exp": exp}}
+		if !expiresAt.IsZero() {
+			update["$set"] = bson.M{"expiresat": expiresAt}
+		}
+
+		bulk.Upsert(bson.M{"guildid": guildID, "userid": userID, "period": period}, update)
+	}
+	_, err := bulk.Run()
+	helpers.RelaxLog(err)
+}
+
+// ensurePeriodExpIndexes indexes the period rankings and expires old weekly and monthly EXP
+func ensurePeriodExpIndexes() error {
+	collection := helpers.MdbCollection(models.LevelsPeriodExpTable)
+
+	for _, index := range []mgo.Index{
+		{Key: []string{"guildid", "period", "-exp"}},
+		{Key: []string{"expiresat"}, ExpireAfter: time.Second},
+	} {
+		err := collection.EnsureIndex(index)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneArchivedSeasons removes archived seasons which ended a long time ago
+func pruneArchivedSeasons() error {
+	_, err := helpers.MdbCollection(models.LevelsSeasonsTable).RemoveAll(bson.M{
+		"archived":   true,
+		"archivedat": bson.M{"$lt": time.Now().Add(-levelsArchivedSeasonRetention)},
+	})
+	return err
+}
+
+// GetPeriodRanking returns the members with the most EXP gained during the period, use guildID global for all guilds
+func GetPeriodRanking(guildID string, period string, limit int) (ranking []models.LevelsPeriodExpEntry, err error) {
+	if guildID != "global" {
+		err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LevelsPeriodExpTable).Find(
+			bson.M{"guildid": guildID, "period": period, "exp": bson.M{"$gt": 0}},
+		).Sort("-exp").Limit(limit)).All(&ranking)
+		return ranking, err
+	}
+
+	var result []struct {
+		UserID string `bson:"_id"`
+		Exp    int64  `bson:"exp"`
+	}
+	err = helpers.MdbCollection(models.LevelsPeriodExpTable).Pipe([]bson.M{
+		{"$match": bson.M{"period": period, "exp": bson.M{"$gt": 0}}},
+		{"$group": bson.M{"_id": "$userid", "exp": bson.M{"$sum": "$exp"}}},
+		{"$sort": bson.M{"exp": -1}},
+		{"$limit": limit},
+	}).All(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result {
+		ranking = append(ranking, models.LevelsPeriodExpEntry{
+			UserID:  item.UserID,
+			GuildID: guildID,
+			Period:  period,
+			Exp:     item.Exp,
+		})
+	}
+	return ranking, nil
+}
+
+// GetSeason returns the newest season on the guild with the given name
+func GetSeason(guildID string, name string) (season models.LevelsSeasonEntry, err error) {
+	err = helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.LevelsSeasonsTable).Find(
+			bson.M{"guildid": guildID, "name": strings.ToLower(name)},
+		).Sort("-startat"),
+		&season,
+	)
+	return season, err
+}
+
+// GetSeasons returns all seasons on the guild, newest first
+func GetSeasons(guildID string) (seasons []models.LevelsSeasonEntry, err error) {
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LevelsSeasonsTable).Find(
+		bson.M{"guildid": guildID},
+	).Sort("-startat")).All(&seasons)
+	return seasons, err
+}
+
+// GetSeasonRanking returns the ranking of a season, archived seasons are read from the archive
+func GetSeasonRanking(season models.LevelsSeasonEntry, limit int) (ranking []models.LevelsPeriodExpEntry, err error) {
+	if !season.Archived {
+		return GetPeriodRanking(season.GuildID, GetPeriodSeason(season), limit)
+	}
+
+	for _, topUser := range season.TopUsers {
+		if len(ranking) >= limit {
+			break
+		}
+		ranking = append(ranking, models.LevelsPeriodExpEntry{
+			UserID:  topUser.UserID,
+			GuildID: season.GuildID,
+			Period:  GetPeriodSeason(season),
+			Exp:     topUser.Exp,
+		})
+	}
+	return ranking, nil
+}
+
+func getActiveSeasons(guildID string) []models.LevelsSeasonEntry {
+	activeSeasonsLock.RLock()
+	defer activeSeasonsLock.RUnlock()
+
+	now := time.Now()
+	result := make([]models.LevelsSeasonEntry, 0)
+	for _, season := range activeSeasons[guildID] {
+		if !season.StartAt.After(now) && season.EndAt.After(now) {
+			result = append(result, season)
+		}
+	}
+	return result
+}
+
+func refreshActiveSeasons() (err error) {
+	var seasons []models.LevelsSeasonEntry
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.LevelsSeasonsTable).Find(
+		bson.M{"archived": false},
+	)).All(&seasons)
+	if err != nil {
+		return err
+	}
+
+	newActiveSeasons := make(map[string][]models.LevelsSeasonEntry, 0)
+	for _, season := range seasons {
+		newActiveSeasons[season.GuildID] = append(newActiveSeasons[season.GuildID], season)
+	}
+
+	activeSeasonsLock.Lock()
+	activeSeasons = newActiveSeasons
+	activeSeasonsLock.Unlock()
+
+	return nil
+}
+
+// levelsSeasonsLoop keeps the active seasons cached and archives seasons which ended
+func levelsSeasonsLoop() {
+	log := cache.GetLogger()
+
+	defer helpers.Recover()
+	defer func() {
+		go func() {
+			log.WithField("module", "levels").Error("The levelsSeasonsLoop died. Please investigate! Will be restarted in 60 seconds")
+			time.Sleep(60 * time.Second)
+			levelsSeasonsLoop()
+		}()
+	}()
+
+	err := ensurePeriodExpIndexes()
+	helpers.RelaxLog(err)
+
+	for {
+		err = refreshActiveSeasons()
+		helpers.RelaxLog(err)
+
+		activeSeasonsLock.RLock()
+		var endedSeasons []models.LevelsSeasonEntry
+		for _, seasons := range activeSeasons {
+			for _, season := range seasons {
+				if !season.EndAt.After(time.Now()) {
+					endedSeasons = append(endedSeasons, season)
+				}
+			}
+		}
+		activeSeasonsLock.RUnlock()
+
+		for _, season := range endedSeasons {
+			archived, err := archiveSeason(season)
+			if err != nil {
+				helpers.RelaxLog(err)
+				continue
+			}
+			if !archived {
+				// archived by another process
+				continue
+			}
+			log.WithField("module", "levels").Infof("archived season %s (%s) on #%s",
+				season.Name, helpers.MdbIdToHuman(season.ID), season.GuildID)
+		}
+
+		if len(endedSeasons) > 0 {
+			err = refreshActiveSeasons()
+			helpers.RelaxLog(err)
+
+			err = pruneArchivedSeasons()
+			helpers.RelaxLog(err)
+		}
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// archiveSeason stores the final ranking of a season and rewards the top members, returns false if the season has already been archived
+func archiveSeason(season models.LevelsSeasonEntry) (archived bool, err error) {
+	// claim the season, only the process which marks it as archived hands out the rewards
+	season.Archived = true
+	season.ArchivedAt = time.Now()
+	err = helpers.MdbCollection(models.LevelsSeasonsTable).Update(
+		bson.M{"_id": season.ID, "archived": false},
+		bson.M{"$set": bson.M{"archived": season.Archived, "archivedat": season.ArchivedAt}},
+	)
+	if err != nil {
+		if helpers.IsMdbNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			// release the claim so the season gets archived again
+			errRelease := helpers.MdbCollection(models.LevelsSeasonsTable).Update(
+				bson.M{"_id": season.ID},
+				bson.M{"$set": bson.M{"archived": false}},
+			)
+			helpers.RelaxLog(errRelease)
+		}
+	}()
+
+	ranking, err := GetPeriodRanking(season.GuildID, GetPeriodSeason(season), levelsSeasonArchiveSize)
+	if err != nil {
+		return false, err
+	}
+
+	season.TopUsers = make([]models.LevelsSeasonRankEntry, 0)
+	for i, item := range ranking {
+		season.TopUsers = append(season.TopUsers, models.LevelsSeasonRankEntry{
+			UserID:  item.UserID,
+			Exp:     item.Exp,
+			Ranking: i + 1,
+		})
+	}
+
+	rewardedUserIDs := make([]string, 0)
+	if season.RewardCount > 0 {
+		var rewardBadge models.ProfileBadgeEntry
+		if season.RewardBadgeID != "" {
+			rewardBadge = getBadgeByID(season.RewardBadgeID)
+		}
+
+		for _, topUser := range season.TopUsers {
+			if len(rewardedUserIDs) >= season.RewardCount {
+				break
+			}
+			// only reward users which are still on the server
+			if !helpers.GetIsInGuild(season.GuildID, topUser.UserID) {
+				continue
+			}
+
+			if season.RewardRoleID != "" {
+				errRole := cache.GetSession().SessionForGuildS(season.GuildID).GuildMemberRoleAdd(season.GuildID, topUser.UserID, season.RewardRoleID)
+				if errRole != nil {
+					cache.GetLogger().WithField("module", "levels").Warnf("failed to add season reward role: %s", errRole.Error())
+				}
+			}
+
+			if rewardBadge.ID != "" {
+				var isAlreadyAllowed bool
+				for _, allowedUserID := range rewardBadge.AllowedUserIDs {
+					if allowedUserID == topUser.UserID {
+						isAlreadyAllowed = true
+					}
+				}
+				if !isAlreadyAllowed {
+					rewardBadge.AllowedUserIDs = append(rewardBadge.AllowedUserIDs, topUser.UserID)
+				}
+			}
+
+			rewardedUserIDs = append(rewardedUserIDs, topUser.UserID)
+		}
+
+		if rewardBadge.ID != "" {
+			err = helpers.MDbUpdate(models.ProfileBadgesTable, rewardBadge.ID, rewardBadge)
+			helpers.RelaxLog(err)
+		}
+	}
+
+	err = helpers.MDbUpdate(models.LevelsSeasonsTable, season.ID, season)
+	if err != nil {
+		return false, err
+	}
+
+	// the ranking is archived in the season, the EXP of the members is not needed anymore
+	_, err = helpers.MdbCollection(models.LevelsPeriodExpTable).RemoveAll(
+		bson.M{"guildid": season.GuildID, "period": GetPeriodSeason(season)},
+	)
+	helpers.RelaxLog(err)
+
+	_, err = helpers.EventlogLog(time.Now(), season.GuildID, helpers.MdbIdToHuman(season.ID),
+		models.EventlogTargetTypeRobyulLevelsSeason, cache.GetSession().SessionForGuildS(season.GuildID).State.User.ID,
+		models.EventlogTypeRobyulLevelsSeasonEnd, "",
+		nil,
+		[]models.ElasticEventlogOption{
+			{
+				Key:   "season_name",
+				Value: season.Name,
+			},
+			{
+				Key:   "season_rewardeduserids",
+				Value: strings.Join(rewardedUserIDs, ";"),
+				Type:  models.EventlogTargetTypeUser,
+			},
+		}, false)
+	helpers.RelaxLog(err)
+
+	return true, nil
+}
+
+// getPeriodLeaderboardEmbed creates an embed showing the top members of a period ranking
+func getPeriodLeaderboardEmbed(guild *discordgo.Guild, title string, ranking []models.LevelsPeriodExpEntry) *discordgo.MessageEmbed {
+	leaderboardEmbed := &discordgo.MessageEmbed{
+		Color:  0x0FADED,
+		Title:  title,
+		Fields: []*discordgo.MessageEmbedField{},
+	}
+
+	for i, item := range ranking {
+		username := "N/A"
+		currentMember, err := helpers.GetGuildMemberWithoutApi(guild.ID, item.UserID)
+		if err == nil && currentMember.User != nil {
+			username = currentMember.User.Username
+			if currentMember.Nick != "" {
+				username += " ~ " + currentMember.Nick
+			}
+		} else if user, err := helpers.GetUserWithoutAPI(item.UserID); err == nil {
+			username = user.Username
+		}
+
+		leaderboardEmbed.Fields = append(leaderboardEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%d. %s", i+1, username),
+			Value:  fmt.Sprintf("EXP: %s", strconv.FormatInt(item.Exp, 10)),
+			Inline: false,
+		})
+	}
+
+	if guild.Icon != "" {
+		leaderboardEmbed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: guild.IconURL()}
+	}
+
+	return leaderboardEmbed
+}
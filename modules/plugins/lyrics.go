@@ -168,7 +168,7 @@ func (l *Lyrics) GeniusRequest(location string, object interface{}) error {
 		return err
 	}
 	req.Header.Add("User-Agent", helpers.DEFAULT_UA)
-	req.Header.Add("Authorization", helpers.GetConfig().Path("genius.token").Data().(string))
+	req.Header.Add("Authorization", helpers.GetConfig().Genius.Token)
 	resp, err := helpers.DefaultClient.Do(req)
 
 	if resp != nil && resp.Body != nil {
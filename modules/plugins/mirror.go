@@ -1,7 +1,9 @@
 package plugins
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"sync"
@@ -16,9 +18,9 @@ import (
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/sirupsen/logrus"
-	"github.com/vmihailenco/msgpack"
 )
 
 type Mirror struct{}
@@ -40,13 +42,35 @@ var (
 	}
 )
 
+const (
+	// mirrorMaxUploadSize is the maximum size of attachments which will be uploaded again by upload mirrors
+	mirrorMaxUploadSize = 8 * 1024 * 1024
+
+	// mirrorsCacheName is used to reload the mirrors in all processes
+	mirrorsCacheName = "mirrors"
+
+	// mirrorMessagesExpiration is how long edits, deletions and replies of a message are mirrored
+	mirrorMessagesExpiration = 30 * 24 * time.Hour
+)
+
 func (m *Mirror) Init(session *shardmanager.Manager) {
 	var err error
 	mirrors, err = m.GetMirrors()
 	helpers.Relax(err)
 
+	err = m.ensureMirrorMessagesIndexes()
+	helpers.RelaxLog(err)
+
+	helpers.OnCacheInvalidation(mirrorsCacheName, func() (err error) {
+		mirrors, err = m.GetMirrors()
+		return err
+	})
+
 	session.AddHandler(m.OnMessage)
+	session.AddHandler(m.OnMessageUpdate)
 	session.AddHandler(m.OnMessageDelete)
+	session.AddHandler(m.OnReactionAdd)
+	session.AddHandler(m.OnReactionRemove)
 }
 
 func (m *Mirror) Uninit(session *shardmanager.Manager) {
@@ -72,7 +96,7 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 				})
 				helpers.Relax(err)
 
-				mirrors, err = m.GetMirrors()
+				err = helpers.InvalidateCache(mirrorsCacheName)
 				helpers.Relax(err)
 
 				_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(newID),
@@ -113,21 +137,21 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 
 				beforeType := mirrorEntry.Type
 
-				var typeText string
 				switch mirrorEntry.Type {
 				case models.MirrorTypeLink:
 					mirrorEntry.Type = models.MirrorTypeText
-					typeText = "text"
+					break
+				case models.MirrorTypeText:
+					mirrorEntry.Type = models.MirrorTypeUpload
 					break
 				default:
 					mirrorEntry.Type = models.MirrorTypeLink
-					typeText = "link"
 					break
 				}
 				err = helpers.MDbUpdate(models.MirrorsTable, mirrorEntry.ID, mirrorEntry)
 				helpers.Relax(err)
 
-				mirrors, err = m.GetMirrors()
+				err = helpers.InvalidateCache(mirrorsCacheName)
 				helpers.Relax(err)
 
 				_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(mirrorEntry.ID),
@@ -144,7 +168,58 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 					nil, false)
 				helpers.RelaxLog(err)
 
-				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.mirror.toggle-success", typeText))
+				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.mirror.toggle-success", mirrorTypeText(mirrorEntry.Type)))
+				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+				return
+			})
+			return
+		case "bidirectional": // [p]mirror bidirectional <mirror id>
+			session.ChannelTyping(msg.ChannelID)
+			helpers.RequireRobyulMod(msg, func() {
+				if len(args) < 2 {
+					helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+					return
+				}
+
+				channel, err := helpers.GetChannel(msg.ChannelID)
+				helpers.Relax(err)
+
+				var mirrorEntry models.MirrorEntry
+				err = helpers.MdbOne(
+					helpers.MdbCollection(models.MirrorsTable).Find(bson.M{"_id": helpers.HumanToMdbId(args[1])}),
+					&mirrorEntry,
+				)
+				if helpers.IsMdbNotFound(err) {
+					helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+					return
+				}
+				helpers.Relax(err)
+
+				mirrorEntry.Bidirectional = !mirrorEntry.Bidirectional
+				err = helpers.MDbUpdate(models.MirrorsTable, mirrorEntry.ID, mirrorEntry)
+				helpers.Relax(err)
+
+				err = helpers.InvalidateCache(mirrorsCacheName)
+				helpers.Relax(err)
+
+				_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(mirrorEntry.ID),
+					models.EventlogTargetTypeRobyulMirror, msg.Author.ID,
+					models.EventlogTypeRobyulMirrorUpdate, "",
+					[]models.ElasticEventlogChange{
+						{
+							Key:      "mirror_bidirectional",
+							OldValue: helpers.StoreBoolAsString(!mirrorEntry.Bidirectional),
+							NewValue: helpers.StoreBoolAsString(mirrorEntry.Bidirectional),
+						},
+					},
+					nil, false)
+				helpers.RelaxLog(err)
+
+				if mirrorEntry.Bidirectional {
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.mirror.bidirectional-enabled"))
+				} else {
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.mirror.bidirectional-disabled"))
+				}
 				helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 				return
 			})
@@ -198,7 +273,7 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbUpdate(models.MirrorsTable, mirrorEntry.ID, mirrorEntry)
 				helpers.Relax(err)
 
-				mirrors, err = m.GetMirrors()
+				err = helpers.InvalidateCache(mirrorsCacheName)
 				helpers.Relax(err)
 
 				_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(mirrorEntry.ID),
@@ -236,15 +311,9 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 
 				resultMessage := ":fax: Mirrors:\n"
 				for _, entry := range entryBucket {
-					var entryTypeText string
-					switch entry.Type {
-					case models.MirrorTypeLink:
-						entryTypeText = "link"
-					case models.MirrorTypeText:
-						entryTypeText = "text"
-					}
-					resultMessage += fmt.Sprintf(":satellite: Mirror `%s` (Mode: `%s`, %d channels):\n",
-						helpers.MdbIdToHuman(entry.ID), entryTypeText, len(entry.ConnectedChannels))
+					resultMessage += fmt.Sprintf(":satellite: Mirror `%s` (Mode: `%s`, Bidirectional: `%s`, %d channels):\n",
+						helpers.MdbIdToHuman(entry.ID), mirrorTypeText(entry.Type),
+						helpers.StoreBoolAsString(entry.Bidirectional), len(entry.ConnectedChannels))
 					for _, mirroredChannelEntry := range entry.ConnectedChannels {
 						mirroredChannel, err := helpers.GetChannel(mirroredChannelEntry.ChannelID)
 						if err != nil {
@@ -299,7 +368,10 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 				err = helpers.MDbDelete(models.MirrorsTable, mirrorEntry.ID)
 				helpers.Relax(err)
 
-				mirrors, err = m.GetMirrors()
+				_, err = helpers.MdbCollection(models.MirrorMessagesTable).RemoveAll(bson.M{"mirrorid": mirrorEntry.ID})
+				helpers.RelaxLog(err)
+
+				err = helpers.InvalidateCache(mirrorsCacheName)
 				helpers.Relax(err)
 
 				_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(mirrorEntry.ID),
@@ -321,7 +393,7 @@ func (m *Mirror) Action(command string, content string, msg *discordgo.Message,
 			helpers.RequireRobyulMod(msg, func() {
 				var err error
 				session.ChannelTyping(msg.ChannelID)
-				mirrors, err = m.GetMirrors()
+				err = helpers.InvalidateCache(mirrorsCacheName)
 				helpers.Relax(err)
 				_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.mirror.refreshed-config"))
 				helpers.Relax(err)
@@ -360,6 +432,10 @@ func (m *Mirror) OnMessage(session *discordgo.Session, msg *discordgo.MessageCre
 						return
 					}
 				}
+				// ignore our own mirrored messages to prevent loops
+				if m.isMirrorWebhookMessage(msg.Message, mirroredChannelEntry) {
+					return
+				}
 				var linksToRepost []string
 				// get mirror attachements
 				if len(msg.Attachments) > 0 {
@@ -378,16 +454,13 @@ func (m *Mirror) OnMessage(session *discordgo.Session, msg *discordgo.MessageCre
 						}
 					}
 				}
-				// get full content message
-				newContent := msg.Content
-				if len(msg.Attachments) > 0 {
-					for _, attachement := range msg.Attachments {
-						newContent += "\n" + attachement.URL
-					}
-				}
 				switch mirrorEntry.Type {
 				case models.MirrorTypeText:
-					m.postMirrorMessage(mirrorEntry, msg.Message, msg.Author, newContent)
+					m.postMirrorMessage(mirrorEntry, msg.Message, msg.Author, m.getMirrorContent(mirrorEntry, msg.Message), nil)
+					break
+				case models.MirrorTypeUpload:
+					m.postMirrorMessage(mirrorEntry, msg.Message, msg.Author,
+						m.getMirrorContent(mirrorEntry, msg.Message), m.downloadAttachments(msg.Attachments))
 					break
 				default:
 					// post mirror links
@@ -398,7 +471,7 @@ func (m *Mirror) OnMessage(session *discordgo.Session, msg *discordgo.MessageCre
 							m.postMirrorMessage(mirrorEntry, msg.Message, msg.Author,
 								fmt.Sprintf("posted %s in `#%s` on the `%s` server (<#%s>)",
 									linkToRepost, sourceChannel.Name, sourceGuild.Name, sourceChannel.ID,
-								),
+								), nil,
 							)
 						}
 					}
@@ -410,7 +483,115 @@ func (m *Mirror) OnMessage(session *discordgo.Session, msg *discordgo.MessageCre
 
 }
 
-func (m *Mirror) postMirrorMessage(mirrorEntry models.MirrorEntry, sourceMessage *discordgo.Message, author *discordgo.User, message string) {
+type mirrorAttachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// getMirrorContent returns the content of a mirrored copy for text and upload mirrors
+func (m *Mirror) getMirrorContent(mirrorEntry models.MirrorEntry, sourceMessage *discordgo.Message) (content string) {
+	content = sourceMessage.Content
+	for _, attachement := range sourceMessage.Attachments {
+		// upload mirrors only link attachments which are too big to be uploaded again
+		if mirrorEntry.Type == models.MirrorTypeUpload && attachement.Size <= mirrorMaxUploadSize {
+			continue
+		}
+		content += "\n" + attachement.URL
+	}
+	return content
+}
+
+// downloadAttachments downloads all attachments which are small enough to be uploaded again
+func (m *Mirror) downloadAttachments(attachments []*discordgo.MessageAttachment) (result []mirrorAttachment) {
+	for _, attachement := range attachments {
+		if attachement.Size > mirrorMaxUploadSize {
+			continue
+		}
+
+		data, err := helpers.NetGetUAWithErrorAndTimeout(attachement.URL, helpers.DEFAULT_UA, 60*time.Second)
+		if err != nil {
+			helpers.RelaxLog(err)
+			continue
+		}
+
+		result = append(result, mirrorAttachment{
+			Name:        attachement.Filename,
+			ContentType: http.DetectContentType(data),
+			Data:        data,
+		})
+	}
+	return result
+}
+
+// isMirrorWebhookMessage returns true if the message has been posted by the webhook we use to mirror into the channel
+func (m *Mirror) isMirrorWebhookMessage(message *discordgo.Message, mirroredChannelEntry models.MirrorChannelEntry) bool {
+	if message.WebhookID == "" {
+		return false
+	}
+
+	webhook, err := helpers.GetWebhook(mirroredChannelEntry.GuildID, mirroredChannelEntry.ChannelID)
+	if err != nil || webhook == nil {
+		return false
+	}
+
+	return webhook.ID == message.WebhookID
+}
+
+// getReplyPrefix returns a link to the replied to message in the target channel for bidirectional mirrors
+func (m *Mirror) getReplyPrefix(mirrorEntry models.MirrorEntry, sourceMessage *discordgo.Message, targetChannelID string) string {
+	if !mirrorEntry.Bidirectional || sourceMessage.MessageReference == nil || sourceMessage.MessageReference.MessageID == "" {
+		return ""
+	}
+
+	correspondingMessageID := m.getCorrespondingMessageID(mirrorEntry,
+		sourceMessage.MessageReference.ChannelID, sourceMessage.MessageReference.MessageID, targetChannelID)
+	if correspondingMessageID == "" {
+		return ""
+	}
+
+	return helpers.GetTextF("plugins.mirror.reply-prefix", helpers.MessageDeeplink(targetChannelID, correspondingMessageID)) + "\n"
+}
+
+// getCorrespondingMessageID returns the ID of the copy of a message in another channel of the mirror
+func (m *Mirror) getCorrespondingMessageID(mirrorEntry models.MirrorEntry, channelID, messageID, targetChannelID string) string {
+	rootChannelID := channelID
+	rootMessageID := messageID
+
+	// resolve copies to their source message
+	var copyEntry models.MirrorMessageEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.MirrorMessagesTable).Find(bson.M{"mirrorid": mirrorEntry.ID, "messageid": messageID}),
+		&copyEntry,
+	)
+	if err == nil {
+		rootChannelID = copyEntry.SourceChannelID
+		rootMessageID = copyEntry.SourceMessageID
+	} else if !helpers.IsMdbNotFound(err) {
+		helpers.RelaxLog(err)
+		return ""
+	}
+
+	if rootChannelID == targetChannelID {
+		return rootMessageID
+	}
+
+	var targetEntry models.MirrorMessageEntry
+	err = helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.MirrorMessagesTable).Find(bson.M{"mirrorid": mirrorEntry.ID, "sourcemessageid": rootMessageID, "channelid": targetChannelID}),
+		&targetEntry,
+	)
+	if err != nil {
+		if !helpers.IsMdbNotFound(err) {
+			helpers.RelaxLog(err)
+		}
+		return ""
+	}
+
+	return targetEntry.MessageID
+}
+
+func (m *Mirror) postMirrorMessage(mirrorEntry models.MirrorEntry, sourceMessage *discordgo.Message, author *discordgo.User, message string, attachments []mirrorAttachment) {
 	for _, channelToMirrorToEntry := range mirrorEntry.ConnectedChannels {
 		if channelToMirrorToEntry.ChannelID != sourceMessage.ChannelID {
 			robyulIsOnTargetGuild := false
@@ -426,99 +607,272 @@ func (m *Mirror) postMirrorMessage(mirrorEntry models.MirrorEntry, sourceMessage
 				if err != nil {
 					continue
 				}
-				result, err := helpers.WebhookExecuteWithResult(
+				files := make([]*discordgo.File, 0)
+				for _, attachment := range attachments {
+					files = append(files, &discordgo.File{
+						Name:        attachment.Name,
+						ContentType: attachment.ContentType,
+						Reader:      bytes.NewReader(attachment.Data),
+					})
+				}
+				result, err := helpers.WebhookExecuteWithFilesAndResult(
 					webhook.ID, webhook.Token,
 					&discordgo.WebhookParams{
-						Content:   message,
+						Content:   m.getReplyPrefix(mirrorEntry, sourceMessage, channelToMirrorToEntry.ChannelID) + message,
 						Username:  author.Username,
 						AvatarURL: helpers.GetAvatarUrl(author),
-					})
-				helpers.RelaxLog(err)
+					}, files)
+				if err != nil {
+					helpers.RelaxLog(err)
+					continue
+				}
 				metrics.MirrorsPostsSent.Add(1)
-				err = m.rememberPostedMessage(sourceMessage, result)
+				err = m.rememberPostedMessage(mirrorEntry, sourceMessage, result, webhook.ID)
 				helpers.RelaxLog(err)
 			}
 		}
 	}
 }
 
+func mirrorTypeText(mirrorType models.MirrorType) string {
+	switch mirrorType {
+	case models.MirrorTypeText:
+		return "text"
+	case models.MirrorTypeUpload:
+		return "upload"
+	}
+	return "link"
+}
+
 func (m *Mirror) GetMirrors() (entryBucket []models.MirrorEntry, err error) {
 	err = helpers.MDbIter(helpers.MdbCollection(models.MirrorsTable).Find(nil)).All(&entryBucket)
 	return entryBucket, err
 }
 
-type Mirror_PostedMessage struct {
-	ChannelID string
-	MessageID string
-}
+// ensureMirrorMessagesIndexes indexes the message lookups and expires old mirrored messages
+func (m *Mirror) ensureMirrorMessagesIndexes() error {
+	collection := helpers.MdbCollection(models.MirrorMessagesTable)
 
-func (m *Mirror) getRememberedMessageKey(sourceMessageID string) (key string) {
-	return fmt.Sprintf("robyul2-discord:mirror:postedmessage:%s", sourceMessageID)
+	for _, index := range []mgo.Index{
+		{Key: []string{"mirrorid", "sourcemessageid"}},
+		{Key: []string{"mirrorid", "messageid"}},
+		{Key: []string{"createdat"}, ExpireAfter: mirrorMessagesExpiration},
+	} {
+		err := collection.EnsureIndex(index)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (m *Mirror) rememberPostedMessage(sourceMessage *discordgo.Message, mirroredMessage *discordgo.Message) error {
-	redis := cache.GetRedisClient()
-	key := m.getRememberedMessageKey(sourceMessage.ID)
-
-	item := new(Mirror_PostedMessage)
-	item.ChannelID = mirroredMessage.ChannelID
-	item.MessageID = mirroredMessage.ID
-
-	itemBytes, err := msgpack.Marshal(&item)
+func (m *Mirror) rememberPostedMessage(mirrorEntry models.MirrorEntry, sourceMessage *discordgo.Message, mirroredMessage *discordgo.Message, webhookID string) error {
+	sourceChannel, err := helpers.GetChannelWithoutApi(sourceMessage.ChannelID)
 	if err != nil {
 		return err
 	}
-
-	_, err = redis.LPush(key, itemBytes).Result()
+	mirroredChannel, err := helpers.GetChannelWithoutApi(mirroredMessage.ChannelID)
 	if err != nil {
 		return err
 	}
 
-	_, err = redis.Expire(key, time.Hour*1).Result()
+	_, err = helpers.MDbInsertWithoutLogging(models.MirrorMessagesTable, models.MirrorMessageEntry{
+		MirrorID:        mirrorEntry.ID,
+		SourceGuildID:   sourceChannel.GuildID,
+		SourceChannelID: sourceMessage.ChannelID,
+		SourceMessageID: sourceMessage.ID,
+		SourceAuthorID:  sourceMessage.Author.ID,
+		GuildID:         mirroredChannel.GuildID,
+		ChannelID:       mirroredMessage.ChannelID,
+		MessageID:       mirroredMessage.ID,
+		WebhookID:       webhookID,
+		CreatedAt:       time.Now(),
+	})
 	return err
 }
 
-func (m *Mirror) getRememberedMessages(sourceMessage *discordgo.Message) ([]Mirror_PostedMessage, error) {
-	redis := cache.GetRedisClient()
-	key := m.getRememberedMessageKey(sourceMessage.ID)
+func (m *Mirror) getRememberedMessages(mirrorEntry models.MirrorEntry, sourceMessageID string) (rememberedMessages []models.MirrorMessageEntry, err error) {
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.MirrorMessagesTable).Find(
+		bson.M{"mirrorid": mirrorEntry.ID, "sourcemessageid": sourceMessageID},
+	)).All(&rememberedMessages)
+	return rememberedMessages, err
+}
+
+func (m *Mirror) OnMessageUpdate(session *discordgo.Session, msg *discordgo.MessageUpdate) {
+	defer helpers.Recover()
 
-	length, err := redis.LLen(key).Result()
-	if err != nil {
-		return []Mirror_PostedMessage{}, err
+	// ignore updates without a new content, for example embed unfurls
+	if msg.Author == nil || msg.EditedTimestamp == "" {
+		return
 	}
 
-	if length <= 0 {
-		return []Mirror_PostedMessage{}, err
+	for _, mirror := range mirrors {
+		// link mirrors do not copy the message content
+		if mirror.Type == models.MirrorTypeLink {
+			continue
+		}
+
+		for _, mirrorChannel := range mirror.ConnectedChannels {
+			if mirrorChannel.ChannelID != msg.ChannelID {
+				continue
+			}
+
+			rememberedMessages, err := m.getRememberedMessages(mirror, msg.ID)
+			helpers.Relax(err)
+
+			for _, messageData := range rememberedMessages {
+				webhook, err := helpers.GetWebhook(messageData.GuildID, messageData.ChannelID)
+				if err != nil || webhook.ID != messageData.WebhookID {
+					continue
+				}
+
+				_, err = helpers.WebhookEditMessageWithResult(webhook.ID, webhook.Token, messageData.MessageID,
+					m.getReplyPrefix(mirror, msg.Message, messageData.ChannelID)+m.getMirrorContent(mirror, msg.Message))
+				if err != nil {
+					cache.GetLogger().WithFields(logrus.Fields{
+						"module":            "mirror",
+						"sourceChannelID":   msg.ChannelID,
+						"sourceMessageID":   msg.ID,
+						"sourceAuthorID":    msg.Author.ID,
+						"mirroredChannelID": messageData.ChannelID,
+						"mirroredMessageID": messageData.MessageID,
+					}).Warn(
+						"Editing mirrored message failed:", err.Error(),
+					)
+				}
+			}
+		}
 	}
+}
 
-	result, err := redis.LRange(key, 0, length-1).Result()
-	if err != nil {
-		return []Mirror_PostedMessage{}, err
+func (m *Mirror) OnReactionAdd(session *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	defer helpers.Recover()
+
+	// ignore our own reactions to prevent loops
+	if reaction.UserID == session.State.User.ID {
+		return
 	}
 
-	rememberedMessages := make([]Mirror_PostedMessage, 0)
-	for _, messageData := range result {
-		var message Mirror_PostedMessage
-		err = msgpack.Unmarshal([]byte(messageData), &message)
-		if err != nil {
+	for _, mirror := range mirrors {
+		if !mirror.Bidirectional {
+			continue
+		}
+
+		for _, mirrorChannel := range mirror.ConnectedChannels {
+			if mirrorChannel.ChannelID != reaction.ChannelID {
+				continue
+			}
+
+			for _, channelToReactToEntry := range mirror.ConnectedChannels {
+				if channelToReactToEntry.ChannelID == reaction.ChannelID {
+					continue
+				}
+
+				messageID := m.getCorrespondingMessageID(mirror, reaction.ChannelID, reaction.MessageID, channelToReactToEntry.ChannelID)
+				if messageID == "" {
+					continue
+				}
+
+				err := cache.GetSession().SessionForGuildS(channelToReactToEntry.GuildID).MessageReactionAdd(
+					channelToReactToEntry.ChannelID, messageID, reaction.Emoji.APIName())
+				if err != nil {
+					cache.GetLogger().WithFields(logrus.Fields{
+						"module":            "mirror",
+						"sourceChannelID":   reaction.ChannelID,
+						"sourceMessageID":   reaction.MessageID,
+						"mirroredChannelID": channelToReactToEntry.ChannelID,
+						"mirroredMessageID": messageID,
+					}).Warn(
+						"Adding reaction to mirrored message failed:", err.Error(),
+					)
+				}
+			}
+		}
+	}
+}
+
+// OnReactionRemove removes the mirrored reaction once nobody on the source message reacts with the emoji anymore
+func (m *Mirror) OnReactionRemove(session *discordgo.Session, reaction *discordgo.MessageReactionRemove) {
+	defer helpers.Recover()
+
+	// ignore our own reactions to prevent loops
+	if reaction.UserID == session.State.User.ID {
+		return
+	}
+
+	for _, mirror := range mirrors {
+		if !mirror.Bidirectional {
 			continue
 		}
-		rememberedMessages = append(rememberedMessages, message)
+
+		for _, mirrorChannel := range mirror.ConnectedChannels {
+			if mirrorChannel.ChannelID != reaction.ChannelID {
+				continue
+			}
+
+			sourceMessage, err := session.ChannelMessage(reaction.ChannelID, reaction.MessageID)
+			if err != nil {
+				helpers.RelaxLog(err)
+				return
+			}
+			if mirrorReactionRemaining(sourceMessage, reaction.Emoji) {
+				return
+			}
+
+			for _, channelToReactToEntry := range mirror.ConnectedChannels {
+				if channelToReactToEntry.ChannelID == reaction.ChannelID {
+					continue
+				}
+
+				messageID := m.getCorrespondingMessageID(mirror, reaction.ChannelID, reaction.MessageID, channelToReactToEntry.ChannelID)
+				if messageID == "" {
+					continue
+				}
+
+				err = cache.GetSession().SessionForGuildS(channelToReactToEntry.GuildID).MessageReactionRemove(
+					channelToReactToEntry.ChannelID, messageID, reaction.Emoji.APIName(), "@me")
+				if err != nil {
+					cache.GetLogger().WithFields(logrus.Fields{
+						"module":            "mirror",
+						"sourceChannelID":   reaction.ChannelID,
+						"sourceMessageID":   reaction.MessageID,
+						"mirroredChannelID": channelToReactToEntry.ChannelID,
+						"mirroredMessageID": messageID,
+					}).Warn(
+						"Removing reaction from mirrored message failed:", err.Error(),
+					)
+				}
+			}
+		}
 	}
+}
+
+// mirrorReactionRemaining returns true if someone besides the bot still reacts to the message with the emoji
+func mirrorReactionRemaining(message *discordgo.Message, emoji discordgo.Emoji) bool {
+	for _, messageReaction := range message.Reactions {
+		if messageReaction.Emoji == nil || messageReaction.Emoji.APIName() != emoji.APIName() {
+			continue
+		}
 
-	return rememberedMessages, nil
+		count := messageReaction.Count
+		if messageReaction.Me {
+			count--
+		}
+		return count > 0
+	}
+	return false
 }
 
 func (m *Mirror) OnMessageDelete(session *discordgo.Session, msg *discordgo.MessageDelete) {
 	defer helpers.Recover()
 
 	var err error
-	var rememberedMessages []Mirror_PostedMessage
+	var rememberedMessages []models.MirrorMessageEntry
 
 	for _, mirror := range mirrors {
 		for _, mirrorChannel := range mirror.ConnectedChannels {
 			if mirrorChannel.ChannelID == msg.ChannelID {
-				rememberedMessages, err = m.getRememberedMessages(msg.Message)
+				rememberedMessages, err = m.getRememberedMessages(mirror, msg.ID)
 				helpers.Relax(err)
 
 				for _, messageData := range rememberedMessages {
@@ -528,7 +882,7 @@ func (m *Mirror) OnMessageDelete(session *discordgo.Session, msg *discordgo.Mess
 							"module":            "mirror",
 							"sourceChannelID":   msg.ChannelID,
 							"sourceMessageID":   msg.ID,
-							"sourceAuthorID":    msg.Author.ID,
+							"sourceAuthorID":    messageData.SourceAuthorID,
 							"mirroredChannelID": messageData.ChannelID,
 							"mirroredMessageID": messageData.MessageID,
 						}).Warn(
@@ -536,6 +890,12 @@ func (m *Mirror) OnMessageDelete(session *discordgo.Session, msg *discordgo.Mess
 						)
 					}
 				}
+
+				if len(rememberedMessages) > 0 {
+					_, err = helpers.MdbCollection(models.MirrorMessagesTable).RemoveAll(
+						bson.M{"mirrorid": mirror.ID, "sourcemessageid": msg.ID})
+					helpers.RelaxLog(err)
+				}
 			}
 		}
 	}
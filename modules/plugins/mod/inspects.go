@@ -98,7 +98,7 @@ func (m *Mod) inspectsUserGotBannedOnGuild(bannedUser *discordgo.GuildBanAdd) er
 
 					var labelText string
 					if joins[0].VanityInviteUsedName != "" {
-						labelText = " (`" + helpers.GetConfig().Path("website.vanityurl_domain").Data().(string) + "/" + joins[0].VanityInviteUsedName + "`)"
+						labelText = " (`" + helpers.GetConfig().Website.VanityURLDomain + "/" + joins[0].VanityInviteUsedName + "`)"
 					}
 
 					joinsText = fmt.Sprintf(":white_check_mark: User joined this server once (%s) with the invite `%s`%s created by `%s (#%s)` %s\n",
@@ -122,7 +122,7 @@ func (m *Mod) inspectsUserGotBannedOnGuild(bannedUser *discordgo.GuildBanAdd) er
 
 					var labelText string
 					if lastJoin.VanityInviteUsedName != "" {
-						labelText = " (`" + helpers.GetConfig().Path("website.vanityurl_domain").Data().(string) + "/" + joins[0].VanityInviteUsedName + "`)"
+						labelText = " (`" + helpers.GetConfig().Website.VanityURLDomain + "/" + joins[0].VanityInviteUsedName + "`)"
 					}
 
 					joinsText = fmt.Sprintf(":warning: User joined this server %d times (last time %s)\n"+
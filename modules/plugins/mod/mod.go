@@ -719,7 +719,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 				totalChannels += len(guild.Channels)
 				totalMembers += len(users)
 			}
-			resultText += fmt.Sprintf("Total Stats: Servers `%d`, Channels: `%d`, Members: `%d`", len(helpers.AllGuilds()), totalChannels, totalMembers)
+			resultText += fmt.Sprintf("Total Stats: Servers `%d`, Channels: `%d`, Members: `%d`", helpers.GuildCount(), totalChannels, totalMembers)
 
 			for _, resultPage := range helpers.Pagify(resultText, "\n") {
 				_, err := helpers.SendMessage(msg.ChannelID, resultPage)
@@ -1023,7 +1023,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 			Description: helpers.GetText("plugins.mod.inspect-in-progress"),
 			URL:         helpers.GetAvatarUrl(targetUser),
 			Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: helpers.GetAvatarUrl(targetUser)},
-			Footer:      &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.mod.inspect-embed-footer", targetUser.ID, len(helpers.AllGuilds()))},
+			Footer:      &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.mod.inspect-embed-footer", targetUser.ID, helpers.GuildCount())},
 			Color:       0x0FADED,
 		}
 		var resultMessages []*discordgo.Message
@@ -1054,10 +1054,10 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 
 		resultBansText := ""
 		if len(bannedOnServerList) <= 0 {
-			resultBansText += fmt.Sprintf(":white_check_mark: User is banned on none servers.\n:black_medium_small_square:Checked %d servers.\n", len(helpers.AllGuilds())-len(checkFailedServerList))
+			resultBansText += fmt.Sprintf(":white_check_mark: User is banned on none servers.\n:black_medium_small_square:Checked %d servers.\n", helpers.GuildCount()-len(checkFailedServerList))
 		} else {
 			if isExtendedInspect == false {
-				resultBansText += fmt.Sprintf(":warning: User is banned on **%d** servers.\n:black_medium_small_square:Checked %d servers.\n", len(bannedOnServerList), len(helpers.AllGuilds())-len(checkFailedServerList))
+				resultBansText += fmt.Sprintf(":warning: User is banned on **%d** servers.\n:black_medium_small_square:Checked %d servers.\n", len(bannedOnServerList), helpers.GuildCount()-len(checkFailedServerList))
 			} else {
 				resultBansText += fmt.Sprintf(":warning: User is banned on **%d** servers:\n", len(bannedOnServerList))
 				i := 0
@@ -1070,7 +1070,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 						break BannedOnLoop
 					}
 				}
-				resultBansText += fmt.Sprintf(":black_medium_small_square:Checked %d servers.\n", len(helpers.AllGuilds())-len(checkFailedServerList))
+				resultBansText += fmt.Sprintf(":black_medium_small_square:Checked %d servers.\n", helpers.GuildCount()-len(checkFailedServerList))
 			}
 		}
 
@@ -1123,7 +1123,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 
 				var labelText string
 				if joins[0].VanityInviteUsedName != "" {
-					labelText = " (`" + helpers.GetConfig().Path("website.vanityurl_domain").Data().(string) + "/" + joins[0].VanityInviteUsedName + "`)"
+					labelText = " (`" + helpers.GetConfig().Website.VanityURLDomain + "/" + joins[0].VanityInviteUsedName + "`)"
 				}
 
 				joinsText = fmt.Sprintf(":white_check_mark: User joined this server once (%s) with the invite `%s`%s created by `%s (#%s)` %s\n",
@@ -1147,7 +1147,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 
 				var labelText string
 				if lastJoin.VanityInviteUsedName != "" {
-					labelText = " (`" + helpers.GetConfig().Path("website.vanityurl_domain").Data().(string) + "/" + joins[0].VanityInviteUsedName + "`)"
+					labelText = " (`" + helpers.GetConfig().Website.VanityURLDomain + "/" + joins[0].VanityInviteUsedName + "`)"
 				}
 
 				joinsText = fmt.Sprintf(":warning: User joined this server %d times (last time %s)\n"+
@@ -1215,7 +1215,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 				chooseEmbed := &discordgo.MessageEmbed{
 					Title:       fmt.Sprintf("@%s Enable Auto Inspect Triggers", msg.Author.Username),
 					Description: "**Please wait a second...** :construction_site:",
-					Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Robyul is currently on %d servers.", len(helpers.AllGuilds()))},
+					Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Robyul is currently on %d servers.", helpers.GuildCount())},
 					Color:       0x0FADED,
 				}
 				chooseMessages, err := helpers.SendEmbed(msg.ChannelID, chooseEmbed)
@@ -1479,16 +1479,7 @@ func (m *Mod) Action(command string, content string, msg *discordgo.Message, ses
 				usersMatched := make([]*discordgo.User, 0)
 				for _, serverGuild := range helpers.AllGuilds() {
 					if serverGuild.ID == currentChannel.GuildID {
-						members := make([]*discordgo.Member, 0)
-						for _, botGuild := range session.State.Guilds {
-							if botGuild.ID == serverGuild.ID {
-								for _, member := range botGuild.Members {
-									members = append(members, member)
-								}
-							}
-						}
-
-						for _, serverMember := range members {
+						for _, serverMember := range serverGuild.Members {
 							fullUserNameToSearch := serverMember.User.Username + "#" + serverMember.User.Discriminator + " ~ " + serverMember.Nick + " ~ " + serverMember.User.ID
 							if fuzzy.MatchFold(searchText, fullUserNameToSearch) {
 								userIsAlreadyInList := false
@@ -2360,15 +2351,15 @@ func (m *Mod) OnGuildMemberAdd(member *discordgo.Member, session *discordgo.Sess
 							"\n_inspected because User joined this Server._",
 						URL:       helpers.GetAvatarUrl(member.User),
 						Thumbnail: &discordgo.MessageEmbedThumbnail{URL: helpers.GetAvatarUrl(member.User)},
-						Footer:    &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.mod.inspect-embed-footer", member.User.ID, len(helpers.AllGuilds()))},
+						Footer:    &discordgo.MessageEmbedFooter{Text: helpers.GetTextF("plugins.mod.inspect-embed-footer", member.User.ID, helpers.GuildCount())},
 						Color:     0x0FADED,
 					}
 
 					resultBansText := ""
 					if len(bannedOnServerList) <= 0 {
-						resultBansText += fmt.Sprintf(":white_check_mark: User is banned on none servers.\n:black_medium_small_square:Checked %d servers.", len(helpers.AllGuilds())-len(checkFailedServerList))
+						resultBansText += fmt.Sprintf(":white_check_mark: User is banned on none servers.\n:black_medium_small_square:Checked %d servers.", helpers.GuildCount()-len(checkFailedServerList))
 					} else {
-						resultBansText += fmt.Sprintf(":warning: User is banned on **%d** server(s).\n:black_medium_small_square:Checked %d servers.", len(bannedOnServerList), len(helpers.AllGuilds())-len(checkFailedServerList))
+						resultBansText += fmt.Sprintf(":warning: User is banned on **%d** server(s).\n:black_medium_small_square:Checked %d servers.", len(bannedOnServerList), helpers.GuildCount()-len(checkFailedServerList))
 					}
 
 					commonGuildsText := ""
@@ -2400,7 +2391,7 @@ func (m *Mod) OnGuildMemberAdd(member *discordgo.Member, session *discordgo.Sess
 
 							var labelText string
 							if joins[0].VanityInviteUsedName != "" {
-								labelText = " (`" + helpers.GetConfig().Path("website.vanityurl_domain").Data().(string) + "/" + joins[0].VanityInviteUsedName + "`)"
+								labelText = " (`" + helpers.GetConfig().Website.VanityURLDomain + "/" + joins[0].VanityInviteUsedName + "`)"
 							}
 
 							joinsText = fmt.Sprintf(":white_check_mark: User joined this server once with the invite `%s`%s created by `%s (#%s)` %s\n",
@@ -2423,7 +2414,7 @@ func (m *Mod) OnGuildMemberAdd(member *discordgo.Member, session *discordgo.Sess
 
 							var labelText string
 							if lastJoin.VanityInviteUsedName != "" {
-								labelText = " (`" + helpers.GetConfig().Path("website.vanityurl_domain").Data().(string) + "/" + joins[0].VanityInviteUsedName + "`)"
+								labelText = " (`" + helpers.GetConfig().Website.VanityURLDomain + "/" + joins[0].VanityInviteUsedName + "`)"
 							}
 
 							joinsText = fmt.Sprintf(":warning: User joined this server %d times\nLast time with the invite `%s`%s created by `%s (#%s)` %s\n",
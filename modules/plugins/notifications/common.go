@@ -108,11 +108,12 @@ func refreshNotificationSettingsCache() (err error) {
 	return nil
 }
 
+// asyncRefresh reloads the notification settings in all processes
 func asyncRefresh() {
 	go func() {
 		defer helpers.Recover()
 
-		err := refreshNotificationSettingsCache()
+		err := helpers.InvalidateCache(notificationsCacheName)
 		helpers.RelaxLog(err)
 	}()
 }
@@ -31,6 +31,7 @@ func (m *Handler) Commands() []string {
 
 func (m *Handler) Init(session *shardmanager.Manager) {
 	session.AddHandler(m.OnMessage)
+	helpers.OnCacheInvalidation(notificationsCacheName, refreshNotificationSettingsCache)
 	go func() {
 		defer helpers.Recover()
 
@@ -103,12 +104,7 @@ func (m *Handler) Action(command string, content string, msg *discordgo.Message,
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			cache.GetLogger().WithField("module", "notifications").Info(fmt.Sprintf("Added Notification Keyword \"%s\" to Guild %s (#%s) for User %s (#%s)", keywords, guild.Name, guild.ID, msg.Author.Username, msg.Author.ID))
 			session.ChannelMessageDelete(msg.ChannelID, msg.ID) // Do not get error as it might fail because deletion permissions are not given to the user
-			go func() {
-				defer helpers.Recover()
-
-				err := refreshNotificationSettingsCache()
-				helpers.RelaxLog(err)
-			}()
+			asyncRefresh()
 		case "delete", "del", "remove": // [p]notifications delete <keyword(s)>
 			channel, err := helpers.GetChannel(msg.ChannelID)
 			helpers.Relax(err)
@@ -147,12 +143,7 @@ func (m *Handler) Action(command string, content string, msg *discordgo.Message,
 			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 			cache.GetLogger().WithField("module", "notifications").Info(fmt.Sprintf("Deleted Notification Keyword \"%s\" from Guild %s (#%s) for User %s (#%s)", entryBucket.Keyword, guild.Name, guild.ID, msg.Author.Username, msg.Author.ID))
 			session.ChannelMessageDelete(msg.ChannelID, msg.ID) // Do not get error as it might fail because deletion permissions are not given to the user
-			go func() {
-				defer helpers.Recover()
-
-				err := refreshNotificationSettingsCache()
-				helpers.RelaxLog(err)
-			}()
+			asyncRefresh()
 		case "list": // [p]notifications list
 			handleList(session, msg)
 			return
@@ -235,12 +226,7 @@ func (m *Handler) Action(command string, content string, msg *discordgo.Message,
 
 						helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.notifications.ignore-channel-add-success", targetChannel.ID))
 
-						go func() {
-							defer helpers.Recover()
-
-							err := refreshNotificationSettingsCache()
-							helpers.RelaxLog(err)
-						}()
+						asyncRefresh()
 						return
 					}
 					helpers.Relax(err)
@@ -264,12 +250,7 @@ func (m *Handler) Action(command string, content string, msg *discordgo.Message,
 
 					helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.notifications.ignore-channel-remove-success", targetChannel.ID))
 
-					go func() {
-						defer helpers.Recover()
-
-						err := refreshNotificationSettingsCache()
-						helpers.RelaxLog(err)
-					}()
+					asyncRefresh()
 				})
 			}
 		case "toggle-mode", "toggle-modes", "toggle-layout", "toggle-layouts":
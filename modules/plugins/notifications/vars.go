@@ -27,4 +27,7 @@ var (
 
 const (
 	UserConfigNotificationsLayoutModeKey = "notifications:layout-mode"
+
+	// notificationsCacheName is used to reload the notification settings in all processes
+	notificationsCacheName = "notifications"
 )
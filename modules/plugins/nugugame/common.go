@@ -93,7 +93,7 @@ func loadMiscImages() {
 		"shadow-border.png",
 	}
 
-	miscImagesFolderPath := helpers.GetConfig().Path("assets_folder").Data().(string) + "biasgame/misc/"
+	miscImagesFolderPath := helpers.GetConfig().AssetsFolder + "biasgame/misc/"
 
 	// load misc images
 	for _, fileName := range validMiscImages {
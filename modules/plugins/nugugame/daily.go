@@ -0,0 +1,358 @@
+package nugugame
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	DAILY_NUGUGAME_SEQUENCE_KEY = "dailySequence"
+	DAILY_NUGUGAME_DIFFICULTY   = "medium"
+)
+
+// getDailyDay returns the day of the daily challenge for the given time
+func getDailyDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// makeDailySequence shuffles the idols with the day as seed, the result is the same for the same idols and day
+func makeDailySequence(idolIds []string, day time.Time) []string {
+	sortedIds := make([]string, len(idolIds))
+	copy(sortedIds, idolIds)
+	sort.Strings(sortedIds)
+
+	sequence := make([]string, len(sortedIds))
+	random := rand.New(rand.NewSource(day.Unix()))
+	for i, randomIndex := range random.Perm(len(sortedIds)) {
+		sequence[i] = sortedIds[randomIndex]
+	}
+	return sequence
+}
+
+// getDailySequence returns the idols of the daily challenge for a day
+func getDailySequence(day time.Time) []bson.ObjectId {
+	cacheKey := DAILY_NUGUGAME_SEQUENCE_KEY + day.Format("2006-01-02")
+
+	// the sequence is saved the first time it is used, so refreshing the difficulties during the day doesn't change it
+	var sequence []string
+	if err := getModuleCache(cacheKey, &sequence); err != nil || len(sequence) == 0 {
+		var idolIds []string
+		for _, idolId := range getNugugameIdolsByDifficulty(DAILY_NUGUGAME_DIFFICULTY) {
+			if idol := idols.GetMatchingIdolById(bson.ObjectIdHex(idolId)); idol != nil && !idol.Deleted {
+				idolIds = append(idolIds, idolId)
+			}
+		}
+		if len(idolIds) == 0 {
+			return nil
+		}
+
+		// another instance could be saving the sequence at the same time, only the first one is kept
+		marshaledSequence, err := json.Marshal(makeDailySequence(idolIds, day))
+		helpers.Relax(err)
+		_, err = cache.GetRedisClient().SetNX(fmt.Sprintf("robyul2-discord:nugugame:%s", cacheKey), marshaledSequence, time.Hour*48).Result()
+		helpers.Relax(err)
+
+		err = getModuleCache(cacheKey, &sequence)
+		helpers.Relax(err)
+	}
+
+	var sequenceIds []bson.ObjectId
+	for _, idolId := range sequence {
+		sequenceIds = append(sequenceIds, bson.ObjectIdHex(idolId))
+	}
+	return sequenceIds
+}
+
+// getNextDailyIdol returns the first idol of the daily sequence the game didn't have yet
+func (g *nuguGame) getNextDailyIdol() *idols.Idol {
+	usedIdols := make(map[bson.ObjectId]bool)
+	for _, idol := range append(g.CorrectIdols, g.IncorrectIdols...) {
+		usedIdols[idol.ID] = true
+	}
+
+	for _, idolId := range g.DailySequence {
+		if usedIdols[idolId] {
+			continue
+		}
+
+		// idols deleted since the sequence was made are skipped for everyone
+		if idol := idols.GetMatchingIdolById(idolId); idol != nil && !idol.Deleted {
+			return idol
+		}
+	}
+	return nil
+}
+
+// startDailyNuguGame starts the daily challenge or resumes it from cache
+func startDailyNuguGame(msg *discordgo.Message, commandArgs []string) {
+
+	// check for daily sub commands
+	if len(commandArgs) > 1 {
+		switch commandArgs[1] {
+		case "rankings", "ranking", "ranks", "rank", "leaderboard":
+			displayDailyRanking(msg, commandArgs[2:])
+		case "streak", "streaks":
+			displayDailyStreak(msg, commandArgs[2:])
+		default:
+			helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+		}
+		return
+	}
+
+	// if the user already has a game, do nothing
+	if game := getNuguGameByUserID(msg.Author.ID); game != nil {
+		helpers.SendMessage(msg.ChannelID, "You already have a current nugu game running, you must finish it before starting a new one.")
+		return
+	}
+
+	// if the channel already has a game, do nothing
+	if game := getNuguGamesByChannelID(msg.ChannelID); game != nil {
+		helpers.SendMessage(msg.ChannelID, "Only one running nugu game is allowed per channel.")
+		return
+	}
+
+	day := getDailyDay(time.Now())
+
+	// resume the daily challenge if the user was playing it before a restart
+	if game := getCachedSingleGame(msg.Author.ID); game != nil && game.IsDaily && game.Day.Equal(day) {
+		game.start(msg)
+		return
+	}
+
+	// every user only gets one try per day
+	var dailyEntry models.NuguGameDailyEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.NuguGameDailyTable).Find(bson.M{"userid": msg.Author.ID, "day": day}),
+		&dailyEntry,
+	)
+	if err == nil {
+		helpers.SendMessage(msg.ChannelID, fmt.Sprintf("You already played today's daily challenge, your score was **%d**. A new challenge starts %s.",
+			dailyEntry.Score, humanize.Time(day.Add(24*time.Hour))))
+		return
+	}
+	if !helpers.IsMdbNotFound(err) {
+		helpers.Relax(err)
+	}
+
+	sequence := getDailySequence(day)
+	if len(sequence) == 0 {
+		helpers.SendMessage(msg.ChannelID, "The daily challenge is not available right now, please try again later.")
+		return
+	}
+
+	_, err = helpers.MDbInsertWithoutLogging(models.NuguGameDailyTable, models.NuguGameDailyEntry{
+		UserID:  msg.Author.ID,
+		GuildID: msg.GuildID,
+		Day:     day,
+	})
+	helpers.Relax(err)
+
+	game := &nuguGame{
+		Gender:          "mixed",
+		WaitingForGuess: false,
+		GameType:        "idol",
+		Difficulty:      DAILY_NUGUGAME_DIFFICULTY,
+		LivesRemaining:  difficultyLives[DAILY_NUGUGAME_DIFFICULTY],
+		GuildID:         msg.GuildID,
+		IsDaily:         true,
+		Day:             day,
+		DailySequence:   sequence,
+	}
+	game.start(msg)
+}
+
+// finishDailyNuguGame saves the score of the daily challenge and updates the streak of the user
+func finishDailyNuguGame(g *nuguGame) {
+	defer helpers.Recover()
+
+	err := helpers.MdbCollection(models.NuguGameDailyTable).Update(
+		bson.M{"userid": g.User.ID, "day": g.Day},
+		bson.M{"$set": bson.M{"score": len(g.CorrectIdols), "finished": true}},
+	)
+	if err != nil && !helpers.IsMdbNotFound(err) {
+		helpers.Relax(err)
+	}
+
+	var streakEntry models.NuguGameStreakEntry
+	err = helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.NuguGameStreaksTable).Find(bson.M{"userid": g.User.ID}),
+		&streakEntry,
+	)
+	if err != nil && !helpers.IsMdbNotFound(err) {
+		helpers.Relax(err)
+	}
+
+	streakEntry = getNextStreak(streakEntry, g.Day)
+	err = helpers.MDbUpsertWithoutLogging(models.NuguGameStreaksTable, bson.M{"userid": g.User.ID}, bson.M{"$set": bson.M{
+		"currentstreak": streakEntry.CurrentStreak,
+		"longeststreak": streakEntry.LongestStreak,
+		"lastday":       streakEntry.LastDay,
+	}})
+	helpers.Relax(err)
+
+	helpers.SendMessage(g.ChannelID, fmt.Sprintf("**@%s** Daily challenge done! Current streak: **%d** day(s), longest streak: **%d** day(s).",
+		g.User.Username, streakEntry.CurrentStreak, streakEntry.LongestStreak))
+}
+
+// getNextStreak returns the streak after playing the daily challenge of the given day
+func getNextStreak(streak models.NuguGameStreakEntry, day time.Time) models.NuguGameStreakEntry {
+
+	// challenges of a day already counted don't change the streak
+	if !streak.LastDay.IsZero() && !day.After(streak.LastDay) {
+		return streak
+	}
+
+	if !streak.LastDay.IsZero() && streak.LastDay.Add(24*time.Hour).Equal(day) {
+		streak.CurrentStreak++
+	} else {
+		streak.CurrentStreak = 1
+	}
+	if streak.CurrentStreak > streak.LongestStreak {
+		streak.LongestStreak = streak.CurrentStreak
+	}
+	streak.LastDay = day
+
+	return streak
+}
+
+// getCurrentStreak returns the streak a user has right now, streaks are lost after missing a day
+func getCurrentStreak(streak models.NuguGameStreakEntry, today time.Time) int {
+	if streak.LastDay.IsZero() || streak.LastDay.Add(24*time.Hour).Before(today) {
+		return 0
+	}
+	return streak.CurrentStreak
+}
+
+// displayDailyRanking sends embed of the scores of todays daily challenge on the server or globally
+func displayDailyRanking(msg *discordgo.Message, commandArgs []string) {
+	cache.GetSession().SessionForGuildS(msg.GuildID).ChannelTyping(msg.ChannelID)
+
+	day := getDailyDay(time.Now())
+	query := bson.M{"day": day, "finished": true, "guildid": msg.GuildID}
+
+	embedTitle := "Nugu Game Daily Challenge - Server Rankings"
+	var embedIcon string
+	if guild, err := helpers.GetGuild(msg.GuildID); err == nil {
+		embedIcon = guild.IconURL()
+	}
+
+	// check arguments
+	for _, arg := range commandArgs {
+		if arg == "global" {
+			delete(query, "guildid")
+			embedTitle = "Nugu Game Daily Challenge - Global Rankings"
+			embedIcon = cache.GetSession().SessionForGuildS(msg.GuildID).State.User.AvatarURL("512")
+			continue
+		}
+
+		// if a arg was passed that didn't match any check, send invalid args message
+		helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+		return
+	}
+
+	var dailyEntries []models.NuguGameDailyEntry
+	err := helpers.MDbIter(helpers.MdbCollection(models.NuguGameDailyTable).Find(query).Sort("-score").Limit(50)).All(&dailyEntries)
+	helpers.Relax(err)
+	if len(dailyEntries) == 0 {
+		helpers.SendMessage(msg.ChannelID, "Nobody finished today's daily challenge yet.")
+		return
+	}
+
+	// get streaks of all ranked users
+	var userIds []string
+	for _, dailyEntry := range dailyEntries {
+		userIds = append(userIds, dailyEntry.UserID)
+	}
+	var streakEntries []models.NuguGameStreakEntry
+	err = helpers.MDbIter(helpers.MdbCollection(models.NuguGameStreaksTable).Find(bson.M{"userid": bson.M{"$in": userIds}})).All(&streakEntries)
+	helpers.Relax(err)
+	streaks := make(map[string]int)
+	for _, streakEntry := range streakEntries {
+		streaks[streakEntry.UserID] = getCurrentStreak(streakEntry, day)
+	}
+
+	// create embed
+	embed := &discordgo.MessageEmbed{
+		Color: 0x0FADED, // blueish
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    embedTitle,
+			IconURL: embedIcon,
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Challenge of %s (UTC)", day.Format("January 2, 2006")),
+		},
+	}
+
+	for i, dailyEntry := range dailyEntries {
+
+		displayName := "*Unknown*"
+		if user, err := helpers.GetUser(dailyEntry.UserID); err == nil {
+			displayName = user.Username
+		}
+		if len(displayName) > 25 {
+			displayName = displayName[0:25] + "..."
+		}
+
+		embed.Fields = append(embed.Fields, []*discordgo.MessageEmbedField{
+			{
+				Name:   fmt.Sprintf("Rank #%d", i+1),
+				Value:  displayName,
+				Inline: true,
+			},
+			{
+				Name:   "Score",
+				Value:  humanize.Comma(int64(dailyEntry.Score)),
+				Inline: true,
+			},
+			{
+				Name:   "Streak",
+				Value:  fmt.Sprintf("%d day(s)", streaks[dailyEntry.UserID]),
+				Inline: true,
+			},
+		}...)
+	}
+
+	helpers.SendPagedMessage(msg, embed, 21)
+}
+
+// displayDailyStreak sends the daily challenge streak of a user
+func displayDailyStreak(msg *discordgo.Message, commandArgs []string) {
+	targetUser := msg.Author
+	for _, arg := range commandArgs {
+		user, err := helpers.GetUserFromMention(arg)
+		if err != nil {
+			helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+			return
+		}
+		targetUser = user
+	}
+
+	var streakEntry models.NuguGameStreakEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.NuguGameStreaksTable).Find(bson.M{"userid": targetUser.ID}),
+		&streakEntry,
+	)
+	if err != nil && !helpers.IsMdbNotFound(err) {
+		helpers.Relax(err)
+	}
+
+	day := getDailyDay(time.Now())
+	playedToday := "no"
+	if streakEntry.LastDay.Equal(day) {
+		playedToday = "yes"
+	}
+
+	helpers.SendMessage(msg.ChannelID, fmt.Sprintf("**@%s** Current daily streak: **%d** day(s)\nLongest streak: **%d** day(s)\nPlayed today: %s",
+		targetUser.Username, getCurrentStreak(streakEntry, day), streakEntry.LongestStreak, playedToday))
+}
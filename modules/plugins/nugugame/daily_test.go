@@ -0,0 +1,89 @@
+package nugugame
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/models"
+)
+
+func TestMakeDailySequence(t *testing.T) {
+	day := getDailyDay(time.Date(2018, 6, 1, 15, 30, 0, 0, time.UTC))
+	idolIds := []string{"a", "b", "c", "d", "e", "f"}
+
+	sequence := makeDailySequence(idolIds, day)
+	if len(sequence) != len(idolIds) {
+		t.Fatal("sequence should contain every idol once")
+	}
+
+	// the order of the given idols must not matter
+	shuffledSequence := makeDailySequence([]string{"f", "e", "d", "c", "b", "a"}, day)
+	for i := range sequence {
+		if sequence[i] != shuffledSequence[i] {
+			t.Fatal("sequences of the same day should be equal")
+		}
+	}
+
+	if idolIds[0] != "a" {
+		t.Fatal("given idols should not be changed")
+	}
+}
+
+func TestGetNextStreak(t *testing.T) {
+	day := getDailyDay(time.Date(2018, 6, 1, 15, 30, 0, 0, time.UTC))
+
+	streak := getNextStreak(models.NuguGameStreakEntry{}, day)
+	if streak.CurrentStreak != 1 || streak.LongestStreak != 1 || !streak.LastDay.Equal(day) {
+		t.Fatal("first daily challenge should start a streak")
+	}
+
+	if getNextStreak(streak, day).CurrentStreak != 1 {
+		t.Fatal("playing the same day again should not change the streak")
+	}
+
+	streak = getNextStreak(streak, day.Add(24*time.Hour))
+	if streak.CurrentStreak != 2 || streak.LongestStreak != 2 {
+		t.Fatal("playing the next day should continue the streak")
+	}
+
+	streak = getNextStreak(streak, day.Add(4*24*time.Hour))
+	if streak.CurrentStreak != 1 || streak.LongestStreak != 2 {
+		t.Fatal("missing a day should reset the current streak only")
+	}
+
+	if getCurrentStreak(streak, day.Add(6*24*time.Hour)) != 0 {
+		t.Fatal("streak should be lost after missing a day")
+	}
+	if getCurrentStreak(streak, day.Add(5*24*time.Hour)) != 1 {
+		t.Fatal("streak should be kept until the day after the last challenge")
+	}
+}
+
+func TestGetRankedPoints(t *testing.T) {
+	if getRankedPoints("medium", 0) != 40 {
+		t.Fatal("instant answers should give double points")
+	}
+	if getRankedPoints("medium", NUGUGAME_DEFULT_ROUND_DELAY*time.Second) != 20 {
+		t.Fatal("late answers should give the base points")
+	}
+	if getRankedPoints("koreaboo", time.Second) <= getRankedPoints("easy", time.Second) {
+		t.Fatal("harder difficulties should give more points")
+	}
+}
+
+func TestGetRankedRatingChanges(t *testing.T) {
+	ratings := map[string]float64{"a": 1000, "b": 1000, "c": 1000}
+
+	if len(getRankedRatingChanges(ratings, map[string]int{"a": 50})) != 0 {
+		t.Fatal("solo players should not change their rating")
+	}
+
+	changes := getRankedRatingChanges(ratings, map[string]int{"a": 100, "b": 50, "c": 10})
+	if changes["a"] <= 0 || changes["c"] >= 0 || math.Abs(changes["b"]) > 0.0001 {
+		t.Fatal("ratings should change by placement")
+	}
+	if math.Abs(changes["a"]+changes["b"]+changes["c"]) > 0.0001 {
+		t.Fatal("rating changes should add up to zero")
+	}
+}
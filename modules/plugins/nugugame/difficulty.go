@@ -32,6 +32,8 @@ var difficultyLives = map[string]int{
 	"hard":     5,
 	"koreaboo": 5,
 }
+var difficultyRefreshTimer *time.Timer
+var difficultyRefreshTimerMutex sync.Mutex
 var idolsByDifficultyMutex sync.RWMutex
 var idolsByDifficulty = map[string][]string{
 	"easy":     {},
@@ -56,6 +58,21 @@ func startDifficultyCacheLoop() {
 	}()
 }
 
+// scheduleDifficultyRefresh refreshes the difficulties a minute after the idols changed, more changes in that time are refreshed together
+func scheduleDifficultyRefresh() {
+	difficultyRefreshTimerMutex.Lock()
+	defer difficultyRefreshTimerMutex.Unlock()
+
+	if difficultyRefreshTimer != nil {
+		difficultyRefreshTimer.Stop()
+	}
+	difficultyRefreshTimer = time.AfterFunc(time.Minute, func() {
+		defer helpers.Recover()
+
+		refreshDifficulties()
+	})
+}
+
 // getIdolsByDifficulty will return the objectID hexs of all idols for a certain difficulty of the nugugame
 func getAllNugugameIdols() map[string][]string {
 	idolsByDifficultyMutex.RLock()
@@ -94,7 +111,7 @@ func refreshDifficulties() {
 	winCounts := make(map[string]int)
 	for _, game := range games {
 		idol := idols.GetMatchingIdolById(game.GameWinner)
-		if idol.Deleted == false && len(idol.Images) != 0 {
+		if idol != nil && idol.Deleted == false && len(idol.Images) != 0 {
 			winCounts[game.GameWinner.Hex()] += 1
 		}
 	}
@@ -5,8 +5,11 @@ import (
 	"regexp"
 
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
+	"github.com/globalsign/mgo"
 )
 
 // module struct
@@ -35,10 +38,17 @@ func (m *Module) Init(session *shardmanager.Manager) {
 
 		currentNuguGames = make(map[string]*nuguGame)
 
+		// ranked ratings are upserted, every user has one ranked entry
+		err = helpers.MdbCollection(models.NuguGameRankedTable).EnsureIndex(mgo.Index{Key: []string{"userid"}, Unique: true})
+		helpers.RelaxLog(err)
+
 		// start cache loops
 		startDifficultyCacheLoop()
 		startCacheRefreshLoop()
 
+		// refresh difficulties when idols are edited, merged or deleted
+		idols.OnIdolsChanged(scheduleDifficultyRefresh)
+
 		// load all images and information
 		loadMiscImages()
 	}()
@@ -98,6 +108,16 @@ func (m *Module) Action(command string, content string, msg *discordgo.Message,
 			case "server-rankings", "server-ranking", "server-ranks", "server-rank":
 				displayNugugameRanking(msg, commandArgs, true)
 
+			case "daily":
+				startDailyNuguGame(msg, commandArgs)
+
+			case "ranked":
+				if len(commandArgs) == 2 && (commandArgs[1] == "rankings" || commandArgs[1] == "ranking" || commandArgs[1] == "ranks" || commandArgs[1] == "rank") {
+					displayRankedRanking(msg)
+				} else {
+					startNuguGame(msg, commandArgs)
+				}
+
 			case "stats":
 				displayNuguGameStats(msg, commandArgs)
 
@@ -51,6 +51,7 @@ func startNuguGame(msg *discordgo.Message, commandArgs []string) {
 	gameType := "idol"
 	gameDifficulty := "medium"
 	lives := 3
+	isRanked := false
 
 	// validate game arguments and adjust game settings as needed
 	if len(commandArgs) > 0 {
@@ -66,6 +67,13 @@ func startNuguGame(msg *discordgo.Message, commandArgs []string) {
 				continue
 			}
 
+			// ranked games are always multi games
+			if arg == "ranked" {
+				isMulti = true
+				isRanked = true
+				continue
+			}
+
 			if arg == "group" {
 				gameType = "group"
 				continue
@@ -102,21 +110,30 @@ func startNuguGame(msg *discordgo.Message, commandArgs []string) {
 			Difficulty:      gameDifficulty,
 			LivesRemaining:  lives,
 			GuildID:         msg.GuildID,
+			IsRanked:        isRanked,
 		}
 	}
 
-	game.ChannelID = msg.ChannelID
-	game.User = msg.Author
-	game.GuessChannel = make(chan *discordgo.Message)
-	game.GuessTimeoutTimer = time.NewTimer(NUGUGAME_DEFULT_ROUND_DELAY * time.Second)
+	game.start(msg)
+}
 
-	if game.UsersCorrectGuesses == nil {
-		game.UsersCorrectGuesses = make(map[string][]bson.ObjectId)
+// start sets up the channel and timer of the game and sends the first round
+func (g *nuguGame) start(msg *discordgo.Message) {
+	g.ChannelID = msg.ChannelID
+	g.User = msg.Author
+	g.GuessChannel = make(chan *discordgo.Message)
+	g.GuessTimeoutTimer = time.NewTimer(NUGUGAME_DEFULT_ROUND_DELAY * time.Second)
+
+	if g.UsersCorrectGuesses == nil {
+		g.UsersCorrectGuesses = make(map[string][]bson.ObjectId)
+	}
+	if g.UsersPoints == nil {
+		g.UsersPoints = make(map[string]int)
 	}
 
-	game.saveGame()
-	game.sendRound()
-	game.watchForGuesses()
+	g.saveGame()
+	g.sendRound()
+	g.watchForGuesses()
 }
 
 // sendRound sends the next round in the game
@@ -170,7 +187,11 @@ func (g *nuguGame) sendRound() {
 	if g.GameType == "group" {
 		roundMessage = "What is the idols group name?"
 	}
-	if !g.IsMultigame {
+	if g.IsDaily {
+		roundMessage = fmt.Sprintf("**@%s** - Daily Challenge\nCurrent Score: %d\nLives Remaining: %d\n%s", g.User.Username, len(g.CorrectIdols), g.LivesRemaining, roundMessage)
+	} else if g.IsRanked {
+		roundMessage = fmt.Sprintf("**Ranked Multi Game**\nCurrent Score: %d\nLives Remaining: %d\n%s", len(g.CorrectIdols), g.LivesRemaining, roundMessage)
+	} else if !g.IsMultigame {
 		roundMessage = fmt.Sprintf("**@%s**\nCurrent Score: %d\nLives Remaining: %d\n%s", g.User.Username, len(g.CorrectIdols), g.LivesRemaining, roundMessage)
 	} else {
 		roundMessage = fmt.Sprintf("**Multi Game**\nCurrent Score: %d\nLives Remaining: %d\n%s", len(g.CorrectIdols), g.LivesRemaining, roundMessage)
@@ -188,6 +209,7 @@ func (g *nuguGame) sendRound() {
 	// update game state
 	g.WaitingForGuess = true
 	g.LastRoundMessage = fileSendMessage[0]
+	g.RoundStartTime = time.Now()
 
 	// clear timeout channel and reset timer
 	if !g.GuessTimeoutTimer.Stop() {
@@ -216,6 +238,13 @@ func (g *nuguGame) watchForGuesses() {
 					continue
 				}
 
+				// everyone who guessed is rated, even without points
+				if g.IsRanked {
+					if _, ok := g.UsersPoints[userMsg.Author.ID]; !ok {
+						g.UsersPoints[userMsg.Author.ID] = 0
+					}
+				}
+
 				// if guess is correct add green check mark too it, save the correct guess, and send next round
 				userGuess := strings.ToLower(alphaNumericRegex.ReplaceAllString(userMsg.Content, ""))
 
@@ -237,6 +266,10 @@ func (g *nuguGame) watchForGuesses() {
 
 							g.UsersCorrectGuesses[userMsg.Author.ID] = append(g.UsersCorrectGuesses[userMsg.Author.ID], currentIdol.ID)
 
+							if g.IsRanked {
+								g.UsersPoints[userMsg.Author.ID] += getRankedPoints(g.Difficulty, time.Since(g.RoundStartTime))
+							}
+
 						} else {
 
 							// if the user guessed nayoung correctly, add cute nayoung emoji instead of a checkmark
@@ -347,6 +380,13 @@ func (g *nuguGame) finishGame() {
 
 	helpers.SendMessage(g.ChannelID, finalMessage)
 	recordNuguGame(g)
+
+	if g.IsDaily {
+		finishDailyNuguGame(g)
+	}
+	if g.IsRanked {
+		finishRankedNuguGame(g)
+	}
 }
 
 // saveGame saves the nugu game to the current running games
@@ -374,6 +414,11 @@ func (g *nuguGame) getNewRandomIdol() *idols.Idol {
 	var idol *idols.Idol
 	var idolPool []*idols.Idol
 
+	// daily games use the same idols in the same order for everyone
+	if g.IsDaily {
+		return g.getNextDailyIdol()
+	}
+
 	idolIds := getNugugameIdolsByDifficulty(g.Difficulty)
 	if len(idolIds) > 0 {
 		for _, idolID := range idolIds {
@@ -541,6 +586,12 @@ func convertCachedNugugame(cachedNugugame nuguGameForCache) *nuguGame {
 		LivesRemaining:      cachedNugugame.LivesRemaining,
 		UsersCorrectGuesses: cachedNugugame.UsersCorrectGuesses,
 		GuildID:             cachedNugugame.GuildID,
+		IsDaily:             cachedNugugame.IsDaily,
+		Day:                 cachedNugugame.Day,
+		DailySequence:       cachedNugugame.DailySequence,
+		IsRanked:            cachedNugugame.IsRanked,
+		UsersPoints:         cachedNugugame.UsersPoints,
+		RoundStartTime:      cachedNugugame.RoundStartTime,
 	}
 
 	for _, idolId := range cachedNugugame.CorrectIdols {
@@ -589,6 +640,12 @@ func convertNugugameToCached(nugugames map[string]*nuguGame) map[string]nuguGame
 			CurrentIdolId:       game.CurrentIdol.ID,
 			UsersCorrectGuesses: game.UsersCorrectGuesses,
 			GuildID:             game.GuildID,
+			IsDaily:             game.IsDaily,
+			Day:                 game.Day,
+			DailySequence:       game.DailySequence,
+			IsRanked:            game.IsRanked,
+			UsersPoints:         game.UsersPoints,
+			RoundStartTime:      game.RoundStartTime,
 		}
 
 		for _, idol := range game.CorrectIdols {
@@ -0,0 +1,212 @@
+package nugugame
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	// RANKED_BASE_RATING is the rating of users without ranked games
+	RANKED_BASE_RATING = 1000.0
+	// RANKED_K is the most rating points a user can win or lose in one game
+	RANKED_K = 32.0
+)
+
+// points for a correct guess, answering right away gives up to double the points
+var rankedDifficultyPoints = map[string]int{
+	"easy":     10,
+	"medium":   20,
+	"hard":     30,
+	"koreaboo": 40,
+}
+
+// rankedRatingsMutex makes sure the ranked games of this process are recorded one after another
+var rankedRatingsMutex sync.Mutex
+
+// getRankedPoints returns the points for a correct guess after the given time
+func getRankedPoints(difficulty string, answerTime time.Duration) int {
+	basePoints := rankedDifficultyPoints[difficulty]
+
+	roundTime := NUGUGAME_DEFULT_ROUND_DELAY * time.Second
+	speed := 1 - float64(answerTime)/float64(roundTime)
+	speed = math.Max(0, math.Min(1, speed))
+
+	return basePoints + int(math.Round(float64(basePoints)*speed))
+}
+
+// getRankedRatingChanges compares the points of every player with every other player and returns the rating changes
+func getRankedRatingChanges(ratings map[string]float64, points map[string]int) map[string]float64 {
+	changes := make(map[string]float64)
+	if len(points) < 2 {
+		return changes
+	}
+
+	// the k factor is split between all opponents so big games don't change ratings more than small ones
+	k := RANKED_K / float64(len(points)-1)
+	for userID, userPoints := range points {
+		for opponentID, opponentPoints := range points {
+			if userID == opponentID {
+				continue
+			}
+
+			score := 0.5
+			if userPoints > opponentPoints {
+				score = 1
+			} else if userPoints < opponentPoints {
+				score = 0
+			}
+
+			expectedScore := 1 / (1 + math.Pow(10, (ratings[opponentID]-ratings[userID])/400))
+			changes[userID] += k * (score - expectedScore)
+		}
+	}
+
+	return changes
+}
+
+// finishRankedNuguGame updates the ratings of everyone who guessed in a ranked game and sends the results
+func finishRankedNuguGame(g *nuguGame) {
+	defer helpers.Recover()
+
+	if len(g.UsersPoints) == 0 {
+		return
+	}
+
+	rankedRatingsMutex.Lock()
+	defer rankedRatingsMutex.Unlock()
+
+	ratings := make(map[string]float64)
+	for userID := range g.UsersPoints {
+		rating, err := getRankedRating(userID)
+		helpers.Relax(err)
+		ratings[userID] = rating
+	}
+
+	changes := getRankedRatingChanges(ratings, g.UsersPoints)
+	for userID, points := range g.UsersPoints {
+		newRating, err := updateRankedRating(userID, changes[userID], points)
+		helpers.Relax(err)
+		ratings[userID] = newRating
+	}
+
+	// sort players by points
+	var userIds []string
+	for userID := range g.UsersPoints {
+		userIds = append(userIds, userID)
+	}
+	sort.Slice(userIds, func(i, j int) bool {
+		return g.UsersPoints[userIds[i]] > g.UsersPoints[userIds[j]]
+	})
+
+	resultMessage := "**Ranked Results**"
+	for _, userID := range userIds {
+		userName := "*Unknown*"
+		if user, err := helpers.GetUser(userID); err == nil && user != nil {
+			userName = user.Username
+		}
+
+		resultMessage += fmt.Sprintf("\n%s: %s points | Rating: %.0f (%+.0f)",
+			userName, humanize.Comma(int64(g.UsersPoints[userID])), ratings[userID], changes[userID])
+	}
+
+	helpers.SendMessage(g.ChannelID, resultMessage)
+}
+
+// getRankedRating returns the ranked rating of a user
+func getRankedRating(userID string) (float64, error) {
+	var rankedEntry models.NuguGameRankedEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.NuguGameRankedTable).Find(bson.M{"userid": userID}),
+		&rankedEntry,
+	)
+	if helpers.IsMdbNotFound(err) {
+		return RANKED_BASE_RATING, nil
+	}
+	return rankedEntry.Rating, err
+}
+
+// updateRankedRating adds the rating change and points of a game to the user and returns the new rating
+func updateRankedRating(userID string, change float64, points int) (float64, error) {
+	selector := bson.M{"userid": userID}
+
+	// the first ranked game of the user starts at the base rating
+	_, err := helpers.MdbCollection(models.NuguGameRankedTable).Upsert(selector, bson.M{
+		"$setOnInsert": bson.M{"rating": RANKED_BASE_RATING, "games": 0, "points": 0},
+	})
+	// the unique index rejects the entry if another process inserted it at the same time
+	if err != nil && !mgo.IsDup(err) {
+		return 0, err
+	}
+
+	var rankedEntry models.NuguGameRankedEntry
+	_, err = helpers.MdbCollection(models.NuguGameRankedTable).Find(selector).Apply(mgo.Change{
+		Update:    bson.M{"$inc": bson.M{"rating": change, "games": 1, "points": points}},
+		ReturnNew: true,
+	}, &rankedEntry)
+
+	return rankedEntry.Rating, err
+}
+
+// displayRankedRanking sends embed of the users with the highest ranked ratings
+func displayRankedRanking(msg *discordgo.Message) {
+	cache.GetSession().SessionForGuildS(msg.GuildID).ChannelTyping(msg.ChannelID)
+
+	var rankedEntries []models.NuguGameRankedEntry
+	err := helpers.MDbIter(helpers.MdbCollection(models.NuguGameRankedTable).Find(bson.M{}).Sort("-rating").Limit(50)).All(&rankedEntries)
+	helpers.Relax(err)
+	if len(rankedEntries) == 0 {
+		helpers.SendMessage(msg.ChannelID, "No rankings found")
+		return
+	}
+
+	// create embed
+	embed := &discordgo.MessageEmbed{
+		Color: 0x0FADED, // blueish
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    "Nugu Game Ranked Ratings",
+			IconURL: cache.GetSession().SessionForGuildS(msg.GuildID).State.User.AvatarURL("512"),
+		},
+	}
+
+	for i, rankedEntry := range rankedEntries {
+
+		displayName := "*Unknown*"
+		if user, err := helpers.GetUser(rankedEntry.UserID); err == nil {
+			displayName = user.Username
+		}
+		if len(displayName) > 25 {
+			displayName = displayName[0:25] + "..."
+		}
+
+		embed.Fields = append(embed.Fields, []*discordgo.MessageEmbedField{
+			{
+				Name:   fmt.Sprintf("Rank #%d", i+1),
+				Value:  displayName,
+				Inline: true,
+			},
+			{
+				Name:   "Rating",
+				Value:  fmt.Sprintf("%.0f", rankedEntry.Rating),
+				Inline: true,
+			},
+			{
+				Name:   "Games | Points",
+				Value:  fmt.Sprintf("%s | %s", humanize.Comma(int64(rankedEntry.Games)), humanize.Comma(int64(rankedEntry.Points))),
+				Inline: true,
+			},
+		}...)
+	}
+
+	helpers.SendPagedMessage(msg, embed, 21)
+}
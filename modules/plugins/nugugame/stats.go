@@ -81,6 +81,9 @@ func recordNuguGame(g *nuguGame) {
 		Difficulty:          game.Difficulty,
 		UsersCorrectGuesses: game.UsersCorrectGuesses,
 		IsMultigame:         game.IsMultigame,
+		IsDaily:             game.IsDaily,
+		IsRanked:            game.IsRanked,
+		UsersPoints:         game.UsersPoints,
 	}
 	helpers.MDbInsert(models.NuguGameTable, nugugameEntry)
 }
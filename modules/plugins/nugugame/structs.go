@@ -27,6 +27,12 @@ type nuguGame struct {
 	LivesRemaining      int
 	UsersCorrectGuesses map[string][]bson.ObjectId // userid => []ids of idols they got right.  used in multi only
 	GuildID             string
+	IsDaily             bool            // if true the idols are taken from the daily sequence
+	Day                 time.Time       // day of the daily challenge, used in daily only
+	DailySequence       []bson.ObjectId // idols of the daily challenge in order, used in daily only
+	IsRanked            bool            // if true guesses give points and the ratings of the users are updated
+	UsersPoints         map[string]int  // userid => points they earned. used in ranked only
+	RoundStartTime      time.Time       // when the current round was sent, used for points in ranked games
 }
 
 // nuguGameForCache is only the information necessary to restore a game.
@@ -44,4 +50,10 @@ type nuguGameForCache struct {
 	LivesRemaining      int
 	UsersCorrectGuesses map[string][]bson.ObjectId // userid => []ids of idols they got right.  used in multi only
 	GuildID             string
+	IsDaily             bool
+	Day                 time.Time
+	DailySequence       []bson.ObjectId
+	IsRanked            bool
+	UsersPoints         map[string]int
+	RoundStartTime      time.Time
 }
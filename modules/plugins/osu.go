@@ -57,7 +57,7 @@ func (o *Osu) Action(command string, content string, msg *discordgo.Message, ses
 	jsonc, err := helpers.GetJSON(
 		fmt.Sprintf(
 			"https://osu.ppy.sh/api/get_user?k=%s&u=%s&type=u&m=%s",
-			helpers.GetConfig().Path("osu").Data().(string),
+			helpers.GetConfig().Osu,
 			user,
 			mode,
 		),
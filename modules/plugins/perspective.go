@@ -58,7 +58,7 @@ func (m *Perspective) Commands() []string {
 // TODO: add timeout between specific user messages (don't notify for old messages)
 
 func (m *Perspective) Init(session *shardmanager.Manager) {
-	m.googleApiKey = helpers.GetConfig().Path("google.api_key").Data().(string)
+	m.googleApiKey = helpers.GetConfig().Google.APIKey
 	go func() {
 		defer helpers.Recover()
 
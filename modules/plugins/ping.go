@@ -77,9 +77,9 @@ func (p *Ping) OnMessage(session *discordgo.Session, message *discordgo.MessageC
 	redisTaken := time.Since(started)
 	text = text + "\nRedis Latency: " + redisTaken.String()
 
-	if helpers.GetConfig().Path("elasticsearch.url").Data().(string) != "" {
+	if helpers.GetConfig().Elasticsearch.URL != "" {
 		started = time.Now()
-		cache.GetElastic().Ping(helpers.GetConfig().Path("elasticsearch.url").Data().(string)).Do(context.Background())
+		cache.GetElastic().Ping(helpers.GetConfig().Elasticsearch.URL).Do(context.Background())
 		elasticTaken := time.Since(started)
 		text = text + "\nElasticSearch Latency: " + elasticTaken.String()
 	}
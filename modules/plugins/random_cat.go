@@ -23,7 +23,7 @@ func (rc RandomCat) Commands() []string {
 
 func (rc RandomCat) Init(session *shardmanager.Manager) {
 	RandomCatEndpoint = "https://thecatapi.com/api/images/get?api_key=" +
-		helpers.GetConfig().Path("thecatapi-api-key").Data().(string) +
+		helpers.GetConfig().TheCatAPIKey +
 		"&format=xml&results_per_page=1&type=jpg,gif,png"
 }
 
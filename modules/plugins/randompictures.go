@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
-	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -39,6 +40,15 @@ var (
 	}
 )
 
+var (
+	errRandomPicturesNotCached = errors.New("no pictures cached for this source")
+)
+
+const (
+	// randomPicturesMaxPicks is how many pictures are picked at most to find one that hasn't been posted recently
+	randomPicturesMaxPicks = 10
+)
+
 const (
 	driveSearchText       = "\"%s\" in parents and (mimeType = \"image/gif\" or mimeType = \"image/jpeg\" or mimeType = \"image/png\" or mimeType = \"application/vnd.google-apps.folder\")"
 	driveFieldsText       = "nextPageToken, files(id, size, mimeType)"
@@ -56,8 +66,10 @@ func (rp *RandomPictures) Commands() []string {
 
 func (rp *RandomPictures) Init(session *shardmanager.Manager) {
 
-	// Get drive service
-	driveService = cache.GetGoogleDriveService()
+	// Get drive service, drive sources can't be used without it
+	if cache.HasGoogleDrive() {
+		driveService = cache.GetGoogleDriveService()
+	}
 
 	go func() {
 		log := cache.GetLogger()
@@ -65,9 +77,6 @@ func (rp *RandomPictures) Init(session *shardmanager.Manager) {
 		defer helpers.Recover()
 
 		for {
-			var marshalled []byte
-			redisClient := cache.GetRedisClient()
-
 			var rpSources []models.RandompictureSourceEntry
 			err := helpers.MDbIter(helpers.MdbCollection(models.RandompictureSourcesTable).Find(nil)).All(&rpSources)
 			if len(rpSources) <= 0 {
@@ -76,40 +85,18 @@ func (rp *RandomPictures) Init(session *shardmanager.Manager) {
 			}
 			helpers.Relax(err)
 
-			log.WithField("module", "randompictures").Info("gathering google drive picture cache")
+			log.WithField("module", "randompictures").Info("gathering picture cache")
 			for _, sourceEntry := range rpSources {
-				var key1 string
-				var key2 string
-				var fileHash string
-				var i int
-				var entry *drive.File
-				for i, entry = range rp.getFileCache(sourceEntry) {
-					fileHash = rp.GetFileHash(sourceEntry.ID, sourceEntry.PreviousID, entry.Id)
-					key1 = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-n:%s:entry:%d", helpers.MdbIdToHuman(sourceEntry.ID), i+1)
-					key2 = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-hash:%s", fileHash)
-					marshalled, err = msgpack.Marshal(entry)
-					if err != nil {
-						raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-						continue
-					}
-					err = redisClient.Set(key1, fileHash, 7*24*time.Hour).Err()
-					if err != nil {
-						raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-						continue
-					}
-					err = redisClient.Set(key2, marshalled, 7*24*time.Hour).Err()
-					if err != nil {
-						raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-						continue
-					}
+				// sources are cached and posted by the process running the guild
+				if !session.OwnsGuild(sourceEntry.GuildID) {
+					continue
 				}
-				key := fmt.Sprintf("robyul2-discord:randompictures:filescache:%s:entry:%s", helpers.MdbIdToHuman(sourceEntry.ID), "count")
-				err = redisClient.Set(key, i+1, 7*24*time.Hour).Err()
+
+				_, err = rp.cacheFiles(sourceEntry, 7*24*time.Hour)
 				if err != nil {
-					raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
+					raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{"SourceID": helpers.MdbIdToHuman(sourceEntry.ID)})
 					continue
 				}
-				rp.updateImagesCachedMetric()
 			}
 
 			time.Sleep(12 * time.Hour)
@@ -123,8 +110,6 @@ func (rp *RandomPictures) Init(session *shardmanager.Manager) {
 		for {
 			time.Sleep(time.Duration(rand.Intn(30)+60) * time.Minute)
 
-			redisClient := cache.GetRedisClient()
-
 			var rpSources []models.RandompictureSourceEntry
 			err := helpers.MDbIter(helpers.MdbCollection(models.RandompictureSourcesTable).Find(nil)).All(&rpSources)
 			helpers.Relax(err)
@@ -132,50 +117,28 @@ func (rp *RandomPictures) Init(session *shardmanager.Manager) {
 				time.Sleep(30 * time.Second)
 				continue
 			}
-			helpers.Relax(err)
-
-			var fileHash string
-			var key string
 
 			for _, sourceEntry := range rpSources {
-				if len(sourceEntry.PostToChannelIDs) > 0 {
-					key = fmt.Sprintf("robyul2-discord:randompictures:filescache:%s:entry:%s", helpers.MdbIdToHuman(sourceEntry.ID), "count")
-					pictureCount, err := redisClient.Get(key).Int64()
-					if err == nil {
-						for _, postToChannelID := range sourceEntry.PostToChannelIDs {
-						RetryNewPicture:
-							chosenPicN := rand.Intn(int(pictureCount)) + 1
-							key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-n:%s:entry:%d", helpers.MdbIdToHuman(sourceEntry.ID), chosenPicN)
-							fileHash = redisClient.Get(key).Val()
-							if fileHash == "" {
-								continue
-							}
-							key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-hash:%s", fileHash)
-							resultBytes, err := redisClient.Get(key).Bytes()
-							if err == nil {
-								var gPicture *drive.File
-								msgpack.Unmarshal(resultBytes, &gPicture)
-
-								err = rp.postItem(sourceEntry.GuildID, postToChannelID, "", gPicture, sourceEntry.ID, sourceEntry.PreviousID, strconv.Itoa(chosenPicN))
-								if err != nil {
-									if errG, ok := err.(*googleapi.Error); ok {
-										if strings.Contains("The download quota for this file has been exceeded", errG.Error()) {
-											goto RetryNewPicture
-										} else {
-											raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-											continue
-										}
-									} else {
-										if errD, ok := err.(*discordgo.RESTError); !ok ||
-											errD.Message == nil ||
-											(errD.Message.Code != discordgo.ErrCodeMissingPermissions && errD.Message.Code != discordgo.ErrCodeUnknownChannel) {
-											raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{"GuildID": sourceEntry.GuildID})
-										}
-										continue
-									}
-								}
-							}
+				if !session.OwnsGuild(sourceEntry.GuildID) {
+					continue
+				}
+
+				for _, postToChannelID := range sourceEntry.PostToChannelIDs {
+					err = rp.postRandomItem(sourceEntry, sourceEntry.GuildID, postToChannelID, "")
+					if err != nil {
+						if err == errRandomPicturesNotCached {
+							continue
 						}
+						if _, ok := err.(*googleapi.Error); ok {
+							raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
+							continue
+						}
+						if errD, ok := err.(*discordgo.RESTError); !ok ||
+							errD.Message == nil ||
+							(errD.Message.Code != discordgo.ErrCodeMissingPermissions && errD.Message.Code != discordgo.ErrCodeUnknownChannel) {
+							raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{"GuildID": sourceEntry.GuildID})
+						}
+						continue
 					}
 				}
 			}
@@ -183,6 +146,15 @@ func (rp *RandomPictures) Init(session *shardmanager.Manager) {
 	}()
 	cache.GetLogger().WithField("module", "randompictures").Info("Started post loop (1h)")
 
+	go func() {
+		defer helpers.Recover()
+
+		err := rp.loadUploadChannels()
+		helpers.Relax(err)
+	}()
+	helpers.OnCacheInvalidation(randomPicturesUploadChannelsCacheName, rp.loadUploadChannels)
+	session.AddHandler(rp.OnMessageCreate)
+
 	go rp.setServerFeaturesLoop()
 }
 
@@ -329,13 +301,19 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 					guild, err := helpers.GetGuild(channel.GuildID)
 					helpers.Relax(err)
 
+					sourceType := models.RandompictureSourceTypeDrive
 					postToChannelIDs := make([]string, 0)
 					driveFolderIDs := make([]string, 0)
+					uploadChannelIDs := make([]string, 0)
+					var directory string
 					aliases := make([]string, 0)
 					blacklistedRoleIDs := make([]string, 0)
 					data := helpers.ParseKeyValueString(
 						strings.TrimSpace(strings.Replace(content, args[0], "", 1)),
 					)
+					if typeText, ok := data["type"]; ok {
+						sourceType = strings.ToLower(strings.TrimSpace(typeText))
+					}
 					if channelIDsText, ok := data["channel"]; ok {
 						postToChannelIDsParsed := strings.Split(channelIDsText, ",")
 						for _, parsedID := range postToChannelIDsParsed {
@@ -351,6 +329,11 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 					if folderIDsText, ok := data["folder"]; ok {
 						folderIDsParsed := strings.Split(folderIDsText, ",")
 						for _, parsedID := range folderIDsParsed {
+							if driveService == nil {
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+								helpers.Relax(err)
+								return
+							}
 							result, err := driveService.Files.List().Q(fmt.Sprintf(driveSearchText, parsedID)).Fields(googleapi.Field(driveFieldsText)).PageSize(1).Do()
 							if err != nil || len(result.Files) <= 0 {
 								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
@@ -360,6 +343,26 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 							driveFolderIDs = append(driveFolderIDs, parsedID)
 						}
 					}
+					if uploadChannelIDsText, ok := data["upload"]; ok {
+						uploadChannelIDsParsed := strings.Split(uploadChannelIDsText, ",")
+						for _, parsedID := range uploadChannelIDsParsed {
+							channelParsed, err := helpers.GetChannelFromMention(msg, parsedID)
+							if err != nil || channelParsed == nil || channelParsed.ID == "" {
+								_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+								helpers.Relax(err)
+								return
+							}
+							uploadChannelIDs = append(uploadChannelIDs, channelParsed.ID)
+						}
+					}
+					if directoryText, ok := data["directory"]; ok {
+						directory = filepath.Clean(strings.TrimSpace(directoryText))
+						if directoryInfo, err := os.Stat(directory); err != nil || !directoryInfo.IsDir() {
+							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+							helpers.Relax(err)
+							return
+						}
+					}
 					if aliasesText, ok := data["alias"]; ok {
 						aliasesParsed := strings.Split(aliasesText, ",")
 						for _, parsedAlias := range aliasesParsed {
@@ -380,29 +383,59 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 						}
 					}
 
-					if len(aliases) <= 0 || len(driveFolderIDs) <= 0 {
+					sourceComplete := false
+					switch sourceType {
+					case models.RandompictureSourceTypeDrive:
+						sourceComplete = len(driveFolderIDs) > 0
+					case models.RandompictureSourceTypeChannel:
+						sourceComplete = len(uploadChannelIDs) > 0
+					case models.RandompictureSourceTypeDirectory:
+						sourceComplete = directory != ""
+					case models.RandompictureSourceTypeStorage:
+						sourceComplete = true
+					}
+					if len(aliases) <= 0 || !sourceComplete {
 						_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
 						helpers.Relax(err)
 						return
 					}
 
-					newID, err := helpers.MDbInsert(
-						models.RandompictureSourcesTable,
-						models.RandompictureSourceEntry{
-							PostToChannelIDs:   postToChannelIDs,
-							DriveFolderIDs:     driveFolderIDs,
-							Aliases:            aliases,
-							GuildID:            channel.GuildID,
-							BlacklistedRoleIDs: blacklistedRoleIDs,
-						},
-					)
+					newSource := models.RandompictureSourceEntry{
+						Type:               sourceType,
+						PostToChannelIDs:   postToChannelIDs,
+						DriveFolderIDs:     driveFolderIDs,
+						UploadChannelIDs:   uploadChannelIDs,
+						Directory:          directory,
+						Aliases:            aliases,
+						GuildID:            channel.GuildID,
+						BlacklistedRoleIDs: blacklistedRoleIDs,
+					}
+					newID, err := helpers.MDbInsert(models.RandompictureSourcesTable, newSource)
 					helpers.Relax(err)
+					newSource.ID = newID
+
+					if sourceType == models.RandompictureSourceTypeChannel {
+						err = helpers.InvalidateCache(randomPicturesUploadChannelsCacheName)
+						helpers.RelaxLog(err)
+					}
+
+					// cache the pictures of the new source right away
+					go func() {
+						defer helpers.Recover()
+
+						_, err := rp.cacheFiles(newSource, 7*24*time.Hour)
+						helpers.RelaxLog(err)
+					}()
 
 					_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(newID),
 						models.EventlogTargetTypeRobyulRandomPictureSource, msg.Author.ID,
 						models.EventlogTypeRobyulRandomPictureSourceCreate, "",
 						nil,
 						[]models.ElasticEventlogOption{
+							{
+								Key:   "randompicture_source_type",
+								Value: sourceType,
+							},
 							{
 								Key:   "randompicture_source_posttochannelids",
 								Value: strings.Join(postToChannelIDs, ";"),
@@ -412,6 +445,15 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 								Key:   "randompicture_source_drivefolderids",
 								Value: strings.Join(driveFolderIDs, ";"),
 							},
+							{
+								Key:   "randompicture_source_uploadchannelids",
+								Value: strings.Join(uploadChannelIDs, ";"),
+								Type:  models.EventlogTargetTypeChannel,
+							},
+							{
+								Key:   "randompicture_source_directory",
+								Value: directory,
+							},
 							{
 								Key:   "randompicture_source_aliases",
 								Value: strings.Join(aliases, ";"),
@@ -460,10 +502,10 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 							totalCachedImages += pictureCount
 						}
 
-						listText += fmt.Sprintf(":arrow_forward: `%s`: on %s (`#%s`), %d Aliases (`%s`), %d Folders, %d Channels, %d Skipped Roles, %s\n",
+						listText += fmt.Sprintf(":arrow_forward: `%s`: on %s (`#%s`), %d Aliases (`%s`), %s, %d Channels, %d Skipped Roles, %s\n",
 							helpers.MdbIdToHuman(rpSource.ID), rpSourceGuild.Name, rpSourceGuild.ID,
 							len(rpSource.Aliases), strings.Join(rpSource.Aliases, ","),
-							len(rpSource.DriveFolderIDs),
+							rp.describeSource(rpSource),
 							len(rpSource.PostToChannelIDs), len(rpSource.BlacklistedRoleIDs),
 							cacheText)
 						totalSources += 1
@@ -507,11 +549,20 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 					err = helpers.MDbDelete(models.RandompictureSourcesTable, entryBucket.ID)
 					helpers.Relax(err)
 
+					if entryBucket.Type == models.RandompictureSourceTypeChannel {
+						err = helpers.InvalidateCache(randomPicturesUploadChannelsCacheName)
+						helpers.RelaxLog(err)
+					}
+
 					_, err = helpers.EventlogLog(time.Now(), entryBucket.GuildID, helpers.MdbIdToHuman(entryBucket.ID),
 						models.EventlogTargetTypeRobyulRandomPictureSource, msg.Author.ID,
 						models.EventlogTypeRobyulRandomPictureSourceRemove, "",
 						nil,
 						[]models.ElasticEventlogOption{
+							{
+								Key:   "randompicture_source_type",
+								Value: entryBucket.Type,
+							},
 							{
 								Key:   "randompicture_source_posttochannelids",
 								Value: strings.Join(entryBucket.PostToChannelIDs, ";"),
@@ -521,6 +572,15 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 								Key:   "randompicture_source_drivefolderids",
 								Value: strings.Join(entryBucket.DriveFolderIDs, ";"),
 							},
+							{
+								Key:   "randompicture_source_uploadchannelids",
+								Value: strings.Join(entryBucket.UploadChannelIDs, ";"),
+								Type:  models.EventlogTargetTypeChannel,
+							},
+							{
+								Key:   "randompicture_source_directory",
+								Value: entryBucket.Directory,
+							},
 							{
 								Key:   "randompicture_source_aliases",
 								Value: strings.Join(entryBucket.Aliases, ";"),
@@ -537,6 +597,41 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 					return
 				})
 				return
+			case "add-pictures", "add-picture": // [p]randompictures add-pictures <source id> + attachments
+				helpers.RequireMod(msg, func() {
+					if len(args) < 2 {
+						helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.too-few"))
+						return
+					}
+					session.ChannelTyping(msg.ChannelID)
+
+					channel, err := helpers.GetChannel(msg.ChannelID)
+					helpers.Relax(err)
+
+					var entryBucket models.RandompictureSourceEntry
+					err = helpers.MdbOne(
+						helpers.MdbCollection(models.RandompictureSourcesTable).Find(bson.M{"guildid": channel.GuildID, "_id": helpers.HumanToMdbId(args[1])}),
+						&entryBucket,
+					)
+					if helpers.IsMdbNotFound(err) ||
+						(entryBucket.Type != models.RandompictureSourceTypeStorage && entryBucket.Type != models.RandompictureSourceTypeChannel) {
+						helpers.SendMessage(msg.ChannelID, helpers.GetText("bot.arguments.invalid"))
+						return
+					}
+					helpers.Relax(err)
+
+					added, err := rp.addPicturesToSource(entryBucket, msg)
+					helpers.Relax(err)
+					if added <= 0 {
+						_, err = helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.randompictures.add-pictures-none"))
+						helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+						return
+					}
+
+					_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.randompictures.add-pictures-success", added))
+					helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+				})
+				return
 			case "refresh": // [p]randompictures refresh <source id>
 				helpers.RequireRobyulMod(msg, func() {
 					session.ChannelTyping(msg.ChannelID)
@@ -562,43 +657,9 @@ func (rp *RandomPictures) Action(command string, content string, msg *discordgo.
 
 					for _, rpSource := range rpSources {
 						if helpers.MdbIdToHuman(rpSource.ID) == args[1] {
-							var fileHash string
-							var key string
-							var i int
-							var entry *drive.File
-							var marshalled []byte
-							redisClient := cache.GetRedisClient()
 							helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.randompictures.refresh-started"))
-							for i, entry = range rp.getFileCache(rpSource) {
-								key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-n:%s:entry:%d", helpers.MdbIdToHuman(rpSource.ID), i+1)
-								fileHash = rp.GetFileHash(rpSource.ID, rpSource.PreviousID, entry.Id)
-								if fileHash == "" {
-									continue
-								}
-								err = redisClient.Set(key, fileHash, time.Hour*24).Err()
-								if err != nil {
-									raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-									continue
-								}
-								key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-hash:%s", fileHash)
-								marshalled, err = msgpack.Marshal(entry)
-								if err != nil {
-									raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-									continue
-								}
-								err = redisClient.Set(key, marshalled, time.Hour*24).Err()
-								if err != nil {
-									raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-									continue
-								}
-							}
-							key = fmt.Sprintf("robyul2-discord:randompictures:filescache:%s:entry:%s", helpers.MdbIdToHuman(rpSource.ID), "count")
-							err = redisClient.Set(key, i+1, time.Hour*24).Err()
-							if err != nil {
-								raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-								continue
-							}
-							rp.updateImagesCachedMetric()
+							_, err = rp.cacheFiles(rpSource, time.Hour*24)
+							helpers.Relax(err)
 							_, err := helpers.SendMessage(msg.ChannelID, helpers.GetText("plugins.randompictures.refresh-success"))
 							helpers.Relax(err)
 							return
@@ -772,7 +833,7 @@ func (rp *RandomPictures) postRandomItemFromContent(channel *discordgo.Channel,
 			}
 		}
 
-		targetMember, err := cache.GetSession().SessionForGuildS(channel.GuildID).State.Member(channel.GuildID, msg.Author.ID)
+		targetMember, err := helpers.GetGuildMember(channel.GuildID, msg.Author.ID)
 		if err != nil {
 			return false, err
 		}
@@ -814,34 +875,148 @@ func (rp *RandomPictures) postRandomItemFromContent(channel *discordgo.Channel,
 		}
 	}
 	if matchEntry.ID != "" {
-		redisClient := cache.GetRedisClient()
-		key := fmt.Sprintf("robyul2-discord:randompictures:filescache:%s:entry:%s", helpers.MdbIdToHuman(matchEntry.ID), "count")
-		pictureCount, err := redisClient.Get(key).Int64()
-		if err == nil {
-			chosenPicN := rand.Intn(int(pictureCount)) + 1
-			key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-n:%s:entry:%d", helpers.MdbIdToHuman(matchEntry.ID), chosenPicN)
-			fileHash := redisClient.Get(key).Val()
-			if fileHash == "" {
-				return false, errors.New("unable to gather data for pic")
-			}
-			key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-hash:%s", fileHash)
-			resultBytes, err := redisClient.Get(key).Bytes()
-			if err != nil {
-				return false, errors.New("invalid picture data cached")
-			}
-			var gPicture *drive.File
-			msgpack.Unmarshal(resultBytes, &gPicture)
-			err = rp.postItem(channel.GuildID, msg.ChannelID, initialMessage.ID, gPicture, matchEntry.ID, matchEntry.PreviousID, strconv.Itoa(chosenPicN))
-			if err == nil {
-				return true, nil
-			} else {
-				return false, err
-			}
+		err := rp.postRandomItem(matchEntry, channel.GuildID, msg.ChannelID, initialMessage.ID)
+		if err != nil {
+			return false, err
 		}
+		return true, nil
 	}
 	return false, errors.New("unable to match to source")
 }
 
+// cacheFiles saves the pictures of a source to redis and returns the amount of pictures
+func (rp *RandomPictures) cacheFiles(sourceEntry models.RandompictureSourceEntry, expiration time.Duration) (int, error) {
+	files, err := rp.getSource(sourceEntry).listFiles(sourceEntry)
+	if err != nil {
+		return 0, err
+	}
+
+	redisClient := cache.GetRedisClient()
+	var key string
+	var fileHash string
+	var marshalled []byte
+	for i, file := range files {
+		fileHash = rp.GetFileHash(sourceEntry.ID, sourceEntry.PreviousID, file.Id)
+		marshalled, err = msgpack.Marshal(file)
+		if err != nil {
+			raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
+			continue
+		}
+		key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-n:%s:entry:%d", helpers.MdbIdToHuman(sourceEntry.ID), i+1)
+		err = redisClient.Set(key, fileHash, expiration).Err()
+		if err != nil {
+			raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
+			continue
+		}
+		key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-hash:%s", fileHash)
+		err = redisClient.Set(key, marshalled, expiration).Err()
+		if err != nil {
+			raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
+			continue
+		}
+	}
+	key = fmt.Sprintf("robyul2-discord:randompictures:filescache:%s:entry:%s", helpers.MdbIdToHuman(sourceEntry.ID), "count")
+	err = redisClient.Set(key, len(files), expiration).Err()
+	if err != nil {
+		return 0, err
+	}
+	rp.updateImagesCachedMetric()
+
+	return len(files), nil
+}
+
+// postRandomItem posts a random picture of the source, tries other pictures if google drive doesn't allow downloading a picture
+func (rp *RandomPictures) postRandomItem(sourceEntry models.RandompictureSourceEntry, guildID string, channelID string, messageID string) (err error) {
+	var file *RandomPictures_File
+	var fileHash string
+	var pictureN int
+	for i := 0; i < 3; i++ {
+		file, fileHash, pictureN, err = rp.getRandomCachedFile(sourceEntry, guildID)
+		if err != nil {
+			return err
+		}
+
+		err = rp.postItem(guildID, channelID, messageID, sourceEntry, file, fileHash, strconv.Itoa(pictureN))
+		if !isDriveQuotaError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// getRandomCachedFile picks a random cached picture of the source, pictures posted recently on the guild are less likely to be picked
+func (rp *RandomPictures) getRandomCachedFile(sourceEntry models.RandompictureSourceEntry, guildID string) (file *RandomPictures_File, fileHash string, pictureN int, err error) {
+	redisClient := cache.GetRedisClient()
+	key := fmt.Sprintf("robyul2-discord:randompictures:filescache:%s:entry:%s", helpers.MdbIdToHuman(sourceEntry.ID), "count")
+	pictureCount, err := redisClient.Get(key).Int64()
+	if err != nil || pictureCount <= 0 {
+		return nil, "", 0, errRandomPicturesNotCached
+	}
+
+	recentlyPosted, historyLength := rp.getRecentlyPostedFileHashes(guildID)
+
+	for i := 0; i < randomPicturesMaxPicks; i++ {
+		pictureN = rand.Intn(int(pictureCount)) + 1
+		key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-n:%s:entry:%d", helpers.MdbIdToHuman(sourceEntry.ID), pictureN)
+		fileHash = redisClient.Get(key).Val()
+		if fileHash == "" {
+			continue
+		}
+
+		// the last pick is posted even if it has been posted recently
+		if position, posted := recentlyPosted[fileHash]; posted && i < randomPicturesMaxPicks-1 {
+			if rand.Float64() >= getRandomPicturesWeight(position, historyLength) {
+				continue
+			}
+		}
+
+		key = fmt.Sprintf("robyul2-discord:randompictures:filescache:by-hash:%s", fileHash)
+		resultBytes, err := redisClient.Get(key).Bytes()
+		if err != nil {
+			return nil, "", 0, errors.New("invalid picture data cached")
+		}
+		err = msgpack.Unmarshal(resultBytes, &file)
+		return file, fileHash, pictureN, err
+	}
+
+	return nil, "", 0, errors.New("unable to gather data for pic")
+}
+
+// getRecentlyPostedFileHashes returns the position of every picture in the history of the guild, 0 is the newest, and the length of the history
+func (rp *RandomPictures) getRecentlyPostedFileHashes(guildID string) (map[string]int, int) {
+	key := fmt.Sprintf("robyul2-discord:randompictures:history:%s", guildID)
+	recentlyPosted := make(map[string]int)
+
+	result, err := cache.GetRedisClient().LRange(key, 0, -1).Result()
+	if err != nil {
+		helpers.RelaxLog(err)
+		return recentlyPosted, 0
+	}
+
+	var item RandomPictures_HistoryItem
+	for i, itemString := range result {
+		item = RandomPictures_HistoryItem{}
+		err = msgpack.Unmarshal([]byte(itemString), &item)
+		if err != nil || item.FileHash == "" {
+			continue
+		}
+
+		if _, ok := recentlyPosted[item.FileHash]; !ok {
+			recentlyPosted[item.FileHash] = i
+		}
+	}
+
+	return recentlyPosted, len(result)
+}
+
+// getRandomPicturesWeight returns the chance to post a picture again, the newest picture in the history has the lowest chance
+func getRandomPicturesWeight(position int, historyLength int) float64 {
+	if position < 0 || position >= historyLength {
+		return 1
+	}
+	return float64(position+1) / float64(historyLength+1)
+}
+
 func (rp *RandomPictures) getFileCache(sourceEntry models.RandompictureSourceEntry) []*drive.File {
 	var allFiles []*drive.File
 
@@ -911,6 +1086,20 @@ Loop:
 	return allFiles
 }
 
+// describeSource returns where the pictures of a source come from for the list of sources
+func (rp *RandomPictures) describeSource(sourceEntry models.RandompictureSourceEntry) string {
+	switch sourceEntry.Type {
+	case models.RandompictureSourceTypeStorage:
+		return fmt.Sprintf("%d Uploaded Pictures", len(sourceEntry.ObjectNames))
+	case models.RandompictureSourceTypeChannel:
+		return fmt.Sprintf("%d Upload Channels (%d Pictures)", len(sourceEntry.UploadChannelIDs), len(sourceEntry.ObjectNames))
+	case models.RandompictureSourceTypeDirectory:
+		return "Local Directory"
+	default:
+		return fmt.Sprintf("%d Folders", len(sourceEntry.DriveFolderIDs))
+	}
+}
+
 func (rp *RandomPictures) isValidDriveFile(file *drive.File) bool {
 	if file.MimeType == "application/vnd.google-apps.folder" {
 		return false
@@ -948,69 +1137,36 @@ func (rp *RandomPictures) updateImagesCachedMetric() {
 	metrics.RandomPictureSourcesImagesCachedCount.Set(totalImages)
 }
 
-func (rp *RandomPictures) postItem(guildID string, channelID string, messageID string, file *drive.File, sourceID bson.ObjectId, previousSourceID, pictureID string) error {
-	// fmt.Println("channelID:", channelID, "messageID:", messageID, "file:", file.Name, "sourceID:", sourceID, "pictureID:", pictureID)
-	file, err := driveService.Files.Get(file.Id).Fields(googleapi.Field(driveFieldsSingleText)).Do()
+func (rp *RandomPictures) postItem(guildID string, channelID string, messageID string, sourceEntry models.RandompictureSourceEntry, file *RandomPictures_File, fileHash string, pictureID string) error {
+	item, err := rp.getSource(sourceEntry).getItem(sourceEntry, file, fileHash)
 	if err != nil {
 		return err
 	}
 
-	camerModelText := ""
-	if file.ImageMediaMetadata.CameraModel != "" {
-		camerModelText = fmt.Sprintf(" 📷 `%s`", file.ImageMediaMetadata.CameraModel)
-	}
-
-	linkToPost := helpers.GetConfig().Path("imageproxy.base_url").Data().(string)
-
-	splitFilename := strings.Split(file.Name, ".")
-
-	linkToPost = fmt.Sprintf(linkToPost, rp.GetFileHash(sourceID, previousSourceID, file.Id), url.QueryEscape(strings.Join(splitFilename[0:len(splitFilename)-1], "-")+"."+strings.ToLower(splitFilename[len(splitFilename)-1])))
-	linkToHistory := helpers.GetConfig().Path("website.randompictures_base_url").Data().(string) + guildID
-
-	// open link to prepare cache
-	request, err := http.NewRequest("GET", linkToPost, nil)
-	if err == nil {
-		request.Header.Set("User-Agent", helpers.DEFAULT_UA)
-		resp, err := httpClient.Do(request)
-		if err != nil {
-			if errU, ok := err.(*url.Error); ok {
-				if !strings.Contains(errU.Err.Error(), "Client.Timeout exceeded while awaiting headers") {
-					raven.CaptureError(fmt.Errorf("%#v", errU.Err), map[string]string{})
-				} else {
-					cache.GetLogger().WithField("module", "randompictures").Warn(fmt.Sprintf("warming up cache for %s failed: time out", linkToPost))
-				}
-			} else {
-				raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
-			}
-		}
-		if resp != nil && resp.Body != nil {
-			defer resp.Body.Close()
-		}
-	}
-	helpers.RelaxLog(err)
+	linkToHistory := helpers.GetConfig().Website.RandomPicturesBaseURL + guildID
 
-	err = rp.appendLinkToServerHistory(linkToPost, sourceID, pictureID, file.Name, guildID)
+	err = rp.appendLinkToServerHistory(item.Link, sourceEntry.ID, pictureID, item.Filename, fileHash, guildID)
 	helpers.RelaxLog(err)
 
 	var shortUrl string
 	if cache.GetPolr() != nil {
-		shortUrl, err = cache.GetPolr().Shorten(linkToPost, "", false)
+		shortUrl, err = cache.GetPolr().Shorten(item.Link, "", false)
 		helpers.RelaxLog(err)
 	}
 
 	if shortUrl == "" {
-		shortUrl = linkToPost
+		shortUrl = item.Link
 	}
 
 	embed := &discordgo.MessageEmbed{
 		URL:   shortUrl,
-		Title: "🏷 " + file.Name + camerModelText,
+		Title: "🏷 " + item.Title,
 		Author: &discordgo.MessageEmbedAuthor{
 			URL:  linkToHistory,
 			Name: "🖼  Gallery",
 		},
 		Image: &discordgo.MessageEmbedImage{
-			URL: linkToPost,
+			URL: item.Link,
 		},
 	}
 
@@ -1044,7 +1200,7 @@ func (rp *RandomPictures) GetFileHash(sourceID bson.ObjectId, previousID string,
 	return helpers.GetMD5Hash(helpers.MdbIdToHuman(sourceID) + "-" + fileID)
 }
 
-func (rp *RandomPictures) appendLinkToServerHistory(link string, sourceID bson.ObjectId, pictureID string, fileName string, guildID string) error {
+func (rp *RandomPictures) appendLinkToServerHistory(link string, sourceID bson.ObjectId, pictureID string, fileName string, fileHash string, guildID string) error {
 	redis := cache.GetRedisClient()
 	key := fmt.Sprintf("robyul2-discord:randompictures:history:%s", guildID)
 
@@ -1053,6 +1209,7 @@ func (rp *RandomPictures) appendLinkToServerHistory(link string, sourceID bson.O
 	item.SourceID = helpers.MdbIdToHuman(sourceID)
 	item.PictureID = pictureID
 	item.Filename = fileName
+	item.FileHash = fileHash
 	item.GuildID = guildID
 	item.Time = time.Now()
 
@@ -1118,6 +1275,7 @@ type RandomPictures_HistoryItem struct {
 	SourceID  string
 	PictureID string
 	Filename  string
+	FileHash  string
 	GuildID   string
 	Time      time.Time
 }
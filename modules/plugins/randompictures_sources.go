@@ -0,0 +1,383 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seklfreak/Robyul2/cache"
+	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/bwmarrin/discordgo"
+	raven "github.com/getsentry/raven-go"
+	"github.com/globalsign/mgo/bson"
+	"google.golang.org/api/googleapi"
+)
+
+// randomPicturesSource is where the pictures of a source entry are stored
+type randomPicturesSource interface {
+	// listFiles returns all pictures of the source, the result is cached in redis
+	listFiles(sourceEntry models.RandompictureSourceEntry) ([]*RandomPictures_File, error)
+	// getItem returns what to post for a cached picture
+	getItem(sourceEntry models.RandompictureSourceEntry, file *RandomPictures_File, fileHash string) (*randomPicturesItem, error)
+}
+
+// RandomPictures_File is a cached picture, the fields are named like the ones of drive.File so older caches and the image proxy can still read them
+type RandomPictures_File struct {
+	Id       string
+	Name     string
+	Size     int64
+	MimeType string
+}
+
+type randomPicturesItem struct {
+	Link     string
+	Title    string
+	Filename string
+}
+
+var randomPicturesImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif"}
+
+// randomPicturesUploadChannelsCacheName is used to reload the upload channels in all processes
+const randomPicturesUploadChannelsCacheName = "randompictures-upload-channels"
+
+// upload channel ID => channel source ID
+var randomPicturesUploadChannels = make(map[string]bson.ObjectId)
+var randomPicturesUploadChannelsMutex sync.RWMutex
+
+// getSource returns the source for the type of the source entry
+func (rp *RandomPictures) getSource(sourceEntry models.RandompictureSourceEntry) randomPicturesSource {
+	switch sourceEntry.Type {
+	case models.RandompictureSourceTypeStorage, models.RandompictureSourceTypeChannel:
+		return &randomPicturesStorageSource{}
+	case models.RandompictureSourceTypeDirectory:
+		return &randomPicturesDirectorySource{}
+	default:
+		return &randomPicturesDriveSource{rp: rp}
+	}
+}
+
+// randomPicturesDriveSource reads pictures from google drive folders and posts them with the image proxy
+type randomPicturesDriveSource struct {
+	rp *RandomPictures
+}
+
+func (s *randomPicturesDriveSource) listFiles(sourceEntry models.RandompictureSourceEntry) ([]*RandomPictures_File, error) {
+	if driveService == nil {
+		return nil, errors.New("google drive is not set up")
+	}
+
+	var files []*RandomPictures_File
+	for _, driveFile := range s.rp.getFileCache(sourceEntry) {
+		files = append(files, &RandomPictures_File{
+			Id:       driveFile.Id,
+			Name:     driveFile.Name,
+			Size:     driveFile.Size,
+			MimeType: driveFile.MimeType,
+		})
+	}
+	return files, nil
+}
+
+func (s *randomPicturesDriveSource) getItem(sourceEntry models.RandompictureSourceEntry, file *RandomPictures_File, fileHash string) (*randomPicturesItem, error) {
+	if driveService == nil {
+		return nil, errors.New("google drive is not set up")
+	}
+
+	driveFile, err := driveService.Files.Get(file.Id).Fields(googleapi.Field(driveFieldsSingleText)).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	camerModelText := ""
+	if driveFile.ImageMediaMetadata != nil && driveFile.ImageMediaMetadata.CameraModel != "" {
+		camerModelText = fmt.Sprintf(" 📷 `%s`", driveFile.ImageMediaMetadata.CameraModel)
+	}
+
+	splitFilename := strings.Split(driveFile.Name, ".")
+	linkToPost := fmt.Sprintf(helpers.GetConfig().ImageProxy.BaseURL, fileHash, url.QueryEscape(strings.Join(splitFilename[0:len(splitFilename)-1], "-")+"."+strings.ToLower(splitFilename[len(splitFilename)-1])))
+
+	// open link to prepare cache
+	request, err := http.NewRequest("GET", linkToPost, nil)
+	if err == nil {
+		request.Header.Set("User-Agent", helpers.DEFAULT_UA)
+		resp, err := httpClient.Do(request)
+		if err != nil {
+			if errU, ok := err.(*url.Error); ok {
+				if !strings.Contains(errU.Err.Error(), "Client.Timeout exceeded while awaiting headers") {
+					raven.CaptureError(fmt.Errorf("%#v", errU.Err), map[string]string{})
+				} else {
+					cache.GetLogger().WithField("module", "randompictures").Warn(fmt.Sprintf("warming up cache for %s failed: time out", linkToPost))
+				}
+			} else {
+				raven.CaptureError(fmt.Errorf("%#v", err), map[string]string{})
+			}
+		}
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+	}
+	helpers.RelaxLog(err)
+
+	return &randomPicturesItem{
+		Link:     linkToPost,
+		Title:    driveFile.Name + camerModelText,
+		Filename: driveFile.Name,
+	}, nil
+}
+
+// isDriveQuotaError checks if google drive doesn't allow more downloads of a file right now
+func isDriveQuotaError(err error) bool {
+	if errG, ok := err.(*googleapi.Error); ok {
+		return strings.Contains(errG.Error(), "The download quota for this file has been exceeded")
+	}
+	return false
+}
+
+// randomPicturesStorageSource posts pictures uploaded to the object storage, used by storage and channel sources
+type randomPicturesStorageSource struct{}
+
+func (s *randomPicturesStorageSource) listFiles(sourceEntry models.RandompictureSourceEntry) ([]*RandomPictures_File, error) {
+	if len(sourceEntry.ObjectNames) <= 0 {
+		return nil, nil
+	}
+
+	var storageEntries []models.StorageEntry
+	err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.StorageTable).Find(
+		bson.M{"objectname": bson.M{"$in": sourceEntry.ObjectNames}},
+	)).All(&storageEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*RandomPictures_File
+	for _, storageEntry := range storageEntries {
+		files = append(files, &RandomPictures_File{
+			Id:       storageEntry.ObjectName,
+			Name:     storageEntry.Filename,
+			Size:     int64(storageEntry.Filesize),
+			MimeType: storageEntry.MimeType,
+		})
+	}
+	return files, nil
+}
+
+func (s *randomPicturesStorageSource) getItem(sourceEntry models.RandompictureSourceEntry, file *RandomPictures_File, fileHash string) (*randomPicturesItem, error) {
+	link, err := helpers.GetFileLink(file.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &randomPicturesItem{
+		Link:     link,
+		Title:    file.Name,
+		Filename: file.Name,
+	}, nil
+}
+
+// randomPicturesDirectorySource reads pictures from a local directory, pictures are uploaded to the object storage the first time they are posted
+type randomPicturesDirectorySource struct{}
+
+func (s *randomPicturesDirectorySource) listFiles(sourceEntry models.RandompictureSourceEntry) ([]*RandomPictures_File, error) {
+	if sourceEntry.Directory == "" {
+		return nil, errors.New("directory is not set")
+	}
+
+	var files []*RandomPictures_File
+	err := filepath.Walk(sourceEntry.Directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Size() > 8000000 { // bigger than 8 MB? (discords file size limit)
+			return nil
+		}
+		if !isRandomPicturesImageFilename(info.Name()) {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(sourceEntry.Directory, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, &RandomPictures_File{
+			Id:   relativePath,
+			Name: info.Name(),
+			Size: info.Size(),
+		})
+		return nil
+	})
+	return files, err
+}
+
+func (s *randomPicturesDirectorySource) getItem(sourceEntry models.RandompictureSourceEntry, file *RandomPictures_File, fileHash string) (*randomPicturesItem, error) {
+	key := fmt.Sprintf("robyul2-discord:randompictures:directory-objects:%s", fileHash)
+	redisClient := cache.GetRedisClient()
+
+	objectName := redisClient.Get(key).Val()
+	if objectName == "" {
+		path := filepath.Join(sourceEntry.Directory, file.Id)
+		if !strings.HasPrefix(path, filepath.Clean(sourceEntry.Directory)+string(os.PathSeparator)) {
+			return nil, errors.New("picture is outside of the directory")
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		objectName, err = helpers.AddFile("", data, helpers.AddFileMetadata{
+			Filename: file.Name,
+			GuildID:  sourceEntry.GuildID,
+			AdditionalMetadata: map[string]string{
+				"randompictures_source": helpers.MdbIdToHuman(sourceEntry.ID),
+				"path":                  file.Id,
+			},
+		}, "randompictures", true)
+		if err != nil {
+			return nil, err
+		}
+
+		err = redisClient.Set(key, objectName, 0).Err()
+		helpers.RelaxLog(err)
+	}
+
+	link, err := helpers.GetFileLink(objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &randomPicturesItem{
+		Link:     link,
+		Title:    file.Name,
+		Filename: file.Name,
+	}, nil
+}
+
+// isRandomPicturesImageFilename checks if the file name has the extension of a supported image type
+func isRandomPicturesImageFilename(filename string) bool {
+	extension := strings.ToLower(filepath.Ext(filename))
+	for _, imageExtension := range randomPicturesImageExtensions {
+		if extension == imageExtension {
+			return true
+		}
+	}
+	return false
+}
+
+// loadUploadChannels remembers the upload channels of all channel sources
+func (rp *RandomPictures) loadUploadChannels() error {
+	var rpSources []models.RandompictureSourceEntry
+	err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.RandompictureSourcesTable).Find(
+		bson.M{"type": models.RandompictureSourceTypeChannel},
+	)).All(&rpSources)
+	if err != nil {
+		return err
+	}
+
+	uploadChannels := make(map[string]bson.ObjectId)
+	for _, rpSource := range rpSources {
+		for _, uploadChannelID := range rpSource.UploadChannelIDs {
+			uploadChannels[uploadChannelID] = rpSource.ID
+		}
+	}
+
+	randomPicturesUploadChannelsMutex.Lock()
+	randomPicturesUploadChannels = uploadChannels
+	randomPicturesUploadChannelsMutex.Unlock()
+	return nil
+}
+
+// OnMessageCreate adds pictures posted in upload channels to their channel source
+func (rp *RandomPictures) OnMessageCreate(session *discordgo.Session, message *discordgo.MessageCreate) {
+	defer helpers.Recover()
+
+	if message.Author == nil || message.Author.Bot || len(message.Attachments) <= 0 {
+		return
+	}
+
+	randomPicturesUploadChannelsMutex.RLock()
+	sourceID, isUploadChannel := randomPicturesUploadChannels[message.ChannelID]
+	randomPicturesUploadChannelsMutex.RUnlock()
+	if !isUploadChannel {
+		return
+	}
+
+	var sourceEntry models.RandompictureSourceEntry
+	err := helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.RandompictureSourcesTable).Find(bson.M{"_id": sourceID}),
+		&sourceEntry,
+	)
+	if err != nil {
+		helpers.RelaxLog(err)
+		return
+	}
+
+	added, err := rp.addPicturesToSource(sourceEntry, message.Message)
+	helpers.RelaxLog(err)
+	if added > 0 {
+		session.MessageReactionAdd(message.ChannelID, message.ID, "✅")
+	}
+}
+
+// addPicturesToSource uploads the pictures attached to the message to the object storage and adds them to a storage or channel source
+func (rp *RandomPictures) addPicturesToSource(sourceEntry models.RandompictureSourceEntry, msg *discordgo.Message) (int, error) {
+	var objectNames []string
+	for _, attachment := range msg.Attachments {
+		if attachment.Size > 8000000 || !isRandomPicturesImageFilename(attachment.Filename) {
+			continue
+		}
+
+		data, err := helpers.NetGetUAWithError(attachment.URL, helpers.DEFAULT_UA)
+		if err != nil {
+			helpers.RelaxLog(err)
+			continue
+		}
+
+		objectName, err := helpers.AddFile("", data, helpers.AddFileMetadata{
+			Filename:  attachment.Filename,
+			ChannelID: msg.ChannelID,
+			UserID:    msg.Author.ID,
+			AdditionalMetadata: map[string]string{
+				"randompictures_source": helpers.MdbIdToHuman(sourceEntry.ID),
+			},
+		}, "randompictures", true)
+		if err != nil {
+			helpers.RelaxLog(err)
+			continue
+		}
+
+		objectNames = append(objectNames, objectName)
+	}
+
+	if len(objectNames) <= 0 {
+		return 0, nil
+	}
+
+	err := helpers.MdbCollection(models.RandompictureSourcesTable).UpdateId(
+		sourceEntry.ID,
+		bson.M{"$push": bson.M{"objectnames": bson.M{"$each": objectNames}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// update the cache right away so the new pictures can be posted
+	// the entry is reloaded, so pictures added by other messages in the meantime are cached as well
+	err = helpers.MdbOneWithoutLogging(
+		helpers.MdbCollection(models.RandompictureSourcesTable).Find(bson.M{"_id": sourceEntry.ID}),
+		&sourceEntry,
+	)
+	if err != nil {
+		return len(objectNames), err
+	}
+	_, err = rp.cacheFiles(sourceEntry, 7*24*time.Hour)
+	return len(objectNames), err
+}
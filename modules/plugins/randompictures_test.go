@@ -0,0 +1,49 @@
+package plugins
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Seklfreak/Robyul2/models"
+)
+
+func TestGetRandomPicturesWeight(t *testing.T) {
+	if getRandomPicturesWeight(-1, 100) != 1 {
+		t.Fatal("pictures not in the history should always be posted")
+	}
+	if getRandomPicturesWeight(0, 100) >= getRandomPicturesWeight(50, 100) {
+		t.Fatal("the newest picture should have the lowest chance")
+	}
+	if weight := getRandomPicturesWeight(99, 100); weight >= 1 || weight <= 0.9 {
+		t.Fatal("the oldest picture should have almost full chance", weight)
+	}
+}
+
+func TestRandomPicturesDirectorySource(t *testing.T) {
+	directory, err := ioutil.TempDir("", "randompictures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	err = os.MkdirAll(filepath.Join(directory, "album"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.jpg", "album/b.PNG", "notes.txt"} {
+		err = ioutil.WriteFile(filepath.Join(directory, name), []byte("data"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := (&randomPicturesDirectorySource{}).listFiles(models.RandompictureSourceEntry{Directory: directory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Id != "a.jpg" || files[1].Id != filepath.Join("album", "b.PNG") {
+		t.Fatal("unexpected files", files)
+	}
+}
@@ -22,8 +22,11 @@ func (r *Ratelimit) Init(session *shardmanager.Manager) {
 }
 
 func (r *Ratelimit) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
+	remaining, err := ratelimits.RemainingCommands(msg.Author.ID)
+	helpers.Relax(err)
+
 	helpers.SendMessage(
 		msg.ChannelID,
-		"You've still got "+strconv.Itoa(int(ratelimits.Container.Get(msg.Author.ID)))+" commands left",
+		"You've still got "+strconv.Itoa(remaining)+" commands left",
 	)
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/Seklfreak/Robyul2/version"
@@ -49,8 +50,8 @@ func (r *Reddit) Commands() []string {
 func (r *Reddit) Init(session *shardmanager.Manager) {
 	var err error
 	redditSession, err = geddit.NewOAuthSession(
-		helpers.GetConfig().Path("reddit.id").Data().(string),
-		helpers.GetConfig().Path("reddit.secret").Data().(string),
+		helpers.GetConfig().Reddit.ID,
+		helpers.GetConfig().Reddit.Secret,
 		RedditUserAgent,
 		"https://robyul.chat",
 	)
@@ -60,8 +61,8 @@ func (r *Reddit) Init(session *shardmanager.Manager) {
 		return
 	}
 	err = redditSession.LoginAuth(
-		helpers.GetConfig().Path("reddit.username").Data().(string),
-		helpers.GetConfig().Path("reddit.password").Data().(string),
+		helpers.GetConfig().Reddit.Username,
+		helpers.GetConfig().Reddit.Password,
 	)
 	if err != nil {
 		r.logger().WithError(err).Error("failed to login to reddit")
@@ -87,6 +88,8 @@ func (r *Reddit) checkSubredditLoop() {
 	var newPost bool
 
 	for {
+		start := time.Now()
+
 		err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.RedditSubredditsTable).Find(nil)).All(&entries)
 		helpers.Relax(err)
 
@@ -119,8 +122,8 @@ func (r *Reddit) checkSubredditLoop() {
 				if strings.Contains(err.Error(), "oauth2: token expired and refresh token is not set") {
 					// login when token expired
 					err = redditSession.LoginAuth(
-						helpers.GetConfig().Path("reddit.username").Data().(string),
-						helpers.GetConfig().Path("reddit.password").Data().(string),
+						helpers.GetConfig().Reddit.Username,
+						helpers.GetConfig().Reddit.Password,
 					)
 					helpers.Relax(err)
 					r.logger().Warn("logged in again after token expired")
@@ -174,6 +177,8 @@ func (r *Reddit) checkSubredditLoop() {
 			time.Sleep(2 * time.Second)
 		}
 
+		prom.FeedLoopDuration.WithLabelValues("reddit").Observe(time.Since(start).Seconds())
+
 		if len(entries) <= 10 {
 			time.Sleep(time.Second * 60)
 		}
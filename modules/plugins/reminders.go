@@ -43,6 +43,12 @@ func (r *Reminders) Init(session *shardmanager.Manager) {
 		defer helpers.Recover()
 
 		for {
+			// reminders are sent by the process running shard 0, so they are only sent once
+			if !session.OwnsShard(0) {
+				time.Sleep(10 * time.Second)
+				continue
+			}
+
 			reminderBucket := make([]models.RemindersEntry, 0)
 			err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.RemindersTable).Find(nil)).All(&reminderBucket)
 			if err != nil {
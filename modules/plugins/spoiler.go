@@ -46,7 +46,7 @@ func (s *Spoiler) Commands() []string {
 func (s *Spoiler) Init(session *shardmanager.Manager) {
 	var err error
 
-	s.cachePath = helpers.GetConfig().Path("cache_folder").Data().(string)
+	s.cachePath = helpers.GetConfig().CacheFolder
 
 	s.ffmpegBinary, err = exec.LookPath("ffmpeg")
 	if err != nil {
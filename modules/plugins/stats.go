@@ -1546,10 +1546,22 @@ func (s *Stats) Action(command string, content string, msg *discordgo.Message, s
 		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
 		return
 	case "shardingstats":
+		args := strings.Fields(content)
+		if len(args) > 0 && strings.ToLower(args[0]) == "restart" {
+			helpers.RequireBotAdmin(msg, func() {
+				s.restartShards(args[1:], msg)
+			})
+			return
+		}
+
 		fullStatus := cache.GetSession().GetFullStatus()
 		var content string
 		for _, shard := range fullStatus.Shards {
-			content += fmt.Sprintf("Shard **%d**: %s guilds\n", shard.Shard, humanize.Comma(int64(shard.NumGuilds)))
+			content += fmt.Sprintf("Shard **%d**: %s guilds", shard.Shard, humanize.Comma(int64(shard.NumGuilds)))
+			if shard.Process != "" {
+				content += fmt.Sprintf(" (`%s`)", shard.Process)
+			}
+			content += "\n"
 		}
 		content += fmt.Sprintf("total guilds: %s", humanize.Comma(int64(fullStatus.NumGuilds)))
 
@@ -1559,6 +1571,63 @@ func (s *Stats) Action(command string, content string, msg *discordgo.Message, s
 	}
 }
 
+// restartShards restarts one shard, or all shards of this process one after another
+func (s *Stats) restartShards(args []string, msg *discordgo.Message) {
+	manager := cache.GetSession()
+
+	if len(args) > 0 && strings.ToLower(args[0]) == "all" {
+		cache.GetLogger().WithField("module", "stats").Warnf("restarting all shards on request by %s#%s (%s)",
+			msg.Author.Username, msg.Author.Discriminator, msg.Author.ID)
+
+		_, err := helpers.SendMessage(msg.ChannelID,
+			helpers.GetTextForMessage(msg, "plugins.stats.shards-restarting", len(manager.OwnedShards())))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+
+		err = manager.RollingRestart()
+		helpers.Relax(err)
+
+		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.stats.shards-restarted"))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+
+	var shard int
+	var err error
+	if len(args) > 0 {
+		shard, err = strconv.Atoi(args[0])
+	}
+	if len(args) <= 0 || err != nil || shard < 0 || shard >= manager.GetNumShards() {
+		prefix := helpers.GetPrefixForServer(msg.GuildID)
+		_, err = helpers.SendMessage(msg.ChannelID,
+			helpers.GetTextForMessage(msg, "plugins.stats.shard-invalid", prefix, prefix))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+
+	if !manager.OwnsShard(shard) {
+		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.stats.shard-not-owned", shard))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+
+	cache.GetLogger().WithField("module", "stats").Warnf("restarting shard %d on request by %s#%s (%s)",
+		shard, msg.Author.Username, msg.Author.Discriminator, msg.Author.ID)
+
+	_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.stats.shard-restarting", shard))
+	helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+
+	err = manager.RestartShard(shard)
+	if err == shardmanager.ErrShardRestarting {
+		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.stats.shard-already-restarting", shard))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
+	}
+	helpers.Relax(err)
+
+	_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.stats.shard-restarted", shard))
+	helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+}
+
 func (r *Stats) setEmbedEmojiPage(reactionEmbed *discordgo.MessageEmbed, author *discordgo.User, guild *discordgo.Guild, pageN int, maxPagesN int) {
 	reactionEmbed.Fields = []*discordgo.MessageEmbedField{}
 	pageText := ""
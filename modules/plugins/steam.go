@@ -34,7 +34,7 @@ func (m *Steam) Commands() []string {
 }
 
 func (m *Steam) Init(session *shardmanager.Manager) {
-	m.apiKey = helpers.GetConfig().Path("steam.api_key").Data().(string)
+	m.apiKey = helpers.GetConfig().Steam.APIKey
 }
 
 func (m *Steam) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
@@ -36,7 +36,7 @@ var (
 )
 
 func (s *Streamable) Init(session *shardmanager.Manager) {
-	data, err := ioutil.ReadFile(helpers.GetConfig().Path("assets_folder").Data().(string) + "providers.json")
+	data, err := ioutil.ReadFile(helpers.GetConfig().AssetsFolder + "providers.json")
 	helpers.Relax(err)
 
 	oEmbedHandler = oembed.NewOembed()
@@ -106,8 +106,8 @@ func (s *Streamable) Action(command string, content string, msg *discordgo.Messa
 	request, err := http.NewRequest("GET", createStreamableEndpoint, nil)
 	helpers.Relax(err)
 	request.Header.Add("user-agent", helpers.DEFAULT_UA)
-	request.SetBasicAuth(helpers.GetConfig().Path("streamable.username").Data().(string),
-		helpers.GetConfig().Path("streamable.password").Data().(string))
+	request.SetBasicAuth(helpers.GetConfig().Streamable.Username,
+		helpers.GetConfig().Streamable.Password)
 	response, err := httpClient.Do(request)
 	if err != nil {
 		if strings.Contains(err.Error(), "Client.Timeout exceeded while awaiting headers") {
@@ -1,32 +1,42 @@
 package plugins
 
 import (
-	"bytes"
-	"context"
-	"encoding/base64"
-	"errors"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
+	"unicode/utf8"
 
-	"cloud.google.com/go/translate"
-	"github.com/Jeffail/gabs"
+	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/models"
+	"github.com/Seklfreak/Robyul2/services/translation"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
-	"golang.org/x/text/language"
-	"google.golang.org/api/option"
+	"github.com/globalsign/mgo/bson"
 )
 
 type Translator struct {
-	ctx    context.Context
-	client *translate.Client
+	// providers translate the texts, the first provider which supports the languages is used
+	providers []translation.TranslationProvider
+	// alternative translates Korean for a second opinion
+	alternative translation.TranslationProvider
 }
 
 const (
 	googleTranslateHexColor = "#4285f4"
-	naverTranslateEndpoint  = "https://papago.naver.com/apis/n2mt/translate"
+
+	// translatorChannelsCacheName is used to reload the translator channels in all processes
+	translatorChannelsCacheName = "translator-channels"
+	// translatorReactionKey is claimed for every translation of a message by flag reaction, %s is the message and %s the language
+	translatorReactionKey = "robyul2-discord:translator:reaction:%s:%s"
+	// translatorMessageLimit is the maximum length of a Discord message
+	translatorMessageLimit = 2000
+	// translatorDescriptionLimit is the maximum length of an embed description
+	translatorDescriptionLimit = 2048
+)
+
+var (
+	translatorChannels []models.TranslatorChannelEntry
 )
 
 func (t *Translator) Commands() []string {
@@ -38,14 +48,31 @@ func (t *Translator) Commands() []string {
 }
 
 func (t *Translator) Init(session *shardmanager.Manager) {
-	t.ctx = context.Background()
+	if helpers.GetConfig().Google.APIKey != "" {
+		google, err := translation.NewGoogle(helpers.GetConfig().Google.APIKey)
+		helpers.Relax(err)
+		t.providers = append(t.providers, google)
+	}
+	if helpers.GetConfig().Translator.LibreTranslate.URL != "" {
+		t.providers = append(t.providers, translation.NewLibreTranslate(
+			helpers.GetConfig().Translator.LibreTranslate.URL,
+			helpers.GetConfig().Translator.LibreTranslate.APIKey,
+		))
+	}
+	t.alternative = translation.NewPapago()
 
-	client, err := translate.NewClient(
-		t.ctx,
-		option.WithAPIKey(helpers.GetConfig().Path("google.api_key").Data().(string)),
-	)
+	var err error
+	translatorChannels, err = t.getTranslatorChannels()
 	helpers.Relax(err)
-	t.client = client
+
+	helpers.OnCacheInvalidation(translatorChannelsCacheName, func() (err error) {
+		translatorChannels, err = t.getTranslatorChannels()
+		return err
+	})
+}
+
+func (t *Translator) Uninit(session *shardmanager.Manager) {
+
 }
 
 func (t *Translator) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
@@ -53,124 +80,476 @@ func (t *Translator) Action(command string, content string, msg *discordgo.Messa
 		return
 	}
 
+	args := strings.Fields(content)
+	if len(args) >= 1 {
+		switch args[0] {
+		case "channel", "channels":
+			t.actionChannel(args[1:], msg, session)
+			return
+		case "reactions":
+			t.actionReactions(args[1:], msg)
+			return
+		}
+	}
+
 	session.ChannelTyping(msg.ChannelID)
-	// Assumed format: <lang_in> <lang_out> <text>
+	// Assumed format: [<lang_in>] <lang_out> <text>
 	parts := strings.Fields(content)
 
-	if len(parts) < 3 {
-		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.translator.check_format"))
+	if len(parts) < 2 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.check_format"))
 		return
 	}
 
-	source, err := language.Parse(parts[0])
-	if err != nil {
-		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.translator.unknown_lang_specific", parts[0]))
+	source := translation.AutoDetect
+	target, ok := translation.ParseLanguage(parts[0])
+	if !ok || target == translation.AutoDetect {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.unknown_lang_specific", parts[0]))
 		return
 	}
+	text := strings.Join(parts[1:], " ")
+	// with two languages the first one is the source
+	if len(parts) >= 3 {
+		if secondLanguage, ok := translation.ParseLanguage(parts[1]); ok && secondLanguage != translation.AutoDetect {
+			source, target = target, secondLanguage
+			text = strings.Join(parts[2:], " ")
+		}
+	}
 
-	target, err := language.Parse(parts[1])
-	if err != nil {
-		helpers.SendMessage(msg.ChannelID, helpers.GetTextF("plugins.translator.unknown_lang_specific", parts[1]))
+	result, err := translation.Translate(t.providers, text, source, target)
+	if err == translation.ErrUnsupportedLanguage {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.unknown_lang"))
 		return
 	}
+	helpers.Relax(err)
 
-	fullInputText := ""
-	for _, partInputText := range parts[2:] {
-		fullInputText += " " + partInputText
+	translateEmbed := &discordgo.MessageEmbed{
+		Title:       t.embedTitle(helpers.GetLocaleForUser(msg.GuildID, msg.Author.ID), source, result),
+		Footer:      &discordgo.MessageEmbedFooter{Text: helpers.GetTextForMessage(msg, "plugins.translator.embed-footer", result.Provider.Website())},
+		Description: result.Text,
+		Color:       helpers.GetDiscordColorFromHex(googleTranslateHexColor),
+		Fields:      []*discordgo.MessageEmbedField{},
 	}
-	fullInputText = strings.TrimSpace(strings.Replace(fullInputText, "\n", " ", -1))
-	targetInput := ""
-	var targetInputs []string
 
-	for _, word := range strings.Fields(fullInputText) {
-		if len(targetInput)+len(word)+1 < 200 {
-			targetInput += " " + word
+	if t.alternative != nil && t.alternative.Supports(result.Source, target) && t.alternative.Name() != result.Provider.Name() {
+		alternativeResult, err := t.alternative.Translate(text, result.Source, target)
+		if err != nil {
+			helpers.SendError(msg, err)
 		} else {
-			targetInputs = append(targetInputs, strings.TrimSpace(targetInput))
-			targetInput = ""
-			if len(word) < 200 {
-				targetInput = word
+			translateEmbed.Fields = append(translateEmbed.Fields, &discordgo.MessageEmbedField{
+				Name:  helpers.GetTextForMessage(msg, "plugins.translator.embed-title-alternative-naver"),
+				Value: alternativeResult.Text,
+			})
+			translateEmbed.Footer = &discordgo.MessageEmbedFooter{Text: helpers.GetTextForMessage(msg, "plugins.translator.embed-footer-plus-naver",
+				result.Provider.Website(), alternativeResult.Provider.Website())}
+		}
+	}
+
+	_, err = helpers.SendEmbed(msg.ChannelID, translateEmbed)
+	helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
+}
+
+// embedTitle returns the title of a translation, detected source languages are marked
+func (t *Translator) embedTitle(locale, source string, result translation.Translation) string {
+	sourceText := strings.ToUpper(result.Source)
+	if source == translation.AutoDetect {
+		sourceText = helpers.GetTextForLocale(locale, "plugins.translator.detected-language", sourceText)
+	}
+	return helpers.GetTextForLocale(locale, "plugins.translator.translation-embed-title", sourceText, strings.ToUpper(result.Target))
+}
+
+// [p]translate channel add <#channel> <language> [<#target channel>], [p]translate channel list or [p]translate channel remove <id>
+func (t *Translator) actionChannel(args []string, msg *discordgo.Message, session *discordgo.Session) {
+	var subCommand string
+	if len(args) >= 1 {
+		subCommand = args[0]
+	}
+
+	channel, err := helpers.GetChannel(msg.ChannelID)
+	helpers.Relax(err)
+
+	switch subCommand {
+	case "add":
+		helpers.RequireMod(msg, func() {
+			if len(args) < 3 {
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+				return
 			}
+			session.ChannelTyping(msg.ChannelID)
+
+			sourceChannel, err := helpers.GetChannelFromMention(msg, args[1])
+			if err != nil || sourceChannel.ID == "" || sourceChannel.GuildID != channel.GuildID {
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+				return
+			}
+			targetLanguage, ok := translation.ParseLanguage(args[2])
+			if !ok || targetLanguage == translation.AutoDetect {
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.unknown_lang_specific", args[2]))
+				return
+			}
+			targetChannel := sourceChannel
+			if len(args) >= 4 {
+				targetChannel, err = helpers.GetChannelFromMention(msg, args[3])
+				if err != nil || targetChannel.ID == "" || targetChannel.GuildID != channel.GuildID {
+					helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+					return
+				}
+			}
+
+			newID, err := helpers.MDbInsert(models.TranslatorChannelsTable, models.TranslatorChannelEntry{
+				GuildID:         channel.GuildID,
+				ChannelID:       sourceChannel.ID,
+				TargetLanguage:  targetLanguage,
+				TargetChannelID: targetChannel.ID,
+				AddedByUserID:   msg.Author.ID,
+				AddedAt:         time.Now(),
+			})
+			helpers.Relax(err)
+
+			_, err = helpers.EventlogLog(time.Now(), channel.GuildID, helpers.MdbIdToHuman(newID),
+				models.EventlogTargetTypeRobyulTranslatorChannel, msg.Author.ID,
+				models.EventlogTypeRobyulTranslatorChannelAdd, "",
+				nil,
+				[]models.ElasticEventlogOption{
+					{
+						Key:   "translator_channelid",
+						Value: sourceChannel.ID,
+						Type:  models.EventlogTargetTypeChannel,
+					},
+					{
+						Key:   "translator_targetlanguage",
+						Value: targetLanguage,
+					},
+					{
+						Key:   "translator_targetchannelid",
+						Value: targetChannel.ID,
+						Type:  models.EventlogTargetTypeChannel,
+					},
+				}, false)
+			helpers.RelaxLog(err)
+
+			err = helpers.InvalidateCache(translatorChannelsCacheName)
+			helpers.RelaxLog(err)
+
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.channel-add-success",
+				sourceChannel.ID, strings.ToUpper(targetLanguage), targetChannel.ID))
+			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		})
+		return
+	case "delete", "del", "remove":
+		helpers.RequireMod(msg, func() {
+			if len(args) < 2 {
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.too-few"))
+				return
+			}
+
+			var entry models.TranslatorChannelEntry
+			err = helpers.MdbOne(
+				helpers.MdbCollection(models.TranslatorChannelsTable).Find(bson.M{"guildid": channel.GuildID, "_id": helpers.HumanToMdbId(args[1])}),
+				&entry,
+			)
+			if helpers.IsMdbNotFound(err) {
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.channel-not-found"))
+				return
+			}
+			helpers.Relax(err)
+
+			err = helpers.MDbDelete(models.TranslatorChannelsTable, entry.ID)
+			helpers.Relax(err)
+
+			_, err = helpers.EventlogLog(time.Now(), entry.GuildID, helpers.MdbIdToHuman(entry.ID),
+				models.EventlogTargetTypeRobyulTranslatorChannel, msg.Author.ID,
+				models.EventlogTypeRobyulTranslatorChannelRemove, "",
+				nil,
+				[]models.ElasticEventlogOption{
+					{
+						Key:   "translator_channelid",
+						Value: entry.ChannelID,
+						Type:  models.EventlogTargetTypeChannel,
+					},
+					{
+						Key:   "translator_targetlanguage",
+						Value: entry.TargetLanguage,
+					},
+					{
+						Key:   "translator_targetchannelid",
+						Value: entry.TargetChannelID,
+						Type:  models.EventlogTargetTypeChannel,
+					},
+				}, false)
+			helpers.RelaxLog(err)
+
+			err = helpers.InvalidateCache(translatorChannelsCacheName)
+			helpers.RelaxLog(err)
+
+			_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.channel-remove-success"))
+			helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		})
+		return
+	case "", "list":
+		var entryBucket []models.TranslatorChannelEntry
+		err = helpers.MDbIter(helpers.MdbCollection(models.TranslatorChannelsTable).Find(bson.M{"guildid": channel.GuildID})).All(&entryBucket)
+		helpers.Relax(err)
+
+		if len(entryBucket) <= 0 {
+			helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "plugins.translator.channel-list-empty"))
+			return
 		}
+
+		resultMessage := helpers.GetTextForMessage(msg, "plugins.translator.channel-list-title") + "\n"
+		for _, entry := range entryBucket {
+			resultMessage += fmt.Sprintf("`%s`: translating <#%s> into **%s** in <#%s>\n",
+				helpers.MdbIdToHuman(entry.ID), entry.ChannelID, strings.ToUpper(entry.TargetLanguage), entry.TargetChannelID)
+		}
+
+		_, err = helpers.SendMessage(msg.ChannelID, resultMessage)
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+		return
 	}
-	targetInputs = append(targetInputs, strings.TrimSpace(targetInput))
 
-	m := ""
-	for _, partToTranslate := range targetInputs {
-		translations, err := t.client.Translate(
-			t.ctx,
-			strings.Fields(partToTranslate),
-			target,
-			&translate.Options{
-				Format: translate.Text,
-				Source: source,
-				Model:  "nmt",
-			},
-		)
+	helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+}
+
+// [p]translate reactions <enable|disable>
+func (t *Translator) actionReactions(args []string, msg *discordgo.Message) {
+	helpers.RequireMod(msg, func() {
+		channel, err := helpers.GetChannel(msg.ChannelID)
 		helpers.Relax(err)
-		for _, piece := range translations {
-			m += piece.Text + " "
+
+		settings := helpers.GuildSettingsGetCached(channel.GuildID)
+		enabled := !settings.TranslatorFlagReactions
+		if len(args) >= 1 {
+			switch args[0] {
+			case "enable", "on":
+				enabled = true
+			case "disable", "off":
+				enabled = false
+			default:
+				helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.arguments.invalid"))
+				return
+			}
 		}
+
+		oldEnabled := settings.TranslatorFlagReactions
+		settings.TranslatorFlagReactions = enabled
+		err = helpers.GuildSettingsSet(channel.GuildID, settings)
+		helpers.Relax(err)
+
+		_, err = helpers.EventlogLog(time.Now(), channel.GuildID, channel.GuildID,
+			models.EventlogTargetTypeGuild, msg.Author.ID,
+			models.EventlogTypeRobyulTranslatorConfigUpdate, "",
+			[]models.ElasticEventlogChange{
+				{
+					Key:      "translator_flag_reactions",
+					OldValue: helpers.StoreBoolAsString(oldEnabled),
+					NewValue: helpers.StoreBoolAsString(enabled),
+				},
+			},
+			nil, false)
+		helpers.RelaxLog(err)
+
+		successKey := "plugins.translator.reactions-disabled"
+		if enabled {
+			successKey = "plugins.translator.reactions-enabled"
+		}
+		_, err = helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, successKey))
+		helpers.RelaxMessage(err, msg.ChannelID, msg.ID)
+	})
+}
+
+func (t *Translator) getTranslatorChannels() (entries []models.TranslatorChannelEntry, err error) {
+	err = helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.TranslatorChannelsTable).Find(nil)).All(&entries)
+	return
+}
+
+// OnMessage reposts messages in translator channels translated, with a webhook which looks like the author
+func (t *Translator) OnMessage(content string, msg *discordgo.Message, session *discordgo.Session) {
+	if msg.Author == nil || msg.Author.Bot || msg.WebhookID != "" || strings.TrimSpace(msg.Content) == "" {
+		return
 	}
 
-	translatedNaverResult := ""
-	if (strings.ToLower(source.String()) == "en" || strings.ToLower(source.String()) == "ko") && (strings.ToLower(target.String()) == "en" || strings.ToLower(target.String()) == "ko") {
-		for _, partToTranslaste := range targetInputs {
-			data := url.Values{}
-			decoded, err := base64.StdEncoding.DecodeString("rlWxnJA0VwczLJkmZSwiZGljdERpc3BsYXkiOjUsInNvdXJjZSI6ImVuIiwidGFyZ2V0Ijoia28iLCJ0ZXh0IjoiQUFBQUFBQUFBQSJ9")
-			helpers.Relax(err)
-			jsonData := string(decoded)
-			jsonData = strings.Replace(jsonData, "\"dictDisplay\":5", "\"dictDisplay\":0", -1)
-			jsonData = strings.Replace(jsonData, "\"source\":\"en\"", fmt.Sprintf("\"source\":\"%s\"", source.String()), -1)
-			jsonData = strings.Replace(jsonData, "\"target\":\"ko\"", fmt.Sprintf("\"target\":\"%s\"", target.String()), -1)
-			jsonData = strings.Replace(jsonData, "\"text\":\"AAAAAAAAAA\"", fmt.Sprintf("\"text\":\"%s\"", strings.Replace(partToTranslaste, "\"", "'", -1)), -1)
-			data.Set("data", base64.StdEncoding.EncodeToString([]byte(jsonData)))
-
-			request, err := http.NewRequest("POST", naverTranslateEndpoint, bytes.NewBufferString(data.Encode()))
-			if err != nil {
-				helpers.SendError(msg, err)
-				continue
+	for _, translatorChannel := range translatorChannels {
+		if translatorChannel.ChannelID != msg.ChannelID {
+			continue
+		}
+
+		go func(translatorChannel models.TranslatorChannelEntry) {
+			defer helpers.Recover()
+
+			// ignore commands
+			prefix := helpers.GetPrefixForServer(translatorChannel.GuildID)
+			if prefix != "" && strings.HasPrefix(content, prefix) {
+				return
+			}
+			if !helpers.ModuleIsAllowedSilent(msg.ChannelID, msg.ID, msg.Author.ID, helpers.ModulePermTranslator) {
+				return
 			}
 
-			request.Header.Set("User-Agent", helpers.DEFAULT_UA)
-			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-			response, err := helpers.DefaultClient.Do(request)
+			err := t.repostTranslated(translatorChannel, msg)
 			if err != nil {
-				helpers.SendError(msg, err)
-				continue
+				cache.GetLogger().WithField("module", "translator").WithField("channelID", msg.ChannelID).Warnf(
+					"reposting translated message failed: %s", err.Error())
 			}
+		}(translatorChannel)
+	}
+}
 
-			if response != nil && response.Body != nil {
-				defer response.Body.Close()
-			}
+// repostTranslated posts the translated message, messages which are in the target language already are skipped
+func (t *Translator) repostTranslated(translatorChannel models.TranslatorChannelEntry, msg *discordgo.Message) (err error) {
+	result, err := translation.Translate(t.providers, msg.Content, translation.AutoDetect, translatorChannel.TargetLanguage)
+	if err != nil {
+		return err
+	}
+	if translation.SameLanguage(result.Source, translatorChannel.TargetLanguage) || strings.EqualFold(result.Text, msg.Content) {
+		return nil
+	}
 
-			if response.StatusCode == 200 {
-				resultParsed, err := gabs.ParseJSONBuffer(response.Body)
-				if err != nil {
-					helpers.SendError(msg, err)
-					continue
-				}
-				translatedNaverResult += " " + resultParsed.Path("translatedText").Data().(string)
-			} else {
-				helpers.SendError(msg, errors.New(fmt.Sprintf("Unexpected status code from Naver API: %d", response.StatusCode)))
-			}
-		}
+	text := translatorTruncate(result.Text)
+
+	webhook, err := helpers.GetWebhook(translatorChannel.GuildID, translatorChannel.TargetChannelID)
+	if err != nil && !strings.Contains(err.Error(), "no permission to manage webhooks") {
+		return err
+	}
+	if webhook != nil && webhook.ID != "" && webhook.Token != "" {
+		_, err = helpers.WebhookExecuteWithResult(
+			webhook.ID,
+			webhook.Token,
+			&discordgo.WebhookParams{
+				Content:         text,
+				Username:        msg.Author.Username,
+				AvatarURL:       helpers.GetAvatarUrl(msg.Author),
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
+			},
+		)
+		return err
 	}
 
-	translateEmbed := &discordgo.MessageEmbed{
-		Title:       helpers.GetTextF("plugins.translator.translation-embed-title", strings.ToUpper(source.String()), strings.ToUpper(target.String())),
-		Footer:      &discordgo.MessageEmbedFooter{Text: helpers.GetText("plugins.translator.embed-footer")},
-		Description: m,
-		Color:       helpers.GetDiscordColorFromHex(googleTranslateHexColor),
-		Fields:      []*discordgo.MessageEmbedField{},
+	// without webhook permissions the translation is posted by Robyul, mentions in the translation are not pinged
+	_, err = cache.GetSession().Session(0).ChannelMessageSendComplex(translatorChannel.TargetChannelID, &discordgo.MessageSend{
+		Content:         translatorTruncate(fmt.Sprintf("**%s**: %s", msg.Author.Username, text)),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	return err
+}
+
+// translatorTruncate cuts texts which do not fit into a Discord message
+func translatorTruncate(text string) string {
+	if utf8.RuneCountInString(text) <= translatorMessageLimit {
+		return text
+	}
+	return string([]rune(text)[:translatorMessageLimit-1]) + "…"
+}
+
+// translatorTruncateDescription cuts texts which do not fit into an embed description,
+// the description is cut at a character because helpers.TruncateEmbed counts bytes
+func translatorTruncateDescription(text string) string {
+	if len(text) <= translatorDescriptionLimit {
+		return text
 	}
-	if translatedNaverResult != "" {
-		translateEmbed.Fields = append(translateEmbed.Fields, &discordgo.MessageEmbedField{
-			Name:  helpers.GetText("plugins.translator.embed-title-alternative-naver"),
-			Value: translatedNaverResult})
-		translateEmbed.Footer = &discordgo.MessageEmbedFooter{Text: helpers.GetText("plugins.translator.embed-footer-plus-naver")}
+	cut := translatorDescriptionLimit - len("…")
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
 	}
+	return text[:cut] + "…"
+}
+
+// OnReactionAdd translates the message into the language of the country if a member reacts with a flag
+func (t *Translator) OnReactionAdd(reaction *discordgo.MessageReactionAdd, session *discordgo.Session) {
+	targetLanguage, ok := translation.LanguageForFlag(reaction.Emoji.Name)
+	if !ok {
+		return
+	}
+
+	go func() {
+		defer helpers.Recover()
+
+		channel, err := helpers.GetChannel(reaction.ChannelID)
+		helpers.Relax(err)
+
+		if !helpers.GuildSettingsGetCached(channel.GuildID).TranslatorFlagReactions {
+			return
+		}
+
+		user, err := helpers.GetUser(reaction.UserID)
+		helpers.Relax(err)
+		if user.Bot {
+			return
+		}
+
+		if !helpers.ModuleIsAllowedSilent(reaction.ChannelID, reaction.MessageID, reaction.UserID, helpers.ModulePermTranslator) {
+			return
+		}
+
+		// every message is translated into every language once, the claim is released if the translation failed
+		claimKey := fmt.Sprintf(translatorReactionKey, reaction.MessageID, targetLanguage)
+		claimed, err := cache.GetRedisClient().SetNX(claimKey, helpers.ProcessName(), time.Hour).Result()
+		if err != nil || !claimed {
+			return
+		}
+
+		message, err := helpers.GetMessage(reaction.ChannelID, reaction.MessageID)
+		if err != nil {
+			cache.GetRedisClient().Del(claimKey)
+			return
+		}
+		if message.Author == nil || strings.TrimSpace(message.Content) == "" {
+			return
+		}
+
+		result, err := translation.Translate(t.providers, message.Content, translation.AutoDetect, targetLanguage)
+		if err != nil {
+			cache.GetRedisClient().Del(claimKey)
+			cache.GetLogger().WithField("module", "translator").WithField("messageID", message.ID).Warnf(
+				"translating message by reaction failed: %s", err.Error())
+			return
+		}
+		if translation.SameLanguage(result.Source, targetLanguage) {
+			return
+		}
+
+		locale := helpers.GetLocaleForUser(channel.GuildID, reaction.UserID)
+		translateEmbed := &discordgo.MessageEmbed{
+			Author: &discordgo.MessageEmbedAuthor{
+				Name:    message.Author.Username,
+				IconURL: helpers.GetAvatarUrl(message.Author),
+				URL:     helpers.MessageDeeplink(message.ChannelID, message.ID),
+			},
+			Title:       t.embedTitle(locale, translation.AutoDetect, result),
+			Description: translatorTruncateDescription(result.Text),
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: helpers.GetTextForLocale(locale, "plugins.translator.embed-footer-reaction", result.Provider.Website(), user.Username),
+			},
+			Color: helpers.GetDiscordColorFromHex(googleTranslateHexColor),
+		}
+
+		_, err = helpers.SendEmbed(reaction.ChannelID, translateEmbed)
+		if err != nil {
+			cache.GetRedisClient().Del(claimKey)
+		}
+		helpers.RelaxLog(err)
+	}()
+}
+
+func (t *Translator) OnMessageDelete(msg *discordgo.MessageDelete, session *discordgo.Session) {
+
+}
+
+func (t *Translator) OnGuildMemberAdd(member *discordgo.Member, session *discordgo.Session) {
+
+}
+
+func (t *Translator) OnGuildMemberRemove(member *discordgo.Member, session *discordgo.Session) {
+
+}
+
+func (t *Translator) OnReactionRemove(reaction *discordgo.MessageReactionRemove, session *discordgo.Session) {
+
+}
+
+func (t *Translator) OnGuildBanAdd(user *discordgo.GuildBanAdd, session *discordgo.Session) {
+
+}
+
+func (t *Translator) OnGuildBanRemove(user *discordgo.GuildBanRemove, session *discordgo.Session) {
 
-	_, err = helpers.SendEmbed(msg.ChannelID, translateEmbed)
-	helpers.RelaxEmbed(err, msg.ChannelID, msg.ID)
 }
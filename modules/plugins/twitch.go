@@ -17,6 +17,7 @@ import (
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
@@ -122,9 +123,9 @@ func (m *Twitch) Commands() []string {
 }
 
 func (m *Twitch) Init(session *shardmanager.Manager) {
-	m.token = helpers.GetConfig().Path("twitch.token").Data().(string)
-	m.secret = helpers.GetConfig().Path("twitch.secret").Data().(string)
-	m.refreshToken = helpers.GetConfig().Path("twitch.refresh_token").Data().(string)
+	m.token = helpers.GetConfig().Twitch.Token
+	m.secret = helpers.GetConfig().Twitch.Secret
+	m.refreshToken = helpers.GetConfig().Twitch.RefreshToken
 
 	go m.checkTwitchFeedsLoop()
 	cache.GetLogger().WithField("module", "twitch").Info("Started twitch loop (60s)")
@@ -238,6 +239,7 @@ func (m *Twitch) checkTwitchFeedsLoop() {
 		elapsed := time.Since(start)
 		logger.Infof("checked %d channels for %d feeds, took %s", len(bundledEntries), len(entries), elapsed)
 		metrics.TwitchRefreshTime.Set(elapsed.Seconds())
+		prom.FeedLoopDuration.WithLabelValues("twitch").Observe(elapsed.Seconds())
 
 		time.Sleep(30 * time.Second)
 	}
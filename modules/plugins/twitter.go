@@ -17,6 +17,7 @@ import (
 	"github.com/Seklfreak/Robyul2/emojis"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
@@ -55,19 +56,19 @@ func (m *Twitter) Commands() []string {
 
 func (t *Twitter) Init(session *shardmanager.Manager) {
 	config := oauth1.NewConfig(
-		helpers.GetConfig().Path("twitter.consumer_key").Data().(string),
-		helpers.GetConfig().Path("twitter.consumer_secret").Data().(string))
+		helpers.GetConfig().Twitter.ConsumerKey,
+		helpers.GetConfig().Twitter.ConsumerSecret)
 	token := oauth1.NewToken(
-		helpers.GetConfig().Path("twitter.access_token").Data().(string),
-		helpers.GetConfig().Path("twitter.access_secret").Data().(string))
+		helpers.GetConfig().Twitter.AccessToken,
+		helpers.GetConfig().Twitter.AccessSecret)
 	httpClient := config.Client(oauth1.NoContext, token)
 	twitterClient = twitter.NewClient(httpClient)
 
-	anaconda.SetConsumerKey(helpers.GetConfig().Path("twitter.consumer_key").Data().(string))
-	anaconda.SetConsumerSecret(helpers.GetConfig().Path("twitter.consumer_secret").Data().(string))
+	anaconda.SetConsumerKey(helpers.GetConfig().Twitter.ConsumerKey)
+	anaconda.SetConsumerSecret(helpers.GetConfig().Twitter.ConsumerSecret)
 	anacondaClient = anaconda.NewTwitterApi(
-		helpers.GetConfig().Path("twitter.access_token").Data().(string),
-		helpers.GetConfig().Path("twitter.access_secret").Data().(string),
+		helpers.GetConfig().Twitter.AccessToken,
+		helpers.GetConfig().Twitter.AccessSecret,
 	)
 	go func() {
 		defer helpers.Recover()
@@ -192,7 +193,7 @@ func (t *Twitter) startTwitterStream() {
 		}
 
 		// check if we can send messages
-		channelPermission, err := cache.GetSession().SessionForGuildS(entry.GuildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(entry.GuildID).State.User.ID, channel.ID)
+		channelPermission, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 		if err != nil {
 			continue
 		}
@@ -287,7 +288,7 @@ func (m *Twitter) checkTwitterFeedsLoop() {
 			}
 
 			// check if we can send messages
-			channelPermission, err := cache.GetSession().SessionForGuildS(entry.GuildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(entry.GuildID).State.User.ID, channel.ID)
+			channelPermission, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 			if err != nil {
 				continue
 			}
@@ -423,6 +424,7 @@ func (m *Twitter) checkTwitterFeedsLoop() {
 		elapsed := time.Since(start)
 		cache.GetLogger().WithField("module", "twitter").Infof("checked %d accounts for %d feeds, took %s", len(bundledEntries), len(twitterEntriesCache), elapsed)
 		metrics.TwitterRefreshTime.Set(elapsed.Seconds())
+		prom.FeedLoopDuration.WithLabelValues("twitter").Observe(elapsed.Seconds())
 
 		if len(bundledEntries) <= 10 {
 			time.Sleep(10 * time.Minute)
@@ -116,9 +116,9 @@ func (vi VanityInvite) actionSet(args []string, in *discordgo.Message, out **dis
 
 	*out = vi.newMsg(helpers.GetTextF("plugins.vanityinvite.set-success",
 		args[1], targetChannel.ID,
-		helpers.GetConfig().Path("website.vanityurl_domain").Data().(string),
+		helpers.GetConfig().Website.VanityURLDomain,
 		args[1],
-		fmt.Sprintf(helpers.GetConfig().Path("website.vanityurl_stats_base_url").Data().(string), targetChannel.GuildID),
+		fmt.Sprintf(helpers.GetConfig().Website.VanityURLStatsBaseURL, targetChannel.GuildID),
 	))
 	return vi.actionFinish
 }
@@ -141,9 +141,9 @@ func (vi VanityInvite) actionStatus(args []string, in *discordgo.Message, out **
 
 	*out = vi.newMsg(helpers.GetTextF("plugins.vanityinvite.status",
 		vanityInvite.VanityNamePretty, vanityInvite.ChannelID,
-		helpers.GetConfig().Path("website.vanityurl_domain").Data().(string),
+		helpers.GetConfig().Website.VanityURLDomain,
 		vanityInvite.VanityNamePretty,
-		fmt.Sprintf(helpers.GetConfig().Path("website.vanityurl_stats_base_url").Data().(string), channel.GuildID),
+		fmt.Sprintf(helpers.GetConfig().Website.VanityURLStatsBaseURL, channel.GuildID),
 	))
 	return vi.actionFinish
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
@@ -79,7 +80,7 @@ func (r *VLive) checkVliveFeedsLoop() {
 			}
 
 			// check if we can send messages
-			channelPermission, err := cache.GetSession().SessionForGuildS(entry.GuildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(entry.GuildID).State.User.ID, channel.ID)
+			channelPermission, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 			if err != nil {
 				continue
 			}
@@ -130,6 +131,7 @@ func (r *VLive) checkVliveFeedsLoop() {
 		elapsed := time.Since(start)
 		cache.GetLogger().WithField("module", "vlive").Info(fmt.Sprintf("checked %d channels for %d feeds with %d workers, took %s", len(bundledEntries), len(entries), VLiveWorkers, elapsed))
 		metrics.VliveRefreshTime.Set(elapsed.Seconds())
+		prom.FeedLoopDuration.WithLabelValues("vlive").Observe(elapsed.Seconds())
 
 		bundledEntries = nil
 
@@ -31,7 +31,7 @@ func (w *Weather) Commands() []string {
 }
 
 func (w *Weather) Init(session *shardmanager.Manager) {
-	w.darkSkyClient = darksky.New(helpers.GetConfig().Path("darksky.api_key").Data().(string))
+	w.darkSkyClient = darksky.New(helpers.GetConfig().DarkSky.APIKey)
 }
 
 func (w *Weather) Action(command string, content string, msg *discordgo.Message, session *discordgo.Session) {
@@ -68,7 +68,7 @@ func (w *Weather) Action(command string, content string, msg *discordgo.Message,
 
 	if content != "" {
 		geocodingUrl := fmt.Sprintf(googleMapsGeocodingEndpoint,
-			helpers.GetConfig().Path("google.api_key").Data().(string),
+			helpers.GetConfig().Google.APIKey,
 			url.QueryEscape(content),
 		)
 		geocodingResult := helpers.GetJSON(geocodingUrl)
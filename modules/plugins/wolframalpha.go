@@ -51,7 +51,7 @@ func (m *WolframAlpha) Action(command string, content string, msg *discordgo.Mes
 	quitChannel := helpers.StartTypingLoop(msg.ChannelID)
 	defer func() { quitChannel <- 0 }()
 
-	wolframClient := &wolfram.Client{AppID: helpers.GetConfig().Path("wolframalpha.appid").Data().(string)}
+	wolframClient := &wolfram.Client{AppID: helpers.GetConfig().WolframAlpha.AppID}
 
 	if !imageSearch {
 		metrics.WolframAlphaRequests.Add(1)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/bwmarrin/discordgo"
 	"github.com/sirupsen/logrus"
@@ -61,7 +62,8 @@ func (f *feeds) run() {
 }
 
 func (f *feeds) check() {
-	t := time.Now().Unix()
+	start := time.Now()
+	t := start.Unix()
 	var entries []models.YoutubeChannelEntry
 	err := helpers.MDbIterWithoutLogging(helpers.MdbCollection(models.YoutubeChannelTable).Find(
 		bson.M{"nextchecktime": bson.M{"$lte": t}},
@@ -70,6 +72,11 @@ func (f *feeds) check() {
 	// TODO: test
 
 	for _, e := range entries {
+		// the feeds of a guild are checked by the process running the guild
+		if !cache.GetSession().OwnsGuild(e.GuildID) {
+			continue
+		}
+
 		e = f.checkChannelFeeds(e)
 
 		// update next check time
@@ -77,6 +84,7 @@ func (f *feeds) check() {
 		err = helpers.MDbUpdateWithoutLogging(models.YoutubeChannelTable, e.ID, e)
 		helpers.Relax(err)
 	}
+	prom.FeedLoopDuration.WithLabelValues("youtube").Observe(time.Since(start).Seconds())
 }
 
 func (f *feeds) checkChannelFeeds(e models.YoutubeChannelEntry) models.YoutubeChannelEntry {
@@ -87,7 +95,7 @@ func (f *feeds) checkChannelFeeds(e models.YoutubeChannelEntry) models.YoutubeCh
 	}
 
 	// check if we can send messages and embed links in channel
-	channelPermission, err := cache.GetSession().SessionForGuildS(channel.GuildID).State.UserChannelPermissions(cache.GetSession().SessionForGuildS(channel.GuildID).State.User.ID, channel.ID)
+	channelPermission, err := cache.GetRobyulstate().BotChannelPermissions(channel.ID)
 	if err != nil {
 		return e
 	}
@@ -26,8 +26,6 @@ const (
 	youtubeChannelBaseUrl = "https://www.youtube.com/channel/%s"
 	youtubeVideoBaseUrl   = "https://youtu.be/%s"
 	youtubeColor          = "FF0000"
-
-	youtubeConfigFileName = "google.client_credentials_json_location"
 )
 
 func (h *Handler) Commands() []string {
@@ -40,7 +38,7 @@ func (h *Handler) Commands() []string {
 func (h *Handler) Init(session *shardmanager.Manager) {
 	defer helpers.Recover()
 
-	h.service.Init(youtubeConfigFileName)
+	h.service.Init(helpers.GetConfig().Google.ClientCredentialsJSONLocation)
 	h.feedsLoop.Init(&h.service)
 	youtubeService.SetYouTubeService(&h.service)
 }
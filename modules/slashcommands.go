@@ -0,0 +1,79 @@
+package modules
+
+import (
+	"sort"
+
+	"github.com/Seklfreak/Robyul2/modules/commands"
+)
+
+// legacySlashCommands are registered as slash commands for plugins which do not declare their commands yet
+// they pass everything the user typed in their single option to Action
+var legacySlashCommands = []*commands.Command{
+	{
+		Name:        "profile",
+		Description: "Shows your profile or the profile of another member.",
+		Arguments:   legacySlashCommandArguments("user"),
+		Deferred:    true,
+	},
+	{
+		Name:        "level",
+		Description: "Shows your level or the level of another member.",
+		Arguments:   legacySlashCommandArguments("user"),
+	},
+	{
+		Name:        "leaderboard",
+		Description: "Shows the leaderboard of this server.",
+		Arguments:   legacySlashCommandArguments("weekly, monthly, or season <name>"),
+	},
+	{
+		Name:        "lastfm",
+		Description: "Shows your Last.fm stats.",
+		Arguments:   legacySlashCommandArguments("subcommand and arguments"),
+		Deferred:    true,
+	},
+	{
+		Name:        "biasgame",
+		Description: "Starts a biasgame.",
+		Arguments:   legacySlashCommandArguments("gender, rounds, or subcommand"),
+		Deferred:    true,
+	},
+	{
+		Name:        "weather",
+		Description: "Shows the weather at a location.",
+		Arguments:   legacySlashCommandArguments("location"),
+		Deferred:    true,
+	},
+	{
+		Name:        "translate",
+		Description: "Translates a text.",
+		Arguments:   legacySlashCommandArguments("source language, target language, and text"),
+	},
+	{
+		Name:        "urban",
+		Description: "Looks up a word on Urban Dictionary.",
+		Arguments:   legacySlashCommandArguments("word"),
+	},
+}
+
+func legacySlashCommandArguments(description string) []commands.Argument {
+	return []commands.Argument{
+		{Name: "arguments", Description: description, Type: commands.ArgumentTypeRest, Optional: true},
+	}
+}
+
+// SlashCommands returns the definitions of all commands which are available as slash commands
+func SlashCommands() (definitions []*commands.Command) {
+	seen := make(map[*commands.Command]bool)
+	for _, declaredCommand := range declaredCommands {
+		if seen[declaredCommand] {
+			continue
+		}
+		seen[declaredCommand] = true
+		definitions = append(definitions, declaredCommand)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+
+	return append(definitions, legacySlashCommands...)
+}
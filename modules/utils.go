@@ -5,15 +5,19 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/generator"
 	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/Seklfreak/Robyul2/metrics"
+	"github.com/Seklfreak/Robyul2/metrics/prom"
+	"github.com/Seklfreak/Robyul2/modules/commands"
 	"github.com/Seklfreak/Robyul2/modules/plugins/levels"
 	"github.com/Seklfreak/Robyul2/ratelimits"
 	"github.com/Seklfreak/Robyul2/shardmanager"
 	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
 )
 
 // Init warms the caches and initializes the plugins
@@ -24,6 +28,8 @@ func Init(session *shardmanager.Manager) {
 	extendedPluginCount := len(PluginExtendedList)
 	pluginCache = make(map[string]*Plugin)
 	extendedPluginCache = make(map[string]*ExtendedPlugin)
+	declaredCommands = make(map[string]*commands.Command)
+	cooldowns = make(map[string]ratelimits.Cooldown)
 
 	logTemplate := "[PLUG] %s reacts to [ %s]"
 	listeners := ""
@@ -35,6 +41,10 @@ func Init(session *shardmanager.Manager) {
 			pluginCache[cmd] = ref
 			listeners += cmd + " "
 		}
+		for _, cmd := range declareCommands(*ref) {
+			pluginCache[cmd] = ref
+		}
+		declareCooldowns(*ref)
 
 		cache.GetLogger().WithField("module", "modules").Info(fmt.Sprintf(
 			logTemplate,
@@ -55,6 +65,10 @@ func Init(session *shardmanager.Manager) {
 			extendedPluginCache[cmd] = ref
 			listeners += cmd + " "
 		}
+		for _, cmd := range declareCommands(*ref) {
+			extendedPluginCache[cmd] = ref
+		}
+		declareCooldowns(*ref)
 
 		cache.GetLogger().WithField("module", "modules").Info(fmt.Sprintf(
 			logTemplate,
@@ -122,22 +136,138 @@ func CallBotPlugin(command string, content string, msg *discordgo.Message) {
 	// Defer a recovery in case anything panics
 	defer helpers.RecoverDiscord(msg)
 
-	// Consume a key for this action
-	ratelimits.Container.Drain(1, msg.Author.ID)
+	// Resolve the aliases of the guild, ignore disabled commands
+	command, content, ok := helpers.ResolveCommand(msg.GuildID, command, content)
+	if !ok {
+		return
+	}
+
+	// Ignore unknown commands
+	declaredCommand, isDeclared := declaredCommands[command]
+	pluginRef, isPlugin := pluginCache[command]
+	extendedPluginRef, isExtendedPlugin := extendedPluginCache[command]
+	if !isDeclared && !isPlugin && !isExtendedPlugin {
+		return
+	}
 
-	// Track metrics
+	// Track metrics, aliases of declared commands are tracked as the command
+	metricsCommand := command
+	if isDeclared {
+		metricsCommand = declaredCommand.Name
+	}
 	metrics.CommandsExecuted.Add(1)
+	metrics.CommandsExecutedByCommand.Add(metricsCommand, 1)
+	prom.CommandsInvoked.WithLabelValues(metricsCommand).Inc()
+	defer func(start time.Time) {
+		prom.CommandDuration.WithLabelValues(metricsCommand).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	cache.GetLogger().WithFields(logrus.Fields{
+		"module":    "modules",
+		"guildID":   msg.GuildID,
+		"channelID": msg.ChannelID,
+		"userID":    msg.Author.ID,
+		"command":   command,
+	}).Debug("executing command " + command)
+
+	// Check the cooldown of the command
+	if !takeCooldown(command, msg) {
+		return
+	}
+
+	// call declared commands
+	if isDeclared {
+		commands.Execute(declaredCommand, command, content, msg, cache.GetSession().SessionForGuildS(msg.GuildID))
+		return
+	}
 
 	// Call the module
-	if ref, ok := pluginCache[command]; ok {
-		(*ref).Action(command, content, msg, cache.GetSession().SessionForGuildS(msg.GuildID))
+	if isPlugin {
+		(*pluginRef).Action(command, content, msg, cache.GetSession().SessionForGuildS(msg.GuildID))
 	}
 	// call the extended module
-	if ref, ok := extendedPluginCache[command]; ok {
-		(*ref).Action(command, content, msg, cache.GetSession().SessionForGuildS(msg.GuildID))
+	if isExtendedPlugin {
+		(*extendedPluginRef).Action(command, content, msg, cache.GetSession().SessionForGuildS(msg.GuildID))
 	}
 }
 
+// CommandAllowed returns false if the author is not allowed to run commands in the guild of the message
+// It is checked for commands sent as messages and for slash commands, every check consumes one command of the command limit
+// A message is sent if the author hit the command limit
+func CommandAllowed(msg *discordgo.Message) bool {
+	if helpers.IsBlacklisted(msg.Author.ID) {
+		return false
+	}
+
+	if helpers.IsBlacklistedGuild(msg.GuildID) {
+		return false
+	}
+
+	if !helpers.GuildIsOnWhitelist(msg.GuildID) {
+		return false
+	}
+
+	if helpers.IsBotAdmin(msg.Author.ID) {
+		return true
+	}
+
+	retryAfter, err := ratelimits.TakeCommand(msg.Author.ID)
+	if err != nil {
+		// do not block commands if the limiter is unavailable
+		cache.GetLogger().WithField("module", "modules").Warnf("failed to take command limit: %s", err.Error())
+		return true
+	}
+	if retryAfter > 0 {
+		helpers.SendMessage(msg.ChannelID, helpers.GetTextForMessage(msg, "bot.ratelimit.hit", msg.Author.ID))
+		return false
+	}
+
+	return true
+}
+
+// ClaimInteraction returns false if the interaction has already been received by any process
+// If redis is unavailable interactions are not deduplicated
+func ClaimInteraction(interactionID string, expiration time.Duration) bool {
+	claimed, err := cache.GetRedisClient().SetNX("robyul2-discord:interactions:"+interactionID, helpers.ProcessName(), expiration).Result()
+	if err != nil {
+		cache.GetLogger().WithField("module", "modules").Warnf("failed to claim interaction: %s", err.Error())
+		return true
+	}
+	return claimed
+}
+
+// SendCommandHelp sends the generated help of a declared command, returns false if the command has not been declared
+// path are the names of the command and subcommands the help is requested for
+func SendCommandHelp(path []string, msg *discordgo.Message) bool {
+	if len(path) <= 0 {
+		return false
+	}
+
+	declaredCommand, ok := declaredCommands[strings.ToLower(path[0])]
+	if !ok {
+		return false
+	}
+
+	commands.SendHelp(declaredCommand, path, msg)
+	return true
+}
+
+// declareCommands registers the declared commands of a plugin and returns the names they react to
+func declareCommands(plugin interface{}) (names []string) {
+	declarativePlugin, ok := plugin.(DeclarativePlugin)
+	if !ok {
+		return nil
+	}
+
+	for _, declaredCommand := range declarativePlugin.DeclareCommands() {
+		for _, name := range declaredCommand.Names() {
+			declaredCommands[name] = declaredCommand
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func CallExtendedPlugin(content string, msg *discordgo.Message) {
 	defer helpers.Recover()
 
@@ -207,8 +337,8 @@ func CallExtendedPluginOnGuildBanRemove(user *discordgo.GuildBanRemove) {
 func checkDuplicateCommands() {
 	cmds := make(map[string]string)
 
-	for _, plug := range PluginList {
-		for _, cmd := range plug.Commands() {
+	check := func(plug interface{}, names []string) {
+		for _, cmd := range names {
 			t := helpers.Typeof(plug)
 
 			if occupant, ok := cmds[cmd]; ok {
@@ -219,4 +349,25 @@ func checkDuplicateCommands() {
 			cmds[cmd] = t
 		}
 	}
+
+	for _, plug := range PluginList {
+		check(plug, plug.Commands())
+		check(plug, declaredCommandNames(plug))
+	}
+	for _, plug := range PluginExtendedList {
+		check(plug, declaredCommandNames(plug))
+	}
+}
+
+// declaredCommandNames returns the names and aliases of the declared commands of a plugin
+func declaredCommandNames(plugin interface{}) (names []string) {
+	declarativePlugin, ok := plugin.(DeclarativePlugin)
+	if !ok {
+		return nil
+	}
+
+	for _, declaredCommand := range declarativePlugin.DeclareCommands() {
+		names = append(names, declaredCommand.Names()...)
+	}
+	return names
 }
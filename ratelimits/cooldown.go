@@ -0,0 +1,67 @@
+package ratelimits
+
+import (
+	"time"
+)
+
+// CooldownScope defines who shares a cooldown
+type CooldownScope int
+
+const (
+	// CooldownScopeUser applies the cooldown to each user in each guild
+	CooldownScopeUser CooldownScope = iota
+	// CooldownScopeChannel applies the cooldown to everyone in a channel
+	CooldownScopeChannel
+	// CooldownScopeGuild applies the cooldown to everyone in a guild
+	CooldownScopeGuild
+	// CooldownScopeGlobalUser applies the cooldown to each user, shared by all guilds
+	CooldownScopeGlobalUser
+)
+
+func (s CooldownScope) String() string {
+	switch s {
+	case CooldownScopeChannel:
+		return "channel"
+	case CooldownScopeGuild:
+		return "guild"
+	case CooldownScopeGlobalUser:
+		return "global user"
+	}
+	return "user"
+}
+
+// Cooldown is declared by plugins to limit how often a command may be used
+type Cooldown struct {
+	// Command is the name of the cooldown, guild overrides refer to it
+	Command string
+	// Aliases are other commands sharing this cooldown
+	Aliases []string
+	Scope   CooldownScope
+	// Duration is the default duration, it can be overwritten by guild admins
+	Duration time.Duration
+	// Manual cooldowns are not taken for every command, the plugin takes them itself, for example when a game starts
+	Manual bool
+}
+
+// CooldownKey returns the limiter key of the cooldown for the scope of the message
+func CooldownKey(cooldown Cooldown, guildID, channelID, userID string) string {
+	switch cooldown.Scope {
+	case CooldownScopeChannel:
+		return Key("cooldown", cooldown.Command, "channel", channelID)
+	case CooldownScopeGuild:
+		return Key("cooldown", cooldown.Command, "guild", guildID)
+	case CooldownScopeGlobalUser:
+		return Key("cooldown", cooldown.Command, "user", userID)
+	}
+	return Key("cooldown", cooldown.Command, "user", guildID, userID)
+}
+
+// TakeCooldown starts the cooldown, returns the remaining time if the cooldown is still running
+func TakeCooldown(cooldown Cooldown, duration time.Duration, guildID, channelID, userID string) (retryAfter time.Duration, err error) {
+	return Take(CooldownKey(cooldown, guildID, channelID, userID), 1, duration)
+}
+
+// ResetCooldown ends the cooldown early, for example when a game the cooldown has been started for finished
+func ResetCooldown(cooldown Cooldown, guildID, channelID, userID string) (err error) {
+	return Reset(CooldownKey(cooldown, guildID, channelID, userID))
+}
@@ -0,0 +1,215 @@
+package ratelimits
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Limiter counts actions per key in a time window
+// Limits of the RedisLimiter are shared by all shards and processes using the same Redis
+type Limiter interface {
+	// Take consumes one of $limit actions of $key in $window
+	// Returns the time until the next action is allowed, 0 if the action is allowed
+	Take(key string, limit int, window time.Duration) (retryAfter time.Duration, err error)
+
+	// Reset removes all consumed actions of $key
+	Reset(key string) (err error)
+
+	// Count returns the consumed actions of $key in the current window
+	Count(key string) (count int, err error)
+}
+
+const (
+	// CommandLimit is the amount of commands a user may run in CommandWindow, the same as the previous command bucket held
+	CommandLimit = 64
+	// CommandWindow is the window of the command limit, users who hit the limit have to wait until it ends, the previous command bucket refilled every 10 seconds
+	CommandWindow = 10 * time.Second
+)
+
+var (
+	limiter     Limiter = NewMemoryLimiter()
+	limiterLock sync.RWMutex
+)
+
+// SetLimiter sets the limiter used for cooldowns and limits
+func SetLimiter(newLimiter Limiter) {
+	limiterLock.Lock()
+	defer limiterLock.Unlock()
+	limiter = newLimiter
+}
+
+// GetLimiter returns the limiter used for cooldowns and limits, a process local limiter if SetLimiter has not been called
+func GetLimiter() Limiter {
+	limiterLock.RLock()
+	defer limiterLock.RUnlock()
+	return limiter
+}
+
+// Take consumes one of $limit actions of $key in $window using the current limiter
+func Take(key string, limit int, window time.Duration) (retryAfter time.Duration, err error) {
+	return GetLimiter().Take(key, limit, window)
+}
+
+// Reset removes all consumed actions of $key using the current limiter
+func Reset(key string) (err error) {
+	return GetLimiter().Reset(key)
+}
+
+// TakeCommand consumes a command of the user, the limit is shared by all guilds
+// Returns the time until the user may run commands again if the user ran too many commands
+func TakeCommand(userID string) (retryAfter time.Duration, err error) {
+	return Take(Key("commands", userID), CommandLimit, CommandWindow)
+}
+
+// RemainingCommands returns how many commands the user may run in the current window
+func RemainingCommands(userID string) (remaining int, err error) {
+	count, err := GetLimiter().Count(Key("commands", userID))
+	if err != nil {
+		return 0, err
+	}
+	if count >= CommandLimit {
+		return 0, nil
+	}
+	return CommandLimit - count, nil
+}
+
+// takeScript increments the counter of the window and returns the remaining milliseconds of the window if the limit is hit
+var takeScript = redis.NewScript(`
+local current = redis.call("INCR", KEYS[1])
+if current == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if current > tonumber(ARGV[1]) then
+	local ttl = redis.call("PTTL", KEYS[1])
+	if ttl < 0 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		ttl = tonumber(ARGV[2])
+	end
+	return ttl
+end
+return 0
+`)
+
+// RedisLimiter is a fixed window limiter stored in Redis
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter creates a limiter storing its counters in Redis
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{
+		client: client,
+		prefix: "robyul2-discord:ratelimits:",
+	}
+}
+
+func (l *RedisLimiter) Take(key string, limit int, window time.Duration) (retryAfter time.Duration, err error) {
+	if limit <= 0 || window <= 0 {
+		return 0, nil
+	}
+
+	ttl, err := takeScript.Run(l.client, []string{l.prefix + key}, limit, window.Nanoseconds()/int64(time.Millisecond)).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ttl) * time.Millisecond, nil
+}
+
+func (l *RedisLimiter) Reset(key string) (err error) {
+	return l.client.Del(l.prefix + key).Err()
+}
+
+func (l *RedisLimiter) Count(key string) (count int, err error) {
+	count, err = l.client.Get(l.prefix + key).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+type memoryLimiterWindow struct {
+	Count     int
+	ExpiresAt time.Time
+}
+
+// MemoryLimiter is a process local fixed window limiter
+type MemoryLimiter struct {
+	sync.Mutex
+
+	windows map[string]*memoryLimiterWindow
+}
+
+// NewMemoryLimiter creates a process local limiter, expired windows are cleaned up regularly
+func NewMemoryLimiter() *MemoryLimiter {
+	newLimiter := &MemoryLimiter{
+		windows: make(map[string]*memoryLimiterWindow),
+	}
+	go newLimiter.cleanup()
+	return newLimiter
+}
+
+func (l *MemoryLimiter) Take(key string, limit int, window time.Duration) (retryAfter time.Duration, err error) {
+	if limit <= 0 || window <= 0 {
+		return 0, nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	current, ok := l.windows[key]
+	if !ok || !now.Before(current.ExpiresAt) {
+		current = &memoryLimiterWindow{ExpiresAt: now.Add(window)}
+		l.windows[key] = current
+	}
+
+	current.Count++
+	if current.Count > limit {
+		return current.ExpiresAt.Sub(now), nil
+	}
+	return 0, nil
+}
+
+func (l *MemoryLimiter) Reset(key string) (err error) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.windows, key)
+	return nil
+}
+
+func (l *MemoryLimiter) Count(key string) (count int, err error) {
+	l.Lock()
+	defer l.Unlock()
+
+	current, ok := l.windows[key]
+	if !ok || !time.Now().Before(current.ExpiresAt) {
+		return 0, nil
+	}
+	return current.Count, nil
+}
+
+func (l *MemoryLimiter) cleanup() {
+	for {
+		time.Sleep(1 * time.Minute)
+
+		now := time.Now()
+		l.Lock()
+		for key, window := range l.windows {
+			if !now.Before(window.ExpiresAt) {
+				delete(l.windows, key)
+			}
+		}
+		l.Unlock()
+	}
+}
+
+// Key joins the parts to a limiter key
+func Key(parts ...string) string {
+	return strings.Join(parts, ":")
+}
@@ -17,13 +17,16 @@ import (
 	"github.com/Seklfreak/Robyul2/cache"
 	"github.com/Seklfreak/Robyul2/generator"
 	"github.com/Seklfreak/Robyul2/helpers"
+	"github.com/Seklfreak/Robyul2/interactions"
 	"github.com/Seklfreak/Robyul2/models"
 	"github.com/Seklfreak/Robyul2/modules/plugins"
+	"github.com/Seklfreak/Robyul2/modules/plugins/idols"
 	"github.com/Seklfreak/Robyul2/modules/plugins/levels"
 	"github.com/bradfitz/slice"
 	"github.com/bwmarrin/discordgo"
 	restful "github.com/emicklei/go-restful"
 	raven "github.com/getsentry/raven-go"
+	"github.com/globalsign/mgo/bson"
 	"github.com/olivere/elastic"
 	"github.com/pkg/errors"
 	"github.com/vmihailenco/msgpack"
@@ -70,6 +73,15 @@ func NewRestServices() []*restful.WebService {
 	service.Route(service.GET("/{user-id}/{guild-id}").Filter(webkeyAuthenticate).To(GetProfile))
 	services = append(services, service)
 
+	service = new(restful.WebService)
+	service.
+		Path("/interactions").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	service.Route(service.POST("").To(HandleInteraction))
+	services = append(services, service)
+
 	service = new(restful.WebService)
 	service.
 		Path("/rankings").
@@ -77,6 +89,7 @@ func NewRestServices() []*restful.WebService {
 		Produces(restful.MIME_JSON)
 
 	service.Route(service.GET("/{guild-id}").Filter(webkeyAuthenticate).To(GetRankings))
+	service.Route(service.GET("/{guild-id}/seasons").Filter(webkeyAuthenticate).To(GetRankingSeasons))
 	service.Route(service.GET("/user/{user-id}/{guild-id}").Filter(webkeyAuthenticate).To(GetUserRanking))
 	service.Route(service.GET("/user/{user-id}/all").Filter(webkeyAuthenticate).To(GetAllUserRanking))
 	services = append(services, service)
@@ -159,6 +172,23 @@ func NewRestServices() []*restful.WebService {
 	service.Route(service.GET("").Filter(webkeyAuthenticate).To(GetAllBackgrounds))
 	services = append(services, service)
 
+	service = new(restful.WebService)
+	service.
+		Path("/idols").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	service.Route(service.GET("").Filter(webkeyAuthenticate).To(SearchIdols))
+	service.Route(service.GET("/groups").Filter(webkeyAuthenticate).To(GetIdolGroups))
+	service.Route(service.GET("/history").Filter(webkeyAuthenticate).To(GetIdolsHistory))
+	service.Route(service.GET("/images/{object-name}").Filter(webkeyAuthenticate).To(GetIdolImage))
+	service.Route(service.GET("/suggestions").Filter(sessionAndWebkeyAuthenticate).Filter(robyulModAuthenticate).To(GetIdolSuggestions))
+	service.Route(service.POST("/suggestions/{suggestion-id}/approve").Filter(sessionAndWebkeyAuthenticate).Filter(robyulModUserAuthenticate).To(ApproveIdolSuggestion))
+	service.Route(service.POST("/suggestions/{suggestion-id}/deny").Filter(sessionAndWebkeyAuthenticate).Filter(robyulModUserAuthenticate).To(DenyIdolSuggestion).Reads(&models.Rest_Receive_IdolSuggestionReview{}))
+	service.Route(service.GET("/{idol-id}").Filter(webkeyAuthenticate).To(FindIdol))
+	service.Route(service.GET("/{idol-id}/history").Filter(webkeyAuthenticate).To(GetIdolHistory))
+	services = append(services, service)
+
 	service = new(restful.WebService)
 	service.Route(service.GET("/ping").Filter(webkeyAuthenticate).To(Ping))
 	services = append(services, service)
@@ -173,14 +203,14 @@ func webkeyAuthenticate(request *restful.Request, response *restful.Response, ch
 
 	if strings.HasPrefix(authorizationHeader, "Webkey ") {
 		webkey := strings.TrimSpace(strings.Replace(authorizationHeader, "Webkey ", "", -1))
-		if webkey == helpers.GetConfig().Path("website.webkey").Data().(string) {
+		if webkey == helpers.GetConfig().Website.WebKey {
 			isAuthenticated = true
 			request.SetAttribute("UserID", "global")
 		}
 	}
 
 	queryWebkey := strings.TrimSpace(request.QueryParameter("webkey"))
-	if queryWebkey == helpers.GetConfig().Path("website.webkey").Data().(string) {
+	if queryWebkey == helpers.GetConfig().Website.WebKey {
 		isAuthenticated = true
 		request.SetAttribute("UserID", "global")
 	}
@@ -201,14 +231,14 @@ func sessionAndWebkeyAuthenticate(request *restful.Request, response *restful.Re
 
 	if strings.HasPrefix(authorizationHeader, "Webkey ") {
 		webkey := strings.TrimSpace(strings.Replace(authorizationHeader, "Webkey ", "", -1))
-		if webkey == helpers.GetConfig().Path("website.webkey").Data().(string) {
+		if webkey == helpers.GetConfig().Website.WebKey {
 			isAuthenticated = true
 			request.SetAttribute("UserID", "global")
 		}
 	}
 
 	queryWebkey := strings.TrimSpace(request.QueryParameter("webkey"))
-	if queryWebkey == helpers.GetConfig().Path("website.webkey").Data().(string) {
+	if queryWebkey == helpers.GetConfig().Website.WebKey {
 		isAuthenticated = true
 		request.SetAttribute("UserID", "global")
 	}
@@ -237,6 +267,33 @@ func sessionAndWebkeyAuthenticate(request *restful.Request, response *restful.Re
 	return
 }
 
+// robyulModAuthenticate has to run after sessionAndWebkeyAuthenticate
+func robyulModAuthenticate(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	userID, _ := request.Attribute("UserID").(string)
+
+	if userID != "global" && !helpers.IsRobyulMod(userID) {
+		response.WriteErrorString(401, "401: Not Authorized")
+		return
+	}
+
+	chain.ProcessFilter(request, response)
+	return
+}
+
+// robyulModUserAuthenticate has to run after sessionAndWebkeyAuthenticate
+// the webkey does not identify a user, routes which record the mod require a session of a Robyul mod
+func robyulModUserAuthenticate(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	userID, _ := request.Attribute("UserID").(string)
+
+	if userID == "" || userID == "global" || !helpers.IsRobyulMod(userID) {
+		response.WriteErrorString(401, "401: Not Authorized")
+		return
+	}
+
+	chain.ProcessFilter(request, response)
+	return
+}
+
 func GetAllBotGuilds(request *restful.Request, response *restful.Response) {
 	var botPrefix string
 
@@ -460,6 +517,17 @@ func GetProfile(request *restful.Request, response *restful.Response) {
 	}
 }
 
+// HandleInteraction passes slash commands to the interactions router, requests are authenticated by their signature
+func HandleInteraction(request *restful.Request, response *restful.Response) {
+	router := interactions.GetRouter()
+	if router == nil {
+		response.WriteErrorString(http.StatusNotFound, "404: Not Found")
+		return
+	}
+
+	router.ServeHTTP(response.ResponseWriter, request.Request)
+}
+
 func GetRankings(request *restful.Request, response *restful.Response) {
 	guildID := request.PathParameter("guild-id")
 
@@ -471,6 +539,12 @@ func GetRankings(request *restful.Request, response *restful.Response) {
 		}
 	}
 
+	// ?period=weekly|monthly or ?season=<name> return the EXP gained during that period
+	if request.QueryParameter("period") != "" || request.QueryParameter("season") != "" {
+		getPeriodRankings(request, response, guildID)
+		return
+	}
+
 	var err error
 	var rankingsCount int
 	rankingsCountKey := fmt.Sprintf("robyul2-discord:levels:ranking:%s:by-rank:count", guildID)
@@ -551,6 +625,103 @@ func GetRankings(request *restful.Request, response *restful.Response) {
 	response.WriteEntity(result)
 }
 
+func getPeriodRankings(request *restful.Request, response *restful.Response, guildID string) {
+	var err error
+	var period string
+	var ranking []models.LevelsPeriodExpEntry
+
+	switch request.QueryParameter("period") {
+	case "weekly":
+		period = levels.GetPeriodWeekly(time.Now())
+		ranking, err = levels.GetPeriodRanking(guildID, period, 100)
+	case "monthly":
+		period = levels.GetPeriodMonthly(time.Now())
+		ranking, err = levels.GetPeriodRanking(guildID, period, 100)
+	case "":
+		if guildID == "global" {
+			response.WriteError(http.StatusBadRequest, errors.New("Seasons are only available for guilds"))
+			return
+		}
+		season, errSeason := levels.GetSeason(guildID, request.QueryParameter("season"))
+		if errSeason != nil {
+			response.WriteError(http.StatusNotFound, errors.New("Season not found"))
+			return
+		}
+		period = levels.GetPeriodSeason(season)
+		ranking, err = levels.GetSeasonRanking(season, 100)
+	default:
+		response.WriteError(http.StatusBadRequest, errors.New("Invalid period"))
+		return
+	}
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	result := new(models.Rest_Ranking)
+	result.Ranks = make([]models.Rest_Ranking_Rank_Item, 0)
+	result.Period = period
+
+	for i, item := range ranking {
+		user, _ := helpers.GetUserWithoutAPI(item.UserID)
+		if user == nil || user.ID == "" {
+			continue
+		}
+
+		isMember := true
+		if guildID != "global" && !helpers.GetIsInGuild(guildID, user.ID) {
+			isMember = false
+		}
+
+		result.Ranks = append(result.Ranks, models.Rest_Ranking_Rank_Item{
+			User: models.Rest_User{
+				ID:            user.ID,
+				Username:      user.Username,
+				AvatarHash:    user.Avatar,
+				Discriminator: user.Discriminator,
+				Bot:           user.Bot,
+			},
+			GuildID:  guildID,
+			IsMember: isMember,
+			EXP:      item.Exp,
+			Ranking:  i + 1,
+		})
+	}
+	result.Count = len(result.Ranks)
+
+	response.WriteEntity(result)
+}
+
+func GetRankingSeasons(request *restful.Request, response *restful.Response) {
+	guildID := request.PathParameter("guild-id")
+
+	guild, err := helpers.GetGuild(guildID)
+	if err != nil || guild == nil || guild.ID == "" {
+		response.WriteError(http.StatusNotFound, errors.New("Guild not found"))
+		return
+	}
+
+	seasons, err := levels.GetSeasons(guildID)
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	result := make([]models.Rest_Ranking_Season, 0)
+	for _, season := range seasons {
+		result = append(result, models.Rest_Ranking_Season{
+			ID:          helpers.MdbIdToHuman(season.ID),
+			Name:        season.Name,
+			StartAt:     season.StartAt,
+			EndAt:       season.EndAt,
+			Archived:    season.Archived,
+			RewardCount: season.RewardCount,
+		})
+	}
+
+	response.WriteEntity(result)
+}
+
 func GetUserRanking(request *restful.Request, response *restful.Response) {
 	userID := request.PathParameter("user-id")
 	guildID := request.PathParameter("guild-id")
@@ -637,7 +808,7 @@ func GetAllUserRanking(request *restful.Request, response *restful.Response) {
 
 	result := make([]models.Rest_Ranking_Rank_Item, 0)
 
-	for _, guild := range append(helpers.AllGuilds(), &discordgo.Guild{ID: "global", Name: "global"}) {
+	for _, guild := range append(helpers.AllGuildsWithoutMembers(), &discordgo.Guild{ID: "global", Name: "global"}) {
 		if guild.ID != "global" && !helpers.GetIsInGuild(guild.ID, userID) {
 			continue
 		}
@@ -1196,12 +1367,10 @@ func GotBotStatistics(request *restful.Request, response *restful.Response) {
 	users := make(map[string]string)
 	var guildCount int
 
-	for _, shard := range cache.GetSession().Sessions {
-		for _, guild := range shard.State.Guilds {
-			guildCount++
-			for _, u := range guild.Members {
-				users[u.User.ID] = u.User.Username
-			}
+	for _, guild := range helpers.AllGuilds() {
+		guildCount++
+		for _, u := range guild.Members {
+			users[u.User.ID] = u.User.Username
 		}
 	}
 
@@ -1725,7 +1894,7 @@ func GetEventlog(request *restful.Request, response *restful.Response) {
 	}
 
 	for _, lookupGuildID := range lookupGuildIDs {
-		guild, _ := cache.GetSession().SessionForGuildS(guildID).State.Guild(lookupGuildID)
+		guild, _ := helpers.GetGuildWithoutApi(lookupGuildID)
 		if guild != nil && guild.ID != "" {
 			joinedAt, _ := guild.JoinedAt.Parse()
 
@@ -1875,3 +2044,238 @@ func Ping(_ *restful.Request, response *restful.Response) {
 	response.Write([]byte("pong"))
 	return
 }
+
+func SearchIdols(request *restful.Request, response *restful.Response) {
+	includeDeleted, _ := strconv.ParseBool(request.QueryParameter("deleted"))
+
+	matchingIdols := idols.SearchIdols(
+		request.QueryParameter("search"),
+		request.QueryParameter("group"),
+		request.QueryParameter("gender"),
+		includeDeleted,
+	)
+
+	returnIdols := make([]models.Rest_Idol, 0)
+	for _, idol := range matchingIdols {
+		returnIdols = append(returnIdols, getRestIdol(idol))
+	}
+
+	response.WriteEntity(returnIdols)
+}
+
+func FindIdol(request *restful.Request, response *restful.Response) {
+	idolID := request.PathParameter("idol-id")
+
+	if !bson.IsObjectIdHex(idolID) {
+		response.WriteError(http.StatusNotFound, errors.New("idol not found"))
+		return
+	}
+
+	idol := idols.GetMatchingIdolById(bson.ObjectIdHex(idolID))
+	if idol == nil {
+		response.WriteError(http.StatusNotFound, errors.New("idol not found"))
+		return
+	}
+
+	response.WriteEntity(getRestIdol(idol))
+}
+
+func GetIdolGroups(request *restful.Request, response *restful.Response) {
+	includeDeleted, _ := strconv.ParseBool(request.QueryParameter("deleted"))
+
+	idolsInGroup := make(map[string]int)
+	for _, idol := range idols.SearchIdols("", "", "", includeDeleted) {
+		idolsInGroup[idol.GroupName]++
+	}
+
+	returnGroups := make([]models.Rest_Idol_Group, 0)
+	for groupName, amount := range idolsInGroup {
+		_, aliases := idols.GetAlisesForGroup(groupName)
+
+		returnGroups = append(returnGroups, models.Rest_Idol_Group{
+			Name:    groupName,
+			Aliases: aliases,
+			Idols:   amount,
+		})
+	}
+	sort.Slice(returnGroups, func(i, j int) bool {
+		return strings.ToLower(returnGroups[i].Name) < strings.ToLower(returnGroups[j].Name)
+	})
+
+	response.WriteEntity(returnGroups)
+}
+
+func GetIdolImage(request *restful.Request, response *restful.Response) {
+	objectName := request.PathParameter("object-name")
+
+	// only return images that belong to an idol
+	imageFound := false
+IdolsLoop:
+	for _, idol := range idols.GetAllIdols() {
+		for _, image := range idol.Images {
+			if image.ObjectName == objectName {
+				imageFound = true
+				break IdolsLoop
+			}
+		}
+	}
+	if !imageFound {
+		response.WriteError(http.StatusNotFound, errors.New("image not found"))
+		return
+	}
+
+	data, err := helpers.RetrieveFile(objectName)
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	response.WriteEntity(models.Rest_File{
+		FileName: objectName,
+		FileType: http.DetectContentType(data),
+		Data:     base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+func GetIdolsHistory(request *restful.Request, response *restful.Response) {
+	var idolID bson.ObjectId
+	if request.QueryParameter("idol-id") != "" {
+		if !bson.IsObjectIdHex(request.QueryParameter("idol-id")) {
+			response.WriteError(http.StatusBadRequest, errors.New("invalid idol id"))
+			return
+		}
+		idolID = bson.ObjectIdHex(request.QueryParameter("idol-id"))
+	}
+
+	writeIdolHistory(response, idolID, request.QueryParameter("group"))
+}
+
+func GetIdolHistory(request *restful.Request, response *restful.Response) {
+	idolID := request.PathParameter("idol-id")
+
+	if !bson.IsObjectIdHex(idolID) {
+		response.WriteError(http.StatusNotFound, errors.New("idol not found"))
+		return
+	}
+
+	writeIdolHistory(response, bson.ObjectIdHex(idolID), "")
+}
+
+func writeIdolHistory(response *restful.Response, idolID bson.ObjectId, groupName string) {
+	entries, err := idols.GetAuditHistory(idolID, groupName, 100)
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	returnEntries := make([]models.Rest_Idol_Audit_Entry, 0)
+	for _, entry := range entries {
+		returnEntries = append(returnEntries, models.Rest_Idol_Audit_Entry{
+			CreatedAt: entry.CreatedAt,
+			IdolID:    entry.IdolID.Hex(),
+			GroupName: entry.GroupName,
+			UserID:    entry.UserID,
+			Action:    entry.Action,
+			Source:    entry.Source,
+			Changes:   entry.Changes,
+		})
+	}
+
+	response.WriteEntity(returnEntries)
+}
+
+func GetIdolSuggestions(request *restful.Request, response *restful.Response) {
+	suggestions, err := idols.GetUnresolvedSuggestions()
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	returnSuggestions := make([]models.Rest_Idol_Suggestion, 0)
+	for _, suggestion := range suggestions {
+		returnSuggestions = append(returnSuggestions, models.Rest_Idol_Suggestion{
+			ID:         suggestion.ID.Hex(),
+			UserID:     suggestion.UserID,
+			ChannelID:  suggestion.ChannelID,
+			Name:       suggestion.Name,
+			GroupName:  suggestion.GrouopName,
+			Gender:     suggestion.Gender,
+			ImageURL:   suggestion.ImageURL,
+			Notes:      suggestion.Notes,
+			GroupMatch: suggestion.GroupMatch,
+			IdolMatch:  suggestion.IdolMatch,
+			CreatedAt:  suggestion.ID.Time(),
+		})
+	}
+
+	response.WriteEntity(returnSuggestions)
+}
+
+func ApproveIdolSuggestion(request *restful.Request, response *restful.Response) {
+	suggestionID := request.PathParameter("suggestion-id")
+
+	if !bson.IsObjectIdHex(suggestionID) {
+		response.WriteError(http.StatusNotFound, idols.ErrSuggestionNotFound)
+		return
+	}
+
+	err := idols.ApproveSuggestion(bson.ObjectIdHex(suggestionID), request.Attribute("UserID").(string))
+	writeIdolSuggestionReviewResult(response, err)
+}
+
+func DenyIdolSuggestion(request *restful.Request, response *restful.Response) {
+	suggestionID := request.PathParameter("suggestion-id")
+
+	if !bson.IsObjectIdHex(suggestionID) {
+		response.WriteError(http.StatusNotFound, idols.ErrSuggestionNotFound)
+		return
+	}
+
+	review := new(models.Rest_Receive_IdolSuggestionReview)
+	err := request.ReadEntity(&review)
+	if err != nil {
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = idols.DenySuggestion(bson.ObjectIdHex(suggestionID), request.Attribute("UserID").(string), strings.TrimSpace(review.Notes))
+	writeIdolSuggestionReviewResult(response, err)
+}
+
+func writeIdolSuggestionReviewResult(response *restful.Response, err error) {
+	switch err {
+	case nil:
+		response.WriteHeader(http.StatusNoContent)
+	case idols.ErrSuggestionNotFound:
+		response.WriteError(http.StatusNotFound, err)
+	case idols.ErrSuggestionNotesMissing:
+		response.WriteError(http.StatusBadRequest, err)
+	default:
+		response.WriteError(http.StatusInternalServerError, err)
+	}
+}
+
+func getRestIdol(idol *idols.Idol) models.Rest_Idol {
+	images := make([]models.Rest_Idol_Image, 0)
+	for _, image := range idol.Images {
+		images = append(images, models.Rest_Idol_Image{
+			ObjectName: image.ObjectName,
+			HashString: image.HashString,
+		})
+	}
+
+	return models.Rest_Idol{
+		ID:          idol.ID.Hex(),
+		Name:        idol.Name,
+		NameAliases: idol.NameAliases,
+		GroupName:   idol.GroupName,
+		Gender:      idol.Gender,
+		Deleted:     idol.Deleted,
+		Images:      images,
+		BGGames:     idol.BGGames,
+		BGGameWins:  idol.BGGameWins,
+		BGRounds:    idol.BGRounds,
+		BGRoundWins: idol.BGRoundWins,
+		BGRating:    idol.BGRating,
+	}
+}
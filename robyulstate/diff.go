@@ -0,0 +1,218 @@
+package robyulstate
+
+import (
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// diffHandlers are called with the state before and after an update
+// Handlers are called in their own goroutine after the state has been updated
+type diffHandlers struct {
+	guildUpdate   []func(guildID string, before, after *discordgo.Guild)
+	channelUpdate []func(guildID string, before, after *discordgo.Channel)
+	memberUpdate  []func(guildID string, before, after *discordgo.Member)
+	roleUpdate    []func(guildID string, before, after *discordgo.Role)
+	emojiCreate   []func(guildID string, emoji *discordgo.Emoji)
+	emojiUpdate   []func(guildID string, before, after *discordgo.Emoji)
+	emojiDelete   []func(guildID string, emoji *discordgo.Emoji)
+}
+
+// OnGuildUpdate adds a handler which is called when the settings of a guild change
+func (s *Robyulstate) OnGuildUpdate(handler func(guildID string, before, after *discordgo.Guild)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.guildUpdate = append(s.handlers.guildUpdate, handler)
+}
+
+// OnChannelUpdate adds a handler which is called when a channel changes
+func (s *Robyulstate) OnChannelUpdate(handler func(guildID string, before, after *discordgo.Channel)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.channelUpdate = append(s.handlers.channelUpdate, handler)
+}
+
+// OnMemberUpdate adds a handler which is called when the roles, nickname or name of a cached member change
+func (s *Robyulstate) OnMemberUpdate(handler func(guildID string, before, after *discordgo.Member)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.memberUpdate = append(s.handlers.memberUpdate, handler)
+}
+
+// OnRoleUpdate adds a handler which is called when a role changes
+func (s *Robyulstate) OnRoleUpdate(handler func(guildID string, before, after *discordgo.Role)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.roleUpdate = append(s.handlers.roleUpdate, handler)
+}
+
+// OnEmojiCreate adds a handler which is called when an emoji is added to a guild
+func (s *Robyulstate) OnEmojiCreate(handler func(guildID string, emoji *discordgo.Emoji)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.emojiCreate = append(s.handlers.emojiCreate, handler)
+}
+
+// OnEmojiUpdate adds a handler which is called when an emoji changes
+func (s *Robyulstate) OnEmojiUpdate(handler func(guildID string, before, after *discordgo.Emoji)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.emojiUpdate = append(s.handlers.emojiUpdate, handler)
+}
+
+// OnEmojiDelete adds a handler which is called when an emoji is removed from a guild
+func (s *Robyulstate) OnEmojiDelete(handler func(guildID string, emoji *discordgo.Emoji)) {
+	s.handlersLock.Lock()
+	defer s.handlersLock.Unlock()
+	s.handlers.emojiDelete = append(s.handlers.emojiDelete, handler)
+}
+
+func (s *Robyulstate) guildUpdated(guildID string, before, after *discordgo.Guild) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.guildUpdate {
+		go handler(guildID, before, after)
+	}
+}
+
+func (s *Robyulstate) channelUpdated(guildID string, before, after *discordgo.Channel) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.channelUpdate {
+		go handler(guildID, before, after)
+	}
+}
+
+func (s *Robyulstate) memberUpdated(guildID string, before, after *discordgo.Member) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.memberUpdate {
+		go handler(guildID, before, after)
+	}
+}
+
+func (s *Robyulstate) roleUpdated(guildID string, before, after *discordgo.Role) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.roleUpdate {
+		go handler(guildID, before, after)
+	}
+}
+
+func (s *Robyulstate) emojiCreated(guildID string, emoji *discordgo.Emoji) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.emojiCreate {
+		go handler(guildID, emoji)
+	}
+}
+
+func (s *Robyulstate) emojiUpdated(guildID string, before, after *discordgo.Emoji) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.emojiUpdate {
+		go handler(guildID, before, after)
+	}
+}
+
+func (s *Robyulstate) emojiDeleted(guildID string, emoji *discordgo.Emoji) {
+	s.handlersLock.RLock()
+	defer s.handlersLock.RUnlock()
+	for _, handler := range s.handlers.emojiDelete {
+		go handler(guildID, emoji)
+	}
+}
+
+// guildChanged returns true if a setting shown in the eventlog changed
+func guildChanged(before, after *discordgo.Guild) bool {
+	return before.Name != after.Name ||
+		before.Icon != after.Icon ||
+		before.Region != after.Region ||
+		before.AfkChannelID != after.AfkChannelID ||
+		// EmbedChannelID and EmbedEnabled are sent with every first update
+		before.OwnerID != after.OwnerID ||
+		before.Splash != after.Splash ||
+		before.AfkTimeout != after.AfkTimeout ||
+		before.VerificationLevel != after.VerificationLevel ||
+		before.DefaultMessageNotifications != after.DefaultMessageNotifications
+}
+
+// channelChanged returns true if a setting shown in the eventlog changed, position changes are ignored
+func channelChanged(before, after *discordgo.Channel) bool {
+	return before.Name != after.Name ||
+		before.Topic != after.Topic ||
+		before.NSFW != after.NSFW ||
+		before.Bitrate != after.Bitrate ||
+		before.ParentID != after.ParentID ||
+		!overwritesMatch(before.PermissionOverwrites, after.PermissionOverwrites)
+}
+
+// memberChanged returns true if the roles, nickname or name of the member changed
+// Empty names are ignored, some events do not contain the full user
+func memberChanged(before, after *discordgo.Member) bool {
+	if before.User == nil || after.User == nil {
+		return false
+	}
+	return !stringsMatch(before.Roles, after.Roles) ||
+		before.Nick != after.Nick ||
+		(before.User.Username != after.User.Username && before.User.Username != "" && after.User.Username != "") ||
+		(before.User.Discriminator != after.User.Discriminator && before.User.Discriminator != "" && after.User.Discriminator != "")
+}
+
+// roleChanged returns true if a setting shown in the eventlog changed, position changes are ignored
+func roleChanged(before, after *discordgo.Role) bool {
+	return before.Name != after.Name ||
+		before.Managed != after.Managed ||
+		before.Mentionable != after.Mentionable ||
+		before.Hoist != after.Hoist ||
+		before.Color != after.Color ||
+		before.Permissions != after.Permissions
+}
+
+// emojiChanged returns true if a setting of the emoji changed
+func emojiChanged(before, after *discordgo.Emoji) bool {
+	return before.Name != after.Name ||
+		before.Animated != after.Animated ||
+		before.RequireColons != after.RequireColons ||
+		before.Managed != after.Managed
+}
+
+// overwritesMatch compares permission overwrites independent of their order
+func overwritesMatch(a, b []*discordgo.PermissionOverwrite) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	byID := make(map[string]*discordgo.PermissionOverwrite, len(a))
+	for _, overwrite := range a {
+		byID[overwrite.ID] = overwrite
+	}
+	for _, overwrite := range b {
+		other, ok := byID[overwrite.ID]
+		if !ok ||
+			other.Type != overwrite.Type ||
+			other.Allow != overwrite.Allow ||
+			other.Deny != overwrite.Deny {
+			return false
+		}
+	}
+	return true
+}
+
+// stringsMatch compares lists of IDs independent of their order
+func stringsMatch(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package robyulstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// newTestState creates a state tracking testGuild
+func newTestState(t *testing.T) *Robyulstate {
+	state := NewState()
+	state.Logger = func(msgL, caller int, format string, a ...interface{}) {
+		t.Logf(format, a...)
+	}
+
+	guild := testGuild()
+	guild.Roles = []*discordgo.Role{{ID: "31", Name: "role"}}
+	guild.Emojis = []*discordgo.Emoji{{ID: "41", Name: "emoji"}, {ID: "42", Name: "removed"}}
+	err := state.GuildAdd(guild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// receive waits for a value sent by a diff handler
+func receive(t *testing.T, values chan string, description string) string {
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for", description)
+	}
+	return ""
+}
+
+func TestOnGuildUpdate(t *testing.T) {
+	state := newTestState(t)
+	updates := make(chan string, 1)
+	state.OnGuildUpdate(func(guildID string, before, after *discordgo.Guild) {
+		updates <- guildID + " " + before.Name + " " + after.Name
+	})
+
+	updatedGuild := testGuild()
+	updatedGuild.Name = "Robyul Updated"
+	updatedGuild.Members = nil
+	err := state.GuildUpdate(updatedGuild)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update := receive(t, updates, "the guild update"); update != "1 Robyul Robyul Updated" {
+		t.Fatal("unexpected guild update", update)
+	}
+	if _, err = state.Member("1", "101"); err != nil {
+		t.Fatal("members have not been kept", err)
+	}
+}
+
+func TestOnChannelUpdate(t *testing.T) {
+	state := newTestState(t)
+	updates := make(chan string, 2)
+	state.OnChannelUpdate(func(guildID string, before, after *discordgo.Channel) {
+		updates <- guildID + " " + before.Name + " " + after.Name
+	})
+
+	// position changes are ignored
+	err := state.ChannelUpdate(&discordgo.Channel{ID: "11", GuildID: "1", Name: "general", Position: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = state.ChannelUpdate(&discordgo.Channel{ID: "12", GuildID: "1", Name: "memes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update := receive(t, updates, "the channel update"); update != "1 random memes" {
+		t.Fatal("unexpected channel update", update)
+	}
+}
+
+func TestOnMemberUpdate(t *testing.T) {
+	state := newTestState(t)
+	updates := make(chan string, 1)
+	state.OnMemberUpdate(func(guildID string, before, after *discordgo.Member) {
+		updates <- guildID + " " + before.User.ID + " " + before.Nick + " " + after.Nick
+	})
+
+	updatedMember := testMember("102")
+	updatedMember.GuildID = "1"
+	updatedMember.Nick = "nick"
+	err := state.MemberAdd(updatedMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update := receive(t, updates, "the member update"); update != "1 102  nick" {
+		t.Fatal("unexpected member update", update)
+	}
+	member, err := state.Member("1", "102")
+	if err != nil || member.Nick != "nick" {
+		t.Fatal("member has not been updated", member, err)
+	}
+}
+
+func TestOnRoleUpdate(t *testing.T) {
+	state := newTestState(t)
+	updates := make(chan string, 1)
+	state.OnRoleUpdate(func(guildID string, before, after *discordgo.Role) {
+		updates <- guildID + " " + before.Name + " " + after.Name
+	})
+
+	err := state.RoleAdd("1", &discordgo.Role{ID: "31", Name: "renamed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update := receive(t, updates, "the role update"); update != "1 role renamed" {
+		t.Fatal("unexpected role update", update)
+	}
+}
+
+func TestOnEmojiUpdates(t *testing.T) {
+	state := newTestState(t)
+	updates := make(chan string, 3)
+	state.OnEmojiCreate(func(guildID string, emoji *discordgo.Emoji) {
+		updates <- "create " + emoji.Name
+	})
+	state.OnEmojiUpdate(func(guildID string, before, after *discordgo.Emoji) {
+		updates <- "update " + before.Name + " " + after.Name
+	})
+	state.OnEmojiDelete(func(guildID string, emoji *discordgo.Emoji) {
+		updates <- "delete " + emoji.Name
+	})
+
+	err := state.EmojisUpdate("1", []*discordgo.Emoji{{ID: "41", Name: "renamed"}, {ID: "43", Name: "added"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		received[receive(t, updates, "the emoji updates")] = true
+	}
+	if !received["create added"] || !received["update emoji renamed"] || !received["delete removed"] {
+		t.Fatal("unexpected emoji updates", received)
+	}
+}
+
+func TestMemberIndex(t *testing.T) {
+	state := newTestState(t)
+
+	err := state.MembersChunk("1", []*discordgo.Member{testMember("103"), testMember("104")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = state.MemberRemove(&discordgo.Member{GuildID: "1", User: &discordgo.User{ID: "101"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = state.Member("1", "101"); err != discordgo.ErrStateNotFound {
+		t.Fatal("removed member has been found", err)
+	}
+	for _, userID := range []string{"102", "103", "104"} {
+		member, err := state.Member("1", userID)
+		if err != nil || member.User.ID != userID {
+			t.Fatal("unexpected member for", userID, member, err)
+		}
+	}
+
+	guild, err := state.Guild("1")
+	if err != nil || len(guild.Members) != 3 {
+		t.Fatal("unexpected members", guild, err)
+	}
+}
@@ -0,0 +1,108 @@
+package robyulstate
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// UserChannelPermissions returns the permissions of the user in the channel, like discordgo.State.UserChannelPermissions
+// Members are read from this state, the discordgo state does not track members
+func (s *Robyulstate) UserChannelPermissions(userID, channelID string) (apermissions int, err error) {
+	if s == nil {
+		return 0, discordgo.ErrNilState
+	}
+
+	channel, err := s.Channel(channelID)
+	if err != nil {
+		return 0, err
+	}
+
+	guild, err := s.Guild(channel.GuildID)
+	if err != nil {
+		return 0, err
+	}
+
+	member, err := s.Member(guild.ID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return memberPermissions(guild, channel, userID, member.Roles), nil
+}
+
+// BotChannelPermissions returns the permissions of the bot in the channel
+func (s *Robyulstate) BotChannelPermissions(channelID string) (apermissions int, err error) {
+	if s == nil {
+		return 0, discordgo.ErrNilState
+	}
+
+	s.RLock()
+	userID := s.userID
+	s.RUnlock()
+
+	return s.UserChannelPermissions(userID, channelID)
+}
+
+// memberPermissions calculates the permissions of a member in a channel, the same way as discordgo
+func memberPermissions(guild *discordgo.Guild, channel *discordgo.Channel, userID string, roles []string) (apermissions int) {
+	if userID == guild.OwnerID {
+		return discordgo.PermissionAll
+	}
+
+	for _, role := range guild.Roles {
+		if role.ID == guild.ID {
+			apermissions |= role.Permissions
+			break
+		}
+	}
+
+	for _, role := range guild.Roles {
+		for _, roleID := range roles {
+			if role.ID == roleID {
+				apermissions |= role.Permissions
+				break
+			}
+		}
+	}
+
+	if apermissions&discordgo.PermissionAdministrator == discordgo.PermissionAdministrator {
+		apermissions |= discordgo.PermissionAll
+	}
+
+	// apply @everyone overwrites of the channel
+	for _, overwrite := range channel.PermissionOverwrites {
+		if guild.ID == overwrite.ID {
+			apermissions &= ^overwrite.Deny
+			apermissions |= overwrite.Allow
+			break
+		}
+	}
+
+	// role overwrites are applied together, member overwrites override them
+	denies := 0
+	allows := 0
+	for _, overwrite := range channel.PermissionOverwrites {
+		for _, roleID := range roles {
+			if overwrite.Type == "role" && roleID == overwrite.ID {
+				denies |= overwrite.Deny
+				allows |= overwrite.Allow
+				break
+			}
+		}
+	}
+	apermissions &= ^denies
+	apermissions |= allows
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == "member" && overwrite.ID == userID {
+			apermissions &= ^overwrite.Deny
+			apermissions |= overwrite.Allow
+			break
+		}
+	}
+
+	if apermissions&discordgo.PermissionAdministrator == discordgo.PermissionAdministrator {
+		apermissions |= discordgo.PermissionAllChannel
+	}
+
+	return apermissions
+}
@@ -0,0 +1,67 @@
+package robyulstate
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestUserChannelPermissions(t *testing.T) {
+	state := NewState()
+	state.guildMap["1"] = &discordgo.Guild{
+		ID:      "1",
+		OwnerID: "100",
+		Roles: []*discordgo.Role{
+			{ID: "1", Permissions: discordgo.PermissionReadMessages | discordgo.PermissionSendMessages},
+			{ID: "21", Permissions: discordgo.PermissionManageMessages},
+			{ID: "22", Permissions: discordgo.PermissionAdministrator},
+		},
+		Channels: []*discordgo.Channel{{ID: "11", GuildID: "1", PermissionOverwrites: []*discordgo.PermissionOverwrite{
+			{ID: "1", Type: "role", Deny: discordgo.PermissionSendMessages},
+			{ID: "21", Type: "role", Allow: discordgo.PermissionSendMessages},
+			{ID: "103", Type: "member", Deny: discordgo.PermissionSendMessages},
+		}}},
+		Members: []*discordgo.Member{
+			{User: &discordgo.User{ID: "100"}},
+			{User: &discordgo.User{ID: "101"}},
+			{User: &discordgo.User{ID: "102"}, Roles: []string{"21"}},
+			{User: &discordgo.User{ID: "103"}, Roles: []string{"21"}},
+			{User: &discordgo.User{ID: "104"}, Roles: []string{"22"}},
+		},
+	}
+	state.channelGuild["11"] = "1"
+	state.indexMembers(state.guildMap["1"])
+	state.userID = "102"
+
+	tests := []struct {
+		userID string
+		send   bool
+	}{
+		{userID: "101", send: false},
+		{userID: "102", send: true},
+		{userID: "103", send: false},
+		{userID: "104", send: true},
+	}
+	for _, test := range tests {
+		permissions, err := state.UserChannelPermissions(test.userID, "11")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (permissions&discordgo.PermissionSendMessages == discordgo.PermissionSendMessages) != test.send {
+			t.Fatal("unexpected permissions for", test.userID, permissions)
+		}
+	}
+
+	permissions, err := state.UserChannelPermissions("100", "11")
+	if err != nil || permissions != discordgo.PermissionAll {
+		t.Fatal("owner does not have all permissions", permissions, err)
+	}
+	permissions, err = state.BotChannelPermissions("11")
+	if err != nil || permissions&discordgo.PermissionManageMessages != discordgo.PermissionManageMessages {
+		t.Fatal("unexpected bot permissions", permissions, err)
+	}
+	_, err = state.UserChannelPermissions("105", "11")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("expected not found, got", err)
+	}
+}
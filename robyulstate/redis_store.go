@@ -0,0 +1,343 @@
+package robyulstate
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-redis/redis"
+)
+
+// RedisStore shares the guilds of all processes using the same Redis
+// Guilds are stored without their members, the members of each guild are stored in a hash
+// The guild list is a sorted set by expiration, guilds which are not refreshed by their process expire
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+
+	// Expiration is how long guilds are kept after they have been set or refreshed the last time
+	Expiration time.Duration
+}
+
+// NewRedisStore creates a Store using the Redis client
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client:     client,
+		prefix:     "robyul2-discord:state:",
+		Expiration: 5 * time.Minute,
+	}
+}
+
+func (r *RedisStore) Guild(guildID string) (*discordgo.Guild, error) {
+	pipeline := r.client.Pipeline()
+	guildCmd := pipeline.Get(r.guildKey(guildID))
+	membersCmd := pipeline.HGetAll(r.membersKey(guildID))
+	_, err := pipeline.Exec()
+	if err == redis.Nil {
+		return nil, discordgo.ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.unmarshalGuild(guildCmd, membersCmd)
+}
+
+func (r *RedisStore) Guilds() (guilds []*discordgo.Guild, err error) {
+	return r.guilds(true)
+}
+
+func (r *RedisStore) GuildsWithoutMembers() (guilds []*discordgo.Guild, err error) {
+	return r.guilds(false)
+}
+
+func (r *RedisStore) GuildCount() (int, error) {
+	count, err := r.client.ZCount(r.guildListKey(), r.now(), "+inf").Result()
+	return int(count), err
+}
+
+func (r *RedisStore) guilds(withMembers bool) (guilds []*discordgo.Guild, err error) {
+	guildIDs, err := r.client.ZRangeByScore(r.guildListKey(), redis.ZRangeBy{Min: r.now(), Max: "+inf"}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := r.client.Pipeline()
+	guildCmds := make([]*redis.StringCmd, len(guildIDs))
+	membersCmds := make([]*redis.StringStringMapCmd, len(guildIDs))
+	for i, guildID := range guildIDs {
+		guildCmds[i] = pipeline.Get(r.guildKey(guildID))
+		if withMembers {
+			membersCmds[i] = pipeline.HGetAll(r.membersKey(guildID))
+		}
+	}
+	_, err = pipeline.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	guilds = make([]*discordgo.Guild, 0, len(guildIDs))
+	for i := range guildIDs {
+		// guilds removed in the meantime
+		if guildCmds[i].Err() == redis.Nil {
+			continue
+		}
+
+		guild, err := r.unmarshalGuild(guildCmds[i], membersCmds[i])
+		if err != nil {
+			return nil, err
+		}
+		guilds = append(guilds, guild)
+	}
+	return guilds, nil
+}
+
+func (r *RedisStore) SetGuild(guild *discordgo.Guild) error {
+	data, err := json.Marshal(withoutMembers(guild))
+	if err != nil {
+		return err
+	}
+
+	// channels which have been deleted are removed from the channel index
+	removedChannelIDs := make([]string, 0)
+	previousGuild, err := r.guildWithoutMembers(guild.ID)
+	if err != nil && err != discordgo.ErrStateNotFound {
+		return err
+	}
+	if previousGuild != nil {
+		channelIDs := make(map[string]bool, len(guild.Channels))
+		for _, channel := range guild.Channels {
+			channelIDs[channel.ID] = true
+		}
+		for _, channel := range previousGuild.Channels {
+			if !channelIDs[channel.ID] {
+				removedChannelIDs = append(removedChannelIDs, channel.ID)
+			}
+		}
+	}
+
+	pipeline := r.client.TxPipeline()
+	pipeline.Set(r.guildKey(guild.ID), data, 2*r.Expiration)
+	pipeline.ZAdd(r.guildListKey(), redis.Z{Score: r.expiresAt(), Member: guild.ID})
+	if len(removedChannelIDs) > 0 {
+		pipeline.HDel(r.prefix+"channels", removedChannelIDs...)
+	}
+	if len(guild.Channels) > 0 {
+		channels := make(map[string]interface{}, len(guild.Channels))
+		for _, channel := range guild.Channels {
+			channels[channel.ID] = guild.ID
+		}
+		pipeline.HMSet(r.prefix+"channels", channels)
+	}
+	if guild.Members != nil {
+		members, err := marshalMembers(guild.Members)
+		if err != nil {
+			return err
+		}
+		pipeline.Del(r.membersKey(guild.ID))
+		if len(members) > 0 {
+			pipeline.HMSet(r.membersKey(guild.ID), members)
+		}
+	}
+	pipeline.PExpire(r.membersKey(guild.ID), 2*r.Expiration)
+	_, err = pipeline.Exec()
+	return err
+}
+
+func (r *RedisStore) RemoveGuild(guildID string) error {
+	guild, err := r.guildWithoutMembers(guildID)
+	if err == discordgo.ErrStateNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pipeline := r.client.TxPipeline()
+	pipeline.Del(r.guildKey(guildID), r.membersKey(guildID))
+	pipeline.ZRem(r.guildListKey(), guildID)
+	if len(guild.Channels) > 0 {
+		channelIDs := make([]string, len(guild.Channels))
+		for i, channel := range guild.Channels {
+			channelIDs[i] = channel.ID
+		}
+		pipeline.HDel(r.prefix+"channels", channelIDs...)
+	}
+	_, err = pipeline.Exec()
+	return err
+}
+
+func (r *RedisStore) Refresh(guildIDs []string) error {
+	if len(guildIDs) > 0 {
+		pipeline := r.client.Pipeline()
+		expiresAt := r.expiresAt()
+		for _, guildID := range guildIDs {
+			pipeline.PExpire(r.guildKey(guildID), 2*r.Expiration)
+			pipeline.PExpire(r.membersKey(guildID), 2*r.Expiration)
+			pipeline.ZAdd(r.guildListKey(), redis.Z{Score: expiresAt, Member: guildID})
+		}
+		_, err := pipeline.Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	// remove the guilds of processes which stopped, their keys expire later than the guild list, so their channels are known
+	expiredGuildIDs, err := r.client.ZRangeByScore(r.guildListKey(), redis.ZRangeBy{Min: "-inf", Max: "(" + r.now()}).Result()
+	if err != nil {
+		return err
+	}
+	for _, guildID := range expiredGuildIDs {
+		// the guild might have been set by another process in the meantime
+		expiresAt, err := r.client.ZScore(r.guildListKey(), guildID).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil && expiresAt >= score(time.Now()) {
+			continue
+		}
+
+		err = r.RemoveGuild(guildID)
+		if err != nil {
+			return err
+		}
+		// the guild key expired already
+		err = r.client.ZRem(r.guildListKey(), guildID).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RedisStore) ChannelGuildID(channelID string) (string, error) {
+	guildID, err := r.client.HGet(r.prefix+"channels", channelID).Result()
+	if err == redis.Nil {
+		return "", discordgo.ErrStateNotFound
+	}
+	return guildID, err
+}
+
+func (r *RedisStore) Member(guildID, userID string) (*discordgo.Member, error) {
+	data, err := r.client.HGet(r.membersKey(guildID), userID).Bytes()
+	if err == redis.Nil {
+		return nil, discordgo.ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var member *discordgo.Member
+	err = json.Unmarshal(data, &member)
+	if err != nil {
+		return nil, err
+	}
+	member.GuildID = guildID
+	return member, nil
+}
+
+func (r *RedisStore) SetMembers(guildID string, members []*discordgo.Member) error {
+	fields, err := marshalMembers(members)
+	if err != nil || len(fields) <= 0 {
+		return err
+	}
+
+	pipeline := r.client.TxPipeline()
+	pipeline.HMSet(r.membersKey(guildID), fields)
+	pipeline.PExpire(r.membersKey(guildID), 2*r.Expiration)
+	_, err = pipeline.Exec()
+	return err
+}
+
+func (r *RedisStore) RemoveMember(guildID, userID string) error {
+	return r.client.HDel(r.membersKey(guildID), userID).Err()
+}
+
+func (r *RedisStore) guildKey(guildID string) string {
+	return r.prefix + "guild:" + guildID
+}
+
+func (r *RedisStore) membersKey(guildID string) string {
+	return r.prefix + "members:" + guildID
+}
+
+func (r *RedisStore) guildListKey() string {
+	return r.prefix + "guild-expirations"
+}
+
+// score returns the time as score of the guild list
+func score(t time.Time) float64 {
+	return float64(t.UnixNano() / int64(time.Millisecond))
+}
+
+// now returns the current time as score range of the guild list
+func (r *RedisStore) now() string {
+	return strconv.FormatFloat(score(time.Now()), 'f', 0, 64)
+}
+
+// expiresAt returns the time guilds set or refreshed now expire, as score of the guild list
+func (r *RedisStore) expiresAt() float64 {
+	return score(time.Now().Add(r.Expiration))
+}
+
+// guildWithoutMembers returns the stored guild without loading its members
+func (r *RedisStore) guildWithoutMembers(guildID string) (*discordgo.Guild, error) {
+	guildCmd := r.client.Get(r.guildKey(guildID))
+	if guildCmd.Err() != nil && guildCmd.Err() != redis.Nil {
+		return nil, guildCmd.Err()
+	}
+	return r.unmarshalGuild(guildCmd, nil)
+}
+
+func (r *RedisStore) unmarshalGuild(guildCmd *redis.StringCmd, membersCmd *redis.StringStringMapCmd) (*discordgo.Guild, error) {
+	data, err := guildCmd.Bytes()
+	if err == redis.Nil {
+		return nil, discordgo.ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var guild *discordgo.Guild
+	err = json.Unmarshal(data, &guild)
+	if err != nil {
+		return nil, err
+	}
+
+	// membersCmd is nil if the members have not been requested
+	if membersCmd != nil {
+		guild.Members = make([]*discordgo.Member, 0, len(membersCmd.Val()))
+		for _, memberData := range membersCmd.Val() {
+			var member *discordgo.Member
+			err = json.Unmarshal([]byte(memberData), &member)
+			if err != nil {
+				return nil, err
+			}
+			member.GuildID = guild.ID
+			guild.Members = append(guild.Members, member)
+		}
+	}
+	for _, channel := range guild.Channels {
+		channel.GuildID = guild.ID
+	}
+
+	return guild, nil
+}
+
+// marshalMembers returns the members as hash fields by user ID
+func marshalMembers(members []*discordgo.Member) (map[string]interface{}, error) {
+	fields := make(map[string]interface{}, len(members))
+	for _, member := range members {
+		if member.User == nil {
+			continue
+		}
+
+		data, err := json.Marshal(member)
+		if err != nil {
+			return nil, err
+		}
+		fields[member.User.ID] = string(data)
+	}
+	return fields, nil
+}
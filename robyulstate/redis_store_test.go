@@ -0,0 +1,318 @@
+package robyulstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-redis/redis"
+)
+
+// newTestRedisStore creates a RedisStore using a fresh miniredis server
+func newTestRedisStore(t *testing.T) (*RedisStore, func()) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+
+	return NewRedisStore(client), func() {
+		client.Close()
+		redisServer.Close()
+	}
+}
+
+func testMember(userID string) *discordgo.Member {
+	return &discordgo.Member{User: &discordgo.User{ID: userID, Username: "user " + userID}}
+}
+
+func testGuild() *discordgo.Guild {
+	return &discordgo.Guild{
+		ID:       "1",
+		Name:     "Robyul",
+		Channels: []*discordgo.Channel{{ID: "11", Name: "general"}, {ID: "12", Name: "random"}},
+		Members:  []*discordgo.Member{testMember("101"), testMember("102")},
+	}
+}
+
+func TestRedisStoreGuild(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	_, err := store.Guild("1")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("expected not found, got", err)
+	}
+
+	err = store.SetGuild(testGuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guild, err := store.Guild("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guild.Name != "Robyul" || len(guild.Channels) != 2 || len(guild.Members) != 2 {
+		t.Fatal("unexpected guild", guild.Name, len(guild.Channels), len(guild.Members))
+	}
+	if guild.Channels[0].GuildID != "1" || guild.Members[0].GuildID != "1" {
+		t.Fatal("guild ID has not been set on channels and members")
+	}
+
+	guildID, err := store.ChannelGuildID("12")
+	if err != nil || guildID != "1" {
+		t.Fatal("unexpected guild of channel", guildID, err)
+	}
+	_, err = store.ChannelGuildID("13")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("expected not found, got", err)
+	}
+}
+
+func TestRedisStoreSetGuildKeepsMembers(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	err := store.SetGuild(testGuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// guild updates come without members
+	updatedGuild := testGuild()
+	updatedGuild.Name = "Robyul Updated"
+	updatedGuild.Members = nil
+	err = store.SetGuild(updatedGuild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guild, err := store.Guild("1")
+	if err != nil || guild.Name != "Robyul Updated" || len(guild.Members) != 2 {
+		t.Fatal("members have not been kept", guild, err)
+	}
+
+	// an empty member list replaces the members
+	updatedGuild.Members = []*discordgo.Member{}
+	err = store.SetGuild(updatedGuild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guild, err = store.Guild("1")
+	if err != nil || len(guild.Members) != 0 {
+		t.Fatal("members have not been replaced", guild, err)
+	}
+}
+
+func TestRedisStoreGuilds(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	guilds, err := store.Guilds()
+	if err != nil || len(guilds) != 0 {
+		t.Fatal("expected no guilds", guilds, err)
+	}
+
+	otherGuild := &discordgo.Guild{ID: "2", Name: "Other"}
+	for _, guild := range []*discordgo.Guild{testGuild(), otherGuild} {
+		err = store.SetGuild(guild)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	guilds, err = store.Guilds()
+	if err != nil || len(guilds) != 2 {
+		t.Fatal("unexpected guilds", guilds, err)
+	}
+	for _, guild := range guilds {
+		if guild.ID == "1" && len(guild.Members) != 2 {
+			t.Fatal("members have not been loaded", len(guild.Members))
+		}
+	}
+}
+
+func TestRedisStoreRemoveGuild(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	// removing unknown guilds is no error
+	err := store.RemoveGuild("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.SetGuild(testGuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.RemoveGuild("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.Guild("1")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("expected not found, got", err)
+	}
+	_, err = store.ChannelGuildID("11")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("channels have not been removed", err)
+	}
+	_, err = store.Member("1", "101")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("members have not been removed", err)
+	}
+	guilds, err := store.Guilds()
+	if err != nil || len(guilds) != 0 {
+		t.Fatal("guild has not been removed from the guild list", guilds, err)
+	}
+}
+
+func TestRedisStoreMembers(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	err := store.SetGuild(testGuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := store.Member("1", "101")
+	if err != nil || member.User.Username != "user 101" || member.GuildID != "1" {
+		t.Fatal("unexpected member", member, err)
+	}
+	_, err = store.Member("1", "103")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("expected not found, got", err)
+	}
+
+	// other members are kept
+	updatedMember := testMember("101")
+	updatedMember.Nick = "nick"
+	err = store.SetMembers("1", []*discordgo.Member{updatedMember, testMember("103"), {Nick: "without user"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err = store.Member("1", "101")
+	if err != nil || member.Nick != "nick" {
+		t.Fatal("member has not been updated", member, err)
+	}
+	guild, err := store.Guild("1")
+	if err != nil || len(guild.Members) != 3 {
+		t.Fatal("unexpected members", guild, err)
+	}
+
+	err = store.RemoveMember("1", "102")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Member("1", "102")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("member has not been removed", err)
+	}
+}
+
+func TestRedisStoreGuildsWithoutMembers(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	for _, guild := range []*discordgo.Guild{testGuild(), {ID: "2", Name: "Other"}} {
+		err := store.SetGuild(guild)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	guilds, err := store.GuildsWithoutMembers()
+	if err != nil || len(guilds) != 2 {
+		t.Fatal("unexpected guilds", guilds, err)
+	}
+	for _, guild := range guilds {
+		if len(guild.Members) != 0 {
+			t.Fatal("members have been loaded", len(guild.Members))
+		}
+		if guild.ID == "1" && len(guild.Channels) != 2 {
+			t.Fatal("channels have not been loaded", len(guild.Channels))
+		}
+	}
+
+	count, err := store.GuildCount()
+	if err != nil || count != 2 {
+		t.Fatal("unexpected guild count", count, err)
+	}
+}
+
+func TestRedisStoreSetGuildRemovesChannels(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+
+	err := store.SetGuild(testGuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updatedGuild := testGuild()
+	updatedGuild.Channels = updatedGuild.Channels[:1]
+	err = store.SetGuild(updatedGuild)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.ChannelGuildID("12")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("deleted channel has not been removed", err)
+	}
+	guildID, err := store.ChannelGuildID("11")
+	if err != nil || guildID != "1" {
+		t.Fatal("unexpected guild of channel", guildID, err)
+	}
+}
+
+func TestRedisStoreRefresh(t *testing.T) {
+	store, closeStore := newTestRedisStore(t)
+	defer closeStore()
+	store.Expiration = 50 * time.Millisecond
+
+	otherGuild := &discordgo.Guild{ID: "2", Name: "Other", Channels: []*discordgo.Channel{{ID: "21"}}}
+	for _, guild := range []*discordgo.Guild{testGuild(), otherGuild} {
+		err := store.SetGuild(guild)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// only the process of the first guild is still running
+	time.Sleep(30 * time.Millisecond)
+	err := store.Refresh([]string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	err = store.Refresh([]string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := store.GuildCount()
+	if err != nil || count != 1 {
+		t.Fatal("unexpected guild count", count, err)
+	}
+	guilds, err := store.GuildsWithoutMembers()
+	if err != nil || len(guilds) != 1 || guilds[0].ID != "1" {
+		t.Fatal("guild of a stopped process has not expired", guilds, err)
+	}
+	_, err = store.Guild("2")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("guild of a stopped process has not been removed", err)
+	}
+	_, err = store.ChannelGuildID("21")
+	if err != discordgo.ErrStateNotFound {
+		t.Fatal("channels of a stopped process have not been removed", err)
+	}
+	_, err = store.Member("1", "101")
+	if err != nil {
+		t.Fatal("members of a refreshed guild have been removed", err)
+	}
+}
@@ -2,11 +2,10 @@ package robyulstate
 
 import (
 	"sync"
+	"time"
 
 	"fmt"
 
-	"github.com/Seklfreak/Robyul2/cache"
-	"github.com/Seklfreak/Robyul2/helpers"
 	"github.com/bwmarrin/discordgo"
 	"github.com/davecgh/go-spew/spew"
 	raven "github.com/getsentry/raven-go"
@@ -14,17 +13,34 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Robyulstate tracks the guilds of this process, and calls diff handlers when guilds, channels, members, roles or emoji change
+// If a Store is set the guilds are shared with other processes, guilds of other processes are read from the Store
 type Robyulstate struct {
 	sync.RWMutex
 
-	guildMap map[string]*discordgo.Guild
+	guildMap     map[string]*discordgo.Guild
+	channelGuild map[string]string
+	// memberIndex is the position of each member in guild.Members by guild ID and user ID
+	memberIndex map[string]map[string]int
+	userID      string
+
+	// Store shares the guilds with other processes, guilds are only kept in memory if nil
+	Store Store
+
+	// Members configures which members are cached
+	Members MemberCaching
 
 	Logger func(msgL, caller int, format string, a ...interface{})
+
+	handlersLock sync.RWMutex
+	handlers     diffHandlers
 }
 
 func NewState() *Robyulstate {
 	return &Robyulstate{
-		guildMap: make(map[string]*discordgo.Guild),
+		guildMap:     make(map[string]*discordgo.Guild),
+		channelGuild: make(map[string]string),
+		memberIndex:  make(map[string]map[string]int),
 	}
 }
 
@@ -54,9 +70,13 @@ func (s *Robyulstate) OnInterface(_ *discordgo.Session, i interface{}) {
 
 	var err error
 
-	//fmt.Println("received event:", reflect.TypeOf(i))
-
 	switch t := i.(type) {
+	case *discordgo.Ready:
+		s.Lock()
+		if t.User != nil {
+			s.userID = t.User.ID
+		}
+		s.Unlock()
 	case *discordgo.GuildCreate:
 		err = s.GuildAdd(t.Guild)
 	case *discordgo.GuildUpdate:
@@ -78,96 +98,274 @@ func (s *Robyulstate) OnInterface(_ *discordgo.Session, i interface{}) {
 	case *discordgo.GuildMemberUpdate:
 		err = s.MemberAdd(t.Member)
 	case *discordgo.GuildMembersChunk:
-		for i := range t.Members {
-			t.Members[i].GuildID = t.GuildID
-			err = s.MemberAdd(t.Members[i])
+		err = s.MembersChunk(t.GuildID, t.Members)
+	case *discordgo.MessageCreate:
+		// members are cached when they become active, if they are not cached yet
+		if t.GuildID == "" || t.Member == nil || t.Author == nil || t.Author.Bot || !s.IsLocal(t.GuildID) {
+			return
+		}
+		if _, err := s.Member(t.GuildID, t.Author.ID); err == nil {
+			return
 		}
+		member := new(discordgo.Member)
+		*member = *t.Member
+		member.GuildID = t.GuildID
+		member.User = t.Author
+		err = s.MemberAdd(member)
 	case *discordgo.PresenceUpdate:
-		//s.PresenceAdd(t.GuildID, &t.Presence)
+		err = s.PresenceUpdate(t)
+	case *discordgo.GuildRoleCreate:
+		err = s.RoleAdd(t.GuildID, t.Role)
+	case *discordgo.GuildRoleDelete:
+		err = s.RoleDelete(t.GuildID, t.RoleID)
+	case *discordgo.GuildRoleUpdate:
+		err = s.RoleAdd(t.GuildID, t.Role)
+	}
 
-		s.RLock()
-		if _, ok := s.guildMap[t.GuildID]; !ok || s.guildMap[t.GuildID] == nil {
-			s.RUnlock()
-			return
+	if err != nil {
+		s.Logger(discordgo.LogError, 0, err.Error())
+	}
+
+	return
+}
+
+// Guild returns the guild, guilds of other processes are read from the Store
+func (s *Robyulstate) Guild(guildID string) (*discordgo.Guild, error) {
+	if s == nil {
+		return nil, discordgo.ErrNilState
+	}
+
+	s.RLock()
+	guild, ok := s.guildMap[guildID]
+	if ok {
+		guildCopy := new(discordgo.Guild)
+		*guildCopy = *guild
+		s.RUnlock()
+		return guildCopy, nil
+	}
+	s.RUnlock()
+
+	if s.Store == nil {
+		return nil, discordgo.ErrStateNotFound
+	}
+	return s.Store.Guild(guildID)
+}
+
+// Guilds returns all guilds of this process, and the guilds of other processes from the Store
+func (s *Robyulstate) Guilds() (guilds []*discordgo.Guild, err error) {
+	if s == nil {
+		return nil, discordgo.ErrNilState
+	}
+
+	s.RLock()
+	for _, guild := range s.guildMap {
+		guildCopy := new(discordgo.Guild)
+		*guildCopy = *guild
+		guilds = append(guilds, guildCopy)
+	}
+	s.RUnlock()
+
+	if s.Store == nil {
+		return guilds, nil
+	}
+
+	sharedGuilds, err := s.Store.Guilds()
+	if err != nil {
+		return guilds, err
+	}
+	for _, guild := range sharedGuilds {
+		if !s.IsLocal(guild.ID) {
+			guilds = append(guilds, guild)
 		}
+	}
+	return guilds, nil
+}
 
-		if t.User == nil {
-			s.RUnlock()
-			return
+// GuildsWithoutMembers returns all guilds like Guilds, without their members
+// Use it if the members are not needed, loading the members of guilds of other processes is slow
+func (s *Robyulstate) GuildsWithoutMembers() (guilds []*discordgo.Guild, err error) {
+	if s == nil {
+		return nil, discordgo.ErrNilState
+	}
+
+	s.RLock()
+	for _, guild := range s.guildMap {
+		guilds = append(guilds, withoutMembers(guild))
+	}
+	s.RUnlock()
+
+	if s.Store == nil {
+		return guilds, nil
+	}
+
+	sharedGuilds, err := s.Store.GuildsWithoutMembers()
+	if err != nil {
+		return guilds, err
+	}
+	for _, guild := range sharedGuilds {
+		if !s.IsLocal(guild.ID) {
+			guilds = append(guilds, guild)
 		}
+	}
+	return guilds, nil
+}
 
-		var m *discordgo.Member
-		for _, possibleMember := range s.guildMap[t.GuildID].Members {
-			if possibleMember.User == nil {
-				continue
-			}
+// GuildCount returns the amount of guilds of all processes
+func (s *Robyulstate) GuildCount() (int, error) {
+	if s == nil {
+		return 0, discordgo.ErrNilState
+	}
 
-			if possibleMember.User.ID == t.User.ID {
-				m = possibleMember
-			}
+	s.RLock()
+	count := len(s.guildMap)
+	s.RUnlock()
+
+	if s.Store == nil {
+		return count, nil
+	}
+
+	// the guilds of this process are stored as well
+	sharedCount, err := s.Store.GuildCount()
+	if err != nil {
+		return count, err
+	}
+	if sharedCount < count {
+		return count, nil
+	}
+	return sharedCount, nil
+}
+
+// StoreRefreshLoop refreshes the guilds of this process in the Store, guilds of processes which stopped expire
+func (s *Robyulstate) StoreRefreshLoop(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+
+		if s.Store == nil {
+			continue
+		}
+
+		s.RLock()
+		guildIDs := make([]string, 0, len(s.guildMap))
+		for guildID := range s.guildMap {
+			guildIDs = append(guildIDs, guildID)
 		}
 		s.RUnlock()
 
-		if m == nil {
-			// Member not found; this is a user coming online
-			m = &discordgo.Member{
-				GuildID: t.GuildID,
-				Nick:    t.Nick,
-				User:    t.User,
-				Roles:   t.Roles,
-			}
+		s.share(func(store Store) error {
+			return store.Refresh(guildIDs)
+		})
+	}
+}
 
-		} else {
-			s.Lock()
+// IsLocal returns true if the guild is tracked by this process
+func (s *Robyulstate) IsLocal(guildID string) bool {
+	s.RLock()
+	defer s.RUnlock()
+	_, ok := s.guildMap[guildID]
+	return ok
+}
 
-			if t.Nick != "" {
-				m.Nick = t.Nick
-			}
+// LocalChannel returns the channel if its guild is tracked by this process
+func (s *Robyulstate) LocalChannel(channelID string) (*discordgo.Channel, error) {
+	if s == nil {
+		return nil, discordgo.ErrNilState
+	}
 
-			if t.User.Username != "" {
-				m.User.Username = t.User.Username
-			}
-			if t.User.Discriminator != "" {
-				m.User.Discriminator = t.User.Discriminator
-			}
+	s.RLock()
+	defer s.RUnlock()
 
-			// PresenceUpdates always contain a list of roles, so there's no need to check for an empty list here
-			m.Roles = t.Roles
+	guild, ok := s.guildMap[s.channelGuild[channelID]]
+	if !ok {
+		return nil, discordgo.ErrStateNotFound
+	}
+	for _, channel := range guild.Channels {
+		if channel.ID == channelID {
+			return channel, nil
+		}
+	}
+	return nil, discordgo.ErrStateNotFound
+}
+
+// Channel returns the channel, channels of guilds of other processes are read from the Store
+func (s *Robyulstate) Channel(channelID string) (*discordgo.Channel, error) {
+	channel, err := s.LocalChannel(channelID)
+	if err == nil || s.Store == nil {
+		return channel, err
+	}
 
-			s.Unlock()
+	guildID, err := s.Store.ChannelGuildID(channelID)
+	if err != nil {
+		return nil, err
+	}
+	guild, err := s.Store.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range guild.Channels {
+		if channel.ID == channelID {
+			return channel, nil
 		}
+	}
+	return nil, discordgo.ErrStateNotFound
+}
 
-		err = s.MemberAdd(m)
-	case *discordgo.GuildRoleCreate:
-		err = s.RoleAdd(t.GuildID, t.Role)
-	case *discordgo.GuildRoleDelete:
-		err = s.RoleDelete(t.GuildID, t.RoleID)
-	case *discordgo.GuildRoleUpdate:
-		err = s.RoleAdd(t.GuildID, t.Role)
-		/*
-			case *VoiceStateUpdate:
-				if s.TrackVoice {
-					err = s.voiceStateUpdate(t)
-				}
-		*/
+// Member returns the member of the guild, members of guilds of other processes are read from the Store
+func (s *Robyulstate) Member(guildID, userID string) (*discordgo.Member, error) {
+	if s == nil {
+		return nil, discordgo.ErrNilState
+	}
 
+	s.RLock()
+	guild, ok := s.guildMap[guildID]
+	if ok {
+		defer s.RUnlock()
+		if i, ok := s.memberIndex[guildID][userID]; ok {
+			return guild.Members[i], nil
+		}
+		return nil, discordgo.ErrStateNotFound
 	}
+	s.RUnlock()
 
-	if err != nil {
-		s.Logger(discordgo.LogError, 0, err.Error())
+	if s.Store == nil {
+		return nil, discordgo.ErrStateNotFound
 	}
+	return s.Store.Member(guildID, userID)
+}
 
-	return
+// cacheMember returns true if the member should be cached
+// Bulk members are members received in member lists, and not because of an action of the member
+func (s *Robyulstate) cacheMember(member *discordgo.Member, bulk bool) bool {
+	if member == nil || member.User == nil {
+		return false
+	}
+	if member.User.ID == s.userID {
+		return true
+	}
+
+	switch s.Members {
+	case MemberCachingAll:
+		return true
+	case MemberCachingActive:
+		return !bulk
+	}
+	return false
 }
 
-func (s *Robyulstate) GuildAdd(guild *discordgo.Guild) error {
-	if s == nil {
-		return discordgo.ErrNilState
+// share writes a change to the Store, if there is one
+// Must not be called while holding the lock
+func (s *Robyulstate) share(change func(store Store) error) {
+	if s.Store == nil {
+		return
 	}
 
-	s.Lock()
-	defer s.Unlock()
+	err := change(s.Store)
+	if err != nil {
+		s.Logger(discordgo.LogError, 1, "sharing state failed: "+err.Error())
+	}
+}
 
+// copyGuild copies the guild and its lists, only members which should be cached are copied
+func (s *Robyulstate) copyGuild(guild *discordgo.Guild) *discordgo.Guild {
 	guildCopy := new(discordgo.Guild)
 	*guildCopy = *guild
 
@@ -177,22 +375,73 @@ func (s *Robyulstate) GuildAdd(guild *discordgo.Guild) error {
 	guildCopy.Emojis = make([]*discordgo.Emoji, len(guild.Emojis))
 	copy(guildCopy.Emojis, guild.Emojis)
 
-	guildCopy.Members = make([]*discordgo.Member, len(guild.Members))
-	copy(guildCopy.Members, guild.Members)
+	guildCopy.Members = make([]*discordgo.Member, 0)
+	for _, member := range guild.Members {
+		if s.cacheMember(member, true) {
+			memberCopy := new(discordgo.Member)
+			*memberCopy = *member
+			memberCopy.GuildID = guild.ID
+			guildCopy.Members = append(guildCopy.Members, memberCopy)
+		}
+	}
 
-	guildCopy.Presences = make([]*discordgo.Presence, len(guild.Presences))
-	copy(guildCopy.Presences, guild.Presences)
+	// presences are not tracked
+	guildCopy.Presences = nil
 
 	guildCopy.Channels = make([]*discordgo.Channel, len(guild.Channels))
 	for i, guildChannel := range guild.Channels {
 		guildCopy.Channels[i] = new(discordgo.Channel)
 		*guildCopy.Channels[i] = *guildChannel
+		guildCopy.Channels[i].GuildID = guild.ID
 	}
 
 	guildCopy.VoiceStates = make([]*discordgo.VoiceState, len(guild.VoiceStates))
 	copy(guildCopy.VoiceStates, guild.VoiceStates)
 
+	return guildCopy
+}
+
+// indexMembers remembers the position of all members of the guild, needs the lock
+func (s *Robyulstate) indexMembers(guild *discordgo.Guild) {
+	positions := make(map[string]int, len(guild.Members))
+	for i, member := range guild.Members {
+		if member.User != nil {
+			positions[member.User.ID] = i
+		}
+	}
+	s.memberIndex[guild.ID] = positions
+}
+
+// indexChannels remembers the guild of all channels of the guild, needs the lock
+func (s *Robyulstate) indexChannels(guild *discordgo.Guild) {
+	for _, channel := range guild.Channels {
+		s.channelGuild[channel.ID] = guild.ID
+	}
+}
+
+// withoutMembers returns a copy of the guild without members, for updates of the Store which keep the members
+func withoutMembers(guild *discordgo.Guild) *discordgo.Guild {
+	guildCopy := new(discordgo.Guild)
+	*guildCopy = *guild
+	guildCopy.Members = nil
+	return guildCopy
+}
+
+func (s *Robyulstate) GuildAdd(guild *discordgo.Guild) error {
+	if s == nil {
+		return discordgo.ErrNilState
+	}
+
+	s.Lock()
+	guildCopy := s.copyGuild(guild)
 	s.guildMap[guild.ID] = guildCopy
+	s.indexChannels(guildCopy)
+	s.indexMembers(guildCopy)
+	s.Unlock()
+
+	s.share(func(store Store) error {
+		return store.SetGuild(guildCopy)
+	})
 
 	return nil
 }
@@ -203,58 +452,47 @@ func (s *Robyulstate) GuildUpdate(guild *discordgo.Guild) error {
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	// add new guild
-	if _, ok := s.guildMap[guild.ID]; !ok || s.guildMap[guild.ID] == nil {
-		guildCopy := new(discordgo.Guild)
-		*guildCopy = *guild
-		s.guildMap[guild.ID] = guildCopy
-	}
-
-	if s.guildMap[guild.ID].Name != guild.Name ||
-		s.guildMap[guild.ID].Icon != guild.Icon ||
-		s.guildMap[guild.ID].Region != guild.Region ||
-		s.guildMap[guild.ID].AfkChannelID != guild.AfkChannelID ||
-		//s.guildMap[guild.ID].EmbedChannelID != guild.EmbedChannelID || sent with every first update
-		s.guildMap[guild.ID].OwnerID != guild.OwnerID ||
-		s.guildMap[guild.ID].Splash != guild.Splash ||
-		s.guildMap[guild.ID].AfkTimeout != guild.AfkTimeout ||
-		s.guildMap[guild.ID].VerificationLevel != guild.VerificationLevel ||
-		//s.guildMap[guild.ID].EmbedEnabled != guild.EmbedEnabled || sent with every first update
-		s.guildMap[guild.ID].DefaultMessageNotifications != guild.DefaultMessageNotifications {
-		// guild got updated
-		//fmt.Println("guild update", s.guildMap[guild.ID].Name, "to", guild.Name)
-		guildCopy := new(discordgo.Guild)
-		*guildCopy = *s.guildMap[guild.ID]
-		go helpers.OnEventlogGuildUpdate(guild.ID, guildCopy, guild)
+	before, ok := s.guildMap[guild.ID]
+	if !ok {
+		s.Unlock()
+		return s.GuildAdd(guild)
 	}
 
-	guildCopy := new(discordgo.Guild)
-	*guildCopy = *guild
-
-	guildCopy.Roles = make([]*discordgo.Role, len(guild.Roles))
-	copy(guildCopy.Roles, guild.Roles)
-
-	guildCopy.Emojis = make([]*discordgo.Emoji, len(guild.Emojis))
-	copy(guildCopy.Emojis, guild.Emojis)
+	after := s.copyGuild(guild)
 
-	guildCopy.Members = make([]*discordgo.Member, len(guild.Members))
-	copy(guildCopy.Members, guild.Members)
-
-	guildCopy.Presences = make([]*discordgo.Presence, len(guild.Presences))
-	copy(guildCopy.Presences, guild.Presences)
+	// guild updates do not contain these lists
+	if len(guild.Members) <= 0 {
+		after.Members = before.Members
+	}
+	if len(guild.Channels) <= 0 {
+		after.Channels = before.Channels
+	}
+	if len(guild.VoiceStates) <= 0 {
+		after.VoiceStates = before.VoiceStates
+	}
+	if after.MemberCount <= 0 {
+		after.MemberCount = before.MemberCount
+	}
+	if after.JoinedAt == "" {
+		after.JoinedAt = before.JoinedAt
+	}
+	after.Large = after.Large || before.Large
 
-	guildCopy.Channels = make([]*discordgo.Channel, len(guild.Channels))
-	for i, guildChannel := range guild.Channels {
-		guildCopy.Channels[i] = new(discordgo.Channel)
-		*guildCopy.Channels[i] = *guildChannel
+	s.guildMap[guild.ID] = after
+	s.indexChannels(after)
+	if len(guild.Members) > 0 {
+		s.indexMembers(after)
 	}
+	s.Unlock()
 
-	guildCopy.VoiceStates = make([]*discordgo.VoiceState, len(guild.VoiceStates))
-	copy(guildCopy.VoiceStates, guild.VoiceStates)
+	if guildChanged(before, after) {
+		s.guildUpdated(guild.ID, before, after)
+	}
 
-	*s.guildMap[guild.ID] = *guildCopy
+	s.share(func(store Store) error {
+		return store.SetGuild(withoutMembers(after))
+	})
 
 	return nil
 }
@@ -264,10 +502,24 @@ func (s *Robyulstate) GuildRemove(guild *discordgo.Guild) error {
 		return discordgo.ErrNilState
 	}
 
+	// the guild is only unavailable because of an outage
+	if guild.Unavailable {
+		return nil
+	}
+
 	s.Lock()
-	defer s.Unlock()
+	if oldGuild, ok := s.guildMap[guild.ID]; ok {
+		for _, channel := range oldGuild.Channels {
+			delete(s.channelGuild, channel.ID)
+		}
+	}
+	delete(s.guildMap, guild.ID)
+	delete(s.memberIndex, guild.ID)
+	s.Unlock()
 
-	s.guildMap[guild.ID] = nil
+	s.share(func(store Store) error {
+		return store.RemoveGuild(guild.ID)
+	})
 
 	return nil
 }
@@ -282,65 +534,44 @@ func (s *Robyulstate) EmojisUpdate(guildID string, emojis []*discordgo.Emoji) er
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[guildID]; !ok || s.guildMap[guildID] == nil {
+	guild, ok := s.guildMap[guildID]
+	if !ok {
+		s.Unlock()
 		return errors.New(discordgo.ErrStateNotFound.Error() + ": EmojisUpdate (" + guildID + ")")
 	}
 
-	if s.guildMap[guildID].Emojis == nil {
-		s.guildMap[guildID].Emojis = make([]*discordgo.Emoji, len(emojis))
-		copy(s.guildMap[guildID].Emojis, emojis)
+	oldEmojis := make(map[string]*discordgo.Emoji, len(guild.Emojis))
+	for _, oldEmoji := range guild.Emojis {
+		oldEmojis[oldEmoji.ID] = oldEmoji
 	}
 
-	// remove guild emoji not in emojis
-	for _, oldEmoji := range s.guildMap[guildID].Emojis {
-		emojiRemoved := true
-		for _, newEmoji := range emojis {
-			if newEmoji.ID == oldEmoji.ID {
-				emojiRemoved = false
-			}
+	newEmojis := make([]*discordgo.Emoji, len(emojis))
+	for i, newEmoji := range emojis {
+		newEmojis[i] = new(discordgo.Emoji)
+		*newEmojis[i] = *newEmoji
+
+		oldEmoji, ok := oldEmojis[newEmoji.ID]
+		if !ok {
+			s.emojiCreated(guildID, newEmojis[i])
+			continue
 		}
-		if emojiRemoved {
-			newEmojis := make([]*discordgo.Emoji, 0)
-			for _, previousEmoji := range s.guildMap[guildID].Emojis {
-				if previousEmoji.ID == oldEmoji.ID {
-					continue
-				}
-				newEmojis = append(newEmojis, previousEmoji)
-			}
-			s.guildMap[guildID].Emojis = newEmojis
-			// emoji got removed
-			//fmt.Println("emoji removed", oldEmoji.Name)
-			go helpers.OnEventlogEmojiDelete(guildID, oldEmoji)
+		if emojiChanged(oldEmoji, newEmoji) {
+			s.emojiUpdated(guildID, oldEmoji, newEmojis[i])
 		}
+		delete(oldEmojis, newEmoji.ID)
 	}
-
-	// update guild emoji
-	for j, oldEmoji := range s.guildMap[guildID].Emojis {
-		for i, newEmoji := range emojis {
-			if oldEmoji.ID == newEmoji.ID {
-				if oldEmoji.Name != newEmoji.Name ||
-					oldEmoji.Animated != newEmoji.Animated ||
-					oldEmoji.RequireColons != newEmoji.RequireColons ||
-					oldEmoji.Managed != newEmoji.Managed {
-					// emoji got updated
-					//fmt.Println("emoji update", oldEmoji.Name, "to", newEmoji.Name)
-					go helpers.OnEventlogEmojiUpdate(guildID, oldEmoji, newEmoji)
-				}
-				emojis = append(emojis[:i], emojis[i+1:]...)
-				s.guildMap[guildID].Emojis[j] = newEmoji
-			}
-		}
+	for _, oldEmoji := range oldEmojis {
+		s.emojiDeleted(guildID, oldEmoji)
 	}
 
-	// add guild emoji
-	for _, newEmoji := range emojis {
-		s.guildMap[guildID].Emojis = append(s.guildMap[guildID].Emojis, newEmoji)
-		// emoji got added
-		//fmt.Println("emoji added", newEmoji.Name)
-		go helpers.OnEventlogEmojiCreate(guildID, newEmoji)
-	}
+	guild.Emojis = newEmojis
+	sharedGuild := withoutMembers(guild)
+	s.Unlock()
+
+	s.share(func(store Store) error {
+		return store.SetGuild(sharedGuild)
+	})
 
 	return nil
 }
@@ -355,43 +586,43 @@ func (s *Robyulstate) ChannelUpdate(newChannel *discordgo.Channel) error {
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[newChannel.GuildID]; !ok || s.guildMap[newChannel.GuildID] == nil {
+	guild, ok := s.guildMap[newChannel.GuildID]
+	if !ok {
+		s.Unlock()
 		return errors.New(discordgo.ErrStateNotFound.Error() + ": ChannelUpdate (" + newChannel.GuildID + ")")
 	}
 
-	if s.guildMap[newChannel.GuildID].Channels == nil {
-		s.guildMap[newChannel.GuildID].Channels = make([]*discordgo.Channel, 1)
-		s.guildMap[newChannel.GuildID].Channels[1] = new(discordgo.Channel)
-		*s.guildMap[newChannel.GuildID].Channels[1] = *newChannel
-	}
-
-	// update channel
-	for j, oldChannel := range s.guildMap[newChannel.GuildID].Channels {
-		if oldChannel.ID == newChannel.ID {
-			if oldChannel.Name != newChannel.Name ||
-				oldChannel.Topic != newChannel.Topic ||
-				oldChannel.NSFW != newChannel.NSFW ||
-				//oldChannel.Position != newChannel.Position ||
-				oldChannel.Bitrate != newChannel.Bitrate ||
-				oldChannel.ParentID != newChannel.ParentID ||
-				!helpers.ChannelOverwritesMatch(oldChannel.PermissionOverwrites, newChannel.PermissionOverwrites) {
-				// channel got updated
-				//fmt.Println("channel update", oldChannel.Name, "to", oldChannel.Name)
-				go helpers.OnEventlogChannelUpdate(newChannel.GuildID, oldChannel, newChannel)
-			}
-			s.guildMap[newChannel.GuildID].Channels[j] = newChannel
-			return nil
+	channelCopy := new(discordgo.Channel)
+	*channelCopy = *newChannel
+
+	// channels are replaced instead of changed, readers might still use the old channel
+	channels := make([]*discordgo.Channel, 0, len(guild.Channels)+1)
+	found := false
+	for _, oldChannel := range guild.Channels {
+		if oldChannel.ID != newChannel.ID {
+			channels = append(channels, oldChannel)
+			continue
+		}
+
+		found = true
+		if channelChanged(oldChannel, channelCopy) {
+			s.channelUpdated(newChannel.GuildID, oldChannel, channelCopy)
 		}
+		channels = append(channels, channelCopy)
+	}
+	if !found {
+		channels = append(channels, channelCopy)
 	}
 
-	channelCopy := new(discordgo.Channel)
-	*channelCopy = *newChannel
+	guild.Channels = channels
+	s.channelGuild[newChannel.ID] = newChannel.GuildID
+	sharedGuild := withoutMembers(guild)
+	s.Unlock()
 
-	// add channel
-	//fmt.Println("added channel")
-	s.guildMap[newChannel.GuildID].Channels = append(s.guildMap[newChannel.GuildID].Channels, channelCopy)
+	s.share(func(store Store) error {
+		return store.SetGuild(sharedGuild)
+	})
 
 	return nil
 }
@@ -406,71 +637,127 @@ func (s *Robyulstate) ChannelDelete(channel *discordgo.Channel) error {
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[channel.GuildID]; !ok || s.guildMap[channel.GuildID] == nil {
+	guild, ok := s.guildMap[channel.GuildID]
+	if !ok {
+		s.Unlock()
 		return errors.New(discordgo.ErrStateNotFound.Error() + ": ChannelDelete (" + channel.GuildID + ")")
 	}
 
-	if s.guildMap[channel.GuildID].Channels == nil {
-		s.guildMap[channel.GuildID].Channels = make([]*discordgo.Channel, 0)
-	}
-
-	for j, oldChannel := range s.guildMap[channel.GuildID].Channels {
-		if oldChannel.ID == channel.ID {
-			// remove channel
-			//fmt.Println("removed channel")
-			s.guildMap[channel.GuildID].Channels = append(s.guildMap[channel.GuildID].Channels[:j], s.guildMap[channel.GuildID].Channels[j+1:]...)
+	channels := make([]*discordgo.Channel, 0, len(guild.Channels))
+	for _, oldChannel := range guild.Channels {
+		if oldChannel.ID != channel.ID {
+			channels = append(channels, oldChannel)
 		}
 	}
 
+	guild.Channels = channels
+	delete(s.channelGuild, channel.ID)
+	sharedGuild := withoutMembers(guild)
+	s.Unlock()
+
+	s.share(func(store Store) error {
+		return store.SetGuild(sharedGuild)
+	})
+
 	return nil
 }
 
+// MemberAdd adds or updates a member which joined, changed, or became active
 func (s *Robyulstate) MemberAdd(member *discordgo.Member) error {
 	if s == nil {
 		return discordgo.ErrNilState
 	}
 
-	if member.GuildID == "" {
+	if member.GuildID == "" || member.User == nil {
 		return nil
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[member.GuildID]; !ok || s.guildMap[member.GuildID] == nil {
+	guild, ok := s.guildMap[member.GuildID]
+	if !ok {
+		s.Unlock()
 		return errors.New(discordgo.ErrStateNotFound.Error() + ": MemberAdd (" + member.GuildID + ")")
 	}
 
-	if s.guildMap[member.GuildID].Members == nil {
-		s.guildMap[member.GuildID].Members = make([]*discordgo.Member, 0)
-	}
+	memberCopy := new(discordgo.Member)
+	*memberCopy = *member
 
-	for j, oldMember := range s.guildMap[member.GuildID].Members {
-		if oldMember.User.ID == member.User.ID {
-			// update member
-			oldRoles, newRoles := helpers.StringSliceDiff(oldMember.Roles, member.Roles)
-			if (len(oldRoles) > 0 || len(newRoles) > 0) ||
-				(oldMember.User.Username != member.User.Username && oldMember.User.Username != "" && member.User.Username != "") ||
-				oldMember.Nick != member.Nick ||
-				(oldMember.User.Discriminator != member.User.Discriminator && oldMember.User.Discriminator != "") {
-				//fmt.Println("member", member.User.Username, "update roles:", len(oldMember.Roles), "to:", len(member.Roles))
-				go helpers.OnEventlogMemberUpdate(member.GuildID, oldMember, member)
-			}
+	if j, ok := s.memberIndex[member.GuildID][member.User.ID]; ok {
+		oldMember := guild.Members[j]
 
-			s.guildMap[member.GuildID].Members[j] = new(discordgo.Member)
-			*s.guildMap[member.GuildID].Members[j] = *member
-			return nil
+		// update member
+		if memberChanged(oldMember, memberCopy) {
+			s.memberUpdated(member.GuildID, oldMember, memberCopy)
 		}
+		guild.Members[j] = memberCopy
+		s.Unlock()
+
+		s.share(func(store Store) error {
+			return store.SetMembers(member.GuildID, []*discordgo.Member{memberCopy})
+		})
+		return nil
 	}
 
-	memberCopy := new(discordgo.Member)
-	*memberCopy = *member
+	if !s.cacheMember(memberCopy, false) {
+		s.Unlock()
+		return nil
+	}
 
 	// add member
-	//fmt.Println("added member")
-	s.guildMap[member.GuildID].Members = append(s.guildMap[member.GuildID].Members, memberCopy)
+	guild.Members = append(guild.Members, memberCopy)
+	s.memberIndex[member.GuildID][member.User.ID] = len(guild.Members) - 1
+	s.Unlock()
+
+	s.share(func(store Store) error {
+		return store.SetMembers(member.GuildID, []*discordgo.Member{memberCopy})
+	})
+
+	return nil
+}
+
+// MembersChunk adds the members of a member list requested from the gateway
+func (s *Robyulstate) MembersChunk(guildID string, members []*discordgo.Member) error {
+	if s == nil {
+		return discordgo.ErrNilState
+	}
+
+	s.Lock()
+
+	guild, ok := s.guildMap[guildID]
+	if !ok {
+		s.Unlock()
+		return errors.New(discordgo.ErrStateNotFound.Error() + ": MembersChunk (" + guildID + ")")
+	}
+
+	positions := s.memberIndex[guildID]
+
+	added := make([]*discordgo.Member, 0, len(members))
+	for _, member := range members {
+		if !s.cacheMember(member, true) {
+			continue
+		}
+
+		memberCopy := new(discordgo.Member)
+		*memberCopy = *member
+		memberCopy.GuildID = guildID
+
+		if i, ok := positions[member.User.ID]; ok {
+			guild.Members[i] = memberCopy
+		} else {
+			guild.Members = append(guild.Members, memberCopy)
+			positions[member.User.ID] = len(guild.Members) - 1
+		}
+		added = append(added, memberCopy)
+	}
+	s.Unlock()
+
+	if len(added) > 0 {
+		s.share(func(store Store) error {
+			return store.SetMembers(guildID, added)
+		})
+	}
 
 	return nil
 }
@@ -480,34 +767,93 @@ func (s *Robyulstate) MemberRemove(member *discordgo.Member) error {
 		return discordgo.ErrNilState
 	}
 
-	if member.GuildID == "" {
+	if member.GuildID == "" || member.User == nil {
 		return nil
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[member.GuildID]; !ok || s.guildMap[member.GuildID] == nil {
-		if member.User.ID == cache.GetSession().SessionForGuildS(member.GuildID).State.User.ID {
-			// robyul left a guild, ignore
-			return nil
+	guild, ok := s.guildMap[member.GuildID]
+	if !ok {
+		s.Unlock()
+		// robyul left the guild
+		return nil
+	}
+
+	positions := s.memberIndex[member.GuildID]
+	if i, ok := positions[member.User.ID]; ok {
+		// the list is copied, readers might still use the old list, the last member takes the place of the removed member
+		last := len(guild.Members) - 1
+		members := make([]*discordgo.Member, last)
+		copy(members, guild.Members[:last])
+		if i < last {
+			members[i] = guild.Members[last]
+			positions[members[i].User.ID] = i
 		}
-		return errors.New(discordgo.ErrStateNotFound.Error() + ": MemberRemove (" + member.GuildID + ")")
+		guild.Members = members
+		delete(positions, member.User.ID)
 	}
+	s.Unlock()
 
-	if s.guildMap[member.GuildID].Members == nil {
-		s.guildMap[member.GuildID].Members = make([]*discordgo.Member, 0)
+	s.share(func(store Store) error {
+		return store.RemoveMember(member.GuildID, member.User.ID)
+	})
+
+	return nil
+}
+
+// PresenceUpdate updates the name, nickname and roles of a member, presence updates contain the latest user
+func (s *Robyulstate) PresenceUpdate(presence *discordgo.PresenceUpdate) error {
+	if s == nil {
+		return discordgo.ErrNilState
 	}
 
-	for j, oldMember := range s.guildMap[member.GuildID].Members {
-		if oldMember.User.ID == member.User.ID {
-			// remove member
-			//fmt.Println("removed member")
-			s.guildMap[member.GuildID].Members = append(s.guildMap[member.GuildID].Members[:j], s.guildMap[member.GuildID].Members[j+1:]...)
+	if presence.GuildID == "" || presence.User == nil {
+		return nil
+	}
+
+	if !s.IsLocal(presence.GuildID) {
+		return nil
+	}
+
+	oldMember, err := s.Member(presence.GuildID, presence.User.ID)
+	if err != nil {
+		// members coming online are only cached if all members are cached
+		if s.Members != MemberCachingAll {
+			return nil
 		}
+		return s.MemberAdd(&discordgo.Member{
+			GuildID: presence.GuildID,
+			Nick:    presence.Nick,
+			User:    presence.User,
+			Roles:   presence.Roles,
+		})
 	}
 
-	return nil
+	// the cached member is replaced, not changed, so the diff handlers receive the previous member
+	member := new(discordgo.Member)
+	*member = *oldMember
+	member.User = new(discordgo.User)
+	if oldMember.User != nil {
+		*member.User = *oldMember.User
+	}
+
+	if presence.Nick != "" {
+		member.Nick = presence.Nick
+	}
+	if presence.User.Username != "" {
+		member.User.Username = presence.User.Username
+	}
+	if presence.User.Discriminator != "" {
+		member.User.Discriminator = presence.User.Discriminator
+	}
+	if presence.User.Avatar != "" {
+		member.User.Avatar = presence.User.Avatar
+	}
+	// presence updates always contain a list of roles, so there's no need to check for an empty list here
+	member.Roles = presence.Roles
+
+	return s.MemberAdd(member)
 }
 
 func (s *Robyulstate) RoleAdd(guildID string, role *discordgo.Role) error {
@@ -520,41 +866,41 @@ func (s *Robyulstate) RoleAdd(guildID string, role *discordgo.Role) error {
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[guildID]; !ok || s.guildMap[guildID] == nil {
+	guild, ok := s.guildMap[guildID]
+	if !ok {
+		s.Unlock()
 		return errors.New(discordgo.ErrStateNotFound.Error() + ": RoleAdd (" + guildID + ")")
 	}
 
-	if s.guildMap[guildID].Roles == nil {
-		s.guildMap[guildID].Roles = make([]*discordgo.Role, 0)
-	}
+	roleCopy := new(discordgo.Role)
+	*roleCopy = *role
 
-	for j, oldRole := range s.guildMap[guildID].Roles {
-		if oldRole.ID == role.ID {
-			// update role
-			if oldRole.Name != role.Name ||
-				oldRole.Managed != role.Managed ||
-				oldRole.Mentionable != role.Mentionable ||
-				oldRole.Hoist != role.Hoist ||
-				oldRole.Color != role.Color ||
-				//oldRole.Position != role.Position ||
-				oldRole.Permissions != role.Permissions {
-				go helpers.OnEventlogRoleUpdate(guildID, oldRole, role)
-			}
+	roles := make([]*discordgo.Role, 0, len(guild.Roles)+1)
+	found := false
+	for _, oldRole := range guild.Roles {
+		if oldRole.ID != role.ID {
+			roles = append(roles, oldRole)
+			continue
+		}
 
-			s.guildMap[guildID].Roles[j] = new(discordgo.Role)
-			*s.guildMap[guildID].Roles[j] = *role
-			return nil
+		found = true
+		if roleChanged(oldRole, roleCopy) {
+			s.roleUpdated(guildID, oldRole, roleCopy)
 		}
+		roles = append(roles, roleCopy)
+	}
+	if !found {
+		roles = append(roles, roleCopy)
 	}
 
-	roleCopy := new(discordgo.Role)
-	*roleCopy = *role
+	guild.Roles = roles
+	sharedGuild := withoutMembers(guild)
+	s.Unlock()
 
-	// add role
-	//fmt.Println("added role")
-	s.guildMap[guildID].Roles = append(s.guildMap[guildID].Roles, roleCopy)
+	s.share(func(store Store) error {
+		return store.SetGuild(sharedGuild)
+	})
 
 	return nil
 }
@@ -569,23 +915,27 @@ func (s *Robyulstate) RoleDelete(guildID, roleID string) error {
 	}
 
 	s.Lock()
-	defer s.Unlock()
 
-	if _, ok := s.guildMap[guildID]; !ok || s.guildMap[guildID] == nil {
+	guild, ok := s.guildMap[guildID]
+	if !ok {
+		s.Unlock()
 		return errors.New(discordgo.ErrStateNotFound.Error() + ": RoleDelete (" + guildID + ")")
 	}
 
-	if s.guildMap[guildID].Roles == nil {
-		s.guildMap[guildID].Roles = make([]*discordgo.Role, 0)
-	}
-
-	for j, oldRole := range s.guildMap[guildID].Roles {
-		if oldRole.ID == roleID {
-			// remove role
-			//fmt.Println("removed role")
-			s.guildMap[guildID].Roles = append(s.guildMap[guildID].Roles[:j], s.guildMap[guildID].Roles[j+1:]...)
+	roles := make([]*discordgo.Role, 0, len(guild.Roles))
+	for _, oldRole := range guild.Roles {
+		if oldRole.ID != roleID {
+			roles = append(roles, oldRole)
 		}
 	}
 
+	guild.Roles = roles
+	sharedGuild := withoutMembers(guild)
+	s.Unlock()
+
+	s.share(func(store Store) error {
+		return store.SetGuild(sharedGuild)
+	})
+
 	return nil
 }
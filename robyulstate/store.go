@@ -0,0 +1,79 @@
+package robyulstate
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+// Store shares the guilds tracked by a Robyulstate with other processes
+// Every process writes the guilds of its own shards, and can read the guilds of all processes
+type Store interface {
+	// Guild returns the guild including its members
+	// Returns discordgo.ErrStateNotFound if the guild is not stored
+	Guild(guildID string) (*discordgo.Guild, error)
+
+	// Guilds returns all stored guilds including their members
+	Guilds() ([]*discordgo.Guild, error)
+
+	// GuildsWithoutMembers returns all stored guilds, the members are not loaded
+	GuildsWithoutMembers() ([]*discordgo.Guild, error)
+
+	// GuildCount returns the amount of stored guilds
+	GuildCount() (int, error)
+
+	// SetGuild stores the guild, the members of the guild are only replaced if guild.Members is not nil
+	SetGuild(guild *discordgo.Guild) error
+
+	// RemoveGuild removes the guild and its members
+	RemoveGuild(guildID string) error
+
+	// Refresh keeps the guilds of this process, guilds which are not refreshed expire, for example if their process stopped
+	Refresh(guildIDs []string) error
+
+	// ChannelGuildID returns the ID of the guild of the channel
+	// Returns discordgo.ErrStateNotFound if the channel is not stored
+	ChannelGuildID(channelID string) (string, error)
+
+	// Member returns the member of the guild
+	// Returns discordgo.ErrStateNotFound if the member is not stored
+	Member(guildID, userID string) (*discordgo.Member, error)
+
+	// SetMembers stores the members of the guild, other members of the guild are kept
+	SetMembers(guildID string, members []*discordgo.Member) error
+
+	// RemoveMember removes the member of the guild
+	RemoveMember(guildID, userID string) error
+}
+
+// MemberCaching configures which members are cached
+type MemberCaching int
+
+const (
+	// MemberCachingAll caches all members, including the member lists received when joining guilds and member chunks
+	MemberCachingAll MemberCaching = iota
+
+	// MemberCachingActive caches only members which were active since the start, for example by sending messages
+	MemberCachingActive
+
+	// MemberCachingNone caches no members except the bot itself
+	MemberCachingNone
+)
+
+var memberCachingNames = map[string]MemberCaching{
+	"all":    MemberCachingAll,
+	"active": MemberCachingActive,
+	"none":   MemberCachingNone,
+}
+
+// ParseMemberCaching parses all, active or none, all if empty
+func ParseMemberCaching(text string) (MemberCaching, error) {
+	if text == "" {
+		return MemberCachingAll, nil
+	}
+
+	memberCaching, ok := memberCachingNames[text]
+	if !ok {
+		return MemberCachingAll, errors.Errorf("unknown member caching %q, expected all, active or none", text)
+	}
+	return memberCaching, nil
+}
@@ -0,0 +1,77 @@
+package charts
+
+import (
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// billboardPage is the page of a Billboard chart, %s is the slug of the chart
+const billboardPage = "https://www.billboard.com/charts/%s/"
+
+// billboard is a provider for the weekly Billboard charts, all Billboard charts use the same page layout
+type billboard struct {
+	name  string
+	title string
+	// slugs are the Billboard names of the charts
+	slugs map[ChartType]string
+}
+
+func (p *billboard) Name() string    { return p.name }
+func (p *billboard) Title() string   { return p.title }
+func (p *billboard) Website() string { return "billboard.com" }
+func (p *billboard) Color() int      { return 0x000000 }
+
+func (p *billboard) Charts() []ChartType {
+	var chartTypes []ChartType
+	for _, chartType := range []ChartType{ChartTypeWeekly, ChartTypeAlbumsWeekly} {
+		if _, ok := p.slugs[chartType]; ok {
+			chartTypes = append(chartTypes, chartType)
+		}
+	}
+	return chartTypes
+}
+
+func (p *billboard) URL(chartType ChartType) string {
+	return fmt.Sprintf(billboardPage, p.slugs[chartType])
+}
+
+func (p *billboard) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	doc, err := fetchDocument(p.URL(chartType))
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	return complete(parseBillboard(doc), chartType, limit)
+}
+
+// parseBillboard parses a Billboard chart page, the title is the song, or the album on album charts
+func parseBillboard(doc *goquery.Document) (ranking Ranking) {
+	ranking.Period, _ = doc.Find("#chart-date-picker").Attr("data-date")
+
+	doc.Find("div.o-chart-results-list-row-container").Each(func(_ int, row *goquery.Selection) {
+		var entry Entry
+
+		entry.Rank, _ = parseNumber(row.Find("ul.o-chart-results-list-row > li").First().Find("span").First().Text())
+
+		title := row.Find("h3#title-of-a-story").First()
+		entry.Title = title.Text()
+		entry.Artist = title.NextFiltered("span").Text()
+
+		// the columns after the title are last week, peak, and weeks on chart, new entries have no last week
+		entry.PastRank = entry.Rank
+		if lastWeek, ok := parseNumber(title.Parent().Next().Find("span").First().Text()); ok {
+			entry.PastRank = lastWeek
+		} else {
+			entry.IsNew = true
+		}
+
+		ranking.Entries = append(ranking.Entries, entry)
+	})
+
+	return ranking
+}
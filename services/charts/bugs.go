@@ -0,0 +1,87 @@
+package charts
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var bugsPages = map[ChartType]string{
+	ChartTypeRealtime: "https://music.bugs.co.kr/chart/track/realtime/total",
+	ChartTypeDaily:    "https://music.bugs.co.kr/chart/track/day/total",
+	ChartTypeWeekly:   "https://music.bugs.co.kr/chart/track/week/total",
+}
+
+type bugs struct{}
+
+func (p *bugs) Name() string    { return "bugs" }
+func (p *bugs) Title() string   { return "Bugs" }
+func (p *bugs) Website() string { return "bugs.co.kr" }
+func (p *bugs) Color() int      { return 0xFF3B28 }
+
+func (p *bugs) Charts() []ChartType {
+	return []ChartType{ChartTypeRealtime, ChartTypeDaily, ChartTypeWeekly}
+}
+
+func (p *bugs) URL(chartType ChartType) string {
+	return bugsPages[chartType]
+}
+
+func (p *bugs) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	doc, err := fetchDocument(bugsPages[chartType])
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	ranking, err := parseBugs(doc)
+	if err != nil {
+		return Ranking{}, err
+	}
+	return complete(ranking, chartType, limit)
+}
+
+// parseBugs parses a Bugs chart page
+func parseBugs(doc *goquery.Document) (ranking Ranking, err error) {
+	rows := doc.Find("table.byChart > tbody > tr")
+	if rows.Length() <= 0 && strings.Contains(doc.Text(), "서비스 점검") {
+		return ranking, ErrMaintenance
+	}
+
+	// the period is shown as date and hour, like 2018.12.24 <em>14:00</em>
+	ranking.Period = strings.Join(strings.Fields(doc.Find("header.pgTitle time").First().Text()), " ")
+
+	rows.Each(func(_ int, row *goquery.Selection) {
+		var entry Entry
+
+		entry.Rank, _ = parseNumber(row.Find("div.ranking > strong").Text())
+		entry.PastRank = entry.Rank
+		change := row.Find("div.ranking > p.change")
+		if difference, ok := parseNumber(change.Find("em").Text()); ok {
+			if change.HasClass("up") {
+				entry.PastRank = entry.Rank + difference
+			}
+			if change.HasClass("down") {
+				entry.PastRank = entry.Rank - difference
+			}
+		}
+		if change.HasClass("new") {
+			entry.IsNew = true
+		}
+
+		entry.Title = row.Find("p.title > a").First().Text()
+		var artists []string
+		row.Find("p.artist > a").Not(".more").Each(func(_ int, artist *goquery.Selection) {
+			artists = append(artists, strings.TrimSpace(artist.Text()))
+		})
+		entry.Artist = strings.Join(artists, ", ")
+		entry.Album = row.Find("a.album").First().Text()
+
+		ranking.Entries = append(ranking.Entries, entry)
+	})
+
+	return ranking, nil
+}
@@ -0,0 +1,243 @@
+package charts
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ChartType is a ranking offered by a provider
+type ChartType string
+
+const (
+	ChartTypeRealtime      ChartType = "realtime"
+	ChartTypeDaily         ChartType = "daily"
+	ChartTypeWeekly        ChartType = "weekly"
+	ChartTypeAlbumsWeekly  ChartType = "albums-weekly"
+	ChartTypeAlbumsMonthly ChartType = "albums-monthly"
+	ChartTypeAlbumsYearly  ChartType = "albums-yearly"
+)
+
+var chartTypeTitles = map[ChartType]string{
+	ChartTypeRealtime:      "Realtime",
+	ChartTypeDaily:         "Daily",
+	ChartTypeWeekly:        "Weekly",
+	ChartTypeAlbumsWeekly:  "Weekly Albums",
+	ChartTypeAlbumsMonthly: "Monthly Albums",
+	ChartTypeAlbumsYearly:  "Yearly Albums",
+}
+
+// Title returns the display name of the chart type, for example Weekly Albums
+func (t ChartType) Title() string {
+	return chartTypeTitles[t]
+}
+
+// Albums returns true if the chart ranks albums instead of songs
+func (t ChartType) Albums() bool {
+	return strings.HasPrefix(string(t), "albums-")
+}
+
+var (
+	// ErrUnsupportedChart is returned if a provider does not offer the chart type
+	ErrUnsupportedChart = errors.New("the provider does not offer this chart")
+	// ErrMaintenance is returned while the chart is in maintenance
+	ErrMaintenance = errors.New("the chart is in maintenance")
+	// ErrOverloaded is returned while the chart receives too many requests
+	ErrOverloaded = errors.New("the chart is overloaded")
+	// ErrNoRanking is returned if the chart page contains no ranking, usually because the page changed
+	ErrNoRanking = errors.New("the chart page contains no ranking")
+)
+
+// IsUnavailable returns true if the chart is temporarily unavailable and should be requested again later
+func IsUnavailable(err error) bool {
+	return err == ErrMaintenance || err == ErrOverloaded
+}
+
+// Entry is a song, or an album on album charts
+type Entry struct {
+	Rank int
+	// PastRank is the rank of the previous period, it is the same as Rank if the entry is new or did not move
+	PastRank      int
+	IsNew         bool
+	Title         string
+	Artist        string
+	Album         string
+	MusicVideoURL string
+}
+
+// Ranking is the ranking of a chart for one period
+type Ranking struct {
+	// Period is the time, or the time range, of the ranking as shown by the provider
+	Period  string
+	Entries []Entry
+}
+
+// ChartProvider is a website with music charts
+type ChartProvider interface {
+	// Name identifies the provider in commands and stored snapshots, for example melon
+	Name() string
+	// Title is the display name of the provider, for example Melon
+	Title() string
+	// Website is shown as source of the charts
+	Website() string
+	// Color is the color of the provider in embeds
+	Color() int
+	// Charts returns the chart types the provider offers, the first one is the default
+	Charts() []ChartType
+	// URL returns the page of the chart for humans
+	URL(chartType ChartType) string
+	// Fetch returns the current ranking of the chart with up to limit entries
+	// It returns ErrMaintenance or ErrOverloaded while the chart is unavailable
+	Fetch(chartType ChartType, limit int) (Ranking, error)
+}
+
+var providers = []ChartProvider{
+	&melon{},
+	&ichart{},
+	&genie{},
+	&bugs{},
+	&flo{},
+	&gaon{},
+	&billboard{
+		name:  "billboard",
+		title: "Billboard",
+		slugs: map[ChartType]string{
+			ChartTypeWeekly:       "hot-100",
+			ChartTypeAlbumsWeekly: "billboard-200",
+		},
+	},
+	&billboard{
+		name:  "billboard-global",
+		title: "Billboard Global",
+		slugs: map[ChartType]string{
+			ChartTypeWeekly: "billboard-global-200",
+		},
+	},
+}
+
+// Providers returns all chart providers
+func Providers() []ChartProvider {
+	return providers
+}
+
+// GetProvider returns the provider with the name
+func GetProvider(name string) (ChartProvider, bool) {
+	name = strings.ToLower(name)
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider, true
+		}
+	}
+	return nil, false
+}
+
+// Supports returns true if the provider offers the chart type
+func Supports(provider ChartProvider, chartType ChartType) bool {
+	for _, providerChart := range provider.Charts() {
+		if providerChart == chartType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseChartType returns the chart type of command arguments like realtime, week, or monthly albums
+// The default chart of the provider is returned without arguments, periods without a song chart fall back to the album chart
+func ParseChartType(provider ChartProvider, args []string) (ChartType, bool) {
+	if len(args) <= 0 {
+		return provider.Charts()[0], true
+	}
+
+	var period string
+	var albums bool
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "album", "albums":
+			albums = true
+		case "realtime", "rt", "live":
+			period = "realtime"
+		case "daily", "day":
+			period = "daily"
+		case "weekly", "week":
+			period = "weekly"
+		case "monthly", "month":
+			period = "monthly"
+		case "yearly", "year":
+			period = "yearly"
+		default:
+			return "", false
+		}
+	}
+
+	if period == "" {
+		if !albums {
+			return "", false
+		}
+		// the first album chart of the provider
+		for _, chartType := range provider.Charts() {
+			if chartType.Albums() {
+				return chartType, true
+			}
+		}
+		return "", false
+	}
+
+	songChart, albumChart := ChartType(period), ChartType("albums-"+period)
+	if !albums && Supports(provider, songChart) {
+		return songChart, true
+	}
+	if Supports(provider, albumChart) {
+		return albumChart, true
+	}
+	return "", false
+}
+
+// complete prepares a parsed ranking, it removes empty entries, applies the limit, and uses the title as album on album charts
+func complete(ranking Ranking, chartType ChartType, limit int) (Ranking, error) {
+	entries := make([]Entry, 0, len(ranking.Entries))
+	for _, entry := range ranking.Entries {
+		entry.Title = strings.TrimSpace(entry.Title)
+		entry.Artist = strings.TrimSpace(entry.Artist)
+		entry.Album = strings.TrimSpace(entry.Album)
+
+		if chartType.Albums() {
+			if entry.Album == "" {
+				entry.Album = entry.Title
+			}
+			entry.Title = ""
+			if entry.Album == "" {
+				continue
+			}
+		} else if entry.Title == "" {
+			continue
+		}
+
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) >= limit {
+			break
+		}
+	}
+
+	if strings.TrimSpace(ranking.Period) == "" || len(entries) <= 0 {
+		return Ranking{}, ErrNoRanking
+	}
+	ranking.Period = strings.TrimSpace(ranking.Period)
+	ranking.Entries = entries
+	return ranking, nil
+}
+
+// parseNumber returns the first number in the text, like 12 in "12상승"
+func parseNumber(text string) (int, bool) {
+	start := strings.IndexFunc(text, unicode.IsDigit)
+	if start < 0 {
+		return 0, false
+	}
+	end := strings.IndexFunc(text[start:], func(r rune) bool { return !unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(text) - start
+	}
+
+	number, err := strconv.Atoi(text[start : start+end])
+	return number, err == nil
+}
@@ -0,0 +1,276 @@
+package charts
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func loadFixture(t *testing.T, name string) *goquery.Document {
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func loadFloFixture(t *testing.T, name string) (chart floChart) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = json.Unmarshal(data, &chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chart
+}
+
+func TestParseMelon(t *testing.T) {
+	ranking, err := complete(parseMelon(loadFixture(t, "melon_realtime.html"), true), ChartTypeRealtime, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018.12.24 14:00" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 12 {
+		t.Fatal("expected 12 songs, got", len(entries))
+	}
+	if entries[0].Title != "밤편지" || entries[0].Artist != "아이유" || entries[0].Album != "팔레트" ||
+		entries[0].Rank != 1 || entries[0].PastRank != 1 {
+		t.Fatal("unexpected first place", entries[0])
+	}
+	if entries[1].PastRank != 5 || entries[2].PastRank != 2 || !entries[3].IsNew {
+		t.Fatal("unexpected rank changes", entries[1], entries[2], entries[3])
+	}
+	if entries[4].Artist != "아이유, SUGA" {
+		t.Fatal("expected all artists of a collaboration, got", entries[4].Artist)
+	}
+
+	ranking, err = complete(parseMelon(loadFixture(t, "melon_realtime.html"), false), ChartTypeDaily, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranking.Entries) != 10 || ranking.Period != "2018.12.24" {
+		t.Fatal("expected the limit to be applied without hour, got", len(ranking.Entries), "songs for", ranking.Period)
+	}
+}
+
+func TestParseIChart(t *testing.T) {
+	ranking, err := parseIChart(loadFixture(t, "ichart_realtime.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranking, err = complete(ranking, ChartTypeRealtime, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018.12.24 14:00" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 10 {
+		t.Fatal("expected 10 songs, got", len(entries))
+	}
+	if entries[0].Title != "Blueming" || entries[0].Artist != "아이유" || entries[0].PastRank != 3 ||
+		entries[0].MusicVideoURL != "https://youtu.be/abc123" {
+		t.Fatal("unexpected first place", entries[0])
+	}
+	if entries[1].Title != "FANCY" || entries[1].Rank != 2 || entries[1].PastRank != 1 ||
+		entries[1].MusicVideoURL != "https://youtu.be/def456" {
+		t.Fatal("unexpected second place", entries[1])
+	}
+	if !entries[2].IsNew || entries[3].PastRank != 4 {
+		t.Fatal("unexpected rank changes", entries[2], entries[3])
+	}
+
+	_, err = parseIChart(loadFixture(t, "ichart_maintenance.html"))
+	if err != ErrMaintenance || !IsUnavailable(err) {
+		t.Fatal("expected ErrMaintenance, got", err)
+	}
+}
+
+func TestParseGaon(t *testing.T) {
+	ranking, err := complete(parseGaon(loadFixture(t, "gaon_weekly.html")), ChartTypeAlbumsWeekly, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018.12.16~2018.12.22" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 5 {
+		t.Fatal("expected 5 albums, got", len(entries))
+	}
+	if entries[1].Album != "Love poem" || entries[1].Artist != "아이유" || entries[1].Title != "" || entries[1].PastRank != 4 {
+		t.Fatal("unexpected second place", entries[1])
+	}
+	if entries[2].PastRank != 2 || !entries[3].IsNew || entries[0].PastRank != 1 {
+		t.Fatal("unexpected rank changes", entries[0], entries[2], entries[3])
+	}
+}
+
+func TestParseGenie(t *testing.T) {
+	ranking, err := parseGenie(loadFixture(t, "genie_realtime.html"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranking, err = complete(ranking, ChartTypeRealtime, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018.12.24 14:00" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 4 {
+		t.Fatal("expected 4 songs, got", len(entries))
+	}
+	if entries[1].Title != "Blueming" || entries[1].Artist != "아이유" || entries[1].Album != "Love poem" ||
+		entries[1].Rank != 2 || entries[1].PastRank != 5 {
+		t.Fatal("unexpected second place", entries[1])
+	}
+	if entries[0].PastRank != 1 || entries[2].PastRank != 2 || !entries[3].IsNew {
+		t.Fatal("unexpected rank changes", entries[0], entries[2], entries[3])
+	}
+}
+
+func TestParseBugs(t *testing.T) {
+	ranking, err := parseBugs(loadFixture(t, "bugs_realtime.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranking, err = complete(ranking, ChartTypeRealtime, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018.12.24 14:00" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 4 {
+		t.Fatal("expected 4 songs, got", len(entries))
+	}
+	if entries[1].Title != "에잇(Prod.&Feat. SUGA of BTS)" || entries[1].Artist != "아이유(IU), SUGA" ||
+		entries[1].Album != "에잇" || entries[1].PastRank != 5 {
+		t.Fatal("unexpected second place", entries[1])
+	}
+	if entries[0].PastRank != 1 || entries[2].PastRank != 2 || !entries[3].IsNew {
+		t.Fatal("unexpected rank changes", entries[0], entries[2], entries[3])
+	}
+}
+
+func TestParseFlo(t *testing.T) {
+	ranking, err := parseFlo(loadFloFixture(t, "flo_chart.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranking, err = complete(ranking, ChartTypeRealtime, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018.12.24 14:00" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 4 {
+		t.Fatal("expected 4 songs, got", len(entries))
+	}
+	if entries[1].Artist != "아이유, SUGA" || entries[1].Album != "에잇" || entries[1].PastRank != 5 {
+		t.Fatal("unexpected second place", entries[1])
+	}
+	if entries[0].PastRank != 1 || entries[2].PastRank != 2 || !entries[3].IsNew || entries[3].PastRank != 4 {
+		t.Fatal("unexpected rank changes", entries[0], entries[2], entries[3])
+	}
+
+	_, err = parseFlo(loadFloFixture(t, "flo_maintenance.json"))
+	if err != ErrMaintenance {
+		t.Fatal("expected ErrMaintenance, got", err)
+	}
+}
+
+func TestParseBillboard(t *testing.T) {
+	ranking, err := complete(parseBillboard(loadFixture(t, "billboard_hot100.html")), ChartTypeWeekly, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Period != "2018-12-29" {
+		t.Fatal("unexpected period", ranking.Period)
+	}
+	entries := ranking.Entries
+	if len(entries) != 3 {
+		t.Fatal("expected 3 songs, got", len(entries))
+	}
+	if entries[1].Title != "All I Want For Christmas Is You" || entries[1].Artist != "Mariah Carey" ||
+		entries[1].Rank != 2 || entries[1].PastRank != 9 {
+		t.Fatal("unexpected second place", entries[1])
+	}
+	if entries[0].PastRank != 1 || !entries[2].IsNew || entries[2].PastRank != 3 {
+		t.Fatal("unexpected rank changes", entries[0], entries[2])
+	}
+
+	// album charts use the same page layout
+	ranking, err = complete(parseBillboard(loadFixture(t, "billboard_hot100.html")), ChartTypeAlbumsWeekly, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Entries[0].Album != "Thank U, Next" || ranking.Entries[0].Title != "" {
+		t.Fatal("expected the title to be the album, got", ranking.Entries[0])
+	}
+}
+
+func TestComplete(t *testing.T) {
+	_, err := complete(Ranking{Period: "2018.12.24"}, ChartTypeDaily, 10)
+	if err != ErrNoRanking {
+		t.Fatal("expected ErrNoRanking without entries, got", err)
+	}
+	_, err = complete(Ranking{Entries: []Entry{{Rank: 1, Title: "Psycho"}}}, ChartTypeDaily, 10)
+	if err != ErrNoRanking {
+		t.Fatal("expected ErrNoRanking without period, got", err)
+	}
+}
+
+func TestParseChartType(t *testing.T) {
+	melon, _ := GetProvider("melon")
+	gaon, _ := GetProvider("gaon")
+	billboard, _ := GetProvider("Billboard")
+
+	tests := []struct {
+		provider  ChartProvider
+		args      []string
+		chartType ChartType
+		ok        bool
+	}{
+		{provider: melon, chartType: ChartTypeRealtime, ok: true},
+		{provider: melon, args: []string{"day"}, chartType: ChartTypeDaily, ok: true},
+		{provider: melon, args: []string{"monthly"}},
+		{provider: melon, args: []string{"albums"}},
+		{provider: melon, args: []string{"unknown"}},
+		{provider: gaon, chartType: ChartTypeAlbumsWeekly, ok: true},
+		{provider: gaon, args: []string{"week"}, chartType: ChartTypeAlbumsWeekly, ok: true},
+		{provider: gaon, args: []string{"monthly", "albums"}, chartType: ChartTypeAlbumsMonthly, ok: true},
+		{provider: billboard, args: []string{"weekly"}, chartType: ChartTypeWeekly, ok: true},
+		{provider: billboard, args: []string{"albums"}, chartType: ChartTypeAlbumsWeekly, ok: true},
+		{provider: billboard, args: []string{"album", "week"}, chartType: ChartTypeAlbumsWeekly, ok: true},
+	}
+
+	for _, test := range tests {
+		chartType, ok := ParseChartType(test.provider, test.args)
+		if ok != test.ok || chartType != test.chartType {
+			t.Fatal(test.provider.Name(), test.args, ": unexpected chart type", chartType, ok)
+		}
+	}
+}
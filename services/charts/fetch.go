@@ -0,0 +1,60 @@
+package charts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Seklfreak/Robyul2/helpers"
+)
+
+// get requests a chart page, the caller has to close the body
+// Responses which mean that the chart is unavailable are returned as ErrMaintenance and ErrOverloaded
+func get(url string) (*http.Response, error) {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", helpers.DEFAULT_UA)
+
+	response, err := helpers.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response, nil
+	case http.StatusServiceUnavailable:
+		response.Body.Close()
+		return nil, ErrMaintenance
+	case http.StatusTooManyRequests:
+		response.Body.Close()
+		return nil, ErrOverloaded
+	}
+	response.Body.Close()
+	return nil, fmt.Errorf("unexpected status %d", response.StatusCode)
+}
+
+// fetchDocument requests and parses a chart page
+func fetchDocument(url string) (*goquery.Document, error) {
+	response, err := get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return goquery.NewDocumentFromReader(response.Body)
+}
+
+// fetchJSON requests a chart API and decodes the response into target
+func fetchJSON(url string, target interface{}) error {
+	response, err := get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return json.NewDecoder(response.Body).Decode(target)
+}
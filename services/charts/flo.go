@@ -0,0 +1,104 @@
+package charts
+
+import (
+	"strings"
+)
+
+const (
+	floEndpoint     = "https://www.music-flo.com/api/display/v1/browser/chart/1/track/list?size=100"
+	floFriendlyPage = "https://www.music-flo.com/browse"
+	// floCodeSuccess is the code of successful API responses
+	floCodeSuccess = "2000000"
+)
+
+// floChart is the response of the FLO chart API
+type floChart struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Name          string `json:"name"`
+		BasedOnUpdate string `json:"basedOnUpdate"`
+		TrackList     []struct {
+			Name string `json:"name"`
+			Rank struct {
+				NewYn string `json:"newYn"`
+				// RankBadge is the change since the last update, positive if the song moved up
+				RankBadge int `json:"rankBadge"`
+			} `json:"rank"`
+			ArtistList []struct {
+				Name string `json:"name"`
+			} `json:"artistList"`
+			Album struct {
+				Title string `json:"title"`
+			} `json:"album"`
+		} `json:"trackList"`
+	} `json:"data"`
+}
+
+type flo struct{}
+
+func (p *flo) Name() string    { return "flo" }
+func (p *flo) Title() string   { return "FLO" }
+func (p *flo) Website() string { return "music-flo.com" }
+func (p *flo) Color() int      { return 0x3F3FFF }
+
+// Charts returns the FLO chart, it ranks the last 24 hours and is updated every hour
+func (p *flo) Charts() []ChartType {
+	return []ChartType{ChartTypeRealtime}
+}
+
+func (p *flo) URL(chartType ChartType) string {
+	return floFriendlyPage
+}
+
+func (p *flo) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	var chart floChart
+	err := fetchJSON(floEndpoint, &chart)
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	ranking, err := parseFlo(chart)
+	if err != nil {
+		return Ranking{}, err
+	}
+	return complete(ranking, chartType, limit)
+}
+
+// parseFlo converts a response of the FLO chart API, the API reports maintenance with its own code
+func parseFlo(chart floChart) (ranking Ranking, err error) {
+	if chart.Code != floCodeSuccess {
+		if strings.Contains(chart.Message, "점검") {
+			return ranking, ErrMaintenance
+		}
+		return ranking, ErrNoRanking
+	}
+
+	ranking.Period = chart.Data.BasedOnUpdate
+	for i, track := range chart.Data.TrackList {
+		entry := Entry{
+			Rank:  i + 1,
+			Title: track.Name,
+			Album: track.Album.Title,
+			IsNew: track.Rank.NewYn == "Y",
+		}
+		entry.PastRank = entry.Rank
+		if !entry.IsNew {
+			entry.PastRank = entry.Rank + track.Rank.RankBadge
+		}
+
+		var artists []string
+		for _, artist := range track.ArtistList {
+			artists = append(artists, strings.TrimSpace(artist.Name))
+		}
+		entry.Artist = strings.Join(artists, ", ")
+
+		ranking.Entries = append(ranking.Entries, entry)
+	}
+
+	return ranking, nil
+}
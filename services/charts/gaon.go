@@ -0,0 +1,79 @@
+package charts
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var gaonPages = map[ChartType]string{
+	ChartTypeAlbumsWeekly:  "http://gaonchart.co.kr/main/section/chart/album.gaon?nationGbn=T&serviceGbn=&termGbn=week",
+	ChartTypeAlbumsMonthly: "http://gaonchart.co.kr/main/section/chart/album.gaon?nationGbn=T&serviceGbn=&termGbn=month",
+	ChartTypeAlbumsYearly:  "http://gaonchart.co.kr/main/section/chart/album.gaon?nationGbn=T&serviceGbn=&termGbn=year",
+}
+
+type gaon struct{}
+
+func (p *gaon) Name() string    { return "gaon" }
+func (p *gaon) Title() string   { return "Gaon" }
+func (p *gaon) Website() string { return "gaonchart.co.kr" }
+func (p *gaon) Color() int      { return 0x000000 }
+
+func (p *gaon) Charts() []ChartType {
+	return []ChartType{ChartTypeAlbumsWeekly, ChartTypeAlbumsMonthly, ChartTypeAlbumsYearly}
+}
+
+func (p *gaon) URL(chartType ChartType) string {
+	return gaonPages[chartType]
+}
+
+func (p *gaon) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	doc, err := fetchDocument(gaonPages[chartType])
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	return complete(parseGaon(doc), chartType, limit)
+}
+
+// parseGaon parses a Gaon album chart page, the charts contain domestic and overseas albums
+func parseGaon(doc *goquery.Document) (ranking Ranking) {
+	ranking.Period = strings.Replace(doc.Find("#wrap > div.now > div.fl").Text(), "Album Chart", "", -1)
+
+	// the first row is the header
+	doc.Find("#wrap > div.chart > table > tbody > tr").Each(func(i int, row *goquery.Selection) {
+		if i == 0 {
+			return
+		}
+
+		entry := Entry{
+			Rank:   i,
+			Album:  row.Find("td.subject > p:nth-child(1)").Text(),
+			Artist: row.Find("td.subject > p.singer").Text(),
+		}
+		entry.PastRank = entry.Rank
+
+		if up := row.Find("td.change > .up"); up.Length() > 0 {
+			if difference, err := strconv.Atoi(strings.TrimSpace(up.Text())); err == nil {
+				entry.PastRank = entry.Rank + difference
+			}
+		}
+		if down := row.Find("td.change > .down"); down.Length() > 0 {
+			if difference, err := strconv.Atoi(strings.TrimSpace(down.Text())); err == nil {
+				entry.PastRank = entry.Rank - difference
+			}
+		}
+		if row.Find("td.change > .new").Length() > 0 {
+			entry.IsNew = true
+		}
+
+		ranking.Entries = append(ranking.Entries, entry)
+	})
+
+	return ranking
+}
@@ -0,0 +1,109 @@
+package charts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// geniePage is the chart page, %s is the period, %s if the chart is the realtime chart, and %d the page
+	geniePage = "https://www.genie.co.kr/chart/top200?ditc=%s&rtm=%s&pg=%d"
+	// geniePageSize is the number of songs on every page
+	geniePageSize = 50
+)
+
+type genie struct{}
+
+func (p *genie) Name() string    { return "genie" }
+func (p *genie) Title() string   { return "Genie" }
+func (p *genie) Website() string { return "genie.co.kr" }
+func (p *genie) Color() int      { return 0x4AA5E8 }
+
+func (p *genie) Charts() []ChartType {
+	return []ChartType{ChartTypeRealtime, ChartTypeDaily, ChartTypeWeekly}
+}
+
+func (p *genie) URL(chartType ChartType) string {
+	return p.page(chartType, 1)
+}
+
+func (p *genie) page(chartType ChartType, page int) string {
+	switch chartType {
+	case ChartTypeDaily:
+		return fmt.Sprintf(geniePage, "D", "N", page)
+	case ChartTypeWeekly:
+		return fmt.Sprintf(geniePage, "W", "N", page)
+	}
+	return fmt.Sprintf(geniePage, "D", "Y", page)
+}
+
+func (p *genie) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	var ranking Ranking
+	for page := 1; page == 1 || (page-1)*geniePageSize < limit; page++ {
+		doc, err := fetchDocument(p.page(chartType, page))
+		if err != nil {
+			return Ranking{}, err
+		}
+
+		pageRanking, err := parseGenie(doc, chartType == ChartTypeRealtime)
+		if err != nil {
+			return Ranking{}, err
+		}
+		if ranking.Period == "" {
+			ranking.Period = pageRanking.Period
+		}
+		ranking.Entries = append(ranking.Entries, pageRanking.Entries...)
+		if len(pageRanking.Entries) < geniePageSize {
+			break
+		}
+	}
+
+	return complete(ranking, chartType, limit)
+}
+
+// parseGenie parses a page of a Genie chart, withHour adds the hour to the period of realtime charts
+func parseGenie(doc *goquery.Document, withHour bool) (ranking Ranking, err error) {
+	if doc.Find("tr.list").Length() <= 0 && strings.Contains(doc.Text(), "서비스 점검") {
+		return ranking, ErrMaintenance
+	}
+
+	ranking.Period = doc.Find(".time-select-wrap .date").First().Text()
+	if withHour {
+		ranking.Period = strings.TrimSpace(ranking.Period) + " " + strings.TrimSpace(doc.Find(".time-select-wrap .hour").First().Text())
+	}
+
+	doc.Find("table.list-wrap > tbody > tr.list").Each(func(_ int, row *goquery.Selection) {
+		var entry Entry
+
+		// the number cell contains the rank followed by the change
+		number := row.Find("td.number")
+		entry.Rank, _ = parseNumber(strings.TrimSpace(number.Contents().First().Text()))
+		entry.PastRank = entry.Rank
+		if difference, ok := parseNumber(number.Find("span.rank-up").Text()); ok {
+			entry.PastRank = entry.Rank + difference
+		}
+		if difference, ok := parseNumber(number.Find("span.rank-down").Text()); ok {
+			entry.PastRank = entry.Rank - difference
+		}
+		if number.Find("span.rank-new").Length() > 0 {
+			entry.IsNew = true
+		}
+
+		// titles contain icons like 19금 for explicit songs
+		title := row.Find("a.title").First().Clone()
+		title.Find("span").Remove()
+		entry.Title = title.Text()
+		entry.Artist = row.Find("a.artist").First().Text()
+		entry.Album = row.Find("a.albumtitle").First().Text()
+
+		ranking.Entries = append(ranking.Entries, entry)
+	})
+
+	return ranking, nil
+}
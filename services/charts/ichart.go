@@ -0,0 +1,136 @@
+package charts
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	ichartPages = map[ChartType]string{
+		ChartTypeRealtime: "http://www.instiz.net/iframe_ichart_score.htm?real=1",
+		ChartTypeWeekly:   "http://www.instiz.net/iframe_ichart_score.htm?week=1",
+	}
+	ichartFriendlyPage = "http://www.instiz.net/bbs/list.php?id=spage&no=8"
+)
+
+type ichart struct{}
+
+func (p *ichart) Name() string    { return "ichart" }
+func (p *ichart) Title() string   { return "iChart" }
+func (p *ichart) Website() string { return "instiz.net" }
+func (p *ichart) Color() int      { return 0x1FC679 }
+
+func (p *ichart) Charts() []ChartType {
+	return []ChartType{ChartTypeRealtime, ChartTypeWeekly}
+}
+
+func (p *ichart) URL(chartType ChartType) string {
+	return ichartFriendlyPage
+}
+
+func (p *ichart) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	doc, err := fetchDocument(ichartPages[chartType])
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	ranking, err := parseIChart(doc)
+	if err != nil {
+		return Ranking{}, err
+	}
+	return complete(ranking, chartType, limit)
+}
+
+// parseIChart parses an iChart page, the first place is shown separately from the other places
+func parseIChart(doc *goquery.Document) (ranking Ranking, err error) {
+	text := doc.Text()
+	if strings.Contains(text, "서버 점검으로 인해 현재 서비스가 일시 중단되었습니다") {
+		return ranking, ErrMaintenance
+	}
+	if strings.Contains(text, "사이트 이용자가 많습니다") {
+		return ranking, ErrOverloaded
+	}
+
+	ranking.Period = strings.Replace(doc.Find("#content > div.ichart_score_title > div.ichart_score_title_right.minitext3").Text(), "기준", "", -1)
+
+	first := doc.Find("#score_1st")
+	entry := Entry{
+		Rank:   1,
+		Title:  first.Find("div.ichart_score_song > div.ichart_score_song1 > b").Text(),
+		Artist: first.Find("div.ichart_score_artist > div.ichart_score_artist1 > b").Text(),
+		Album:  first.Find("div.ichart_score_song > div.ichart_score_song2 > span > a").Text(),
+	}
+	if musicVideoURL, ok := doc.Find("#yttop").Attr("href"); ok {
+		entry.MusicVideoURL = ichartMusicVideoURL(musicVideoURL)
+	}
+	entry.PastRank, err = ichartPastRank(entry.Rank, first.Find("div.ichart_score_change.rank"), true)
+	if err != nil {
+		return ranking, err
+	}
+	ranking.Entries = append(ranking.Entries, entry)
+
+	submenus := doc.Find("#content > div.spage_intistore_body > div.ichart_submenu")
+	items := doc.Find("#content > div.spage_intistore_body > div.spage_score_item")
+	for i := 0; i < items.Length(); i++ {
+		item := items.Eq(i)
+		entry := Entry{
+			Rank:   i + 2,
+			Title:  item.Find("div.ichart_score2_song > div.ichart_score2_song1").Text(),
+			Artist: item.Find("div.ichart_score2_artist > div.ichart_score2_artist1").Text(),
+			Album:  item.Find("div.ichart_score2_song > div.ichart_score2_song2 > span > a").Text(),
+		}
+		if musicVideoURL, ok := submenus.Eq(i).Find("ul > li.ichart_mv > a").Attr("href"); ok {
+			entry.MusicVideoURL = ichartMusicVideoURL(musicVideoURL)
+		}
+		change := item.Find("div.ichart_score2_change.rank")
+		entry.PastRank, err = ichartPastRank(entry.Rank, change, false)
+		if err != nil {
+			return ranking, err
+		}
+		if change.Find(".arrow4").Length() > 0 {
+			entry.IsNew = true
+		}
+		ranking.Entries = append(ranking.Entries, entry)
+	}
+
+	return ranking, nil
+}
+
+// ichartPastRank calculates the previous rank from the rank change, .arrow1 is up and .arrow2 is down
+// The change of the first place is in the parent of the arrow, the change of the other places is the text of change
+func ichartPastRank(rank int, change *goquery.Selection, first bool) (int, error) {
+	for arrow, direction := range map[string]int{".arrow1": 1, ".arrow2": -1} {
+		arrowSelection := change.Find(arrow)
+		if arrowSelection.Length() <= 0 {
+			continue
+		}
+
+		changeText := change.Text()
+		if first {
+			changeText = arrowSelection.Parent().Text()
+		}
+		difference, err := strconv.Atoi(strings.TrimSpace(changeText))
+		if err != nil {
+			return 0, err
+		}
+		return rank + direction*difference, nil
+	}
+	return rank, nil
+}
+
+// ichartMusicVideoURL extracts the YouTube URL from javascript:show_youtube('<id>') links
+func ichartMusicVideoURL(href string) string {
+	if strings.Contains(href, "javascript:show_youtube") {
+		parts := strings.Split(href, "'")
+		if len(parts) == 3 {
+			return "https://youtu.be/" + parts[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package charts
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var melonPages = map[ChartType]string{
+	ChartTypeRealtime: "http://www.melon.com/chart/index.htm",
+	ChartTypeDaily:    "http://www.melon.com/chart/day/index.htm",
+	ChartTypeWeekly:   "http://www.melon.com/chart/week/index.htm",
+}
+
+type melon struct{}
+
+func (p *melon) Name() string    { return "melon" }
+func (p *melon) Title() string   { return "Melon" }
+func (p *melon) Website() string { return "melon.com" }
+func (p *melon) Color() int      { return 0x43C85D }
+
+func (p *melon) Charts() []ChartType {
+	return []ChartType{ChartTypeRealtime, ChartTypeDaily, ChartTypeWeekly}
+}
+
+func (p *melon) URL(chartType ChartType) string {
+	return melonPages[chartType]
+}
+
+func (p *melon) Fetch(chartType ChartType, limit int) (Ranking, error) {
+	if !Supports(p, chartType) {
+		return Ranking{}, ErrUnsupportedChart
+	}
+
+	doc, err := fetchDocument(melonPages[chartType])
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	return complete(parseMelon(doc, chartType == ChartTypeRealtime), chartType, limit)
+}
+
+// parseMelon parses a Melon chart page, withHour adds the hour to the period of realtime charts
+func parseMelon(doc *goquery.Document, withHour bool) (ranking Ranking) {
+	ranking.Period = doc.Find(".calendar_prid > .yyyymmdd > .year").Text()
+	if withHour {
+		ranking.Period += " " + doc.Find(".calendar_prid > .hhmm > .hour").Text()
+	}
+
+	doc.Find(".lst50, .lst100").Each(func(_ int, row *goquery.Selection) {
+		var entry Entry
+
+		entry.Title = row.Find(".rank01 > span > a").Text()
+		var artists []string
+		row.Find(".rank02 > a").Each(func(_ int, artist *goquery.Selection) {
+			artists = append(artists, strings.TrimSpace(artist.Text()))
+		})
+		entry.Artist = strings.Join(artists, ", ")
+		entry.Album = row.Find(".rank03 > a").Text()
+		entry.Rank, _ = strconv.Atoi(strings.TrimSpace(row.Find(".rank").Text()))
+		entry.PastRank = entry.Rank
+
+		rankingChange := row.Find("td").Eq(2)
+		if rankingChange.Find(".up").Length() > 0 {
+			rankUpChange, _ := strconv.Atoi(strings.TrimSpace(rankingChange.Find(".up").Text()))
+			entry.PastRank += rankUpChange
+		}
+		if rankingChange.Find(".down").Length() > 0 {
+			rankDownChange, _ := strconv.Atoi(strings.TrimSpace(rankingChange.Find(".down").Text()))
+			entry.PastRank -= rankDownChange
+		}
+		if rankingChange.Find(".new").Length() > 0 {
+			entry.IsNew = true
+		}
+
+		ranking.Entries = append(ranking.Entries, entry)
+	})
+
+	return ranking
+}
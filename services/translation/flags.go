@@ -0,0 +1,64 @@
+package translation
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// regionalIndicatorA is the regional indicator symbol for A, flags are two regional indicators for the country code
+const regionalIndicatorA = 0x1F1E6
+
+// flagLanguages are the languages of the countries, by their lowercase country code
+var flagLanguages = map[string]string{
+	"kr": "ko", "kp": "ko",
+	"us": "en", "gb": "en", "au": "en", "ca": "en", "nz": "en", "ie": "en",
+	"jp": "ja",
+	"cn": "zh-cn", "sg": "zh-cn",
+	"tw": "zh-tw", "hk": "zh-tw", "mo": "zh-tw",
+	"fr": "fr",
+	"de": "de", "at": "de", "ch": "de",
+	"es": "es", "mx": "es", "ar": "es", "co": "es", "cl": "es", "pe": "es", "ve": "es",
+	"it": "it",
+	"pt": "pt", "br": "pt",
+	"ru": "ru",
+	"ua": "uk",
+	"pl": "pl",
+	"nl": "nl", "be": "nl",
+	"se": "sv",
+	"no": "no",
+	"dk": "da",
+	"fi": "fi",
+	"tr": "tr",
+	"gr": "el",
+	"vn": "vi",
+	"th": "th",
+	"id": "id",
+	"my": "ms",
+	"ph": "tl",
+	"in": "hi",
+	"sa": "ar", "ae": "ar", "eg": "ar",
+	"il": "he",
+	"ir": "fa",
+	"cz": "cs",
+	"hu": "hu",
+	"ro": "ro",
+	"bg": "bg",
+}
+
+// LanguageForFlag returns the language of the country of a flag emoji, like ko for 🇰🇷
+func LanguageForFlag(emoji string) (lang string, ok bool) {
+	if utf8.RuneCountInString(emoji) != 2 {
+		return "", false
+	}
+
+	var countryCode strings.Builder
+	for _, r := range emoji {
+		if r < regionalIndicatorA || r > regionalIndicatorA+25 {
+			return "", false
+		}
+		countryCode.WriteRune('a' + r - regionalIndicatorA)
+	}
+
+	lang, ok = flagLanguages[countryCode.String()]
+	return lang, ok
+}
@@ -0,0 +1,80 @@
+package translation
+
+import (
+	"context"
+	"strings"
+
+	"cloud.google.com/go/translate"
+	"golang.org/x/text/language"
+	"google.golang.org/api/option"
+)
+
+// googleChunkSize is the size of the texts sent to Google Translate, longer texts are sent in several parts
+const googleChunkSize = 1000
+
+type google struct {
+	ctx    context.Context
+	client *translate.Client
+}
+
+// NewGoogle returns a provider for Google Translate, it translates between all languages and detects languages
+func NewGoogle(apiKey string) (TranslationProvider, error) {
+	ctx := context.Background()
+
+	client, err := translate.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &google{ctx: ctx, client: client}, nil
+}
+
+func (p *google) Name() string    { return "google" }
+func (p *google) Website() string { return "translate.google.com" }
+
+func (p *google) Supports(source, target string) bool {
+	return target != AutoDetect
+}
+
+func (p *google) Translate(text, source, target string) (translation Translation, err error) {
+	targetTag, err := language.Parse(target)
+	if err != nil {
+		return translation, ErrUnsupportedLanguage
+	}
+	sourceTag := language.Und
+	if source != AutoDetect {
+		sourceTag, err = language.Parse(source)
+		if err != nil {
+			return translation, ErrUnsupportedLanguage
+		}
+	}
+
+	textChunks, separators := chunks(text, googleChunkSize)
+	results, err := p.client.Translate(p.ctx, textChunks, targetTag, &translate.Options{
+		Format: translate.Text,
+		Source: sourceTag,
+		Model:  "nmt",
+	})
+	if err != nil {
+		return translation, err
+	}
+	if len(results) <= 0 {
+		return translation, ErrEmptyTranslation
+	}
+
+	var texts []string
+	for _, result := range results {
+		texts = append(texts, result.Text)
+	}
+
+	translation = Translation{
+		Text:     joinChunks(texts, separators),
+		Source:   source,
+		Target:   target,
+		Provider: p,
+	}
+	if source == AutoDetect {
+		translation.Source = strings.ToLower(results[0].Source.String())
+	}
+	return translation, nil
+}
@@ -0,0 +1,115 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+)
+
+// libreTranslateRequest is the request of the translate endpoint of LibreTranslate compatible APIs
+type libreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// libreTranslateResponse is the response of the translate endpoint, Error is set for failed requests
+type libreTranslateResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage struct {
+		Language   string  `json:"language"`
+		Confidence float64 `json:"confidence"`
+	} `json:"detectedLanguage"`
+	Error string `json:"error"`
+}
+
+type libreTranslate struct {
+	url    string
+	apiKey string
+}
+
+// NewLibreTranslate returns a provider for a LibreTranslate compatible API at the URL, like http://localhost:5000
+// LibreTranslate detects languages, but only translates between base languages, like zh for zh-tw
+func NewLibreTranslate(apiURL, apiKey string) TranslationProvider {
+	return &libreTranslate{url: strings.TrimRight(apiURL, "/"), apiKey: apiKey}
+}
+
+func (p *libreTranslate) Name() string { return "libretranslate" }
+
+func (p *libreTranslate) Website() string {
+	parsedURL, err := url.Parse(p.url)
+	if err != nil || parsedURL.Host == "" {
+		return p.url
+	}
+	return parsedURL.Host
+}
+
+func (p *libreTranslate) Supports(source, target string) bool {
+	return target != AutoDetect
+}
+
+func (p *libreTranslate) Translate(text, source, target string) (translation Translation, err error) {
+	request := libreTranslateRequest{
+		Q:      text,
+		Source: "auto",
+		Target: baseLanguage(target),
+		Format: "text",
+		APIKey: p.apiKey,
+	}
+	if source != AutoDetect {
+		request.Source = baseLanguage(source)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return translation, err
+	}
+
+	httpRequest, err := http.NewRequest("POST", p.url+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return translation, err
+	}
+	httpRequest.Header.Set("User-Agent", helpers.DEFAULT_UA)
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	response, err := helpers.DefaultClient.Do(httpRequest)
+	if err != nil {
+		return translation, err
+	}
+	defer response.Body.Close()
+
+	var result libreTranslateResponse
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return translation, fmt.Errorf("unexpected response from LibreTranslate with status %d: %s", response.StatusCode, err.Error())
+	}
+
+	if response.StatusCode != http.StatusOK {
+		// LibreTranslate responds with Bad Request for languages it does not know
+		if response.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(result.Error), "not supported") {
+			return translation, ErrUnsupportedLanguage
+		}
+		return translation, fmt.Errorf("LibreTranslate error with status %d: %s", response.StatusCode, result.Error)
+	}
+	if result.TranslatedText == "" {
+		return translation, ErrEmptyTranslation
+	}
+
+	translation = Translation{
+		Text:     result.TranslatedText,
+		Source:   source,
+		Target:   target,
+		Provider: p,
+	}
+	if source == AutoDetect {
+		translation.Source = strings.ToLower(result.DetectedLanguage.Language)
+	}
+	return translation, nil
+}
@@ -0,0 +1,139 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+)
+
+const (
+	papagoEndpoint = "https://papago.naver.com/apis/n2mt/translate"
+	// papagoTemplate is the encoded request of the Papago website
+	papagoTemplate = "rlWxnJA0VwczLJkmZSwiZGljdERpc3BsYXkiOjUsInNvdXJjZSI6ImVuIiwidGFyZ2V0Ijoia28iLCJ0ZXh0IjoiQUFBQUFBQUFBQSJ9"
+	// papagoChunkSize is the size of the texts sent to Papago, longer texts are sent in several parts
+	papagoChunkSize = 200
+)
+
+// papagoLanguages are translated from and to Korean
+var papagoLanguages = map[string]string{
+	"ko":    "ko",
+	"en":    "en",
+	"ja":    "ja",
+	"zh-cn": "zh-CN",
+	"zh-tw": "zh-TW",
+	"es":    "es",
+	"fr":    "fr",
+	"de":    "de",
+	"ru":    "ru",
+	"it":    "it",
+	"vi":    "vi",
+	"th":    "th",
+	"id":    "id",
+}
+
+type papago struct {
+	endpoint string
+}
+
+// NewPapago returns a provider for Naver Papago, it translates between Korean and other languages without detection
+func NewPapago() TranslationProvider {
+	return &papago{endpoint: papagoEndpoint}
+}
+
+func (p *papago) Name() string    { return "papago" }
+func (p *papago) Website() string { return "papago.naver.com" }
+
+func (p *papago) Supports(source, target string) bool {
+	if source == AutoDetect || source == target {
+		return false
+	}
+	if _, ok := papagoLanguages[source]; !ok {
+		return false
+	}
+	if _, ok := papagoLanguages[target]; !ok {
+		return false
+	}
+	return source == "ko" || target == "ko"
+}
+
+func (p *papago) Translate(text, source, target string) (translation Translation, err error) {
+	if !p.Supports(source, target) {
+		return translation, ErrUnsupportedLanguage
+	}
+
+	textChunks, separators := chunks(text, papagoChunkSize)
+
+	var texts []string
+	for _, chunk := range textChunks {
+		translatedChunk, err := p.translateChunk(chunk, papagoLanguages[source], papagoLanguages[target])
+		if err != nil {
+			return translation, err
+		}
+		texts = append(texts, translatedChunk)
+	}
+	if len(texts) <= 0 {
+		return translation, ErrEmptyTranslation
+	}
+
+	return Translation{
+		Text:     joinChunks(texts, separators),
+		Source:   source,
+		Target:   target,
+		Provider: p,
+	}, nil
+}
+
+func (p *papago) translateChunk(text, source, target string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(papagoTemplate)
+	if err != nil {
+		return "", err
+	}
+	textJSON, err := json.Marshal(text)
+	if err != nil {
+		return "", err
+	}
+
+	jsonData := string(decoded)
+	jsonData = strings.Replace(jsonData, "\"dictDisplay\":5", "\"dictDisplay\":0", -1)
+	jsonData = strings.Replace(jsonData, "\"source\":\"en\"", fmt.Sprintf("\"source\":\"%s\"", source), -1)
+	jsonData = strings.Replace(jsonData, "\"target\":\"ko\"", fmt.Sprintf("\"target\":\"%s\"", target), -1)
+	jsonData = strings.Replace(jsonData, "\"text\":\"AAAAAAAAAA\"", "\"text\":"+string(textJSON), -1)
+
+	data := url.Values{}
+	data.Set("data", base64.StdEncoding.EncodeToString([]byte(jsonData)))
+
+	request, err := http.NewRequest("POST", p.endpoint, bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("User-Agent", helpers.DEFAULT_UA)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := helpers.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code from Naver API: %d", response.StatusCode)
+	}
+
+	var result struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+	if result.TranslatedText == "" {
+		return "", ErrEmptyTranslation
+	}
+	return result.TranslatedText, nil
+}
@@ -0,0 +1,136 @@
+// Package translation translates texts with Google Translate, Naver Papago and LibreTranslate compatible APIs
+package translation
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// AutoDetect is the source language which lets the provider detect the language of the text
+const AutoDetect = ""
+
+var (
+	// ErrUnsupportedLanguage is returned if no provider translates between the languages
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	// ErrEmptyTranslation is returned if the provider returned no translation
+	ErrEmptyTranslation = errors.New("empty translation")
+)
+
+// Translation is a translated text
+type Translation struct {
+	Text string
+	// Source is the language of the text, detected by the provider if the source was AutoDetect
+	Source   string
+	Target   string
+	Provider TranslationProvider
+}
+
+// TranslationProvider translates texts, languages are lowercase language codes like ko or zh-tw
+type TranslationProvider interface {
+	// Name is the name of the provider, like google
+	Name() string
+	// Website is shown in translations, like translate.google.com
+	Website() string
+	// Supports returns if the provider translates from source to target, source is AutoDetect to detect the language
+	Supports(source, target string) bool
+	// Translate translates the text, ErrUnsupportedLanguage is returned if the provider does not support the languages
+	Translate(text, source, target string) (Translation, error)
+}
+
+// Translate translates the text with the first provider which supports the languages
+func Translate(providers []TranslationProvider, text, source, target string) (Translation, error) {
+	for _, provider := range providers {
+		if !provider.Supports(source, target) {
+			continue
+		}
+
+		translation, err := provider.Translate(text, source, target)
+		if err == ErrUnsupportedLanguage {
+			continue
+		}
+		return translation, err
+	}
+	return Translation{}, ErrUnsupportedLanguage
+}
+
+// ParseLanguage returns the lowercase code of the language, auto is AutoDetect
+func ParseLanguage(input string) (lang string, ok bool) {
+	if strings.ToLower(input) == "auto" {
+		return AutoDetect, true
+	}
+
+	tag, err := language.Parse(input)
+	if err != nil {
+		return "", false
+	}
+	return strings.ToLower(tag.String()), true
+}
+
+// SameLanguage returns if both languages have the same base language, like zh-cn and zh-tw
+func SameLanguage(a, b string) bool {
+	return baseLanguage(a) == baseLanguage(b)
+}
+
+// baseLanguage returns the language without region, like zh for zh-tw
+func baseLanguage(lang string) string {
+	return strings.SplitN(strings.ToLower(lang), "-", 2)[0]
+}
+
+// chunks splits the text into chunks up to size characters, at line breaks if possible, or else at spaces,
+// longer words are cut. The whitespace between the chunks is returned as separators, to join the translated chunks
+func chunks(text string, size int) (result, separators []string) {
+	runes := []rune(text)
+	for len(runes) > size {
+		cut := lastIndexRune(runes[:size+1], func(r rune) bool { return r == '\n' })
+		if cut <= 0 {
+			cut = lastIndexRune(runes[:size+1], unicode.IsSpace)
+		}
+		if cut <= 0 {
+			result = append(result, string(runes[:size]))
+			separators = append(separators, "")
+			runes = runes[size:]
+			continue
+		}
+
+		// the whole whitespace between the chunks is the separator
+		for cut > 1 && unicode.IsSpace(runes[cut-1]) {
+			cut--
+		}
+		next := cut
+		for next < len(runes) && unicode.IsSpace(runes[next]) {
+			next++
+		}
+		result = append(result, string(runes[:cut]))
+		separators = append(separators, string(runes[cut:next]))
+		runes = runes[next:]
+	}
+	if len(runes) > 0 {
+		result = append(result, string(runes))
+	}
+	return result, separators
+}
+
+// joinChunks joins the translated chunks with the separators returned by chunks
+func joinChunks(texts, separators []string) string {
+	var joined strings.Builder
+	for i, text := range texts {
+		joined.WriteString(strings.TrimSpace(text))
+		if i < len(separators) && i < len(texts)-1 {
+			joined.WriteString(separators[i])
+		}
+	}
+	return joined.String()
+}
+
+// lastIndexRune returns the index of the last rune matching f, or -1
+func lastIndexRune(runes []rune, f func(rune) bool) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if f(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,224 @@
+package translation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newLibreTranslateServer stands up a LibreTranslate compatible API which translates hello into Korean and knows no Klingon
+func newLibreTranslateServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" || r.Method != "POST" {
+			t.Fatal("unexpected request", r.Method, r.URL.Path)
+		}
+
+		var request libreTranslateRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if request.APIKey != "secret" || request.Format != "text" {
+			t.Fatal("unexpected request", request)
+		}
+
+		var response libreTranslateResponse
+		switch {
+		case request.Target == "tlh":
+			w.WriteHeader(http.StatusBadRequest)
+			response.Error = "tlh is not supported"
+		case request.Q == "hello" && request.Target == "ko":
+			response.TranslatedText = "안녕하세요"
+			if request.Source == "auto" {
+				response.DetectedLanguage.Language = "en"
+				response.DetectedLanguage.Confidence = 90
+			}
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			response.Error = "unexpected request"
+		}
+
+		err = json.NewEncoder(w).Encode(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}))
+}
+
+func TestLibreTranslate(t *testing.T) {
+	server := newLibreTranslateServer(t)
+	defer server.Close()
+
+	provider := NewLibreTranslate(server.URL+"/", "secret")
+	if provider.Website() != strings.TrimPrefix(server.URL, "http://") {
+		t.Fatal("unexpected website", provider.Website())
+	}
+
+	translation, err := provider.Translate("hello", AutoDetect, "ko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation.Text != "안녕하세요" || translation.Source != "en" || translation.Target != "ko" {
+		t.Fatal("unexpected translation with detection", translation)
+	}
+
+	translation, err = provider.Translate("hello", "en-us", "ko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation.Source != "en-us" {
+		t.Fatal("expected the given source, got", translation.Source)
+	}
+
+	_, err = provider.Translate("hello", AutoDetect, "tlh")
+	if err != ErrUnsupportedLanguage {
+		t.Fatal("expected ErrUnsupportedLanguage, got", err)
+	}
+
+	_, err = provider.Translate("bye", AutoDetect, "ko")
+	if err == nil || !strings.Contains(err.Error(), "unexpected request") {
+		t.Fatal("expected the error of the API, got", err)
+	}
+}
+
+func TestPapago(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := base64.StdEncoding.DecodeString(r.PostForm.Get("data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"source":"ko","target":"zh-TW","text":"\"안녕\""`) {
+			t.Fatal("unexpected request", string(data))
+		}
+
+		w.Write([]byte(`{"translatedText":"你好"}`))
+	}))
+	defer server.Close()
+
+	provider := &papago{endpoint: server.URL}
+
+	translation, err := provider.Translate(`"안녕"`, "ko", "zh-tw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation.Text != "你好" || translation.Provider != provider {
+		t.Fatal("unexpected translation", translation)
+	}
+
+	tests := []struct {
+		source    string
+		target    string
+		supported bool
+	}{
+		{source: "ko", target: "en", supported: true},
+		{source: "ja", target: "ko", supported: true},
+		{source: AutoDetect, target: "ko"},
+		{source: "en", target: "ja"},
+		{source: "ko", target: "tlh"},
+	}
+	for _, test := range tests {
+		if provider.Supports(test.source, test.target) != test.supported {
+			t.Fatal(test.source, "to", test.target, ": expected supported to be", test.supported)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	server := newLibreTranslateServer(t)
+	defer server.Close()
+
+	providers := []TranslationProvider{NewPapago(), NewLibreTranslate(server.URL, "secret")}
+
+	// Papago does not detect languages, so LibreTranslate is used
+	translation, err := Translate(providers, "hello", AutoDetect, "ko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation.Provider.Name() != "libretranslate" || translation.Source != "en" {
+		t.Fatal("unexpected translation", translation)
+	}
+
+	_, err = Translate(providers, "hello", AutoDetect, "tlh")
+	if err != ErrUnsupportedLanguage {
+		t.Fatal("expected ErrUnsupportedLanguage, got", err)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		input string
+		lang  string
+		ok    bool
+	}{
+		{input: "auto", lang: AutoDetect, ok: true},
+		{input: "KO", lang: "ko", ok: true},
+		{input: "zh-TW", lang: "zh-tw", ok: true},
+		{input: "korean"},
+	}
+	for _, test := range tests {
+		lang, ok := ParseLanguage(test.input)
+		if lang != test.lang || ok != test.ok {
+			t.Fatal(test.input, ": unexpected language", lang, ok)
+		}
+	}
+
+	if !SameLanguage("zh-cn", "zh-tw") || SameLanguage("ko", "en") {
+		t.Fatal("unexpected base languages")
+	}
+}
+
+func TestLanguageForFlag(t *testing.T) {
+	tests := []struct {
+		emoji string
+		lang  string
+		ok    bool
+	}{
+		{emoji: "🇰🇷", lang: "ko", ok: true},
+		{emoji: "🇧🇷", lang: "pt", ok: true},
+		{emoji: "🇹🇼", lang: "zh-tw", ok: true},
+		{emoji: "🇦🇶"},
+		{emoji: "⭐"},
+		{emoji: "KR"},
+	}
+	for _, test := range tests {
+		lang, ok := LanguageForFlag(test.emoji)
+		if lang != test.lang || ok != test.ok {
+			t.Fatal(test.emoji, ": unexpected language", lang, ok)
+		}
+	}
+}
+
+func TestChunks(t *testing.T) {
+	text := "aaa bbb  ccc\ndddddddd"
+	result, separators := chunks(text, 7)
+	expected := []string{"aaa bbb", "ccc", "ddddddd", "d"}
+	if strings.Join(result, "|") != strings.Join(expected, "|") {
+		t.Fatal("unexpected chunks", result)
+	}
+	if joinChunks(result, separators) != text {
+		t.Fatalf("whitespace has not been kept: %q", joinChunks(result, separators))
+	}
+
+	// line breaks are preferred over spaces
+	result, separators = chunks("aa\nbb cc", 7)
+	if strings.Join(result, "|") != "aa|bb cc" || strings.Join(separators, "|") != "\n" {
+		t.Fatal("unexpected chunks", result, separators)
+	}
+
+	// multibyte characters are counted as one character and not cut
+	result, _ = chunks("가나다 라마", 4)
+	if strings.Join(result, "|") != "가나다|라마" {
+		t.Fatal("unexpected chunks", result)
+	}
+	result, _ = chunks("가나다", 2)
+	if strings.Join(result, "|") != "가나|다" {
+		t.Fatal("unexpected chunks", result)
+	}
+}
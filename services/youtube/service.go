@@ -21,11 +21,12 @@ type Service struct {
 	sync.RWMutex
 }
 
-func (s *Service) Init(configFilePath string) {
+// Init creates the YouTube client with the google service account credentials stored at $configFile
+func (s *Service) Init(configFile string) {
 	s.Lock()
 	defer s.Unlock()
 
-	s.init(configFilePath)
+	s.init(configFile)
 
 	err := s.quota.Init()
 	helpers.Relax(err)
@@ -160,9 +161,7 @@ func (s *Service) GetCheckingInterval() int64 {
 	return s.quota.GetInterval()
 }
 
-func (s *Service) init(configFilePath string) {
-	configFile := helpers.GetConfig().Path(configFilePath).Data().(string)
-
+func (s *Service) init(configFile string) {
 	authJSON, err := ioutil.ReadFile(configFile)
 	helpers.Relax(err)
 
@@ -0,0 +1,171 @@
+package shardmanager
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultIdentifyInterval is the time between two identifies, Discord allows one identify every 5 seconds
+const DefaultIdentifyInterval = 5 * time.Second
+
+// Coordinator decides which shards are run by this process, and shares the status of the shards between processes
+// The Manager only opens gateway connections for the shards claimed through the Coordinator
+type Coordinator interface {
+	// Claim claims shards in addition to the already owned shards
+	// Returns the newly claimed shards, which the Manager will start
+	Claim(numShards int, owned []int) (claimed []int, err error)
+
+	// Renew extends the claims of the owned shards
+	// Returns the shards which are no longer owned by this process, the Manager will stop them
+	Renew(owned []int) (lost []int, err error)
+
+	// Release gives up the claims of the shards, other processes can claim them afterwards
+	Release(shards []int) (err error)
+
+	// WaitIdentify blocks until the shard is allowed to identify
+	WaitIdentify(shard int) (err error)
+
+	// PublishStatus shares the status of the shards run by this process
+	PublishStatus(statuses []*ShardStatus) (err error)
+
+	// Status returns the latest published status of all shards
+	Status(numShards int) (statuses []*ShardStatus, err error)
+}
+
+// StaticCoordinator runs a fixed set of shards in this process
+// Processes using a StaticCoordinator do not know about each other, make sure the shards of all processes do not overlap
+type StaticCoordinator struct {
+	sync.Mutex
+
+	// Shards are the shards run by this process, all shards if empty
+	Shards []int
+
+	// IdentifyInterval is the time between two identifies of this process
+	IdentifyInterval time.Duration
+
+	lastIdentify time.Time
+	statuses     map[int]*ShardStatus
+}
+
+func (c *StaticCoordinator) Claim(numShards int, owned []int) (claimed []int, err error) {
+	shards := c.Shards
+	if len(shards) <= 0 {
+		shards = allShards(numShards)
+	}
+
+	for _, shard := range shards {
+		if shard < 0 || shard >= numShards {
+			return nil, errors.Errorf("shard %d is out of range, there are %d shards", shard, numShards)
+		}
+		if !containsShard(owned, shard) {
+			claimed = append(claimed, shard)
+		}
+	}
+	return claimed, nil
+}
+
+func (c *StaticCoordinator) Renew(owned []int) (lost []int, err error) {
+	return nil, nil
+}
+
+func (c *StaticCoordinator) Release(shards []int) (err error) {
+	return nil
+}
+
+func (c *StaticCoordinator) WaitIdentify(shard int) (err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	interval := c.IdentifyInterval
+	if interval <= 0 {
+		interval = DefaultIdentifyInterval
+	}
+
+	if wait := time.Until(c.lastIdentify.Add(interval)); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastIdentify = time.Now()
+	return nil
+}
+
+func (c *StaticCoordinator) PublishStatus(statuses []*ShardStatus) (err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.statuses == nil {
+		c.statuses = make(map[int]*ShardStatus)
+	}
+	for _, status := range statuses {
+		c.statuses[status.Shard] = status
+	}
+	return nil
+}
+
+func (c *StaticCoordinator) Status(numShards int) (statuses []*ShardStatus, err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	statuses = make([]*ShardStatus, numShards)
+	for shard := range statuses {
+		if status, ok := c.statuses[shard]; ok {
+			statuses[shard] = status
+			continue
+		}
+		statuses[shard] = &ShardStatus{Shard: shard}
+	}
+	return statuses, nil
+}
+
+// ParseShards parses a list of shards and shard ranges, for example 0-3,8
+func ParseShards(text string) (shards []int, err error) {
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, errors.Errorf("invalid shard %q", part)
+		}
+		last := first
+		if len(bounds) > 1 {
+			last, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || last < first {
+				return nil, errors.Errorf("invalid shard range %q", part)
+			}
+		}
+
+		for shard := first; shard <= last; shard++ {
+			if !containsShard(shards, shard) {
+				shards = append(shards, shard)
+			}
+		}
+	}
+
+	sort.Ints(shards)
+	return shards, nil
+}
+
+func allShards(numShards int) (shards []int) {
+	shards = make([]int, numShards)
+	for i := range shards {
+		shards[i] = i
+	}
+	return shards
+}
+
+func containsShard(shards []int, shard int) bool {
+	for _, item := range shards {
+		if item == shard {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package shardmanager
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -43,6 +44,18 @@ type Manager struct {
 	// session settings to apply
 	SessionFunc SessionFunc
 
+	// Coordinator decides which shards are run by this process, by default all shards are run
+	Coordinator Coordinator
+
+	// Process is the name of this process, shown in the status of the shards
+	Process string
+
+	// LeaseInterval is the time between renewing the claimed shards and claiming free shards
+	LeaseInterval time.Duration
+
+	// UnhealthyTimeout is how long a shard can stay disconnected before it is restarted, 0 disables automatic restarts
+	UnhealthyTimeout time.Duration
+
 	nextStatusUpdate     time.Time
 	statusUpdaterStarted bool
 
@@ -51,6 +64,18 @@ type Manager struct {
 
 	bareSession *discordgo.Session
 	started     bool
+
+	// shards run by this process
+	owned               []int
+	leaseRoutineStarted bool
+	// shards whose gateway connection has been opened by this process
+	opened map[int]bool
+
+	// health of the shards, see restart.go
+	healthLock           sync.Mutex
+	disconnectedSince    map[int]time.Time
+	restarting           map[int]bool
+	healthRoutineStarted bool
 }
 
 // New creates a new shard manager with the defaults set, after you have created this you call Manager.Start
@@ -59,8 +84,10 @@ type Manager struct {
 func New(token string) *Manager {
 	// Setup defaults
 	manager := &Manager{
-		token:     token,
-		numShards: -1,
+		token:            token,
+		numShards:        -1,
+		LeaseInterval:    10 * time.Second,
+		UnhealthyTimeout: 3 * time.Minute,
 	}
 
 	manager.OnEvent = manager.LogConnectionEventStd
@@ -130,6 +157,11 @@ func (m *Manager) Init() error {
 		}
 	}
 
+	if m.Coordinator == nil {
+		m.Coordinator = &StaticCoordinator{}
+	}
+
+	// sessions of shards run by other processes are never opened, they can still be used for REST requests
 	m.Sessions = make([]*discordgo.Session, m.numShards)
 	for i := 0; i < m.numShards; i++ {
 		err := m.initSession(i)
@@ -139,6 +171,14 @@ func (m *Manager) Init() error {
 		}
 	}
 
+	claimed, err := m.Coordinator.Claim(m.numShards, m.owned)
+	if err != nil {
+		m.Unlock()
+		return errors.WithMessage(err, "Claim")
+	}
+	m.owned = append(m.owned, claimed...)
+	sort.Ints(m.owned)
+
 	if !m.statusUpdaterStarted {
 		m.statusUpdaterStarted = true
 		go m.statusRoutine()
@@ -151,7 +191,7 @@ func (m *Manager) Init() error {
 	return nil
 }
 
-// Start starts the shard manager, opening all gateway connections
+// Start starts the shard manager, opening the gateway connections of all shards run by this process
 func (m *Manager) Start() error {
 
 	m.Lock()
@@ -164,38 +204,159 @@ func (m *Manager) Start() error {
 		m.Lock()
 	}
 
+	m.started = true
+	owned := m.ownedShards()
 	m.Unlock()
 
-	for i := 0; i < m.numShards; i++ {
-		if i != 0 {
-			// One indentify every 5 seconds
-			time.Sleep(time.Second * 5)
+	err := m.startShards(owned)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	if !m.leaseRoutineStarted {
+		m.leaseRoutineStarted = true
+		go m.leaseRoutine()
+	}
+	if !m.healthRoutineStarted && m.UnhealthyTimeout > 0 {
+		m.healthRoutineStarted = true
+		go m.healthRoutine()
+	}
+	m.Unlock()
+
+	return nil
+}
+
+// startShards opens the gateway connections of the shards, waiting for the coordinator before each identify
+func (m *Manager) startShards(shards []int) error {
+	for _, shard := range shards {
+		err := m.Coordinator.WaitIdentify(shard)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("Failed waiting to identify shard %d", shard))
 		}
 
 		m.Lock()
-		err := m.startSession(i)
+		err = m.startSession(shard)
 		m.Unlock()
 		if err != nil {
-			return errors.WithMessage(err, fmt.Sprintf("Failed starting shard %d", i))
+			return errors.WithMessage(err, fmt.Sprintf("Failed starting shard %d", shard))
 		}
 	}
 
 	return nil
 }
 
-// StopAll stops all the shard sessions and returns the last error that occured
+// StopAll stops all the shard sessions of this process, releases their claims, and returns the last error that occured
 func (m *Manager) StopAll() (err error) {
 	m.Lock()
-	for _, v := range m.Sessions {
-		if e := v.Close(); e != nil {
+	owned := m.owned
+	for _, shard := range owned {
+		if e := m.Sessions[shard].Close(); e != nil {
 			err = e
 		}
 	}
+	m.owned = nil
+	m.opened = nil
+	m.started = false
 	m.Unlock()
 
+	if e := m.Coordinator.Release(owned); e != nil {
+		err = e
+	}
+
 	return
 }
 
+// OwnedShards returns the shards run by this process
+func (m *Manager) OwnedShards() []int {
+	m.RLock()
+	defer m.RUnlock()
+	return m.ownedShards()
+}
+
+// ownedShards is the same as OwnedShards but does not lock the manager
+func (m *Manager) ownedShards() []int {
+	owned := make([]int, len(m.owned))
+	copy(owned, m.owned)
+	return owned
+}
+
+// OwnsShard returns true if the shard is run by this process
+func (m *Manager) OwnsShard(shard int) bool {
+	m.RLock()
+	defer m.RUnlock()
+	return containsShard(m.owned, shard)
+}
+
+// OwnsGuild returns true if the shard of the guild is run by this process
+func (m *Manager) OwnsGuild(guildID string) bool {
+	return m.OwnsShard(int(m.ShardForGuild(guildID)))
+}
+
+// leaseRoutine renews the claims of the owned shards, stops lost shards, and starts newly claimed shards
+func (m *Manager) leaseRoutine() {
+	ticker := time.NewTicker(m.LeaseInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.RLock()
+		started := m.started
+		owned := m.ownedShards()
+		m.RUnlock()
+		if !started {
+			m.Lock()
+			m.leaseRoutineStarted = false
+			m.Unlock()
+			return
+		}
+
+		lost, err := m.Coordinator.Renew(owned)
+		if m.handleError(err, -1, "Failed renewing shards") {
+			continue
+		}
+		for _, shard := range lost {
+			m.stopShard(shard)
+		}
+
+		m.RLock()
+		owned = m.ownedShards()
+		m.RUnlock()
+		claimed, err := m.Coordinator.Claim(m.numShards, owned)
+		if m.handleError(err, -1, "Failed claiming shards") {
+			continue
+		}
+		if len(claimed) > 0 {
+			m.Lock()
+			m.owned = append(m.owned, claimed...)
+			sort.Ints(m.owned)
+			m.Unlock()
+
+			err = m.startShards(claimed)
+			m.handleError(err, -1, "Failed starting claimed shards")
+		}
+
+		m.GetFullStatus()
+	}
+}
+
+// stopShard closes the gateway connection of a shard which is no longer run by this process
+func (m *Manager) stopShard(shard int) {
+	m.Lock()
+	newOwned := make([]int, 0, len(m.owned))
+	for _, ownedShard := range m.owned {
+		if ownedShard != shard {
+			newOwned = append(newOwned, ownedShard)
+		}
+	}
+	m.owned = newOwned
+	delete(m.opened, shard)
+	session := m.Sessions[shard]
+	m.Unlock()
+
+	m.handleError(session.Close(), shard, "Failed closing lost shard")
+	m.handleEvent(EventClose, shard, "claimed by another process")
+}
+
 func (m *Manager) initSession(shard int) error {
 	session, err := m.SessionFunc(m.token)
 	if err != nil {
@@ -208,6 +369,9 @@ func (m *Manager) initSession(shard int) error {
 	session.StateEnabled = true
 	session.MaxRestRetries = 5
 	session.State.MaxMessageCount = 10
+	// members and presences are tracked by the robyul state
+	session.State.TrackMembers = false
+	session.State.TrackPresences = false
 
 	session.ShardCount = m.numShards
 	session.ShardID = shard
@@ -238,6 +402,10 @@ func (m *Manager) startSession(shard int) error {
 	if err != nil {
 		return errors.Wrap(err, "startSession.Open")
 	}
+	if m.opened == nil {
+		m.opened = make(map[int]bool)
+	}
+	m.opened[shard] = true
 	m.handleEvent(EventOpen, shard, "")
 
 	return nil
@@ -288,6 +456,8 @@ func (m *Manager) handleError(err error, shard int, msg string) bool {
 }
 
 func (m *Manager) handleEvent(typ EventType, shard int, msg string) {
+	m.trackHealth(typ, shard)
+
 	if m.OnEvent == nil {
 		return
 	}
@@ -384,7 +554,11 @@ func (m *Manager) updateStatusMessage(mID string) (string, error) {
 		} else {
 			emoji = "🔥"
 		}
-		content += fmt.Sprintf("[%d/%d]: %s (%d,%d)\n", shard.Shard+1, m.numShards, emoji, shard.NumGuilds, status.NumGuilds)
+		content += fmt.Sprintf("[%d/%d]: %s (%d,%d)", shard.Shard+1, m.numShards, emoji, shard.NumGuilds, status.NumGuilds)
+		if shard.Process != "" {
+			content += " " + shard.Process
+		}
+		content += "\n"
 	}
 
 	nameStr := ""
@@ -423,26 +597,52 @@ func (m *Manager) GetFullStatus() *Status {
 
 	m.RLock()
 
-	result := make([]*ShardStatus, len(m.Sessions))
-	for i, shard := range m.Sessions {
-		result[i] = &ShardStatus{
-			Shard: i,
+	local := make([]*ShardStatus, 0, len(m.owned))
+	for _, shard := range m.owned {
+		status := &ShardStatus{
+			Shard:   shard,
+			Process: m.Process,
 		}
 
-		if shard != nil {
-			result[i].Started = true
+		// claimed shards are waiting to identify until they have been opened
+		if session := m.Sessions[shard]; session != nil && m.opened[shard] {
+			status.Started = true
+
+			session.RLock()
+			status.OK = session.DataReady
+			session.RUnlock()
+		}
 
-			shard.RLock()
-			result[i].OK = shard.DataReady
-			shard.RUnlock()
+		if shard < len(shardGuilds) {
+			status.NumGuilds = shardGuilds[shard]
 		}
+
+		local = append(local, status)
 	}
+	numShards := m.numShards
+	coordinator := m.Coordinator
 	m.RUnlock()
 
+	// the shards of other processes are taken from the coordinator
+	result := make([]*ShardStatus, numShards)
+	if coordinator != nil {
+		m.handleError(coordinator.PublishStatus(local), -1, "Failed publishing status")
+
+		shared, err := coordinator.Status(numShards)
+		if !m.handleError(err, -1, "Failed retrieving status") {
+			copy(result, shared)
+		}
+	}
+	for _, status := range local {
+		result[status.Shard] = status
+	}
+
 	totalGuilds := 0
-	for shard, guilds := range shardGuilds {
-		totalGuilds += guilds
-		result[shard].NumGuilds = guilds
+	for shard := range result {
+		if result[shard] == nil {
+			result[shard] = &ShardStatus{Shard: shard}
+		}
+		totalGuilds += result[shard].NumGuilds
 	}
 
 	return &Status{
@@ -477,10 +677,11 @@ type Status struct {
 }
 
 type ShardStatus struct {
-	Shard     int  `json:"shard"`
-	OK        bool `json:"ok"`
-	Started   bool `json:"started"`
-	NumGuilds int  `json:"num_guilds"`
+	Shard     int    `json:"shard"`
+	OK        bool   `json:"ok"`
+	Started   bool   `json:"started"`
+	NumGuilds int    `json:"num_guilds"`
+	Process   string `json:"process,omitempty"`
 }
 
 // Event holds data for an event
@@ -533,6 +734,9 @@ const (
 
 	// Sent when an error occurs
 	EventError
+
+	// Sent when a shard is restarted, manually or because it was unhealthy
+	EventRestart
 )
 
 var (
@@ -544,6 +748,7 @@ var (
 		EventResumed:      "resumed",
 		EventReady:        "ready",
 		EventError:        "error",
+		EventRestart:      "restarting",
 	}
 
 	eventColors = map[EventType]int{
@@ -554,6 +759,7 @@ var (
 		EventResumed:      0x5985ff,
 		EventReady:        0x00ffbf,
 		EventError:        0x7a1bad,
+		EventRestart:      0xf4d742,
 	}
 )
 
@@ -0,0 +1,223 @@
+package shardmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// fakeGateway is a minimal Discord gateway, it accepts identifies and answers with ready events
+type fakeGateway struct {
+	sync.Mutex
+
+	server     *httptest.Server
+	identifies []fakeIdentify
+	resumes    []fakeIdentify
+	connected  map[int]int
+	conns      map[*websocket.Conn]bool
+	reject     bool
+}
+
+// fakeIdentify is an identify or resume of a shard
+type fakeIdentify struct {
+	Shard int
+	Time  time.Time
+}
+
+type fakePayload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d,omitempty"`
+	Sequence int64           `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+var fakeUpgrader = websocket.Upgrader{}
+
+// newFakeGateway starts a fake gateway and points discordgo at it
+func newFakeGateway() *fakeGateway {
+	gateway := &fakeGateway{
+		connected: make(map[int]int),
+		conns:     make(map[*websocket.Conn]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/gateway", gateway.handleGateway)
+	mux.HandleFunc("/api/gateway/bot", gateway.handleGateway)
+	mux.HandleFunc("/ws/", gateway.handleWebsocket)
+	gateway.server = httptest.NewServer(mux)
+
+	discordgo.EndpointGateway = gateway.server.URL + "/api/gateway"
+	discordgo.EndpointGatewayBot = discordgo.EndpointGateway + "/bot"
+
+	return gateway
+}
+
+func (g *fakeGateway) Close() {
+	g.server.Close()
+}
+
+func (g *fakeGateway) handleGateway(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"url":    "ws" + strings.TrimPrefix(g.server.URL, "http") + "/ws",
+		"shards": 1,
+	})
+}
+
+func (g *fakeGateway) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	g.Lock()
+	reject := g.reject
+	g.Unlock()
+	if reject {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := fakeUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	g.Lock()
+	g.conns[conn] = true
+	g.Unlock()
+	defer func() {
+		g.Lock()
+		delete(g.conns, conn)
+		g.Unlock()
+	}()
+
+	err = conn.WriteJSON(&fakePayload{Op: 10, Data: json.RawMessage(`{"heartbeat_interval":45000}`)})
+	if err != nil {
+		return
+	}
+
+	shard := -1
+	defer func() {
+		if shard >= 0 {
+			g.Lock()
+			g.connected[shard]--
+			g.Unlock()
+		}
+	}()
+
+	var sequence int64
+	for {
+		var payload fakePayload
+		err = conn.ReadJSON(&payload)
+		if err != nil {
+			return
+		}
+
+		switch payload.Op {
+		case 1:
+			err = conn.WriteJSON(&fakePayload{Op: 11})
+		case 2:
+			var identify struct {
+				Shard *[2]int `json:"shard"`
+			}
+			err = json.Unmarshal(payload.Data, &identify)
+			if err != nil {
+				return
+			}
+			// discordgo only sends the shard if there are several shards
+			shard = 0
+			if identify.Shard != nil {
+				shard = identify.Shard[0]
+			}
+
+			g.Lock()
+			g.identifies = append(g.identifies, fakeIdentify{Shard: shard, Time: time.Now()})
+			g.connected[shard]++
+			g.Unlock()
+
+			sequence++
+			err = conn.WriteJSON(&fakePayload{
+				Op:       0,
+				Type:     "READY",
+				Sequence: sequence,
+				Data: json.RawMessage(fmt.Sprintf(
+					`{"v":6,"session_id":"shard-%d","user":{"id":"1","username":"Robyul"},"guilds":[]}`, shard)),
+			})
+		case 6:
+			var resume struct {
+				SessionID string `json:"session_id"`
+				Sequence  int64  `json:"seq"`
+			}
+			err = json.Unmarshal(payload.Data, &resume)
+			if err != nil {
+				return
+			}
+			_, err = fmt.Sscanf(resume.SessionID, "shard-%d", &shard)
+			if err != nil {
+				return
+			}
+
+			g.Lock()
+			g.resumes = append(g.resumes, fakeIdentify{Shard: shard, Time: time.Now()})
+			g.connected[shard]++
+			g.Unlock()
+
+			sequence = resume.Sequence + 1
+			err = conn.WriteJSON(&fakePayload{
+				Op:       0,
+				Type:     "RESUMED",
+				Sequence: sequence,
+				Data:     json.RawMessage(`{}`),
+			})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Identifies returns the identifies received so far
+func (g *fakeGateway) Identifies() []fakeIdentify {
+	g.Lock()
+	defer g.Unlock()
+	identifies := make([]fakeIdentify, len(g.identifies))
+	copy(identifies, g.identifies)
+	return identifies
+}
+
+// Resumes returns the resumes received so far
+func (g *fakeGateway) Resumes() []fakeIdentify {
+	g.Lock()
+	defer g.Unlock()
+	resumes := make([]fakeIdentify, len(g.resumes))
+	copy(resumes, g.resumes)
+	return resumes
+}
+
+// Drop closes all connections without a close frame, like a lost network connection
+// New connections are refused while reject is true
+func (g *fakeGateway) Drop(reject bool) {
+	g.Lock()
+	defer g.Unlock()
+	g.reject = reject
+	for conn := range g.conns {
+		conn.UnderlyingConn().Close()
+	}
+}
+
+// Reject sets whether new connections are refused
+func (g *fakeGateway) Reject(reject bool) {
+	g.Lock()
+	defer g.Unlock()
+	g.reject = reject
+}
+
+// Connected returns the number of open connections of the shard
+func (g *fakeGateway) Connected(shard int) int {
+	g.Lock()
+	defer g.Unlock()
+	return g.connected[shard]
+}
@@ -0,0 +1,218 @@
+package shardmanager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+const testIdentifyInterval = 20 * time.Millisecond
+
+// newTestManager creates a manager acting as one process of the bot
+func newTestManager(process string, numShards int, coordinator Coordinator) *Manager {
+	manager := New("Bot test")
+	manager.OnEvent = nil
+	manager.Process = process
+	manager.Coordinator = coordinator
+	manager.LeaseInterval = 50 * time.Millisecond
+	manager.SetNumShards(numShards)
+	return manager
+}
+
+// waitFor polls the condition until it is true or the timeout has been reached
+func waitFor(t *testing.T, timeout time.Duration, description string, condition func() bool) {
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for", description)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestParseShards(t *testing.T) {
+	shards, err := ParseShards("8, 0-3,2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(shards, []int{0, 1, 2, 3, 8}) {
+		t.Fatal("unexpected shards", shards)
+	}
+
+	shards, err = ParseShards("")
+	if err != nil || len(shards) != 0 {
+		t.Fatal("expected no shards, got", shards, err)
+	}
+
+	for _, text := range []string{"a", "3-1", "1-b"} {
+		_, err = ParseShards(text)
+		if err == nil {
+			t.Fatal("expected error for", text)
+		}
+	}
+}
+
+func TestStaticCoordinatorSplitsShards(t *testing.T) {
+	gateway := newFakeGateway()
+	defer gateway.Close()
+
+	first := newTestManager("first", 4, &StaticCoordinator{Shards: []int{0, 1}, IdentifyInterval: testIdentifyInterval})
+	second := newTestManager("second", 4, &StaticCoordinator{Shards: []int{2, 3}, IdentifyInterval: testIdentifyInterval})
+	for _, manager := range []*Manager{first, second} {
+		err := manager.Start()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer manager.StopAll()
+	}
+
+	if !reflect.DeepEqual(first.OwnedShards(), []int{0, 1}) || !reflect.DeepEqual(second.OwnedShards(), []int{2, 3}) {
+		t.Fatal("unexpected shards", first.OwnedShards(), second.OwnedShards())
+	}
+
+	identified := make(map[int]int)
+	for _, identify := range gateway.Identifies() {
+		identified[identify.Shard]++
+	}
+	if !reflect.DeepEqual(identified, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}) {
+		t.Fatal("expected every shard to identify once, got", identified)
+	}
+
+	// a guild on shard 3: (id >> 22) % 4 == 3
+	guildID := "12582912"
+	if first.OwnsGuild(guildID) || !second.OwnsGuild(guildID) {
+		t.Fatal("guild should be owned by the second process only")
+	}
+
+	_, err := (&StaticCoordinator{Shards: []int{4}}).Claim(4, nil)
+	if err == nil {
+		t.Fatal("expected error claiming a shard out of range")
+	}
+}
+
+func TestRedisCoordinatorLeases(t *testing.T) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer redisServer.Close()
+
+	// miniredis only expires keys when its clock is advanced
+	stopClock := make(chan struct{})
+	defer close(stopClock)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				redisServer.FastForward(10 * time.Millisecond)
+			case <-stopClock:
+				return
+			}
+		}
+	}()
+
+	gateway := newFakeGateway()
+	defer gateway.Close()
+
+	newCoordinator := func(process string) *RedisCoordinator {
+		coordinator := NewRedisCoordinator(redis.NewClient(&redis.Options{Addr: redisServer.Addr()}), process)
+		coordinator.MaxShards = 2
+		coordinator.LeaseDuration = time.Second
+		coordinator.IdentifyInterval = testIdentifyInterval
+		return coordinator
+	}
+
+	first := newTestManager("first", 4, newCoordinator("first"))
+	err = first.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.StopAll()
+
+	second := newTestManager("second", 4, newCoordinator("second"))
+	err = second.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(first.OwnedShards(), []int{0, 1}) || !reflect.DeepEqual(second.OwnedShards(), []int{2, 3}) {
+		t.Fatal("unexpected shards", first.OwnedShards(), second.OwnedShards())
+	}
+
+	// identifies of all processes are spaced by the identify interval
+	identifies := gateway.Identifies()
+	for i := 1; i < len(identifies); i++ {
+		if identifies[i].Time.Sub(identifies[i-1].Time) < testIdentifyInterval/2 {
+			t.Fatal("identifies of shards", identifies[i-1].Shard, "and", identifies[i].Shard, "were not spaced")
+		}
+	}
+
+	// each process sees the shards of the other process
+	waitFor(t, 2*time.Second, "the status of the second process", func() bool {
+		status := first.GetFullStatus()
+		return status.Shards[3].Process == "second" && status.Shards[3].OK
+	})
+
+	// a process with a different shard count is refused
+	_, err = newCoordinator("third").Claim(8, nil)
+	if err == nil {
+		t.Fatal("expected error claiming with a different shard count")
+	}
+
+	// a third process takes over the shards of a stopped process
+	third := newTestManager("third", 4, newCoordinator("third"))
+	err = third.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer third.StopAll()
+	if len(third.OwnedShards()) != 0 {
+		t.Fatal("all shards should be claimed already, got", third.OwnedShards())
+	}
+
+	err = second.StopAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 2*time.Second, "the third process to take over", func() bool {
+		return reflect.DeepEqual(third.OwnedShards(), []int{2, 3}) &&
+			gateway.Connected(2) == 1 && gateway.Connected(3) == 1
+	})
+	if !reflect.DeepEqual(first.OwnedShards(), []int{0, 1}) {
+		t.Fatal("the first process should keep its shards, got", first.OwnedShards())
+	}
+}
+
+func TestStatusStartedOnlyForOpenedShards(t *testing.T) {
+	gateway := newFakeGateway()
+	defer gateway.Close()
+
+	manager := newTestManager("first", 2, &StaticCoordinator{IdentifyInterval: testIdentifyInterval})
+	err := manager.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, shard := range manager.GetFullStatus().Shards {
+		if shard.Started {
+			t.Fatal("claimed shard", shard.Shard, "has been reported as started before it has been opened")
+		}
+	}
+
+	err = manager.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.StopAll()
+
+	for _, shard := range manager.GetFullStatus().Shards {
+		if !shard.Started || shard.Process != "first" {
+			t.Fatal("opened shard", shard.Shard, "has not been reported as started")
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package shardmanager
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+)
+
+// DefaultLeaseDuration is how long a shard stays claimed by a process which stopped renewing it
+const DefaultLeaseDuration = 30 * time.Second
+
+// renewLeaseScript extends the lease if it is still owned by the process
+var renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaseScript removes the lease if it is still owned by the process
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisCoordinator claims shards through leases stored in Redis
+// Shards of processes which stop renewing their leases are claimed by other processes
+type RedisCoordinator struct {
+	client *redis.Client
+	prefix string
+
+	// Process is the unique name of this process
+	Process string
+
+	// Shards restricts the shards this process claims, all shards if empty
+	Shards []int
+
+	// MaxShards is the maximum number of shards this process runs, no limit if 0
+	MaxShards int
+
+	// LeaseDuration is how long a claim is valid without being renewed
+	LeaseDuration time.Duration
+
+	// IdentifyInterval is the time between two identifies of all processes
+	IdentifyInterval time.Duration
+}
+
+type redisShardStatus struct {
+	ShardStatus
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewRedisCoordinator creates a coordinator sharing the shards with all processes using the same Redis
+func NewRedisCoordinator(client *redis.Client, process string) *RedisCoordinator {
+	return &RedisCoordinator{
+		client:           client,
+		prefix:           "robyul2-discord:shards:",
+		Process:          process,
+		LeaseDuration:    DefaultLeaseDuration,
+		IdentifyInterval: DefaultIdentifyInterval,
+	}
+}
+
+func (c *RedisCoordinator) Claim(numShards int, owned []int) (claimed []int, err error) {
+	// all processes have to use the same number of shards
+	_, err = c.client.SetNX(c.prefix+"count", numShards, c.LeaseDuration).Result()
+	if err != nil {
+		return nil, err
+	}
+	sharedNumShards, err := c.client.Get(c.prefix + "count").Int()
+	if err != nil {
+		return nil, err
+	}
+	if sharedNumShards != numShards {
+		return nil, errors.Errorf("other processes run %d shards, this process is configured for %d shards",
+			sharedNumShards, numShards)
+	}
+
+	shards := c.Shards
+	if len(shards) <= 0 {
+		shards = allShards(numShards)
+	}
+
+	for _, shard := range shards {
+		if c.MaxShards > 0 && len(owned)+len(claimed) >= c.MaxShards {
+			break
+		}
+		if shard < 0 || shard >= numShards || containsShard(owned, shard) {
+			continue
+		}
+
+		ok, err := c.client.SetNX(c.leaseKey(shard), c.Process, c.LeaseDuration).Result()
+		if err != nil {
+			return claimed, err
+		}
+		if ok {
+			claimed = append(claimed, shard)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (c *RedisCoordinator) Renew(owned []int) (lost []int, err error) {
+	err = c.client.Expire(c.prefix+"count", c.LeaseDuration).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shard := range owned {
+		renewed, err := renewLeaseScript.Run(c.client, []string{c.leaseKey(shard)},
+			c.Process, c.LeaseDuration.Nanoseconds()/int64(time.Millisecond)).Int()
+		if err != nil {
+			return lost, err
+		}
+		if renewed != 1 {
+			lost = append(lost, shard)
+		}
+	}
+
+	return lost, nil
+}
+
+func (c *RedisCoordinator) Release(shards []int) (err error) {
+	for _, shard := range shards {
+		err = releaseLeaseScript.Run(c.client, []string{c.leaseKey(shard)}, c.Process).Err()
+		if err != nil {
+			return err
+		}
+		err = c.client.HDel(c.prefix+"status", strconv.Itoa(shard)).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RedisCoordinator) WaitIdentify(shard int) (err error) {
+	for {
+		ok, err := c.client.SetNX(c.prefix+"identify", c.Process, c.IdentifyInterval).Result()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		wait, err := c.client.PTTL(c.prefix + "identify").Result()
+		if err != nil {
+			return err
+		}
+		if wait <= 0 || wait > c.IdentifyInterval {
+			wait = c.IdentifyInterval / 10
+		}
+		time.Sleep(wait)
+	}
+}
+
+func (c *RedisCoordinator) PublishStatus(statuses []*ShardStatus) (err error) {
+	if len(statuses) <= 0 {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(statuses))
+	for _, status := range statuses {
+		data, err := json.Marshal(&redisShardStatus{
+			ShardStatus: *status,
+			UpdatedAt:   time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		fields[strconv.Itoa(status.Shard)] = string(data)
+	}
+
+	return c.client.HMSet(c.prefix+"status", fields).Err()
+}
+
+func (c *RedisCoordinator) Status(numShards int) (statuses []*ShardStatus, err error) {
+	fields, err := c.client.HGetAll(c.prefix + "status").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses = make([]*ShardStatus, numShards)
+	for shard := range statuses {
+		statuses[shard] = &ShardStatus{Shard: shard}
+
+		data, ok := fields[strconv.Itoa(shard)]
+		if !ok {
+			continue
+		}
+
+		var status redisShardStatus
+		err = json.Unmarshal([]byte(data), &status)
+		if err != nil {
+			return nil, err
+		}
+
+		// ignore the status of processes which stopped publishing
+		if time.Since(status.UpdatedAt) > c.LeaseDuration {
+			continue
+		}
+
+		status.Shard = shard
+		statuses[shard] = &status.ShardStatus
+	}
+
+	return statuses, nil
+}
+
+func (c *RedisCoordinator) leaseKey(shard int) string {
+	return c.prefix + "lease:" + strconv.Itoa(shard)
+}
@@ -0,0 +1,167 @@
+package shardmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// ErrShardNotOwned is returned when restarting a shard which is run by another process
+var ErrShardNotOwned = errors.New("shard is not run by this process")
+
+// ErrShardRestarting is returned when restarting a shard which is already restarting
+var ErrShardRestarting = errors.New("shard is already restarting")
+
+// RestartShard closes the gateway connection of the shard and opens it again
+// The connection is closed in a way which allows resuming the gateway session, so no events are lost
+// The session of the shard stays usable for REST requests while restarting
+func (m *Manager) RestartShard(shard int) error {
+	m.RLock()
+	owned := containsShard(m.owned, shard)
+	var session *discordgo.Session
+	if shard >= 0 && shard < len(m.Sessions) {
+		session = m.Sessions[shard]
+	}
+	m.RUnlock()
+	if !owned || session == nil {
+		return ErrShardNotOwned
+	}
+
+	m.healthLock.Lock()
+	if m.restarting[shard] {
+		m.healthLock.Unlock()
+		return ErrShardRestarting
+	}
+	if m.restarting == nil {
+		m.restarting = make(map[int]bool)
+	}
+	m.restarting[shard] = true
+	m.healthLock.Unlock()
+
+	defer func() {
+		m.healthLock.Lock()
+		delete(m.restarting, shard)
+		m.healthLock.Unlock()
+	}()
+
+	m.handleEvent(EventRestart, shard, "")
+
+	// discord invalidates the gateway session if the connection is closed normally
+	err := session.CloseWithCode(websocket.CloseServiceRestart)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("Failed closing shard %d", shard))
+	}
+
+	err = m.Coordinator.WaitIdentify(shard)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("Failed waiting to identify shard %d", shard))
+	}
+
+	m.Lock()
+	err = m.startSession(shard)
+	m.Unlock()
+	// discordgo might have reconnected on its own in the meantime
+	if errors.Cause(err) == discordgo.ErrWSAlreadyOpen {
+		err = nil
+	}
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("Failed starting shard %d", shard))
+	}
+
+	return nil
+}
+
+// RollingRestart restarts all shards of this process one after another
+// Every shard is connected again before the next shard is closed, identifies respect the identify interval of the coordinator
+func (m *Manager) RollingRestart() (err error) {
+	for _, shard := range m.OwnedShards() {
+		e := m.RestartShard(shard)
+		// skip shards which have been lost or are restarted already
+		if e == ErrShardNotOwned || e == ErrShardRestarting {
+			continue
+		}
+		if e != nil {
+			err = e
+			m.handleError(e, shard, "Failed restarting shard")
+		}
+	}
+	return err
+}
+
+// trackHealth remembers since when shards are disconnected, based on the events of the shards
+func (m *Manager) trackHealth(typ EventType, shard int) {
+	if shard < 0 {
+		return
+	}
+
+	m.healthLock.Lock()
+	defer m.healthLock.Unlock()
+
+	if m.disconnectedSince == nil {
+		m.disconnectedSince = make(map[int]time.Time)
+	}
+
+	switch typ {
+	case EventDisconnected:
+		if _, ok := m.disconnectedSince[shard]; !ok {
+			m.disconnectedSince[shard] = time.Now()
+		}
+	case EventConnected, EventReady, EventResumed:
+		delete(m.disconnectedSince, shard)
+	}
+}
+
+// unhealthyShards returns the owned shards which are disconnected for longer than the UnhealthyTimeout
+func (m *Manager) unhealthyShards() (shards []int) {
+	owned := m.OwnedShards()
+
+	m.healthLock.Lock()
+	defer m.healthLock.Unlock()
+
+	for shard, since := range m.disconnectedSince {
+		if !containsShard(owned, shard) {
+			delete(m.disconnectedSince, shard)
+			continue
+		}
+		if m.restarting[shard] || time.Since(since) < m.UnhealthyTimeout {
+			continue
+		}
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// healthRoutine restarts shards which stay disconnected
+func (m *Manager) healthRoutine() {
+	interval := m.UnhealthyTimeout / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.RLock()
+		started := m.started
+		m.RUnlock()
+		if !started {
+			m.Lock()
+			m.healthRoutineStarted = false
+			m.Unlock()
+			return
+		}
+
+		for _, shard := range m.unhealthyShards() {
+			m.handleEvent(EventError, shard, "shard is disconnected for too long, restarting")
+
+			err := m.RestartShard(shard)
+			if err == ErrShardNotOwned || err == ErrShardRestarting {
+				continue
+			}
+			m.handleError(err, shard, "Failed restarting unhealthy shard")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package shardmanager
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRestartShardResumes(t *testing.T) {
+	gateway := newFakeGateway()
+	defer gateway.Close()
+
+	manager := newTestManager("first", 2, &StaticCoordinator{IdentifyInterval: testIdentifyInterval})
+	err := manager.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.StopAll()
+
+	err = manager.RestartShard(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gateway.Identifies()) != 2 {
+		t.Fatal("restarting should not identify again, got", len(gateway.Identifies()), "identifies")
+	}
+	resumes := gateway.Resumes()
+	if len(resumes) != 1 || resumes[0].Shard != 1 {
+		t.Fatal("expected shard 1 to resume, got", resumes)
+	}
+	waitFor(t, 2*time.Second, "the old connection to close", func() bool {
+		return gateway.Connected(0) == 1 && gateway.Connected(1) == 1
+	})
+	waitFor(t, time.Second, "shard 1 to be ready", func() bool {
+		session := manager.Session(1)
+		session.RLock()
+		defer session.RUnlock()
+		return session.DataReady
+	})
+
+	err = manager.RestartShard(2)
+	if err != ErrShardNotOwned {
+		t.Fatal("expected ErrShardNotOwned, got", err)
+	}
+}
+
+func TestRollingRestart(t *testing.T) {
+	gateway := newFakeGateway()
+	defer gateway.Close()
+
+	manager := newTestManager("first", 3, &StaticCoordinator{IdentifyInterval: testIdentifyInterval})
+	err := manager.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.StopAll()
+
+	err = manager.RollingRestart()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumes := gateway.Resumes()
+	if len(resumes) != 3 {
+		t.Fatal("expected every shard to resume once, got", resumes)
+	}
+	for i, resume := range resumes {
+		if resume.Shard != i {
+			t.Fatal("shards should be restarted in order, got", resumes)
+		}
+		if i > 0 && resume.Time.Sub(resumes[i-1].Time) < testIdentifyInterval/2 {
+			t.Fatal("restarts of shards", i-1, "and", i, "were not spaced")
+		}
+	}
+}
+
+func TestUnhealthyShardIsRestarted(t *testing.T) {
+	gateway := newFakeGateway()
+	defer gateway.Close()
+
+	manager := newTestManager("first", 1, &StaticCoordinator{IdentifyInterval: testIdentifyInterval})
+	manager.UnhealthyTimeout = 200 * time.Millisecond
+
+	var lock sync.Mutex
+	disconnects, restarts := 0, 0
+	manager.OnEvent = func(e *Event) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch e.Type {
+		case EventDisconnected:
+			disconnects++
+		case EventRestart:
+			restarts++
+		}
+	}
+
+	err := manager.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.StopAll()
+
+	// the first reconnect of discordgo fails, its next attempt is only one second later
+	gateway.Drop(true)
+	waitFor(t, 3*time.Second, "the shard to disconnect", func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return disconnects > 0
+	})
+	time.Sleep(100 * time.Millisecond)
+	gateway.Reject(false)
+
+	waitFor(t, 800*time.Millisecond, "the shard to be restarted", func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return restarts > 0 && gateway.Connected(0) == 1
+	})
+}
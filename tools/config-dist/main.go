@@ -0,0 +1,35 @@
+// config-dist writes config.dist.json, generated from helpers.BotConfig
+//
+// Usage: go run ./tools/config-dist [-o config.dist.json]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Seklfreak/Robyul2/helpers"
+)
+
+func main() {
+	outputPath := flag.String("o", "", "file to write to, stdout if empty")
+	flag.Parse()
+
+	data, err := helpers.GenerateDistConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to generate config:", err.Error())
+		os.Exit(1)
+	}
+
+	if *outputPath == "" {
+		os.Stdout.Write(data)
+		return
+	}
+
+	err = ioutil.WriteFile(*outputPath, data, 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write config:", err.Error())
+		os.Exit(1)
+	}
+}
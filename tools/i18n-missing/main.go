@@ -0,0 +1,46 @@
+// i18n-missing reports the texts of the default locale which are not translated yet in the other locales
+//
+// Usage: go run ./tools/i18n-missing [-base _assets/i18n.json] [-locales _assets/i18n]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Jeffail/gabs"
+	"github.com/Seklfreak/Robyul2/helpers"
+)
+
+func main() {
+	basePath := flag.String("base", "_assets/i18n.json", "translations of the default locale")
+	localesPath := flag.String("locales", "_assets/i18n", "folder with the <locale>.json translations")
+	flag.Parse()
+
+	base, err := gabs.ParseJSONFile(*basePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to parse base translations:", err.Error())
+		os.Exit(1)
+	}
+
+	locales, err := helpers.LoadLocaleTranslations(*localesPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load locale translations:", err.Error())
+		os.Exit(1)
+	}
+
+	localeNames := make([]string, 0)
+	for locale := range locales {
+		localeNames = append(localeNames, locale)
+	}
+	sort.Strings(localeNames)
+
+	for _, locale := range localeNames {
+		missing := helpers.GetMissingTranslationKeys(base, locales[locale])
+		fmt.Printf("%s: %d missing\n", locale, len(missing))
+		for _, key := range missing {
+			fmt.Println("  " + key)
+		}
+	}
+}